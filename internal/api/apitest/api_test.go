@@ -0,0 +1,11 @@
+package apitest_test
+
+import (
+	"testing"
+
+	"lawnconnect-api/internal/api/apitest"
+)
+
+func TestBookingLifecycle(t *testing.T) {
+	apitest.RunScenarios(t, apitest.BookingLifecycleScenarios())
+}
@@ -0,0 +1,476 @@
+// Package apitest is an end-to-end test harness for the HTTP API. It serves the
+// real router and services against in-memory repositories, a fake SMTP server and
+// a fake upload service, so handler tests exercise routing, auth and role checks
+// exactly as production does without any external dependency.
+//
+//	func TestBookingLifecycle(t *testing.T) {
+//		apitest.RunScenarios(t, apitest.BookingLifecycleScenarios())
+//	}
+package apitest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"lawnconnect-api/internal/core/domain"
+	coreServices "lawnconnect-api/internal/core/services"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+	"lawnconnect-api/internal/infrastructure/database/repositories/memory"
+	"lawnconnect-api/internal/infrastructure/documents"
+	"lawnconnect-api/internal/infrastructure/health"
+	"lawnconnect-api/internal/infrastructure/metrics"
+	infrastructureServices "lawnconnect-api/internal/infrastructure/services"
+	"lawnconnect-api/internal/server"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// emailTemplates lists every template the services send. The harness renders each
+// one as a plain dump of its data so tests can assert on what was sent.
+var emailTemplates = []string{
+	"password-reset.html",
+	"invoice.html",
+	"payment-reminder.html",
+	"booking-comment.html",
+}
+
+// jwtSecret signs the harness's session tokens.
+const jwtSecret = "apitest-secret-apitest-secret-apitest"
+
+// webhookSecret signs the fake payment provider's webhooks.
+const webhookSecret = "apitest-webhook-secret"
+
+// AuthorizationAmount is the card hold placed on bookings, in minor units.
+const AuthorizationAmount = 10000
+
+// QuoteToleranceBps is how far completion may move the price from the approved
+// quote, in basis points.
+const QuoteToleranceBps = 1000
+
+// Payout limits: the smallest withdrawal, in minor units, and how long earnings
+// are held before they can be withdrawn.
+const (
+	PayoutMinimumAmount = 2000
+	PayoutHoldPeriod    = 72 * time.Hour
+)
+
+// PayoutOriginator names the platform in NACHA payout files.
+var PayoutOriginator = documents.Originator{
+	CompanyName: "LawnConnect",
+	CompanyID:   "1234567890",
+	BankRouting: "021000021",
+	BankName:    "Test Bank",
+}
+
+// HomeLocation is where the addresses scenarios book are geocoded to.
+var HomeLocation = infrastructureServices.Location{Lat: 40.7128, Lng: -74.006}
+
+// MaxRadiusKm is the largest service radius a mower may set.
+const MaxRadiusKm = 100
+
+// HomeArea is the boundary of the service area every harness starts with,
+// about 50 km across around HomeLocation, at standard rates. RunScenarios
+// approves both mowers for it.
+var HomeArea = domain.GeoPolygon{Type: "Polygon", Coordinates: [][][]float64{{
+	{-74.3, 40.5}, {-73.7, 40.5}, {-73.7, 41}, {-74.3, 41}, {-74.3, 40.5},
+}}}
+
+// Routing plans mowers' days from 08:00 with hour-long jobs that may be
+// reached up to two hours after their booked time. Travel is estimated by the
+// haversine matrix at RouteSpeedKmh along roads RouteDetourFactor times longer
+// than the straight line.
+var Routing = coreServices.RouteSettings{DayStart: "08:00", JobDuration: time.Hour, ArrivalWindow: 2 * time.Hour}
+
+const (
+	RouteSpeedKmh     = 30
+	RouteDetourFactor = 1.3
+)
+
+// ReviewWindow is how long after completion customers may review a booking.
+const ReviewWindow = 14 * 24 * time.Hour
+
+// CommentSettings are how long authors may edit and delete their comments.
+var CommentSettings = coreServices.CommentSettings{EditWindow: 15 * time.Minute, DeleteWindow: time.Hour}
+
+// Pricing is the rate table behind price estimates: mowing only, a "metro"
+// region at 1.2, steep lawns at 1.5 and Christmas 2030 as a holiday.
+var Pricing = coreServices.PricingSettings{
+	Currency: "USD",
+	Services: []coreServices.ServiceRate{
+		{Name: "mowing", BaseFee: 2000, Bands: []coreServices.AreaBand{{UpTo: 500, Per100Sqm: 600}, {UpTo: 2000, Per100Sqm: 400}, {Per100Sqm: 250}}},
+	},
+	Regions:           map[string]float64{"metro": 1.2},
+	Terrain:           map[string]float64{domain.TerrainFlat: 1, domain.TerrainSteep: 1.5},
+	WeekendMultiplier: 1.25,
+	HolidayMultiplier: 1.5,
+	Holidays:          []string{"2030-12-25"},
+	RangeBps:          1000,
+}
+
+const genericTemplate = `<html><body>{{range $key, $value := .}}<p>{{$key}}: {{$value}}</p>
+{{end}}</body></html>`
+
+// Harness is a running API backed entirely by in-process fakes.
+type Harness struct {
+	Server   *httptest.Server
+	SMTP     *SMTPServer
+	Uploads  *FakeUploadService
+	Users    repositories.UserRepository
+	Bookings repositories.BookingRepository
+	Ledger   repositories.LedgerRepository
+	// Commission holds the rules admins create through the API.
+	Commission repositories.CommissionRuleRepository
+	Invoices   repositories.InvoiceRepository
+	Reminders  repositories.PaymentReminderRepository
+	Payouts    repositories.PayoutRepository
+	Properties repositories.PropertyRepository
+	// ServiceAreas starts with HomeArea, stored as HomeAreaID.
+	ServiceAreas repositories.ServiceAreaRepository
+	HomeAreaID   primitive.ObjectID
+	Reviews      repositories.ReviewRepository
+	// LedgerService posts entries that have no endpoint, such as opening balances.
+	LedgerService coreServices.LedgerService
+	// Dunning sends payment reminders; scenarios call it directly instead of
+	// waiting for the scheduler.
+	Dunning coreServices.DunningService
+	// Payments is the fake card processor; Webhook builds events to deliver with
+	// DeliverWebhook.
+	Payments *infrastructureServices.FakePaymentProvider
+	// Geocoder only locates addresses given to Set. The addresses scenarios book
+	// are at HomeLocation.
+	Geocoder *infrastructureServices.FakeGeocoder
+	Metrics  *metrics.Metrics
+}
+
+// New starts a fresh API for a single test. Everything is torn down when the test ends.
+func New(t *testing.T) *Harness {
+	t.Helper()
+
+	h := &Harness{
+		SMTP:         NewSMTPServer(t),
+		Uploads:      &FakeUploadService{},
+		Users:        memory.NewUserRepository(),
+		Bookings:     memory.NewBookingRepository(),
+		Ledger:       memory.NewLedgerRepository(),
+		Commission:   memory.NewCommissionRuleRepository(),
+		Invoices:     memory.NewInvoiceRepository(),
+		Reminders:    memory.NewPaymentReminderRepository(),
+		Payouts:      memory.NewPayoutRepository(),
+		Properties:   memory.NewPropertyRepository(),
+		ServiceAreas: memory.NewServiceAreaRepository(),
+		HomeAreaID:   primitive.NewObjectID(),
+		Reviews:      memory.NewReviewRepository(),
+		Payments:     infrastructureServices.NewFakePaymentProvider(webhookSecret),
+		Geocoder:     infrastructureServices.NewFakeGeocoder(HomeLocation),
+		Metrics:      metrics.New(),
+	}
+	h.Metrics.RegisterBookingGauges(h.Bookings)
+
+	emailService := metrics.InstrumentEmailService(infrastructureServices.NewEmailService(
+		h.SMTP.Host(), h.SMTP.Port(), "apitest", "apitest",
+		"noreply@lawnconnect.test", writeTemplates(t), "http://app.test/login",
+	), h.Metrics)
+	users := metrics.InstrumentUserRepository(h.Users, h.Metrics)
+	bookings := metrics.InstrumentBookingRepository(h.Bookings, h.Metrics)
+
+	authService := coreServices.NewAuthService(users, metrics.InstrumentPasswordResetTokenRepository(memory.NewPasswordResetTokenRepository(), h.Metrics), emailService, []byte(jwtSecret), time.Hour, "http://app.test/reset-password")
+	systemMetrics := metrics.InstrumentSystemMetricsRepository(memory.NewSystemMetricsRepository(), h.Metrics)
+	ledgerService := coreServices.NewLedgerService(metrics.InstrumentLedgerRepository(h.Ledger, h.Metrics), users, systemMetrics, "USD")
+	commissionService := coreServices.NewCommissionService(metrics.InstrumentCommissionRuleRepository(h.Commission, h.Metrics), 0)
+	invoices := metrics.InstrumentInvoiceRepository(h.Invoices, h.Metrics)
+	invoiceService := coreServices.NewInvoiceService(invoices, bookings, users, emailService, memory.NewTransactor(), coreServices.InvoiceSettings{
+		Prefix:   "INV",
+		Currency: "USD",
+		Issuer:   documents.Issuer{Name: "LawnConnect"},
+	})
+	h.Dunning = coreServices.NewDunningService(invoices, metrics.InstrumentPaymentReminderRepository(h.Reminders, h.Metrics), bookings, users, emailService, memory.NewTransactor(), coreServices.DunningSettings{
+		Thresholds:     []time.Duration{72 * time.Hour, 168 * time.Hour, 336 * time.Hour},
+		RetryBackoff:   time.Hour,
+		MaxFailedSends: 5,
+	})
+	paymentService := coreServices.NewPaymentService(metrics.InstrumentPaymentProvider(h.Payments, h.Metrics), bookings,
+		metrics.InstrumentPaymentEventRepository(memory.NewPaymentEventRepository(), h.Metrics), invoiceService, memory.NewTransactor(), coreServices.PaymentSettings{
+			Currency:            "USD",
+			AuthorizationAmount: AuthorizationAmount,
+		})
+	h.Geocoder.Strict = true
+	for _, address := range []string{"1 Lawn Street", "1 Lawn Street, Springfield, 12345"} {
+		h.Geocoder.Set(address, HomeLocation)
+	}
+	err := h.ServiceAreas.CreateArea(context.Background(), &domain.ServiceArea{
+		ID:              h.HomeAreaID,
+		Name:            "Home",
+		Boundary:        HomeArea,
+		PriceMultiplier: 1,
+		Active:          true,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("creating home service area: %v", err)
+	}
+	uploads := metrics.InstrumentUploadService(h.Uploads, h.Metrics)
+	locationService := coreServices.NewLocationService(metrics.InstrumentGeocoder(h.Geocoder, h.Metrics), users, coreServices.LocationSettings{MaxRadiusKm: MaxRadiusKm})
+	pricingService := coreServices.NewPricingService(Pricing)
+	serviceAreaService := coreServices.NewServiceAreaService(metrics.InstrumentServiceAreaRepository(h.ServiceAreas, h.Metrics), bookings, users, memory.NewTransactor())
+	propertyService := coreServices.NewPropertyService(metrics.InstrumentPropertyRepository(h.Properties, h.Metrics), pricingService, locationService, serviceAreaService, uploads)
+	bookingService := coreServices.NewBookingService(bookings, users, propertyService, pricingService, locationService, serviceAreaService, ledgerService, commissionService, invoiceService, h.Dunning, paymentService, memory.NewTransactor(), coreServices.BookingSettings{
+		QuoteToleranceBps:    QuoteToleranceBps,
+		WaitlistOutsideAreas: true,
+		Currency:             "USD",
+	})
+	distanceMatrix := metrics.InstrumentDistanceMatrix(infrastructureServices.NewHaversineMatrix(RouteSpeedKmh, RouteDetourFactor), h.Metrics)
+	routeService := coreServices.NewRouteService(bookings, locationService, distanceMatrix, Routing)
+	reviewService := coreServices.NewReviewService(metrics.InstrumentReviewRepository(h.Reviews, h.Metrics), bookings, users, memory.NewTransactor(), coreServices.ReviewSettings{Window: ReviewWindow})
+	commentService := coreServices.NewCommentService(bookings, users, uploads, emailService, CommentSettings)
+	payoutService := coreServices.NewPayoutService(metrics.InstrumentPayoutRepository(h.Payouts, h.Metrics), users, invoices, ledgerService, memory.NewTransactor(), coreServices.PayoutSettings{
+		Currency:      "USD",
+		MinimumAmount: PayoutMinimumAmount,
+		HoldPeriod:    PayoutHoldPeriod,
+		Originator:    PayoutOriginator,
+	})
+	h.LedgerService = ledgerService
+
+	handler := server.New(server.Config{JWTSecret: []byte(jwtSecret), Metrics: h.Metrics}, server.Dependencies{
+		AuthService:        authService,
+		BookingService:     bookingService,
+		LedgerService:      ledgerService,
+		CommissionService:  commissionService,
+		InvoiceService:     invoiceService,
+		DunningService:     h.Dunning,
+		PaymentService:     paymentService,
+		PayoutService:      payoutService,
+		PropertyService:    propertyService,
+		LocationService:    locationService,
+		ServiceAreaService: serviceAreaService,
+		RouteService:       routeService,
+		ReviewService:      reviewService,
+		CommentService:     commentService,
+		UploadService:      uploads,
+		Health: health.NewReporter(
+			health.Dependency{Name: "smtp", Check: health.SMTPCheck(h.SMTP.Host(), h.SMTP.Port())},
+			health.Dependency{Name: "storage", Check: h.Uploads.Ping},
+		),
+	})
+	h.Server = httptest.NewServer(handler)
+	t.Cleanup(h.Server.Close)
+	return h
+}
+
+// User is an account created through the harness, with a valid session token.
+type User struct {
+	ID       primitive.ObjectID
+	Name     string
+	Email    string
+	Password string
+	Role     string
+	Token    string
+}
+
+// RegisterUser creates an account with the given role and logs it in. Customers and
+// mowers go through the public register endpoint; other roles cannot self-register
+// and are inserted directly into the user repository.
+func (h *Harness) RegisterUser(t *testing.T, role string) *User {
+	t.Helper()
+
+	id := primitive.NewObjectID()
+	user := &User{
+		Name:     role + " " + id.Hex()[18:],
+		Email:    role + "-" + id.Hex() + "@example.com",
+		Password: "correct-horse-battery",
+		Role:     role,
+	}
+
+	switch role {
+	case "customer", "mower":
+		resp := h.Do(t, http.MethodPost, "/api/v1/auth/register", "", map[string]string{
+			"name": user.Name, "email": user.Email, "password": user.Password, "role": role,
+		})
+		resp.RequireStatus(t, http.StatusCreated)
+	default:
+		hashed, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.MinCost)
+		if err != nil {
+			t.Fatalf("hashing password: %v", err)
+		}
+		err = h.Users.CreateUser(context.Background(), &domain.User{
+			ID:        id,
+			Name:      user.Name,
+			Email:     user.Email,
+			Password:  string(hashed),
+			Role:      role,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("creating %s user: %v", role, err)
+		}
+	}
+
+	h.Login(t, user)
+	return user
+}
+
+// ApproveMower approves a mower for the given service areas directly in the
+// user repository, replacing any earlier approval.
+func (h *Harness) ApproveMower(t *testing.T, mower *User, areaIDs ...primitive.ObjectID) {
+	t.Helper()
+
+	err := h.Users.UpdateUser(context.Background(), mower.ID, primitive.M{"$set": primitive.M{"serviceAreaIds": areaIDs}})
+	if err != nil {
+		t.Fatalf("approving mower for service areas: %v", err)
+	}
+}
+
+// Login refreshes the user's token and ID from the login endpoint.
+func (h *Harness) Login(t *testing.T, user *User) {
+	t.Helper()
+
+	resp := h.Do(t, http.MethodPost, "/api/v1/auth/login", "", map[string]string{
+		"email": user.Email, "password": user.Password,
+	})
+	resp.RequireStatus(t, http.StatusOK)
+
+	var data struct {
+		User  domain.User `json:"user"`
+		Token string      `json:"token"`
+	}
+	resp.DecodeData(t, &data)
+	user.ID = data.User.ID
+	user.Token = data.Token
+}
+
+// Response is a decoded API response.
+type Response struct {
+	StatusCode int
+	Success    bool            `json:"success"`
+	Message    string          `json:"message"`
+	Data       json.RawMessage `json:"data"`
+	Body       []byte          `json:"-"`
+}
+
+// RequireStatus fails the test immediately if the response has a different status.
+func (r *Response) RequireStatus(t *testing.T, want int) {
+	t.Helper()
+	if r.StatusCode != want {
+		t.Fatalf("status = %d, want %d; body: %s", r.StatusCode, want, r.Body)
+	}
+}
+
+// DecodeData unmarshals the response's data field into out.
+func (r *Response) DecodeData(t *testing.T, out interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(r.Data, out); err != nil {
+		t.Fatalf("decoding response data: %v; body: %s", err, r.Body)
+	}
+}
+
+// Do sends a JSON request to the API. An empty token sends no Authorization header.
+func (h *Harness) Do(t *testing.T, method, path, token string, body interface{}) *Response {
+	t.Helper()
+
+	var payload io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("encoding request body: %v", err)
+		}
+		payload = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, h.Server.URL+path, payload)
+	if err != nil {
+		t.Fatalf("building request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return h.send(t, req)
+}
+
+// Upload posts content as the named file field of a multipart form.
+func (h *Harness) Upload(t *testing.T, path, token, field, filename string, content []byte) *Response {
+	t.Helper()
+
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	part, err := writer.CreateFormFile(field, filename)
+	if err == nil {
+		_, err = part.Write(content)
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		t.Fatalf("encoding multipart form: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, h.Server.URL+path, &form)
+	if err != nil {
+		t.Fatalf("building upload request: %v", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return h.send(t, req)
+}
+
+// DeliverWebhook posts a payment webhook built by h.Payments.Webhook.
+func (h *Harness) DeliverWebhook(t *testing.T, body []byte, header http.Header) *Response {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, h.Server.URL+"/api/v1/payments/webhook", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("building webhook request: %v", err)
+	}
+	req.Header = header.Clone()
+	return h.send(t, req)
+}
+
+func (h *Harness) send(t *testing.T, req *http.Request) *Response {
+	t.Helper()
+
+	res, err := h.Server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", req.Method, req.URL.Path, err)
+	}
+	defer res.Body.Close()
+
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("reading response body: %v", err)
+	}
+
+	resp := &Response{StatusCode: res.StatusCode, Body: raw}
+	if len(raw) > 0 && json.Valid(raw) {
+		json.Unmarshal(raw, resp)
+	}
+	return resp
+}
+
+// writeTemplates writes a generic template for every known email into a temp dir.
+func writeTemplates(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, name := range emailTemplates {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(genericTemplate), 0o644); err != nil {
+			t.Fatalf("writing email template %s: %v", name, err)
+		}
+	}
+	return dir
+}
@@ -0,0 +1,1523 @@
+package apitest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"lawnconnect-api/internal/core/domain"
+	coreServices "lawnconnect-api/internal/core/services"
+	infrastructureServices "lawnconnect-api/internal/infrastructure/services"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Actors available to scenario steps. Every scenario starts with one registered
+// user per actor.
+const (
+	Anonymous  = "anonymous"
+	Customer   = "customer"
+	Mower      = "mower"
+	OtherMower = "otherMower"
+	Admin      = "admin"
+)
+
+// bookingPlaceholder in a step path is replaced by the ID of the most recently
+// created booking.
+const bookingPlaceholder = "{booking}"
+
+// invoicePlaceholder in a step path is replaced by the invoice ID captured by
+// captureInvoice.
+const invoicePlaceholder = "{invoice}"
+
+// payoutPlaceholder and batchPlaceholder in a step path are replaced by the IDs
+// captured by capturePayout and captureBatch.
+const (
+	payoutPlaceholder = "{payout}"
+	batchPlaceholder  = "{batch}"
+)
+
+// propertyPlaceholder in a step path, or in the values of a map[string]string
+// body, is replaced by the property ID captured by captureProperty.
+const propertyPlaceholder = "{property}"
+
+// areaPlaceholder in a step path is replaced by the service area ID captured
+// by captureServiceArea.
+const areaPlaceholder = "{area}"
+
+// mowerPlaceholder in a step path is replaced by the Mower actor's user ID.
+const mowerPlaceholder = "{mower}"
+
+// commentPlaceholder in a step path is replaced by the comment ID captured by
+// captureComment.
+const commentPlaceholder = "{comment}"
+
+// Step is a single request in a scenario and the status it must return.
+type Step struct {
+	Name       string
+	As         string
+	Method     string
+	Path       string
+	Body       interface{}
+	WantStatus int
+	// Setup, when set, runs before the request is sent.
+	Setup func(t *testing.T, env *Env)
+	// Check, when set, runs extra assertions after the status check.
+	Check func(t *testing.T, env *Env, resp *Response)
+}
+
+// Scenario is an ordered list of steps run against a fresh harness.
+type Scenario struct {
+	Name  string
+	Steps []Step
+}
+
+// Env is the state shared by the steps of one scenario.
+type Env struct {
+	*Harness
+	Users      map[string]*User
+	BookingID  string
+	InvoiceID  string
+	PayoutID   string
+	BatchID    string
+	PropertyID string
+	CommentID  string
+	// ServiceAreaID is the last area created through the API; every harness also
+	// has HomeAreaID.
+	ServiceAreaID string
+}
+
+// RunScenarios runs each scenario as a sub-test against its own harness.
+func RunScenarios(t *testing.T, scenarios []Scenario) {
+	for _, sc := range scenarios {
+		sc := sc
+		t.Run(sc.Name, func(t *testing.T) {
+			env := &Env{
+				Harness: New(t),
+				Users:   map[string]*User{Anonymous: {}},
+			}
+			for _, actor := range []string{Customer, Mower, OtherMower, Admin} {
+				role := actor
+				if actor == OtherMower {
+					role = Mower
+				}
+				env.Users[actor] = env.RegisterUser(t, role)
+			}
+			env.ApproveMower(t, env.Users[Mower], env.HomeAreaID)
+			env.ApproveMower(t, env.Users[OtherMower], env.HomeAreaID)
+
+			for i, step := range sc.Steps {
+				env.run(t, i, step)
+			}
+		})
+	}
+}
+
+func (env *Env) run(t *testing.T, index int, step Step) {
+	t.Helper()
+
+	user, ok := env.Users[step.As]
+	if !ok {
+		t.Fatalf("step %d (%s): unknown actor %q", index, step.Name, step.As)
+	}
+
+	if step.Setup != nil {
+		step.Setup(t, env)
+	}
+
+	replacer := strings.NewReplacer(
+		bookingPlaceholder, env.BookingID,
+		invoicePlaceholder, env.InvoiceID,
+		payoutPlaceholder, env.PayoutID,
+		batchPlaceholder, env.BatchID,
+		propertyPlaceholder, env.PropertyID,
+		areaPlaceholder, env.ServiceAreaID,
+		mowerPlaceholder, env.Users[Mower].ID.Hex(),
+		commentPlaceholder, env.CommentID,
+	)
+	path := replacer.Replace(step.Path)
+	body := step.Body
+	if fields, ok := body.(map[string]string); ok {
+		replaced := make(map[string]string, len(fields))
+		for key, value := range fields {
+			replaced[key] = replacer.Replace(value)
+		}
+		body = replaced
+	}
+	resp := env.Do(t, step.Method, path, user.Token, body)
+	if resp.StatusCode != step.WantStatus {
+		t.Fatalf("step %d (%s): %s %s as %s = %d, want %d; body: %s",
+			index, step.Name, step.Method, path, step.As, resp.StatusCode, step.WantStatus, resp.Body)
+	}
+
+	if step.Method == http.MethodPost && step.Path == "/api/v1/bookings" && resp.StatusCode == http.StatusCreated {
+		var created struct {
+			ID string `json:"id"`
+		}
+		resp.DecodeData(t, &created)
+		env.BookingID = created.ID
+	}
+
+	if step.Check != nil {
+		step.Check(t, env, resp)
+	}
+}
+
+// newBooking is the request body used to create bookings in scenarios.
+var newBooking = map[string]string{
+	"date":        "2030-06-01",
+	"time":        "09:00",
+	"address":     "1 Lawn Street",
+	"description": "Front and back lawn",
+}
+
+// propertyAddress is the address of the property registered in scenarios.
+var propertyAddress = map[string]string{"line1": "1 Lawn Street", "city": "Springfield", "postalCode": "12345"}
+
+// newProperty is the request body used to register a property in scenarios: a
+// flat 600 m² lawn in the metro region.
+var newProperty = map[string]interface{}{
+	"label":       "Elm Street rental",
+	"address":     propertyAddress,
+	"region":      "metro",
+	"lawnArea":    600,
+	"serviceType": "mowing",
+	"accessNotes": "Gate code 4321, side gate",
+	"pets":        "Friendly dog in the back yard",
+}
+
+// propertyOn is newProperty on the given terrain.
+func propertyOn(terrain string) map[string]interface{} {
+	body := map[string]interface{}{"terrain": terrain}
+	for key, value := range newProperty {
+		body[key] = value
+	}
+	return body
+}
+
+// pngPhoto is enough of a PNG file for its content type to be detected.
+var pngPhoto = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// fixedQuote is the body a mower accepts a booking with, quoting price.
+func fixedQuote(price float64) map[string]interface{} {
+	return map[string]interface{}{"type": domain.QuoteFixed, "price": price}
+}
+
+// bookingWithCard is newBooking paid with a fake provider payment method.
+func bookingWithCard(paymentMethod string) map[string]string {
+	body := map[string]string{"paymentMethod": paymentMethod}
+	for key, value := range newBooking {
+		body[key] = value
+	}
+	return body
+}
+
+// BookingLifecycleScenarios covers the booking lifecycle and the role routing that
+// guards each transition.
+func BookingLifecycleScenarios() []Scenario {
+	return []Scenario{
+		{
+			Name: "HappyPath",
+			Steps: []Step{
+				{Name: "customer creates booking", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: newBooking, WantStatus: http.StatusCreated},
+				{Name: "customer lists own bookings", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings", WantStatus: http.StatusOK, Check: expectBookingCount(1)},
+				{Name: "customer views booking", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK, Check: expectStatus("pending")},
+				{Name: "mower sees pending job", As: Mower, Method: http.MethodGet, Path: "/api/v1/bookings/pending", WantStatus: http.StatusOK,
+					Setup: setServiceRadius(Mower, HomeLocation, 10), Check: expectBookingCount(1)},
+				{Name: "mower accepts", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(45.5), WantStatus: http.StatusOK},
+				{Name: "customer approves quote", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/quote/approve", WantStatus: http.StatusOK},
+				{Name: "job leaves pending list", As: OtherMower, Method: http.MethodGet, Path: "/api/v1/bookings/pending", WantStatus: http.StatusOK,
+					Setup: setServiceRadius(OtherMower, HomeLocation, 10), Check: expectBookingCount(0)},
+				{Name: "mower lists assigned bookings", As: Mower, Method: http.MethodGet, Path: "/api/v1/bookings", WantStatus: http.StatusOK, Check: expectBookingCount(1)},
+				{Name: "other mower cannot complete", As: OtherMower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", Body: map[string]float64{"price": 45.5}, WantStatus: http.StatusConflict},
+				{Name: "mower completes", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", Body: map[string]float64{"price": 45.5}, WantStatus: http.StatusOK},
+				{Name: "customer sees completion", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK, Check: expectStatus("completed")},
+				{Name: "completed booking cannot be cancelled", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/cancel", WantStatus: http.StatusConflict},
+				{Name: "completed booking cannot be paid twice", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", Body: map[string]float64{"price": 45.5}, WantStatus: http.StatusConflict},
+				{Name: "mower is credited", As: Mower, Method: http.MethodGet, Path: "/api/v1/wallet/statement", WantStatus: http.StatusOK, Check: expectWalletBalance(4550)},
+				{Name: "other mower is not credited", As: OtherMower, Method: http.MethodGet, Path: "/api/v1/wallet/statement", WantStatus: http.StatusOK, Check: expectWalletBalance(0)},
+				{Name: "customers have no wallet", As: Customer, Method: http.MethodGet, Path: "/api/v1/wallet/statement", WantStatus: http.StatusForbidden},
+			},
+		},
+		{
+			Name: "CustomerCancels",
+			Steps: []Step{
+				{Name: "customer creates booking", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: newBooking, WantStatus: http.StatusCreated},
+				{Name: "customer cancels", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/cancel", WantStatus: http.StatusOK},
+				{Name: "cancelled booking cannot be accepted", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(45.5), WantStatus: http.StatusConflict},
+			},
+		},
+		{
+			Name: "MowerRejects",
+			Steps: []Step{
+				{Name: "customer creates booking", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: newBooking, WantStatus: http.StatusCreated},
+				{Name: "mower rejects", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/reject", WantStatus: http.StatusOK},
+				{Name: "customer sees rejection", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK, Check: expectStatus("rejected")},
+			},
+		},
+		{
+			Name: "InvalidTransitions",
+			Steps: []Step{
+				{Name: "customer creates booking", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: newBooking, WantStatus: http.StatusCreated},
+				{Name: "pending booking cannot be completed", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", Body: map[string]float64{"price": 10}, WantStatus: http.StatusConflict},
+				{Name: "price must be positive", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", Body: map[string]float64{"price": -10}, WantStatus: http.StatusBadRequest},
+				{Name: "mower accepts", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(45.5), WantStatus: http.StatusOK},
+				{Name: "second mower cannot accept", As: OtherMower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(45.5), WantStatus: http.StatusConflict},
+				{Name: "unknown booking", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/000000000000000000000000", WantStatus: http.StatusNotFound},
+				{Name: "malformed booking id", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/not-an-id", WantStatus: http.StatusBadRequest},
+			},
+		},
+		{
+			Name: "RoleRouting",
+			Steps: []Step{
+				{Name: "anonymous cannot list bookings", As: Anonymous, Method: http.MethodGet, Path: "/api/v1/bookings", WantStatus: http.StatusUnauthorized},
+				{Name: "anonymous cannot create bookings", As: Anonymous, Method: http.MethodPost, Path: "/api/v1/bookings", Body: newBooking, WantStatus: http.StatusUnauthorized},
+				{Name: "mower cannot create bookings", As: Mower, Method: http.MethodPost, Path: "/api/v1/bookings", Body: newBooking, WantStatus: http.StatusForbidden},
+				{Name: "customer creates booking", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: newBooking, WantStatus: http.StatusCreated},
+				{Name: "customer cannot see pending pool", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/pending", WantStatus: http.StatusForbidden},
+				{Name: "customer cannot accept", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", WantStatus: http.StatusForbidden},
+				{Name: "customer cannot complete", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", Body: map[string]float64{"price": 10}, WantStatus: http.StatusForbidden},
+				{Name: "mower cannot cancel", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/cancel", WantStatus: http.StatusForbidden},
+			},
+		},
+		{
+			Name: "CommissionAndRevenue",
+			Steps: []Step{
+				{Name: "mowers cannot manage commission", As: Mower, Method: http.MethodGet, Path: "/api/v1/admin/commission-rules", WantStatus: http.StatusForbidden},
+				{Name: "percentage is bounded", As: Admin, Method: http.MethodPost, Path: "/api/v1/admin/commission-rules", WantStatus: http.StatusBadRequest,
+					Body: map[string]interface{}{"name": "Too much", "percentageBps": 10001}},
+				{Name: "admin sets 10% commission", As: Admin, Method: http.MethodPost, Path: "/api/v1/admin/commission-rules", WantStatus: http.StatusCreated,
+					Body: map[string]interface{}{"name": "Standard", "percentageBps": 1000}},
+				{Name: "customer creates booking", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: newBooking, WantStatus: http.StatusCreated},
+				{Name: "mower accepts", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(45.5), WantStatus: http.StatusOK},
+				{Name: "customer approves quote", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/quote/approve", WantStatus: http.StatusOK},
+				{Name: "mower completes", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", Body: map[string]float64{"price": 45.5}, WantStatus: http.StatusOK},
+				{Name: "mower is credited net of commission", As: Mower, Method: http.MethodGet, Path: "/api/v1/wallet/statement", WantStatus: http.StatusOK, Check: expectWalletBalance(4095)},
+				{Name: "admin starts a zero-commission promotion", As: Admin, Method: http.MethodPost, Path: "/api/v1/admin/commission-rules", WantStatus: http.StatusCreated,
+					Body: map[string]interface{}{"name": "Launch week", "validFrom": time.Now().Add(-time.Hour), "validUntil": time.Now().Add(24 * time.Hour)}},
+				{Name: "customer creates second booking", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: newBooking, WantStatus: http.StatusCreated},
+				{Name: "mower accepts second booking", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(45.5), WantStatus: http.StatusOK},
+				{Name: "customer approves second quote", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/quote/approve", WantStatus: http.StatusOK},
+				{Name: "mower completes second booking", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", Body: map[string]float64{"price": 45.5}, WantStatus: http.StatusOK},
+				{Name: "promotion takes no commission", As: Mower, Method: http.MethodGet, Path: "/api/v1/wallet/statement", WantStatus: http.StatusOK, Check: expectWalletBalance(8645)},
+				{Name: "admin sees monthly revenue", As: Admin, Method: http.MethodGet, Path: "/api/v1/admin/revenue?period=month", WantStatus: http.StatusOK, Check: expectRevenue(455)},
+				{Name: "unknown period is rejected", As: Admin, Method: http.MethodGet, Path: "/api/v1/admin/revenue?period=year", WantStatus: http.StatusBadRequest},
+				{Name: "unknown rule cannot be deactivated", As: Admin, Method: http.MethodDelete, Path: "/api/v1/admin/commission-rules/000000000000000000000000", WantStatus: http.StatusNotFound},
+			},
+		},
+		{
+			Name: "Invoicing",
+			Steps: []Step{
+				{Name: "customer creates booking", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: newBooking, WantStatus: http.StatusCreated},
+				{Name: "new booking is unbilled", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK, Check: expectBillingStatus("pending")},
+				{Name: "mower accepts", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(45.5), WantStatus: http.StatusOK},
+				{Name: "customer approves quote", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/quote/approve", WantStatus: http.StatusOK},
+				{Name: "mower completes", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", Body: map[string]float64{"price": 45.5}, WantStatus: http.StatusOK},
+				{Name: "completed booking is billed", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK, Check: expectBillingStatus("billed")},
+				{Name: "customer lists invoices", As: Customer, Method: http.MethodGet, Path: "/api/v1/invoices", WantStatus: http.StatusOK, Check: captureInvoice},
+				{Name: "invoice is emailed with PDF", As: Customer, Method: http.MethodGet, Path: "/api/v1/invoices/{invoice}", WantStatus: http.StatusOK,
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						env.SMTP.WaitForMessages(1, 5*time.Second)
+						messages := env.SMTP.MessagesTo(env.Users[Customer].Email)
+						if len(messages) != 1 || !strings.Contains(messages[0].Data, `filename="INV-`) {
+							t.Fatalf("expected one invoice email with a PDF attachment, got %d messages", len(messages))
+						}
+					}},
+				{Name: "customer downloads PDF", As: Customer, Method: http.MethodGet, Path: "/api/v1/invoices/{invoice}/pdf", WantStatus: http.StatusOK,
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						if !strings.HasPrefix(string(resp.Body), "%PDF-") {
+							t.Fatalf("invoice download is not a PDF: %.40q", resp.Body)
+						}
+					}},
+				{Name: "mower downloads HTML", As: Mower, Method: http.MethodGet, Path: "/api/v1/invoices/{invoice}/html", WantStatus: http.StatusOK,
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						if !strings.Contains(string(resp.Body), "45.50 USD") {
+							t.Fatalf("invoice HTML does not show the amount: %s", resp.Body)
+						}
+					}},
+				{Name: "other mower cannot see invoice", As: OtherMower, Method: http.MethodGet, Path: "/api/v1/invoices/{invoice}", WantStatus: http.StatusNotFound},
+				{Name: "customer cannot mark paid", As: Customer, Method: http.MethodPut, Path: "/api/v1/invoices/{invoice}/paid", WantStatus: http.StatusForbidden},
+				{Name: "mower cannot mark paid", As: Mower, Method: http.MethodPut, Path: "/api/v1/invoices/{invoice}/paid", WantStatus: http.StatusForbidden},
+				{Name: "admin marks paid", As: Admin, Method: http.MethodPut, Path: "/api/v1/invoices/{invoice}/paid", WantStatus: http.StatusOK},
+				{Name: "invoice cannot be paid twice", As: Admin, Method: http.MethodPut, Path: "/api/v1/invoices/{invoice}/paid", WantStatus: http.StatusConflict},
+				{Name: "booking is paid", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK, Check: expectBillingStatus("paid")},
+			},
+		},
+		{
+			Name: "PaymentReminders",
+			Steps: []Step{
+				{Name: "customer creates booking", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: newBooking, WantStatus: http.StatusCreated},
+				{Name: "mower accepts", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(45.5), WantStatus: http.StatusOK},
+				{Name: "customer approves quote", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/quote/approve", WantStatus: http.StatusOK},
+				{Name: "mower completes", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", Body: map[string]float64{"price": 45.5}, WantStatus: http.StatusOK},
+				{Name: "customer lists invoices", As: Customer, Method: http.MethodGet, Path: "/api/v1/invoices", WantStatus: http.StatusOK, Check: captureInvoice},
+				{Name: "nothing is due yet", As: Customer, Method: http.MethodGet, Path: "/api/v1/invoices/{invoice}/reminders", WantStatus: http.StatusOK,
+					Setup: sendReminders(time.Hour, 0), Check: expectReminders(0)},
+				{Name: "first reminder after three days", As: Customer, Method: http.MethodGet, Path: "/api/v1/invoices/{invoice}", WantStatus: http.StatusOK,
+					Setup: sendReminders(100*time.Hour, 1), Check: expectInvoiceReminders(1, false)},
+				{Name: "first reminder is not repeated", As: Customer, Method: http.MethodGet, Path: "/api/v1/invoices/{invoice}", WantStatus: http.StatusOK,
+					Setup: sendReminders(101*time.Hour, 0), Check: expectInvoiceReminders(1, false)},
+				{Name: "final notice skips straight to the last level", As: Customer, Method: http.MethodGet, Path: "/api/v1/invoices/{invoice}", WantStatus: http.StatusOK,
+					Setup: sendReminders(400*time.Hour, 1), Check: expectInvoiceReminders(3, true)},
+				{Name: "reminders are emailed", As: Customer, Method: http.MethodGet, Path: "/api/v1/invoices/{invoice}/reminders", WantStatus: http.StatusOK,
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						expectReminders(2)(t, env, resp)
+						env.SMTP.WaitForMessages(3, 5*time.Second)
+						if got := len(env.SMTP.MessagesTo(env.Users[Customer].Email)); got != 3 {
+							t.Fatalf("expected the invoice and two reminders to be emailed, got %d messages", got)
+						}
+					}},
+				{Name: "other mower cannot see reminders", As: OtherMower, Method: http.MethodGet, Path: "/api/v1/invoices/{invoice}/reminders", WantStatus: http.StatusNotFound},
+				{Name: "overdue customer is warned on booking", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: newBooking, WantStatus: http.StatusCreated, Check: expectCustomerOverdue(true)},
+				{Name: "mower cannot mark paid", As: Mower, Method: http.MethodPut, Path: "/api/v1/invoices/{invoice}/paid", WantStatus: http.StatusForbidden},
+				{Name: "admin marks paid", As: Admin, Method: http.MethodPut, Path: "/api/v1/invoices/{invoice}/paid", WantStatus: http.StatusOK},
+				{Name: "paying clears the overdue flag", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: newBooking, WantStatus: http.StatusCreated, Check: expectCustomerOverdue(false)},
+				{Name: "paid invoices get no more reminders", As: Customer, Method: http.MethodGet, Path: "/api/v1/invoices/{invoice}/reminders", WantStatus: http.StatusOK,
+					Setup: sendReminders(1000*time.Hour, 0), Check: expectReminders(2)},
+			},
+		},
+		{
+			Name: "CardPayments",
+			Steps: []Step{
+				{Name: "declined card is refused", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: bookingWithCard(infrastructureServices.FakeCardDeclined), WantStatus: http.StatusPaymentRequired},
+				{Name: "customer books with a card", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: bookingWithCard(infrastructureServices.FakeCardVisa), WantStatus: http.StatusCreated,
+					Check: expectPayment(domain.PaymentAuthorized, 0, 0)},
+				{Name: "mower accepts", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(45.5), WantStatus: http.StatusOK},
+				{Name: "customer approves quote", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/quote/approve", WantStatus: http.StatusOK},
+				{Name: "mower completes", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", Body: map[string]float64{"price": 45.5}, WantStatus: http.StatusOK},
+				{Name: "card is captured", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK,
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						expectPayment(domain.PaymentCaptured, 4550, 0)(t, env, resp)
+						expectBillingStatus("paid")(t, env, resp)
+					}},
+				{Name: "invoice is issued paid", As: Customer, Method: http.MethodGet, Path: "/api/v1/invoices", WantStatus: http.StatusOK,
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						captureInvoice(t, env, resp)
+						var invoices []struct {
+							Status string `json:"status"`
+						}
+						resp.DecodeData(t, &invoices)
+						if invoices[0].Status != domain.InvoicePaid {
+							t.Fatalf("invoice status = %q, want %q", invoices[0].Status, domain.InvoicePaid)
+						}
+					}},
+				{Name: "redelivered refund is applied once", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK,
+					Setup: deliverWebhook(infrastructureServices.PaymentEventRefunded, 1000, 2), Check: expectPayment(domain.PaymentCaptured, 4550, 1000)},
+				{Name: "unsigned webhook is rejected", As: Anonymous, Method: http.MethodPost, Path: "/api/v1/payments/webhook", WantStatus: http.StatusBadRequest,
+					Body: map[string]interface{}{"id": "fake_evt_forged", "type": infrastructureServices.PaymentEventRefunded, "data": map[string]interface{}{"amount": 3550}}},
+				{Name: "customer books another job", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: bookingWithCard(infrastructureServices.FakeCardVisa), WantStatus: http.StatusCreated},
+				{Name: "customer cancels", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/cancel", WantStatus: http.StatusOK},
+				{Name: "hold is released", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK, Check: expectPayment(domain.PaymentVoided, 0, 0)},
+			},
+		},
+		{
+			Name: "FailedCapture",
+			Steps: []Step{
+				{Name: "customer books with a card that cannot be charged", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: bookingWithCard(infrastructureServices.FakeCardCaptureFails), WantStatus: http.StatusCreated},
+				{Name: "mower accepts", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(45.5), WantStatus: http.StatusOK},
+				{Name: "customer approves quote", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/quote/approve", WantStatus: http.StatusOK},
+				{Name: "completion still succeeds", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", Body: map[string]float64{"price": 45.5}, WantStatus: http.StatusOK},
+				{Name: "booking is billed by invoice", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK,
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						expectPayment(domain.PaymentFailed, 0, 0)(t, env, resp)
+						expectBillingStatus("billed")(t, env, resp)
+						expectProviderStatus(infrastructureServices.PaymentStatusVoided)(t, env, resp)
+					}},
+				{Name: "late capture webhook settles the invoice", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK,
+					Setup: deliverWebhook(infrastructureServices.PaymentEventCaptured, 4550, 1),
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						expectPayment(domain.PaymentCaptured, 4550, 0)(t, env, resp)
+						expectBillingStatus("paid")(t, env, resp)
+					}},
+			},
+		},
+		{
+			Name: "PricesWithinTheHold",
+			Steps: []Step{
+				{Name: "customer books with a card", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: bookingWithCard(infrastructureServices.FakeCardVisa), WantStatus: http.StatusCreated},
+				{Name: "quote above the hold is refused", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(150), WantStatus: http.StatusConflict},
+				{Name: "mower quotes within the hold", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(95), WantStatus: http.StatusOK},
+				{Name: "customer approves quote", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/quote/approve", WantStatus: http.StatusOK},
+				{Name: "adjusting the price above the hold is refused", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete",
+					Body: map[string]interface{}{"price": 104.5, "justification": "Extra edging"}, WantStatus: http.StatusConflict},
+				{Name: "mower completes at the quote", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", Body: map[string]float64{"price": 95}, WantStatus: http.StatusOK},
+				{Name: "the quote is captured", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK,
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						expectPayment(domain.PaymentCaptured, 9500, 0)(t, env, resp)
+						expectBillingStatus("paid")(t, env, resp)
+					}},
+			},
+		},
+		{
+			Name: "Quotes",
+			Steps: []Step{
+				{Name: "customer creates booking", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: newBooking, WantStatus: http.StatusCreated},
+				{Name: "accepting needs a quote", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: map[string]string{}, WantStatus: http.StatusBadRequest},
+				{Name: "hourly quotes need an hourly rate", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", WantStatus: http.StatusConflict,
+					Body: map[string]interface{}{"type": domain.QuoteHourly, "estimatedHours": 1.5}},
+				{Name: "mower quotes by the hour", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", WantStatus: http.StatusOK,
+					Body: map[string]interface{}{"type": domain.QuoteHourly, "estimatedHours": 1.5, "note": "About ninety minutes"}, Setup: setHourlyRate(Mower, 30)},
+				{Name: "customer sees the quote", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK, Check: expectQuote(domain.QuoteProposed, 4500)},
+				{Name: "unapproved quote cannot be completed", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", Body: map[string]float64{}, WantStatus: http.StatusConflict},
+				{Name: "mowers cannot approve quotes", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/quote/approve", WantStatus: http.StatusForbidden},
+				{Name: "customer declines", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/quote/decline", Body: map[string]string{"reason": "Too expensive"}, WantStatus: http.StatusOK},
+				{Name: "declined booking is pending again", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK,
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						expectStatus("pending")(t, env, resp)
+						expectQuote(domain.QuoteDeclined, 4500)(t, env, resp)
+					}},
+				{Name: "declined quote cannot be approved", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/quote/approve", WantStatus: http.StatusConflict},
+				{Name: "other mower quotes a fixed price", As: OtherMower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(40), WantStatus: http.StatusOK},
+				{Name: "customer approves quote", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/quote/approve", WantStatus: http.StatusOK},
+				{Name: "approved quote is the price", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK, Check: expectQuote(domain.QuoteApproved, 4000)},
+				{Name: "price cannot move beyond the tolerance", As: OtherMower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", WantStatus: http.StatusConflict,
+					Body: map[string]interface{}{"price": 44.01, "justification": "Overgrown back lawn"}},
+				{Name: "adjustments need a justification", As: OtherMower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", Body: map[string]float64{"price": 44}, WantStatus: http.StatusConflict},
+				{Name: "mower completes with a justified adjustment", As: OtherMower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", WantStatus: http.StatusOK,
+					Body: map[string]interface{}{"price": 44, "justification": "Overgrown back lawn"}},
+				{Name: "adjusted price is charged", As: OtherMower, Method: http.MethodGet, Path: "/api/v1/wallet/statement", WantStatus: http.StatusOK, Check: expectWalletBalance(4400)},
+			},
+		},
+		{
+			Name: "JobDiscovery",
+			Steps: []Step{
+				{Name: "mowers need a service radius to browse", As: Mower, Method: http.MethodGet, Path: "/api/v1/bookings/pending", WantStatus: http.StatusConflict},
+				{Name: "no service radius yet", As: Mower, Method: http.MethodGet, Path: "/api/v1/mower/service-radius", WantStatus: http.StatusNotFound},
+				{Name: "radius is bounded", As: Mower, Method: http.MethodPut, Path: "/api/v1/mower/service-radius", WantStatus: http.StatusBadRequest,
+					Body: map[string]interface{}{"baseAddress": "1 Lawn Street", "radiusKm": MaxRadiusKm + 1}},
+				{Name: "base address must be located", As: Mower, Method: http.MethodPut, Path: "/api/v1/mower/service-radius", WantStatus: http.StatusBadRequest,
+					Body: map[string]interface{}{"baseAddress": "Nowhere in particular", "radiusKm": 10}},
+				{Name: "customers have no service radius", As: Customer, Method: http.MethodPut, Path: "/api/v1/mower/service-radius", WantStatus: http.StatusForbidden,
+					Body: map[string]interface{}{"baseAddress": "1 Lawn Street", "radiusKm": 10}},
+				{Name: "mower works within 10 km of home", As: Mower, Method: http.MethodPut, Path: "/api/v1/mower/service-radius", WantStatus: http.StatusOK,
+					Body: map[string]interface{}{"baseAddress": "1 Lawn Street", "radiusKm": 10}},
+				{Name: "mower sees the service radius", As: Mower, Method: http.MethodGet, Path: "/api/v1/mower/service-radius", WantStatus: http.StatusOK,
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						var radius domain.ServiceRadius
+						resp.DecodeData(t, &radius)
+						if radius.RadiusKm != 10 || radius.BaseLocation == nil || radius.BaseLocation.Lat() != HomeLocation.Lat {
+							t.Fatalf("service radius = %+v", radius)
+						}
+					}},
+				{Name: "bookings need an address that can be located", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", WantStatus: http.StatusBadRequest,
+					Body: map[string]string{"date": "2030-06-01", "time": "09:00", "address": "Nowhere in particular"}},
+				{Name: "customer books at home", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: newBooking, WantStatus: http.StatusCreated},
+				{Name: "customer books across town", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", WantStatus: http.StatusCreated,
+					Body: map[string]string{"date": "2030-06-01", "time": "11:00", "address": "9 Far Road"}, Setup: locate("9 Far Road", acrossTown)},
+				{Name: "mower only sees the local job", As: Mower, Method: http.MethodGet, Path: "/api/v1/bookings/pending", WantStatus: http.StatusOK,
+					Check: expectPendingAddresses("1 Lawn Street")},
+				{Name: "other mower based across town sees both, nearest first", As: OtherMower, Method: http.MethodGet, Path: "/api/v1/bookings/pending", WantStatus: http.StatusOK,
+					Setup: setServiceRadius(OtherMower, acrossTown, 25), Check: expectPendingAddresses("9 Far Road", "1 Lawn Street")},
+			},
+		},
+		{
+			Name: "ServiceAreas",
+			Steps: []Step{
+				{Name: "mowers cannot define service areas", As: Mower, Method: http.MethodPost, Path: "/api/v1/admin/service-areas", Body: upstateArea, WantStatus: http.StatusForbidden},
+				{Name: "boundary must be a closed ring", As: Admin, Method: http.MethodPost, Path: "/api/v1/admin/service-areas", WantStatus: http.StatusBadRequest,
+					Body: map[string]interface{}{"name": "Open", "boundary": map[string]interface{}{"type": "Polygon", "coordinates": [][][]float64{{{-74, 42}, {-73, 42}, {-73, 43}, {-74, 43}}}}}},
+				{Name: "boundary must not cross itself", As: Admin, Method: http.MethodPost, Path: "/api/v1/admin/service-areas", WantStatus: http.StatusBadRequest,
+					Body: map[string]interface{}{"name": "Bowtie", "boundary": map[string]interface{}{"type": "Polygon", "coordinates": [][][]float64{{{-74, 42}, {-73, 43}, {-73, 42}, {-74, 43}, {-74, 42}}}}}},
+				{Name: "booking outside every area is waitlisted", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", WantStatus: http.StatusCreated,
+					Body: upstateBooking, Setup: locate("5 Farm Lane", upstate), Check: expectStatus("waitlisted")},
+				{Name: "waitlisted jobs are not offered", As: Mower, Method: http.MethodGet, Path: "/api/v1/bookings/pending", WantStatus: http.StatusOK,
+					Setup: setServiceRadius(Mower, upstate, 10), Check: expectPendingAddresses()},
+				{Name: "admin covers upstate at a higher rate", As: Admin, Method: http.MethodPost, Path: "/api/v1/admin/service-areas", Body: upstateArea, WantStatus: http.StatusCreated, Check: captureServiceArea},
+				{Name: "the new area releases the waitlisted booking", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK, Check: expectStatus("pending")},
+				{Name: "mower is not approved upstate yet", As: Mower, Method: http.MethodGet, Path: "/api/v1/bookings/pending", WantStatus: http.StatusOK, Check: expectPendingAddresses()},
+				{Name: "unapproved mower cannot accept", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(100), WantStatus: http.StatusConflict},
+				{Name: "only mowers can be approved", As: Admin, Method: http.MethodPut, Path: "/api/v1/admin/mowers/000000000000000000000000/service-areas", WantStatus: http.StatusNotFound,
+					Body: map[string][]string{"areaIds": {}}},
+				{Name: "approved mower lists their areas", As: Mower, Method: http.MethodGet, Path: "/api/v1/mower/service-areas", WantStatus: http.StatusOK,
+					Setup: approveMower(Mower), Check: expectServiceAreas("Home", "Upstate")},
+				{Name: "approved mower sees the upstate job", As: Mower, Method: http.MethodGet, Path: "/api/v1/bookings/pending", WantStatus: http.StatusOK, Check: expectPendingAddresses("5 Farm Lane")},
+				{Name: "mower accepts", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(100), WantStatus: http.StatusOK},
+				{Name: "customer approves quote", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/quote/approve", WantStatus: http.StatusOK},
+				{Name: "mower completes", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", Body: map[string]float64{}, WantStatus: http.StatusOK},
+				{Name: "platform takes the area's commission", As: Admin, Method: http.MethodGet, Path: "/api/v1/admin/revenue?period=month", WantStatus: http.StatusOK, Check: expectRevenue(2000)},
+				{Name: "properties upstate are priced at the area's rate", As: Customer, Method: http.MethodPost, Path: "/api/v1/properties", WantStatus: http.StatusCreated,
+					Body:  map[string]interface{}{"address": map[string]string{"line1": "5 Farm Lane", "city": "Albany", "postalCode": "12207"}, "region": "metro", "lawnArea": 600, "serviceType": "mowing"},
+					Setup: locate("5 Farm Lane, Albany, 12207", upstate), Check: captureProperty},
+				{Name: "upstate weekday estimate", As: Customer, Method: http.MethodGet, Path: "/api/v1/properties/{property}/estimate?date=2030-06-03", WantStatus: http.StatusOK, Check: expectEstimate(7128, 6400, 7900)},
+				{Name: "admin withdraws upstate", As: Admin, Method: http.MethodDelete, Path: "/api/v1/admin/service-areas/{area}", WantStatus: http.StatusOK},
+				{Name: "new upstate bookings are waitlisted again", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: upstateBooking, WantStatus: http.StatusCreated, Check: expectStatus("waitlisted")},
+				{Name: "waitlisted bookings can be cancelled", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/cancel", WantStatus: http.StatusOK},
+				{Name: "unknown area", As: Admin, Method: http.MethodDelete, Path: "/api/v1/admin/service-areas/000000000000000000000000", WantStatus: http.StatusNotFound},
+			},
+		},
+		{
+			Name: "DailyRoute",
+			Steps: []Step{
+				{Name: "customers have no route", As: Customer, Method: http.MethodGet, Path: "/api/v1/mower/route?date=2030-06-01", WantStatus: http.StatusForbidden},
+				{Name: "routes start from a base", As: Mower, Method: http.MethodGet, Path: "/api/v1/mower/route?date=2030-06-01", WantStatus: http.StatusConflict},
+				{Name: "route needs a date", As: Mower, Method: http.MethodGet, Path: "/api/v1/mower/route", WantStatus: http.StatusBadRequest,
+					Setup: setServiceRadius(Mower, HomeLocation, 25)},
+				{Name: "start must be a time of day", As: Mower, Method: http.MethodGet, Path: "/api/v1/mower/route?date=2030-06-01&start=8am", WantStatus: http.StatusBadRequest},
+				{Name: "a day without jobs is an empty route", As: Mower, Method: http.MethodGet, Path: "/api/v1/mower/route?date=2030-06-01", WantStatus: http.StatusOK,
+					Check: expectRoute()},
+				{Name: "customer books to the north at nine", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", WantStatus: http.StatusCreated,
+					Body: map[string]string{"date": "2030-06-01", "time": "09:00", "address": "2 North Road"}, Setup: locate("2 North Road", northOfHome)},
+				{Name: "mower accepts the north job", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(45.5), WantStatus: http.StatusOK},
+				{Name: "customer books to the south at nine", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", WantStatus: http.StatusCreated,
+					Body: map[string]string{"date": "2030-06-01", "time": "09:00", "address": "3 South Road"}, Setup: locate("3 South Road", southOfHome)},
+				{Name: "mower accepts the south job", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(45.5), WantStatus: http.StatusOK},
+				{Name: "customer books across town at ten", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", WantStatus: http.StatusCreated,
+					Body: map[string]string{"date": "2030-06-01", "time": "10:00", "address": "9 Far Road"}, Setup: locate("9 Far Road", acrossTown)},
+				{Name: "mower accepts the job across town", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(45.5), WantStatus: http.StatusOK},
+				{Name: "customer books home the next day", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", WantStatus: http.StatusCreated,
+					Body: map[string]string{"date": "2030-06-02", "time": "09:00", "address": "1 Lawn Street"}},
+				{Name: "mower accepts the next day's job", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(45.5), WantStatus: http.StatusOK},
+				{Name: "route heads south first so the north leg leads across town", As: Mower, Method: http.MethodGet, Path: "/api/v1/mower/route?date=2030-06-01", WantStatus: http.StatusOK,
+					Check: expectRoute("3 South Road", "2 North Road", "9 Far Road")},
+				{Name: "other mower's day is empty", As: OtherMower, Method: http.MethodGet, Path: "/api/v1/mower/route?date=2030-06-01", WantStatus: http.StatusOK,
+					Setup: setServiceRadius(OtherMower, HomeLocation, 25), Check: expectRoute()},
+				{Name: "leaving at noon makes every stop late", As: Mower, Method: http.MethodGet, Path: "/api/v1/mower/route?date=2030-06-01&start=12:00", WantStatus: http.StatusOK,
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						var route domain.Route
+						resp.DecodeData(t, &route)
+						if route.DepartAt != "12:00" || len(route.Stops) != 3 || route.LateStops != 3 {
+							t.Fatalf("route leaving at noon = %+v", route)
+						}
+					}},
+			},
+		},
+		{
+			Name: "Reviews",
+			Steps: []Step{
+				{Name: "customer creates booking", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: newBooking, WantStatus: http.StatusCreated},
+				{Name: "mower accepts", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(45.5), WantStatus: http.StatusOK,
+					Setup: setServiceRadius(Mower, HomeLocation, 10)},
+				{Name: "customer approves quote", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/quote/approve", WantStatus: http.StatusOK},
+				{Name: "unfinished jobs cannot be reviewed", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings/{booking}/review", Body: map[string]interface{}{"rating": 5}, WantStatus: http.StatusConflict},
+				{Name: "mower completes", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", Body: map[string]float64{"price": 45.5}, WantStatus: http.StatusOK},
+				{Name: "a new mower has no ratings", As: Anonymous, Method: http.MethodGet, Path: "/api/v1/mowers/{mower}", WantStatus: http.StatusOK,
+					Check: expectRatings(0, 0, map[string]int64{"1": 0, "2": 0, "3": 0, "4": 0, "5": 0})},
+				{Name: "there is no review yet", As: Mower, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}/review", WantStatus: http.StatusNotFound},
+				{Name: "mowers cannot review", As: Mower, Method: http.MethodPost, Path: "/api/v1/bookings/{booking}/review", Body: map[string]interface{}{"rating": 5}, WantStatus: http.StatusForbidden},
+				{Name: "rating must be one to five stars", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings/{booking}/review", Body: map[string]interface{}{"rating": 6}, WantStatus: http.StatusBadRequest},
+				{Name: "customer reviews the job", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings/{booking}/review", WantStatus: http.StatusCreated,
+					Body: map[string]interface{}{"rating": 4, "comment": "Neat edges, missed a corner"}, Check: expectReview(4, "")},
+				{Name: "a booking is reviewed once", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings/{booking}/review", Body: map[string]interface{}{"rating": 1}, WantStatus: http.StatusConflict},
+				{Name: "the rating is on the booking", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK,
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						var booking domain.Booking
+						resp.DecodeData(t, &booking)
+						if booking.Rating != 4 || strings.Contains(string(resp.Data), `"comments"`) {
+							t.Fatalf("reviewed booking should have the rating but not the comments; body: %s", resp.Body)
+						}
+					}},
+				{Name: "the comment is on the thread", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}/comments", WantStatus: http.StatusOK,
+					Check: expectComments("Neat edges, missed a corner")},
+				{Name: "other mowers cannot see the booking", As: OtherMower, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusNotFound},
+				{Name: "admins can see the booking", As: Admin, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK},
+				{Name: "other mowers cannot see the review", As: OtherMower, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}/review", WantStatus: http.StatusNotFound},
+				{Name: "other mowers cannot reply", As: OtherMower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/review/reply", Body: map[string]string{"text": "Thanks"}, WantStatus: http.StatusNotFound},
+				{Name: "replies need text", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/review/reply", Body: map[string]string{"text": " "}, WantStatus: http.StatusBadRequest},
+				{Name: "mower replies", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/review/reply", WantStatus: http.StatusOK,
+					Body: map[string]string{"text": "Thanks, I'll get that corner next time"}, Check: expectReview(4, "Thanks, I'll get that corner next time")},
+				{Name: "a review is replied to once", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/review/reply", Body: map[string]string{"text": "Again"}, WantStatus: http.StatusConflict},
+				{Name: "customer reads the reply", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}/review", WantStatus: http.StatusOK,
+					Check: expectReview(4, "Thanks, I'll get that corner next time")},
+				{Name: "customer books again", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: newBooking, WantStatus: http.StatusCreated},
+				{Name: "mower accepts again", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(45.5), WantStatus: http.StatusOK},
+				{Name: "customer approves again", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/quote/approve", WantStatus: http.StatusOK},
+				{Name: "mower completes again", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", Body: map[string]float64{"price": 45.5}, WantStatus: http.StatusOK},
+				{Name: "the window closes after completion", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings/{booking}/review", Body: map[string]interface{}{"rating": 5}, WantStatus: http.StatusConflict,
+					Setup: completedAgo(ReviewWindow + time.Hour)},
+				{Name: "late reviews are still taken inside the window", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings/{booking}/review", Body: map[string]interface{}{"rating": 5}, WantStatus: http.StatusCreated,
+					Setup: completedAgo(ReviewWindow - time.Hour), Check: expectReview(5, "")},
+				{Name: "anyone can see the mower's ratings", As: Anonymous, Method: http.MethodGet, Path: "/api/v1/mowers/{mower}", WantStatus: http.StatusOK,
+					Check: expectRatings(2, 4.5, map[string]int64{"1": 0, "2": 0, "3": 0, "4": 1, "5": 1})},
+				{Name: "anyone can read the latest reviews", As: Anonymous, Method: http.MethodGet, Path: "/api/v1/mowers/{mower}/reviews?limit=1", WantStatus: http.StatusOK,
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						var reviews []domain.Review
+						resp.DecodeData(t, &reviews)
+						if len(reviews) != 1 || reviews[0].Rating != 5 || reviews[0].CustomerName != "customer" {
+							t.Fatalf("latest reviews: %s", resp.Body)
+						}
+					}},
+				{Name: "review limit is bounded", As: Anonymous, Method: http.MethodGet, Path: "/api/v1/mowers/{mower}/reviews?limit=1000", WantStatus: http.StatusBadRequest},
+				{Name: "customers have no mower profile", As: Anonymous, Method: http.MethodGet, Path: "/api/v1/mowers/" + primitive.NewObjectID().Hex(), WantStatus: http.StatusNotFound},
+			},
+		},
+		{
+			Name: "Comments",
+			Steps: []Step{
+				{Name: "customer creates booking", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: newBooking, WantStatus: http.StatusCreated},
+				{Name: "unassigned mowers cannot comment", As: Mower, Method: http.MethodPost, Path: "/api/v1/bookings/{booking}/comments", Body: map[string]string{"text": "Hello"}, WantStatus: http.StatusNotFound},
+				{Name: "mower accepts", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(45.5), WantStatus: http.StatusOK,
+					Setup: setServiceRadius(Mower, HomeLocation, 10)},
+				{Name: "the thread starts empty", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}/comments", WantStatus: http.StatusOK, Check: expectComments()},
+				{Name: "comments need text or a photo", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings/{booking}/comments", Body: map[string]string{"text": "  "}, WantStatus: http.StatusBadRequest},
+				{Name: "customer comments", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings/{booking}/comments", Body: map[string]string{"text": "Gate code is 1234"}, WantStatus: http.StatusCreated,
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						captureComment(t, env, resp)
+						expectCommentEmails(Mower, 1)(t, env, resp)
+						expectCommentEmails(Customer, 0)(t, env, resp)
+					}},
+				{Name: "other mowers cannot read the thread", As: OtherMower, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}/comments", WantStatus: http.StatusNotFound},
+				{Name: "other mowers cannot comment", As: OtherMower, Method: http.MethodPost, Path: "/api/v1/bookings/{booking}/comments", Body: map[string]string{"text": "Hello"}, WantStatus: http.StatusNotFound},
+				{Name: "mower replies", As: Mower, Method: http.MethodPost, Path: "/api/v1/bookings/{booking}/comments", Body: map[string]string{"text": "Thanks, see you Monday"}, WantStatus: http.StatusCreated,
+					Check: expectCommentEmails(Customer, 1)},
+				{Name: "mower attaches a photo", As: Mower, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}/comments", WantStatus: http.StatusOK,
+					Setup: commentPhoto("done.png", pngPhoto, http.StatusCreated), Check: expectComments("Gate code is 1234", "Thanks, see you Monday", "")},
+				{Name: "only images are attached", As: Mower, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}/comments", WantStatus: http.StatusOK,
+					Setup: commentPhoto("notes.txt", []byte("not a photo"), http.StatusBadRequest), Check: expectComments("Gate code is 1234", "Thanks, see you Monday", "")},
+				{Name: "admins can read the thread", As: Admin, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}/comments", WantStatus: http.StatusOK,
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						var comments []domain.BookingComment
+						resp.DecodeData(t, &comments)
+						if len(comments) != 3 || comments[0].UserName != env.Users[Customer].Name || comments[1].UserRole != "mower" || len(comments[2].PhotoURLs) != 1 {
+							t.Fatalf("thread: %s", resp.Body)
+						}
+					}},
+				{Name: "invalid comment ID", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/comments/nope", Body: map[string]string{"text": "Gate code is 4321"}, WantStatus: http.StatusBadRequest},
+				{Name: "customer edits their comment", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/comments/{comment}", Body: map[string]string{"text": "Gate code is 4321"}, WantStatus: http.StatusOK,
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						var comment domain.BookingComment
+						resp.DecodeData(t, &comment)
+						if comment.CommentText != "Gate code is 4321" || comment.EditedAt == nil {
+							t.Fatalf("edited comment: %s", resp.Body)
+						}
+					}},
+				{Name: "the edit is on the thread", As: Mower, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}/comments", WantStatus: http.StatusOK,
+					Check: expectComments("Gate code is 4321", "Thanks, see you Monday", "")},
+				{Name: "mowers cannot edit customers' comments", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/comments/{comment}", Body: map[string]string{"text": "Gate is open"}, WantStatus: http.StatusForbidden},
+				{Name: "mowers cannot delete customers' comments", As: Mower, Method: http.MethodDelete, Path: "/api/v1/bookings/{booking}/comments/{comment}", WantStatus: http.StatusForbidden},
+				{Name: "the edit window closes", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/comments/{comment}", Body: map[string]string{"text": "Gate code is 0000"}, WantStatus: http.StatusForbidden,
+					Setup: commentPostedAgo(CommentSettings.EditWindow + time.Minute)},
+				{Name: "the delete window closes", As: Customer, Method: http.MethodDelete, Path: "/api/v1/bookings/{booking}/comments/{comment}", WantStatus: http.StatusForbidden,
+					Setup: commentPostedAgo(CommentSettings.DeleteWindow + time.Minute)},
+				{Name: "admins can delete any time", As: Admin, Method: http.MethodDelete, Path: "/api/v1/bookings/{booking}/comments/{comment}", WantStatus: http.StatusOK},
+				{Name: "deleted comments are gone", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}/comments", WantStatus: http.StatusOK,
+					Check: expectComments("Thanks, see you Monday", "")},
+				{Name: "deleted comments cannot be edited", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/comments/{comment}", Body: map[string]string{"text": "Gate code is 4321"}, WantStatus: http.StatusNotFound},
+			},
+		},
+		{
+			Name: "Properties",
+			Steps: []Step{
+				{Name: "address needs a city and postal code", As: Customer, Method: http.MethodPost, Path: "/api/v1/properties", WantStatus: http.StatusBadRequest,
+					Body: map[string]interface{}{"address": map[string]string{"line1": "1 Lawn Street"}, "lawnArea": 600, "serviceType": "mowing"}},
+				{Name: "lawn area is required", As: Customer, Method: http.MethodPost, Path: "/api/v1/properties", WantStatus: http.StatusBadRequest,
+					Body: map[string]interface{}{"address": propertyAddress, "serviceType": "mowing"}},
+				{Name: "service type must be priced", As: Customer, Method: http.MethodPost, Path: "/api/v1/properties", WantStatus: http.StatusBadRequest,
+					Body: map[string]interface{}{"address": propertyAddress, "lawnArea": 600, "serviceType": "topiary"}},
+				{Name: "region must be priced", As: Customer, Method: http.MethodPost, Path: "/api/v1/properties", WantStatus: http.StatusBadRequest,
+					Body: map[string]interface{}{"address": propertyAddress, "lawnArea": 600, "serviceType": "mowing", "region": "moon"}},
+				{Name: "mowers have no properties", As: Mower, Method: http.MethodPost, Path: "/api/v1/properties", Body: newProperty, WantStatus: http.StatusForbidden},
+				{Name: "customer registers a property", As: Customer, Method: http.MethodPost, Path: "/api/v1/properties", Body: newProperty, WantStatus: http.StatusCreated, Check: captureProperty},
+				{Name: "weekday estimate", As: Customer, Method: http.MethodGet, Path: "/api/v1/properties/{property}/estimate?date=2030-06-03", WantStatus: http.StatusOK, Check: expectEstimate(6480, 5800, 7200)},
+				{Name: "weekend surcharge", As: Customer, Method: http.MethodGet, Path: "/api/v1/properties/{property}/estimate?date=2030-06-01", WantStatus: http.StatusOK, Check: expectEstimate(8100, 7200, 9000)},
+				{Name: "holiday surcharge", As: Customer, Method: http.MethodGet, Path: "/api/v1/properties/{property}/estimate?date=2030-12-25", WantStatus: http.StatusOK, Check: expectEstimate(9720, 8700, 10700)},
+				{Name: "estimate needs a date", As: Customer, Method: http.MethodGet, Path: "/api/v1/properties/{property}/estimate", WantStatus: http.StatusBadRequest},
+				{Name: "estimate for an unpriced service", As: Customer, Method: http.MethodGet, Path: "/api/v1/properties/{property}/estimate?date=2030-06-03&service=topiary", WantStatus: http.StatusBadRequest},
+				{Name: "steeper lawn costs more", As: Customer, Method: http.MethodPut, Path: "/api/v1/properties/{property}", WantStatus: http.StatusOK,
+					Body: propertyOn(domain.TerrainSteep)},
+				{Name: "steep weekday estimate", As: Customer, Method: http.MethodGet, Path: "/api/v1/properties/{property}/estimate?date=2030-06-03", WantStatus: http.StatusOK, Check: expectEstimate(9720, 8700, 10700)},
+				{Name: "booking an unpriced service", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", WantStatus: http.StatusBadRequest,
+					Body: map[string]string{"propertyId": "{property}", "service": "topiary", "date": "2030-06-01", "time": "09:00"}},
+				{Name: "customer books the property", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", WantStatus: http.StatusCreated,
+					Body: map[string]string{"propertyId": "{property}", "date": "2030-06-01", "time": "09:00"},
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						var booking struct {
+							Address    string `json:"address"`
+							PropertyID string `json:"propertyId"`
+							Pets       string `json:"pets"`
+						}
+						resp.DecodeData(t, &booking)
+						if booking.Address != "1 Lawn Street, Springfield, 12345" || booking.PropertyID != env.PropertyID || booking.Pets == "" {
+							t.Fatalf("booking should take the property's address and pets warning; body: %s", resp.Body)
+						}
+						if strings.Contains(string(resp.Body), "Gate code") {
+							t.Fatalf("booking should not show access notes; body: %s", resp.Body)
+						}
+					}},
+				{Name: "booking keeps the estimate", As: Customer, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK,
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						var booking struct {
+							Estimate domain.PriceEstimate `json:"estimate"`
+						}
+						resp.DecodeData(t, &booking)
+						if booking.Estimate.Amount != 12150 {
+							t.Fatalf("booking estimate = %d, want 12150; body: %s", booking.Estimate.Amount, resp.Body)
+						}
+					}},
+				{Name: "approved mowers see the pending booking", As: Mower, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}", WantStatus: http.StatusOK},
+				{Name: "unassigned mowers cannot see access notes", As: Mower, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}/property", WantStatus: http.StatusNotFound},
+				{Name: "mower accepts", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(120), WantStatus: http.StatusOK},
+				{Name: "assigned mower sees access notes", As: Mower, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}/property", WantStatus: http.StatusOK, Check: expectAccessNotes("Gate code 4321")},
+				{Name: "other mowers still cannot", As: OtherMower, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}/property", WantStatus: http.StatusNotFound},
+				{Name: "photos must be images", As: Customer, Method: http.MethodGet, Path: "/api/v1/properties/{property}", WantStatus: http.StatusOK,
+					Setup: uploadPhoto("notes.txt", []byte("not a photo"), http.StatusBadRequest)},
+				{Name: "customer adds a photo", As: Customer, Method: http.MethodGet, Path: "/api/v1/properties/{property}", WantStatus: http.StatusOK,
+					Setup: uploadPhoto("front.png", pngPhoto, http.StatusCreated), Check: expectPhotos(1)},
+				{Name: "unknown photo", As: Customer, Method: http.MethodDelete, Path: "/api/v1/properties/{property}/photos/3", WantStatus: http.StatusNotFound},
+				{Name: "customer removes the photo", As: Customer, Method: http.MethodDelete, Path: "/api/v1/properties/{property}/photos/0", WantStatus: http.StatusOK, Check: expectPhotos(0)},
+				{Name: "customer archives the property", As: Customer, Method: http.MethodDelete, Path: "/api/v1/properties/{property}", WantStatus: http.StatusOK},
+				{Name: "booked property stays visible to the mower", As: Mower, Method: http.MethodGet, Path: "/api/v1/bookings/{booking}/property", WantStatus: http.StatusOK, Check: expectAccessNotes("Gate code 4321")},
+				{Name: "archived property is gone", As: Customer, Method: http.MethodGet, Path: "/api/v1/properties/{property}", WantStatus: http.StatusNotFound},
+				{Name: "archived property is not listed", As: Customer, Method: http.MethodGet, Path: "/api/v1/properties", WantStatus: http.StatusOK,
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						if strings.Contains(string(resp.Data), env.PropertyID) {
+							t.Fatalf("archived property is still listed; body: %s", resp.Body)
+						}
+					}},
+				{Name: "archived property cannot be booked", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", WantStatus: http.StatusNotFound,
+					Body: map[string]string{"propertyId": "{property}", "date": "2030-06-01", "time": "09:00"}},
+			},
+		},
+		{
+			Name: "Payouts",
+			Steps: []Step{
+				{Name: "payouts need bank details", As: Mower, Method: http.MethodPost, Path: "/api/v1/wallet/payouts", Body: map[string]int64{"amount": 2000}, WantStatus: http.StatusBadRequest},
+				{Name: "routing number is checked", As: Mower, Method: http.MethodPut, Path: "/api/v1/wallet/payout-details", WantStatus: http.StatusBadRequest,
+					Body: map[string]string{"accountHolder": "Pat Mower", "routingNumber": "123456789", "accountNumber": "000123456789"}},
+				{Name: "mower saves bank details", As: Mower, Method: http.MethodPut, Path: "/api/v1/wallet/payout-details", WantStatus: http.StatusOK,
+					Body: map[string]string{"accountHolder": "Pat Mower", "routingNumber": "011000015", "accountNumber": "000123456789"},
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						if !strings.Contains(string(resp.Data), `"accountLast4":"6789"`) || strings.Contains(string(resp.Data), "000123456789") {
+							t.Fatalf("payout details should show only the last four digits; body: %s", resp.Body)
+						}
+					}},
+				{Name: "customers have no payout details", As: Customer, Method: http.MethodPut, Path: "/api/v1/wallet/payout-details", Body: map[string]string{}, WantStatus: http.StatusForbidden},
+				{Name: "customer creates booking", As: Customer, Method: http.MethodPost, Path: "/api/v1/bookings", Body: newBooking, WantStatus: http.StatusCreated},
+				{Name: "mower accepts", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/accept", Body: fixedQuote(45.5), WantStatus: http.StatusOK},
+				{Name: "customer approves quote", As: Customer, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/quote/approve", WantStatus: http.StatusOK},
+				{Name: "mower completes", As: Mower, Method: http.MethodPut, Path: "/api/v1/bookings/{booking}/complete", Body: map[string]float64{"price": 45.5}, WantStatus: http.StatusOK},
+				{Name: "unpaid earnings are unsettled", As: Mower, Method: http.MethodGet, Path: "/api/v1/wallet/payout-balance", WantStatus: http.StatusOK, Check: expectPayoutBalance(4550, 0, 4550, 0, 0)},
+				{Name: "customer lists invoices", As: Customer, Method: http.MethodGet, Path: "/api/v1/invoices", WantStatus: http.StatusOK, Check: captureInvoice},
+				{Name: "mowers cannot settle their own invoice", As: Mower, Method: http.MethodPut, Path: "/api/v1/invoices/{invoice}/paid", WantStatus: http.StatusForbidden},
+				{Name: "admin marks paid", As: Admin, Method: http.MethodPut, Path: "/api/v1/invoices/{invoice}/paid", WantStatus: http.StatusOK},
+				{Name: "new earnings are held", As: Mower, Method: http.MethodGet, Path: "/api/v1/wallet/payout-balance", WantStatus: http.StatusOK, Check: expectPayoutBalance(4550, 4550, 0, 0, 0)},
+				{Name: "held earnings cannot be withdrawn", As: Mower, Method: http.MethodPost, Path: "/api/v1/wallet/payouts", Body: map[string]int64{"amount": 2000}, WantStatus: http.StatusBadRequest},
+				{Name: "older balance is available", As: Mower, Method: http.MethodGet, Path: "/api/v1/wallet/payout-balance", WantStatus: http.StatusOK,
+					Setup: creditOpeningBalance(10000), Check: expectPayoutBalance(14550, 4550, 0, 0, 10000)},
+				{Name: "payouts have a minimum", As: Mower, Method: http.MethodPost, Path: "/api/v1/wallet/payouts", Body: map[string]int64{"amount": PayoutMinimumAmount - 1}, WantStatus: http.StatusBadRequest},
+				{Name: "payouts cannot exceed the available balance", As: Mower, Method: http.MethodPost, Path: "/api/v1/wallet/payouts", Body: map[string]int64{"amount": 10001}, WantStatus: http.StatusBadRequest},
+				{Name: "mower requests a payout", As: Mower, Method: http.MethodPost, Path: "/api/v1/wallet/payouts", Body: map[string]int64{"amount": 6000}, WantStatus: http.StatusCreated, Check: capturePayout},
+				{Name: "one payout at a time", As: Mower, Method: http.MethodPost, Path: "/api/v1/wallet/payouts", Body: map[string]int64{"amount": 2000}, WantStatus: http.StatusBadRequest},
+				{Name: "open payout is pending", As: Mower, Method: http.MethodGet, Path: "/api/v1/wallet/payout-balance", WantStatus: http.StatusOK, Check: expectPayoutBalance(14550, 4550, 0, 6000, 4000)},
+				{Name: "mowers cannot approve payouts", As: Mower, Method: http.MethodPost, Path: "/api/v1/admin/payouts/{payout}/approve", WantStatus: http.StatusForbidden},
+				{Name: "admin sees the review queue", As: Admin, Method: http.MethodGet, Path: "/api/v1/admin/payouts", WantStatus: http.StatusOK, Check: expectPayoutStatuses(domain.PayoutRequested)},
+				{Name: "nothing approved to pay yet", As: Admin, Method: http.MethodPost, Path: "/api/v1/admin/payout-batches", WantStatus: http.StatusOK},
+				{Name: "admin approves", As: Admin, Method: http.MethodPost, Path: "/api/v1/admin/payouts/{payout}/approve", WantStatus: http.StatusOK},
+				{Name: "approval is not repeated", As: Admin, Method: http.MethodPost, Path: "/api/v1/admin/payouts/{payout}/approve", WantStatus: http.StatusConflict},
+				{Name: "admin runs the payout batch", As: Admin, Method: http.MethodPost, Path: "/api/v1/admin/payout-batches", WantStatus: http.StatusCreated, Check: captureBatch(1, 6000)},
+				{Name: "payout is debited from the wallet", As: Mower, Method: http.MethodGet, Path: "/api/v1/wallet/statement", WantStatus: http.StatusOK, Check: expectWalletBalance(8550)},
+				{Name: "mower sees the payout paid", As: Mower, Method: http.MethodGet, Path: "/api/v1/wallet/payouts", WantStatus: http.StatusOK, Check: expectPayoutStatuses(domain.PayoutPaid)},
+				{Name: "paid payout cannot be rejected", As: Admin, Method: http.MethodPost, Path: "/api/v1/admin/payouts/{payout}/reject", Body: map[string]string{"reason": "Too late"}, WantStatus: http.StatusConflict},
+				{Name: "admin downloads the CSV export", As: Admin, Method: http.MethodGet, Path: "/api/v1/admin/payout-batches/{batch}/export", WantStatus: http.StatusOK,
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						if !strings.Contains(string(resp.Body), "011000015,000123456789,checking,60.00,USD") {
+							t.Fatalf("CSV export is missing the payout; body: %s", resp.Body)
+						}
+					}},
+				{Name: "admin downloads the NACHA export", As: Admin, Method: http.MethodGet, Path: "/api/v1/admin/payout-batches/{batch}/export?format=nacha", WantStatus: http.StatusOK, Check: expectNACHA(6000)},
+				{Name: "unknown export format", As: Admin, Method: http.MethodGet, Path: "/api/v1/admin/payout-batches/{batch}/export?format=xlsx", WantStatus: http.StatusBadRequest},
+				{Name: "mower requests another payout", As: Mower, Method: http.MethodPost, Path: "/api/v1/wallet/payouts", Body: map[string]int64{"amount": 2000}, WantStatus: http.StatusCreated, Check: capturePayout},
+				{Name: "rejection needs a reason", As: Admin, Method: http.MethodPost, Path: "/api/v1/admin/payouts/{payout}/reject", Body: map[string]string{}, WantStatus: http.StatusBadRequest},
+				{Name: "admin rejects", As: Admin, Method: http.MethodPost, Path: "/api/v1/admin/payouts/{payout}/reject", Body: map[string]string{"reason": "Bank details could not be verified"}, WantStatus: http.StatusOK},
+				{Name: "rejected payout frees the balance", As: Mower, Method: http.MethodGet, Path: "/api/v1/wallet/payout-balance", WantStatus: http.StatusOK, Check: expectPayoutBalance(8550, 4550, 0, 0, 4000)},
+				{Name: "mower sees both payouts", As: Mower, Method: http.MethodGet, Path: "/api/v1/wallet/payouts", WantStatus: http.StatusOK, Check: expectPayoutStatuses(domain.PayoutRejected, domain.PayoutPaid)},
+			},
+		},
+		{
+			Name: "Authentication",
+			Steps: []Step{
+				{Name: "register", As: Anonymous, Method: http.MethodPost, Path: "/api/v1/auth/register", WantStatus: http.StatusCreated,
+					Body: map[string]string{"name": "Dup", "email": "duplicate@example.com", "password": "secret1", "role": "customer"}},
+				{Name: "duplicate email differing in case", As: Anonymous, Method: http.MethodPost, Path: "/api/v1/auth/register", WantStatus: http.StatusConflict,
+					Body: map[string]string{"name": "Dup", "email": " DUPLICATE@example.com", "password": "secret1", "role": "customer"}},
+				{Name: "admin role cannot self-register", As: Anonymous, Method: http.MethodPost, Path: "/api/v1/auth/register", WantStatus: http.StatusBadRequest,
+					Body: map[string]string{"name": "Eve", "email": "eve@example.com", "password": "secret1", "role": "admin"}},
+				{Name: "wrong password", As: Anonymous, Method: http.MethodPost, Path: "/api/v1/auth/login", WantStatus: http.StatusUnauthorized,
+					Body: map[string]string{"email": "duplicate@example.com", "password": "wrong"}},
+				{Name: "forgot password sends email", As: Anonymous, Method: http.MethodPost, Path: "/api/v1/auth/forgot-password", WantStatus: http.StatusOK,
+					Body: map[string]string{"email": "Duplicate@Example.com"},
+					Check: func(t *testing.T, env *Env, resp *Response) {
+						messages := env.SMTP.WaitForMessages(1, 5*time.Second)
+						if len(env.SMTP.MessagesTo("duplicate@example.com")) != 1 {
+							t.Fatalf("expected one password reset email, got %d messages", len(messages))
+						}
+					}},
+				{Name: "reset with bad token", As: Anonymous, Method: http.MethodPost, Path: "/api/v1/auth/reset-password", WantStatus: http.StatusUnauthorized,
+					Body: map[string]string{"token": "bogus", "newPassword": "another-secret"}},
+			},
+		},
+	}
+}
+
+// expectBookingCount asserts that the response data is a list of n bookings.
+func expectBookingCount(n int) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var bookings []map[string]interface{}
+		resp.DecodeData(t, &bookings)
+		if len(bookings) != n {
+			t.Fatalf("got %d bookings, want %d; body: %s", len(bookings), n, resp.Body)
+		}
+	}
+}
+
+// expectStatus asserts that the response data is a booking in the given status.
+func expectStatus(status string) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var booking struct {
+			Status string `json:"status"`
+		}
+		resp.DecodeData(t, &booking)
+		if booking.Status != status {
+			t.Fatalf("booking status = %q, want %q", booking.Status, status)
+		}
+	}
+}
+
+// setHourlyRate sets the actor's hourly rate on their profile.
+func setHourlyRate(actor string, rate float64) func(t *testing.T, env *Env) {
+	return func(t *testing.T, env *Env) {
+		t.Helper()
+		err := env.Harness.Users.UpdateUser(context.Background(), env.Users[actor].ID, bson.M{"$set": bson.M{"hourlyRate": rate}})
+		if err != nil {
+			t.Fatalf("setting hourly rate: %v", err)
+		}
+	}
+}
+
+// expectQuote asserts that the response data is a booking whose quote has the
+// given status and amount in minor units, and that an approved quote set the
+// booking's price.
+func expectQuote(status string, amount int64) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var booking domain.Booking
+		resp.DecodeData(t, &booking)
+		if booking.Quote == nil || booking.Quote.Status != status || booking.Quote.Amount != amount {
+			t.Fatalf("booking quote = %+v, want %s for %d", booking.Quote, status, amount)
+		}
+		if status == domain.QuoteApproved && domain.ToMinorUnits(booking.Price, "USD") != amount {
+			t.Fatalf("booking price = %.2f, want the approved quote of %d", booking.Price, amount)
+		}
+	}
+}
+
+// expectWalletBalance asserts that the response data is a reconciled wallet
+// statement with the given balance in minor units.
+func expectWalletBalance(balance int64) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var statement struct {
+			Balance    int64 `json:"balance"`
+			Reconciled bool  `json:"reconciled"`
+		}
+		resp.DecodeData(t, &statement)
+		if statement.Balance != balance || !statement.Reconciled {
+			t.Fatalf("wallet balance = %d (reconciled %v), want %d; body: %s", statement.Balance, statement.Reconciled, balance, resp.Body)
+		}
+	}
+}
+
+// expectRevenue asserts that the response data is a revenue report whose range
+// total and all-time total are both total minor units.
+func expectRevenue(total int64) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var report struct {
+			Total   int64 `json:"total"`
+			AllTime int64 `json:"allTime"`
+		}
+		resp.DecodeData(t, &report)
+		if report.Total != total || report.AllTime != total {
+			t.Fatalf("revenue total = %d, all time = %d, want %d; body: %s", report.Total, report.AllTime, total, resp.Body)
+		}
+	}
+}
+
+// expectBillingStatus asserts that the response data is a booking in the given
+// billing status.
+func expectBillingStatus(status string) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var booking struct {
+			BillingStatus string `json:"billingStatus"`
+		}
+		resp.DecodeData(t, &booking)
+		if booking.BillingStatus != status {
+			t.Fatalf("booking billing status = %q, want %q", booking.BillingStatus, status)
+		}
+	}
+}
+
+// captureInvoice expects the response data to list exactly one invoice, for the
+// current booking, and records its ID for {invoice} paths.
+func captureInvoice(t *testing.T, env *Env, resp *Response) {
+	t.Helper()
+	var invoices []struct {
+		ID        string `json:"id"`
+		Number    string `json:"number"`
+		BookingID string `json:"bookingId"`
+		Amount    int64  `json:"amount"`
+	}
+	resp.DecodeData(t, &invoices)
+	if len(invoices) != 1 || invoices[0].BookingID != env.BookingID || invoices[0].Amount != 4550 {
+		t.Fatalf("expected one 4550 invoice for booking %s; body: %s", env.BookingID, resp.Body)
+	}
+	if want := "INV-" + strconv.Itoa(time.Now().UTC().Year()) + "-000001"; invoices[0].Number != want {
+		t.Fatalf("invoice number = %q, want %q", invoices[0].Number, want)
+	}
+	env.InvoiceID = invoices[0].ID
+}
+
+// sendReminders runs the dunning job as if after elapsed time and asserts how
+// many reminders it sent.
+func sendReminders(elapsed time.Duration, want int) func(t *testing.T, env *Env) {
+	return func(t *testing.T, env *Env) {
+		t.Helper()
+		sent, err := env.Dunning.SendDueReminders(context.Background(), time.Now().Add(elapsed))
+		if err != nil {
+			t.Fatalf("sending payment reminders: %v", err)
+		}
+		if sent != want {
+			t.Fatalf("sent %d payment reminders, want %d", sent, want)
+		}
+	}
+}
+
+// expectReminders asserts that the response data lists n successful payment reminders.
+func expectReminders(n int) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var reminders []struct {
+			Error string `json:"error"`
+		}
+		resp.DecodeData(t, &reminders)
+		if len(reminders) != n {
+			t.Fatalf("got %d payment reminders, want %d; body: %s", len(reminders), n, resp.Body)
+		}
+		for _, reminder := range reminders {
+			if reminder.Error != "" {
+				t.Fatalf("payment reminder failed: %s", reminder.Error)
+			}
+		}
+	}
+}
+
+// expectInvoiceReminders asserts that the response data is an invoice at the
+// given reminder level and overdue state.
+func expectInvoiceReminders(level int, overdue bool) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var invoice struct {
+			RemindersSent int  `json:"remindersSent"`
+			Overdue       bool `json:"overdue"`
+		}
+		resp.DecodeData(t, &invoice)
+		if invoice.RemindersSent != level || invoice.Overdue != overdue {
+			t.Fatalf("invoice reminders = %d (overdue %v), want %d (overdue %v)", invoice.RemindersSent, invoice.Overdue, level, overdue)
+		}
+	}
+}
+
+// expectCustomerOverdue asserts whether a newly created booking was flagged for
+// the customer's overdue invoices.
+func expectCustomerOverdue(overdue bool) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var booking struct {
+			CustomerOverdue bool `json:"customerOverdue"`
+		}
+		resp.DecodeData(t, &booking)
+		if booking.CustomerOverdue != overdue {
+			t.Fatalf("booking customerOverdue = %v, want %v; body: %s", booking.CustomerOverdue, overdue, resp.Body)
+		}
+	}
+}
+
+// expectPayment asserts that the response data is a booking whose card payment
+// is in the given status with the given captured and refunded amounts.
+func expectPayment(status string, captured, refunded int64) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var booking struct {
+			Payment *domain.BookingPayment `json:"payment"`
+		}
+		resp.DecodeData(t, &booking)
+		payment := booking.Payment
+		if payment == nil || payment.Status != status || payment.CapturedAmount != captured || payment.RefundedAmount != refunded {
+			t.Fatalf("booking payment = %+v, want status %q captured %d refunded %d", payment, status, captured, refunded)
+		}
+		if payment.AuthorizedAmount != AuthorizationAmount {
+			t.Fatalf("authorized amount = %d, want %d", payment.AuthorizedAmount, AuthorizationAmount)
+		}
+	}
+}
+
+// expectProviderStatus checks the state of the booking's payment at the fake
+// processor, such as whether a hold was voided.
+func expectProviderStatus(status string) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var booking struct {
+			Payment *domain.BookingPayment `json:"payment"`
+		}
+		resp.DecodeData(t, &booking)
+		if booking.Payment == nil {
+			t.Fatal("booking has no payment")
+		}
+		if got := env.Payments.Payment(booking.Payment.PaymentID); got == nil || got.Status != status {
+			t.Fatalf("payment at the processor = %+v, want status %q", got, status)
+		}
+	}
+}
+
+// deliverWebhook delivers one payment webhook for the current booking's payment
+// the given number of times, as a provider retrying delivery would.
+func deliverWebhook(eventType string, amount int64, times int) func(t *testing.T, env *Env) {
+	return func(t *testing.T, env *Env) {
+		t.Helper()
+		bookingID, err := primitive.ObjectIDFromHex(env.BookingID)
+		if err != nil {
+			t.Fatalf("invalid booking ID %q: %v", env.BookingID, err)
+		}
+		booking, err := env.Bookings.FindBookingByID(context.Background(), bookingID)
+		if err != nil || booking.Payment == nil {
+			t.Fatalf("booking %s has no payment: %v", env.BookingID, err)
+		}
+
+		body, header := env.Payments.Webhook(eventType, booking.Payment.PaymentID, env.BookingID, amount)
+		for i := 0; i < times; i++ {
+			env.DeliverWebhook(t, body, header).RequireStatus(t, http.StatusOK)
+		}
+	}
+}
+
+// creditOpeningBalance posts an opening balance adjustment to the mower's
+// wallet. Adjustments are not earnings, so they are not held.
+func creditOpeningBalance(amount int64) func(t *testing.T, env *Env) {
+	return func(t *testing.T, env *Env) {
+		t.Helper()
+		_, err := env.LedgerService.Post(context.Background(), domain.LedgerAdjustment, primitive.NilObjectID, "Opening balance",
+			coreServices.Posting{Account: domain.AccountOpeningBalances, Amount: -amount},
+			coreServices.Posting{Account: domain.MowerAccount(env.Users[Mower].ID), Amount: amount},
+		)
+		if err != nil {
+			t.Fatalf("crediting opening balance: %v", err)
+		}
+	}
+}
+
+// expectPayoutBalance asserts that the response data is a payout balance with
+// the given amounts in minor units.
+func expectPayoutBalance(balance, held, unsettled, pending, available int64) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var got domain.PayoutBalance
+		resp.DecodeData(t, &got)
+		if got.Balance != balance || got.Held != held || got.Unsettled != unsettled || got.Pending != pending || got.Available != available {
+			t.Fatalf("payout balance = %+v, want balance %d held %d unsettled %d pending %d available %d", got, balance, held, unsettled, pending, available)
+		}
+	}
+}
+
+// capturePayout records the ID of the payout request in the response data for
+// {payout} paths.
+func capturePayout(t *testing.T, env *Env, resp *Response) {
+	t.Helper()
+	var request struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	resp.DecodeData(t, &request)
+	if request.Status != domain.PayoutRequested {
+		t.Fatalf("payout status = %q, want %q", request.Status, domain.PayoutRequested)
+	}
+	env.PayoutID = request.ID
+}
+
+// captureProperty records the ID of the property in the response data.
+func captureProperty(t *testing.T, env *Env, resp *Response) {
+	t.Helper()
+	var property struct {
+		ID string `json:"id"`
+	}
+	resp.DecodeData(t, &property)
+	env.PropertyID = property.ID
+}
+
+// uploadPhoto uploads a photo of the captured property as the customer and
+// asserts the response status.
+func uploadPhoto(filename string, content []byte, want int) func(t *testing.T, env *Env) {
+	return func(t *testing.T, env *Env) {
+		t.Helper()
+		resp := env.Upload(t, "/api/v1/properties/"+env.PropertyID+"/photos", env.Users[Customer].Token, "photo", filename, content)
+		if resp.StatusCode != want {
+			t.Fatalf("uploading %s = %d, want %d; body: %s", filename, resp.StatusCode, want, resp.Body)
+		}
+	}
+}
+
+// expectPhotos asserts the number of photos of the property in the response data.
+func expectPhotos(n int) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var property domain.Property
+		resp.DecodeData(t, &property)
+		if len(property.Photos) != n {
+			t.Fatalf("property has %d photos, want %d; body: %s", len(property.Photos), n, resp.Body)
+		}
+	}
+}
+
+// expectAccessNotes asserts that the property in the response data has access
+// notes starting with prefix.
+func expectAccessNotes(prefix string) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var property domain.Property
+		resp.DecodeData(t, &property)
+		if !strings.HasPrefix(property.AccessNotes, prefix) {
+			t.Fatalf("access notes = %q, want them to start with %q", property.AccessNotes, prefix)
+		}
+	}
+}
+
+// northOfHome and southOfHome are about 7 km either side of HomeLocation.
+var (
+	northOfHome = infrastructureServices.Location{Lat: HomeLocation.Lat + 0.06, Lng: HomeLocation.Lng}
+	southOfHome = infrastructureServices.Location{Lat: HomeLocation.Lat - 0.06, Lng: HomeLocation.Lng}
+)
+
+// acrossTown is about 15 km north of HomeLocation.
+var acrossTown = infrastructureServices.Location{Lat: HomeLocation.Lat + 0.135, Lng: HomeLocation.Lng}
+
+// upstate is about 220 km north of HomeLocation, outside HomeArea.
+var upstate = infrastructureServices.Location{Lat: 42.65, Lng: -73.75}
+
+// upstateBooking is newBooking at an address located upstate.
+var upstateBooking = map[string]string{"date": "2030-06-01", "time": "09:00", "address": "5 Farm Lane"}
+
+// upstateArea is the body that creates a service area around upstate with a
+// 10% price premium and 20% commission.
+var upstateArea = map[string]interface{}{
+	"name": "Upstate",
+	"boundary": map[string]interface{}{"type": "Polygon", "coordinates": [][][]float64{{
+		{-74, 42.5}, {-73.5, 42.5}, {-73.5, 42.8}, {-74, 42.8}, {-74, 42.5},
+	}}},
+	"priceMultiplier": 1.1,
+	"commissionBps":   2000,
+}
+
+// captureServiceArea records the ID of the service area in the response data.
+func captureServiceArea(t *testing.T, env *Env, resp *Response) {
+	t.Helper()
+	var area struct {
+		ID string `json:"id"`
+	}
+	resp.DecodeData(t, &area)
+	env.ServiceAreaID = area.ID
+}
+
+// approveMower approves the actor for the home area and the captured service
+// area through the admin API.
+func approveMower(actor string) func(t *testing.T, env *Env) {
+	return func(t *testing.T, env *Env) {
+		t.Helper()
+		body := map[string][]string{"areaIds": {env.HomeAreaID.Hex(), env.ServiceAreaID}}
+		resp := env.Do(t, http.MethodPut, "/api/v1/admin/mowers/"+env.Users[actor].ID.Hex()+"/service-areas", env.Users[Admin].Token, body)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("approving mower for service areas = %d; body: %s", resp.StatusCode, resp.Body)
+		}
+	}
+}
+
+// expectServiceAreas asserts that the response data lists service areas with
+// exactly the given names, in order.
+func expectServiceAreas(names ...string) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var areas []domain.ServiceArea
+		resp.DecodeData(t, &areas)
+		got := make([]string, len(areas))
+		for i, area := range areas {
+			got[i] = area.Name
+		}
+		if strings.Join(got, "|") != strings.Join(names, "|") {
+			t.Fatalf("service areas %q, want %q", got, names)
+		}
+	}
+}
+
+// locate makes the harness geocoder resolve address to location.
+func locate(address string, location infrastructureServices.Location) func(t *testing.T, env *Env) {
+	return func(t *testing.T, env *Env) {
+		env.Geocoder.Set(address, location)
+	}
+}
+
+// setServiceRadius sets the actor's base location and service radius through
+// the API.
+func setServiceRadius(actor string, base infrastructureServices.Location, radiusKm float64) func(t *testing.T, env *Env) {
+	return func(t *testing.T, env *Env) {
+		t.Helper()
+		body := map[string]float64{"lat": base.Lat, "lng": base.Lng, "radiusKm": radiusKm}
+		resp := env.Do(t, http.MethodPut, "/api/v1/mower/service-radius", env.Users[actor].Token, body)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("setting service radius = %d; body: %s", resp.StatusCode, resp.Body)
+		}
+	}
+}
+
+// expectPendingAddresses asserts that the response data lists pending bookings
+// at exactly the given addresses, in order, each with its distance.
+func expectPendingAddresses(addresses ...string) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var bookings []domain.Booking
+		resp.DecodeData(t, &bookings)
+		got := make([]string, len(bookings))
+		for i, booking := range bookings {
+			got[i] = booking.Address
+			if booking.Location == nil || (i > 0 && booking.DistanceKm < bookings[i-1].DistanceKm) {
+				t.Fatalf("pending bookings should be located and nearest first; body: %s", resp.Body)
+			}
+		}
+		if strings.Join(got, "|") != strings.Join(addresses, "|") {
+			t.Fatalf("pending bookings at %q, want %q", got, addresses)
+		}
+	}
+}
+
+// expectRoute asserts that the response data is a route visiting exactly the
+// given addresses, in order, on time, and returning to base after the last
+// stop.
+func expectRoute(addresses ...string) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var route domain.Route
+		resp.DecodeData(t, &route)
+		got := make([]string, len(route.Stops))
+		for i, stop := range route.Stops {
+			got[i] = stop.Address
+			if stop.ArriveAt > stop.WindowEnd || stop.StartAt < stop.WindowStart || stop.TravelMinutes <= 0 {
+				t.Fatalf("stop %d is not on time; body: %s", i, resp.Body)
+			}
+		}
+		if strings.Join(got, "|") != strings.Join(addresses, "|") {
+			t.Fatalf("route visits %q, want %q; body: %s", got, addresses, resp.Body)
+		}
+		if route.Provider != "haversine" || route.LateStops != 0 || route.Base == nil {
+			t.Fatalf("unexpected route; body: %s", resp.Body)
+		}
+		if len(route.Stops) > 0 && (route.TotalDistanceKm <= 0 || route.ReturnAt <= route.Stops[len(route.Stops)-1].FinishAt) {
+			t.Fatalf("route should return to base after the last stop; body: %s", resp.Body)
+		}
+	}
+}
+
+// completedAgo moves the current booking's completion back by age.
+func completedAgo(age time.Duration) func(t *testing.T, env *Env) {
+	return func(t *testing.T, env *Env) {
+		t.Helper()
+		bookingID, err := primitive.ObjectIDFromHex(env.BookingID)
+		if err != nil {
+			t.Fatalf("invalid booking ID %q: %v", env.BookingID, err)
+		}
+		err = env.Bookings.UpdateBooking(context.Background(), bookingID, bson.M{"$set": bson.M{"completedTime": time.Now().Add(-age)}})
+		if err != nil {
+			t.Fatalf("backdating completion: %v", err)
+		}
+	}
+}
+
+// expectReview asserts that the response data is a review of the current
+// booking with the given rating and mower reply, if any.
+func expectReview(rating int, reply string) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var review domain.Review
+		resp.DecodeData(t, &review)
+		if review.BookingID.Hex() != env.BookingID || review.MowerID != env.Users[Mower].ID || review.Rating != rating {
+			t.Fatalf("unexpected review; body: %s", resp.Body)
+		}
+		got := ""
+		if review.Reply != nil {
+			got = review.Reply.Text
+		}
+		if got != reply {
+			t.Fatalf("review reply = %q, want %q", got, reply)
+		}
+	}
+}
+
+// expectRatings asserts that the response data is a mower profile with the
+// given rating count, average and distribution.
+func expectRatings(count int64, average float64, distribution map[string]int64) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var profile domain.MowerProfile
+		resp.DecodeData(t, &profile)
+		if profile.ID != env.Users[Mower].ID || profile.Ratings.Count != count || profile.Ratings.Average != average {
+			t.Fatalf("mower ratings = %+v, want %d averaging %.2f; body: %s", profile.Ratings, count, average, resp.Body)
+		}
+		if fmt.Sprint(profile.Ratings.Distribution) != fmt.Sprint(distribution) {
+			t.Fatalf("rating distribution = %v, want %v", profile.Ratings.Distribution, distribution)
+		}
+	}
+}
+
+// captureComment records the ID of the comment in the response data.
+func captureComment(t *testing.T, env *Env, resp *Response) {
+	t.Helper()
+	var comment domain.BookingComment
+	resp.DecodeData(t, &comment)
+	if comment.ID.IsZero() {
+		t.Fatalf("comment has no ID; body: %s", resp.Body)
+	}
+	env.CommentID = comment.ID.Hex()
+}
+
+// commentPhoto posts a photo-only comment on the current booking as the mower
+// and asserts the response status.
+func commentPhoto(filename string, content []byte, want int) func(t *testing.T, env *Env) {
+	return func(t *testing.T, env *Env) {
+		t.Helper()
+		resp := env.Upload(t, "/api/v1/bookings/"+env.BookingID+"/comments", env.Users[Mower].Token, "photo", filename, content)
+		if resp.StatusCode != want {
+			t.Fatalf("commenting %s = %d, want %d; body: %s", filename, resp.StatusCode, want, resp.Body)
+		}
+	}
+}
+
+// commentPostedAgo moves the captured comment's timestamp back by age.
+func commentPostedAgo(age time.Duration) func(t *testing.T, env *Env) {
+	return func(t *testing.T, env *Env) {
+		t.Helper()
+		bookingID, err := primitive.ObjectIDFromHex(env.BookingID)
+		if err != nil {
+			t.Fatalf("invalid booking ID %q: %v", env.BookingID, err)
+		}
+		commentID, err := primitive.ObjectIDFromHex(env.CommentID)
+		if err != nil {
+			t.Fatalf("invalid comment ID %q: %v", env.CommentID, err)
+		}
+		err = env.Bookings.UpdateBookingComment(context.Background(), bookingID, commentID, bson.M{"timestamp": time.Now().Add(-age)})
+		if err != nil {
+			t.Fatalf("backdating comment: %v", err)
+		}
+	}
+}
+
+// expectComments asserts that the response data is a comment thread with the
+// given texts, oldest first.
+func expectComments(texts ...string) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var comments []domain.BookingComment
+		resp.DecodeData(t, &comments)
+		if len(comments) != len(texts) {
+			t.Fatalf("thread has %d comments, want %d; body: %s", len(comments), len(texts), resp.Body)
+		}
+		for i, comment := range comments {
+			if comment.CommentText != texts[i] {
+				t.Fatalf("comment %d = %q, want %q", i, comment.CommentText, texts[i])
+			}
+		}
+	}
+}
+
+// expectCommentEmails asserts how many comment notifications the actor has
+// been sent.
+func expectCommentEmails(actor string, n int) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		got := 0
+		for _, message := range env.SMTP.MessagesTo(env.Users[actor].Email) {
+			if strings.HasPrefix(message.Subject, "New message about your booking") {
+				got++
+			}
+		}
+		if got != n {
+			t.Fatalf("%s was sent %d comment emails, want %d", actor, got, n)
+		}
+	}
+}
+
+// expectEstimate asserts the most likely price and range of the price estimate
+// in the response data.
+func expectEstimate(amount, low, high int64) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var estimate domain.PriceEstimate
+		resp.DecodeData(t, &estimate)
+		if estimate.Amount != amount || estimate.Low != low || estimate.High != high {
+			t.Fatalf("estimate = %d (%d-%d), want %d (%d-%d); body: %s",
+				estimate.Amount, estimate.Low, estimate.High, amount, low, high, resp.Body)
+		}
+	}
+}
+
+// expectPayoutStatuses asserts that the response data lists payout requests in
+// exactly the given statuses, in order.
+func expectPayoutStatuses(statuses ...string) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var requests []struct {
+			Status string `json:"status"`
+		}
+		resp.DecodeData(t, &requests)
+		got := make([]string, len(requests))
+		for i, request := range requests {
+			got[i] = request.Status
+		}
+		if strings.Join(got, ",") != strings.Join(statuses, ",") {
+			t.Fatalf("payout statuses = %v, want %v", got, statuses)
+		}
+	}
+}
+
+// captureBatch asserts that the response data is a payout batch of count
+// payouts totalling total, and records its ID for {batch} paths.
+func captureBatch(count int, total int64) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		var batch domain.PayoutBatch
+		resp.DecodeData(t, &batch)
+		if batch.Count != count || batch.Total != total {
+			t.Fatalf("payout batch has %d payouts totalling %d, want %d totalling %d", batch.Count, batch.Total, count, total)
+		}
+		env.BatchID = batch.ID.Hex()
+	}
+}
+
+// expectNACHA asserts that the response body is a well-formed NACHA file with
+// one credit of amount minor units.
+func expectNACHA(amount int64) func(t *testing.T, env *Env, resp *Response) {
+	return func(t *testing.T, env *Env, resp *Response) {
+		t.Helper()
+		lines := strings.Split(strings.TrimSuffix(string(resp.Body), "\n"), "\n")
+		if len(lines)%10 != 0 {
+			t.Fatalf("NACHA file has %d records, want a multiple of 10", len(lines))
+		}
+		var entries int
+		for _, line := range lines {
+			if len(line) != 94 {
+				t.Fatalf("NACHA record is %d characters, want 94: %q", len(line), line)
+			}
+			if line[0] == '6' {
+				entries++
+				if line[1:3] != "22" || line[3:12] != "011000015" || line[29:39] != fmt.Sprintf("%010d", amount) {
+					t.Fatalf("unexpected NACHA entry: %q", line)
+				}
+			}
+		}
+		if entries != 1 {
+			t.Fatalf("NACHA file has %d entries, want 1", entries)
+		}
+	}
+}
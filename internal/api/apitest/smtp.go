@@ -0,0 +1,296 @@
+package apitest
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tlsSniffTimeout is how long the fake server waits for a TLS ClientHello before
+// treating the connection as plain SMTP and sending its greeting.
+const tlsSniffTimeout = 100 * time.Millisecond
+
+// Message is an email captured by the fake SMTP server.
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Data    string
+}
+
+// SMTPServer is an in-process SMTP server that accepts any credentials and records
+// every message it receives. It speaks both implicit TLS (used by
+// EmailService.SendEmail) and plain SMTP (used by SendEmailWithAttachment) on the
+// same port by sniffing the first byte the client sends.
+type SMTPServer struct {
+	listener  net.Listener
+	tlsConfig *tls.Config
+
+	mu       sync.Mutex
+	messages []Message
+	notify   chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewSMTPServer starts a fake SMTP server on a random localhost port. It is shut
+// down when the test finishes.
+func NewSMTPServer(t testing.TB) *SMTPServer {
+	t.Helper()
+
+	cert, err := selfSignedCertificate()
+	if err != nil {
+		t.Fatalf("fake smtp: generating certificate: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("fake smtp: listen: %v", err)
+	}
+
+	s := &SMTPServer{
+		listener:  listener,
+		tlsConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+		notify:    make(chan struct{}, 1),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Host returns the host the server listens on.
+func (s *SMTPServer) Host() string {
+	return s.listener.Addr().(*net.TCPAddr).IP.String()
+}
+
+// Port returns the port the server listens on.
+func (s *SMTPServer) Port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+// Messages returns a copy of every message received so far.
+func (s *SMTPServer) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// MessagesTo returns the messages addressed to the given recipient.
+func (s *SMTPServer) MessagesTo(recipient string) []Message {
+	var matched []Message
+	for _, m := range s.Messages() {
+		for _, to := range m.To {
+			if strings.EqualFold(to, recipient) {
+				matched = append(matched, m)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// WaitForMessages blocks until at least n messages have been received or the
+// timeout elapses, and returns the messages received.
+func (s *SMTPServer) WaitForMessages(n int, timeout time.Duration) []Message {
+	deadline := time.After(timeout)
+	for {
+		if messages := s.Messages(); len(messages) >= n {
+			return messages
+		}
+		select {
+		case <-s.notify:
+		case <-deadline:
+			return s.Messages()
+		}
+	}
+}
+
+// Close stops the server and waits for open sessions to finish.
+func (s *SMTPServer) Close() {
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *SMTPServer) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			s.handle(conn)
+		}()
+	}
+}
+
+// sniffedConn replays bytes already buffered while sniffing for TLS.
+type sniffedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *sniffedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+func (s *SMTPServer) handle(conn net.Conn) {
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	reader := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(tlsSniffTimeout))
+	first, err := reader.Peek(1)
+	conn.SetReadDeadline(time.Now().Add(30 * time.Second))
+
+	var session net.Conn = &sniffedConn{Conn: conn, reader: reader}
+	if err == nil && first[0] == 0x16 { // TLS handshake record
+		tlsConn := tls.Server(session, s.tlsConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			return
+		}
+		session = tlsConn
+	} else if err != nil {
+		var netErr net.Error
+		if !errors.As(err, &netErr) || !netErr.Timeout() {
+			return
+		}
+	}
+
+	s.converse(session)
+}
+
+// converse runs a minimal SMTP dialogue, enough for net/smtp clients.
+func (s *SMTPServer) converse(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	reply := func(lines ...string) {
+		fmt.Fprint(conn, strings.Join(lines, "\r\n")+"\r\n")
+	}
+
+	var current Message
+	reply("220 localhost fake ESMTP ready")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch verb {
+		case "EHLO", "HELO":
+			reply("250-localhost", "250-AUTH PLAIN LOGIN", "250 8BITMIME")
+		case "AUTH":
+			if strings.HasPrefix(strings.ToUpper(line), "AUTH LOGIN") {
+				reply("334 VXNlcm5hbWU6")
+				reader.ReadString('\n')
+				reply("334 UGFzc3dvcmQ6")
+				reader.ReadString('\n')
+			}
+			reply("235 2.7.0 Authentication successful")
+		case "MAIL":
+			current = Message{From: extractAddress(line)}
+			reply("250 OK")
+		case "RCPT":
+			current.To = append(current.To, extractAddress(line))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			data, err := readData(reader)
+			if err != nil {
+				return
+			}
+			current.Data = data
+			if parsed, err := mail.ReadMessage(strings.NewReader(data)); err == nil {
+				current.Subject = parsed.Header.Get("Subject")
+			}
+			s.record(current)
+			current = Message{}
+			reply("250 OK: queued")
+		case "RSET":
+			current = Message{}
+			reply("250 OK")
+		case "NOOP":
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func (s *SMTPServer) record(m Message) {
+	s.mu.Lock()
+	s.messages = append(s.messages, m)
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// readData reads a DATA payload up to the terminating "." line, undoing dot-stuffing.
+func readData(reader *bufio.Reader) (string, error) {
+	var b strings.Builder
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		if line == ".\r\n" || line == ".\n" {
+			return b.String(), nil
+		}
+		b.WriteString(strings.TrimPrefix(line, "."))
+	}
+}
+
+// extractAddress returns the address between angle brackets in MAIL/RCPT commands.
+func extractAddress(line string) string {
+	start := strings.Index(line, "<")
+	end := strings.LastIndex(line, ">")
+	if start == -1 || end <= start {
+		return ""
+	}
+	return line[start+1 : end]
+}
+
+func selfSignedCertificate() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:     []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
@@ -0,0 +1,54 @@
+package apitest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Upload is a file received by FakeUploadService.
+type Upload struct {
+	Filename string
+	Content  []byte
+	URL      string
+}
+
+// FakeUploadService implements services.UploadService in memory and records every upload.
+type FakeUploadService struct {
+	// Err, when set, is returned by every UploadFile call.
+	Err error
+
+	mu      sync.Mutex
+	uploads []Upload
+}
+
+// UploadFile stores the file in memory and returns a deterministic fake URL.
+func (s *FakeUploadService) UploadFile(ctx context.Context, file io.Reader, filename string) (string, error) {
+	if s.Err != nil {
+		return "", s.Err
+	}
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read upload: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	url := fmt.Sprintf("https://uploads.test/%d/%s", len(s.uploads)+1, filename)
+	s.uploads = append(s.uploads, Upload{Filename: filename, Content: content, URL: url})
+	return url, nil
+}
+
+// Ping reports Err, so tests can simulate a storage outage.
+func (s *FakeUploadService) Ping(ctx context.Context) error {
+	return s.Err
+}
+
+// Uploads returns a copy of every upload received so far.
+func (s *FakeUploadService) Uploads() []Upload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Upload(nil), s.uploads...)
+}
@@ -0,0 +1,457 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	httpresponse "lawnconnect-api/internal/api/http"
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/core/services"
+	"lawnconnect-api/internal/infrastructure/logging"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AdminHandler handles HTTP requests for platform administration.
+type AdminHandler struct {
+	CommissionService  services.CommissionService
+	LedgerService      services.LedgerService
+	PayoutService      services.PayoutService
+	ServiceAreaService services.ServiceAreaService
+}
+
+// NewAdminHandler creates a new AdminHandler.
+func NewAdminHandler(commissionSrv services.CommissionService, ledgerSrv services.LedgerService, payoutSrv services.PayoutService, serviceAreaSrv services.ServiceAreaService) *AdminHandler {
+	return &AdminHandler{CommissionService: commissionSrv, LedgerService: ledgerSrv, PayoutService: payoutSrv, ServiceAreaService: serviceAreaSrv}
+}
+
+// ListCommissionRules returns every commission rule, newest first.
+func (h *AdminHandler) ListCommissionRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.CommissionService.ListRules(r.Context())
+	if err != nil {
+		logging.FromContext(r.Context()).Error("listing commission rules failed", "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to retrieve commission rules")
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusOK, "Commission rules retrieved successfully", rules)
+}
+
+// CreateCommissionRule adds a commission rule. Rules with validFrom or validUntil
+// are promotions and take precedence over standing rules while they run.
+func (h *AdminHandler) CreateCommissionRule(w http.ResponseWriter, r *http.Request) {
+	var reqBody struct {
+		Name          string     `json:"name"`
+		PercentageBps int64      `json:"percentageBps"`
+		FlatFee       int64      `json:"flatFee"`
+		MowerID       string     `json:"mowerId"`
+		ValidFrom     *time.Time `json:"validFrom"`
+		ValidUntil    *time.Time `json:"validUntil"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	rule := &domain.CommissionRule{
+		Name:          reqBody.Name,
+		PercentageBps: reqBody.PercentageBps,
+		FlatFee:       reqBody.FlatFee,
+		ValidFrom:     reqBody.ValidFrom,
+		ValidUntil:    reqBody.ValidUntil,
+		CreatedBy:     r.Context().Value(UserContextKey).(primitive.ObjectID),
+	}
+	if reqBody.MowerID != "" {
+		mowerID, err := primitive.ObjectIDFromHex(reqBody.MowerID)
+		if err != nil {
+			httpresponse.JSONError(w, http.StatusBadRequest, "Invalid mower ID")
+			return
+		}
+		rule.MowerID = mowerID
+	}
+
+	created, err := h.CommissionService.CreateRule(r.Context(), rule)
+	if err != nil {
+		if _, ok := err.(apperror.CustomError); ok {
+			httpresponse.JSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("creating commission rule failed", "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to create commission rule")
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusCreated, "Commission rule created successfully", created)
+}
+
+// DeactivateCommissionRule stops a commission rule from applying to new bookings.
+func (h *AdminHandler) DeactivateCommissionRule(w http.ResponseWriter, r *http.Request) {
+	ruleID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "ruleID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid rule ID")
+		return
+	}
+
+	if err := h.CommissionService.DeactivateRule(r.Context(), ruleID); err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			httpresponse.JSONError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("deactivating commission rule failed", "rule_id", ruleID.Hex(), "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to deactivate commission rule")
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusOK, "Commission rule deactivated successfully", nil)
+}
+
+// Revenue aggregates platform commission by day, week or month. from and to are
+// YYYY-MM-DD dates in UTC; to is exclusive. The default range is the last 30 days.
+func (h *AdminHandler) Revenue(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	period := query.Get("period")
+	if period == "" {
+		period = domain.PeriodDay
+	}
+
+	to := domain.TruncateToPeriod(time.Now(), domain.PeriodDay).AddDate(0, 0, 1)
+	if raw := query.Get("to"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			httpresponse.JSONError(w, http.StatusBadRequest, "to must be a YYYY-MM-DD date")
+			return
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -30)
+	if raw := query.Get("from"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			httpresponse.JSONError(w, http.StatusBadRequest, "from must be a YYYY-MM-DD date")
+			return
+		}
+		from = parsed
+	}
+
+	report, err := h.LedgerService.Revenue(r.Context(), period, from, to)
+	if err != nil {
+		if _, ok := err.(apperror.CustomError); ok {
+			httpresponse.JSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("building revenue report failed", "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to build revenue report")
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusOK, "Revenue report retrieved successfully", report)
+}
+
+// ListPayouts returns payout requests by status, oldest first. The default
+// status is requested, the review queue.
+func (h *AdminHandler) ListPayouts(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = domain.PayoutRequested
+	}
+
+	requests, err := h.PayoutService.ListPayouts(r.Context(), status)
+	if err != nil {
+		if _, ok := err.(apperror.CustomError); ok {
+			httpresponse.JSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("listing payout requests failed", "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to retrieve payout requests")
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusOK, "Payout requests retrieved successfully", requests)
+}
+
+// ApprovePayout queues a payout request for the next batch.
+func (h *AdminHandler) ApprovePayout(w http.ResponseWriter, r *http.Request) {
+	requestID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "payoutID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid payout ID")
+		return
+	}
+	adminID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	request, err := h.PayoutService.ApprovePayout(r.Context(), requestID, adminID)
+	h.respondPayoutReview(w, r, request, err, "approving", "Payout request approved successfully")
+}
+
+// RejectPayout closes a payout request with a reason the mower can see.
+func (h *AdminHandler) RejectPayout(w http.ResponseWriter, r *http.Request) {
+	requestID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "payoutID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid payout ID")
+		return
+	}
+	var reqBody struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if strings.TrimSpace(reqBody.Reason) == "" {
+		httpresponse.JSONError(w, http.StatusBadRequest, "A reason is required to reject a payout")
+		return
+	}
+	adminID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	request, err := h.PayoutService.RejectPayout(r.Context(), requestID, adminID, reqBody.Reason)
+	h.respondPayoutReview(w, r, request, err, "rejecting", "Payout request rejected successfully")
+}
+
+func (h *AdminHandler) respondPayoutReview(w http.ResponseWriter, r *http.Request, request *domain.PayoutRequest, err error, action, message string) {
+	if err != nil {
+		switch err.(type) {
+		case apperror.NotFound:
+			httpresponse.JSONError(w, http.StatusNotFound, err.Error())
+		case apperror.CustomError:
+			httpresponse.JSONError(w, http.StatusConflict, err.Error())
+		default:
+			logging.FromContext(r.Context()).Error(action+" payout request failed", "payout_id", chi.URLParam(r, "payoutID"), "error", err)
+			httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to review payout request")
+		}
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusOK, message, request)
+}
+
+// ListPayoutBatches returns every payout batch, newest first.
+func (h *AdminHandler) ListPayoutBatches(w http.ResponseWriter, r *http.Request) {
+	batches, err := h.PayoutService.ListBatches(r.Context())
+	if err != nil {
+		logging.FromContext(r.Context()).Error("listing payout batches failed", "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to retrieve payout batches")
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusOK, "Payout batches retrieved successfully", batches)
+}
+
+// RunPayoutBatch pays every approved payout request now instead of waiting for
+// the scheduled job.
+func (h *AdminHandler) RunPayoutBatch(w http.ResponseWriter, r *http.Request) {
+	adminID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	batch, err := h.PayoutService.RunBatch(r.Context(), adminID, time.Now())
+	if err != nil {
+		logging.FromContext(r.Context()).Error("running payout batch failed", "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to run payout batch")
+		return
+	}
+	if batch == nil {
+		httpresponse.JSONSuccess(w, http.StatusOK, "No approved payout requests to pay", nil)
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusCreated, "Payout batch created successfully", batch)
+}
+
+// ExportPayoutBatch downloads a batch's bank transfer file. format is csv (the
+// default) or nacha.
+func (h *AdminHandler) ExportPayoutBatch(w http.ResponseWriter, r *http.Request) {
+	batchID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "batchID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid batch ID")
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = services.PayoutExportCSV
+	}
+
+	batch, file, err := h.PayoutService.ExportBatch(r.Context(), batchID, format)
+	if err != nil {
+		switch err.(type) {
+		case apperror.NotFound:
+			httpresponse.JSONError(w, http.StatusNotFound, err.Error())
+		case apperror.CustomError:
+			httpresponse.JSONError(w, http.StatusBadRequest, err.Error())
+		default:
+			logging.FromContext(r.Context()).Error("exporting payout batch failed", "batch_id", batchID.Hex(), "format", format, "error", err)
+			httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to export payout batch")
+		}
+		return
+	}
+
+	contentType, extension := "text/csv; charset=utf-8", "csv"
+	if format == services.PayoutExportNACHA {
+		contentType, extension = "text/plain; charset=us-ascii", "ach"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="payouts-`+batch.ID.Hex()+"."+extension+`"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(file)
+}
+
+// serviceAreaRequest is the body of the create and update service area endpoints.
+type serviceAreaRequest struct {
+	Name     string            `json:"name"`
+	Boundary domain.GeoPolygon `json:"boundary"` // GeoJSON Polygon, [longitude, latitude] positions
+	// PriceMultiplier scales price estimates in the area; omitted means 1.
+	PriceMultiplier float64 `json:"priceMultiplier"`
+	// CommissionBps replaces the default commission and standing rules for
+	// everyone in the area; omitted leaves them in force.
+	CommissionBps *int64 `json:"commissionBps"`
+}
+
+func (req serviceAreaRequest) input() services.ServiceAreaInput {
+	return services.ServiceAreaInput{
+		Name:            req.Name,
+		Boundary:        req.Boundary,
+		PriceMultiplier: req.PriceMultiplier,
+		CommissionBps:   req.CommissionBps,
+	}
+}
+
+// ListServiceAreas returns every service area, oldest first.
+func (h *AdminHandler) ListServiceAreas(w http.ResponseWriter, r *http.Request) {
+	areas, err := h.ServiceAreaService.ListAreas(r.Context())
+	if err != nil {
+		logging.FromContext(r.Context()).Error("listing service areas failed", "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to retrieve service areas")
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusOK, "Service areas retrieved successfully", areas)
+}
+
+// CreateServiceArea adds a service area. Waitlisted bookings inside it move to
+// the pending pool.
+func (h *AdminHandler) CreateServiceArea(w http.ResponseWriter, r *http.Request) {
+	var reqBody serviceAreaRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	adminID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	area, err := h.ServiceAreaService.CreateArea(r.Context(), adminID, reqBody.input())
+	if err != nil {
+		if _, ok := err.(apperror.CustomError); ok {
+			httpresponse.JSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("creating service area failed", "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to create service area")
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusCreated, "Service area created successfully", area)
+}
+
+// UpdateServiceArea replaces a service area's name, boundary and rates.
+func (h *AdminHandler) UpdateServiceArea(w http.ResponseWriter, r *http.Request) {
+	areaID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "areaID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid service area ID")
+		return
+	}
+	var reqBody serviceAreaRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	area, err := h.ServiceAreaService.UpdateArea(r.Context(), areaID, reqBody.input())
+	if err != nil {
+		switch err.(type) {
+		case apperror.NotFound:
+			httpresponse.JSONError(w, http.StatusNotFound, err.Error())
+		case apperror.CustomError:
+			httpresponse.JSONError(w, http.StatusBadRequest, err.Error())
+		default:
+			logging.FromContext(r.Context()).Error("updating service area failed", "service_area_id", areaID.Hex(), "error", err)
+			httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to update service area")
+		}
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusOK, "Service area updated successfully", area)
+}
+
+// DeactivateServiceArea stops a service area from taking new bookings.
+func (h *AdminHandler) DeactivateServiceArea(w http.ResponseWriter, r *http.Request) {
+	areaID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "areaID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid service area ID")
+		return
+	}
+
+	if err := h.ServiceAreaService.DeactivateArea(r.Context(), areaID); err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			httpresponse.JSONError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("deactivating service area failed", "service_area_id", areaID.Hex(), "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to deactivate service area")
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusOK, "Service area deactivated successfully", nil)
+}
+
+// SetMowerServiceAreas replaces the service areas a mower is approved to work
+// in. An empty list withdraws every approval.
+func (h *AdminHandler) SetMowerServiceAreas(w http.ResponseWriter, r *http.Request) {
+	mowerID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "mowerID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid mower ID")
+		return
+	}
+	var reqBody struct {
+		AreaIDs []string `json:"areaIds"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	areaIDs := make([]primitive.ObjectID, 0, len(reqBody.AreaIDs))
+	for _, raw := range reqBody.AreaIDs {
+		areaID, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			httpresponse.JSONError(w, http.StatusBadRequest, "Invalid service area ID")
+			return
+		}
+		areaIDs = append(areaIDs, areaID)
+	}
+
+	areas, err := h.ServiceAreaService.SetMowerAreas(r.Context(), mowerID, areaIDs)
+	if err != nil {
+		switch err.(type) {
+		case apperror.NotFound:
+			httpresponse.JSONError(w, http.StatusNotFound, err.Error())
+		case apperror.CustomError:
+			httpresponse.JSONError(w, http.StatusConflict, err.Error())
+		default:
+			logging.FromContext(r.Context()).Error("approving mower for service areas failed", "mower_id", mowerID.Hex(), "error", err)
+			httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to update the mower's service areas")
+		}
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusOK, "Mower's service areas updated successfully", areas)
+}
+
+// Routes returns the admin routes, to be mounted at /admin behind AuthMiddleware.
+func (h *AdminHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Use(RoleMiddleware("admin", "super_admin"))
+
+	r.Get("/commission-rules", h.ListCommissionRules)                  // GET /api/v1/admin/commission-rules
+	r.Post("/commission-rules", h.CreateCommissionRule)                // POST /api/v1/admin/commission-rules
+	r.Delete("/commission-rules/{ruleID}", h.DeactivateCommissionRule) // DELETE /api/v1/admin/commission-rules/{ruleID}
+	r.Get("/revenue", h.Revenue)                                       // GET /api/v1/admin/revenue
+	r.Get("/payouts", h.ListPayouts)                                   // GET /api/v1/admin/payouts
+	r.Post("/payouts/{payoutID}/approve", h.ApprovePayout)             // POST /api/v1/admin/payouts/{payoutID}/approve
+	r.Post("/payouts/{payoutID}/reject", h.RejectPayout)               // POST /api/v1/admin/payouts/{payoutID}/reject
+	r.Get("/payout-batches", h.ListPayoutBatches)                      // GET /api/v1/admin/payout-batches
+	r.Post("/payout-batches", h.RunPayoutBatch)                        // POST /api/v1/admin/payout-batches
+	r.Get("/payout-batches/{batchID}/export", h.ExportPayoutBatch)     // GET /api/v1/admin/payout-batches/{batchID}/export
+	r.Get("/service-areas", h.ListServiceAreas)                        // GET /api/v1/admin/service-areas
+	r.Post("/service-areas", h.CreateServiceArea)                      // POST /api/v1/admin/service-areas
+	r.Put("/service-areas/{areaID}", h.UpdateServiceArea)              // PUT /api/v1/admin/service-areas/{areaID}
+	r.Delete("/service-areas/{areaID}", h.DeactivateServiceArea)       // DELETE /api/v1/admin/service-areas/{areaID}
+	r.Put("/mowers/{mowerID}/service-areas", h.SetMowerServiceAreas)   // PUT /api/v1/admin/mowers/{mowerID}/service-areas
+
+	return r
+}
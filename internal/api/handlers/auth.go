@@ -2,12 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
-	"log"
 	"net/http"
 
 	httpresponse "lawnconnect-api/internal/api/http"
 	"lawnconnect-api/internal/core/apperror"
 	"lawnconnect-api/internal/core/services"
+	"lawnconnect-api/internal/infrastructure/logging"
 	infrastructureServices "lawnconnect-api/internal/infrastructure/services"
 
 	"github.com/go-chi/chi/v5"
@@ -103,7 +103,7 @@ func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 
 	err := h.AuthService.ForgotPassword(r.Context(), reqBody.Email)
 	if err != nil {
-		log.Printf("ForgotPassword failed for %s: %v", reqBody.Email, err)
+		logging.FromContext(r.Context()).Error("password reset request failed", "error", err)
 		// We return a success message even if the user doesn't exist to prevent
 		// email enumeration attacks.
 	}
@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
 	httpresponse "lawnconnect-api/internal/api/http"
 	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
 	"lawnconnect-api/internal/core/services"
-	"log"
+	"lawnconnect-api/internal/infrastructure/logging"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -15,11 +19,13 @@ import (
 // BookingHandler handles HTTP requests related to bookings.
 type BookingHandler struct {
 	BookingService services.BookingService
+	ReviewService  services.ReviewService
+	CommentService services.CommentService
 }
 
 // NewBookingHandler creates a new BookingHandler.
-func NewBookingHandler(bookingSrv services.BookingService) *BookingHandler {
-	return &BookingHandler{BookingService: bookingSrv}
+func NewBookingHandler(bookingSrv services.BookingService, reviewSrv services.ReviewService, commentSrv services.CommentService) *BookingHandler {
+	return &BookingHandler{BookingService: bookingSrv, ReviewService: reviewSrv, CommentService: commentSrv}
 }
 
 // CreateBooking handles creating a new booking.
@@ -29,6 +35,14 @@ func (h *BookingHandler) CreateBooking(w http.ResponseWriter, r *http.Request) {
 		Time        string `json:"time"`
 		Address     string `json:"address"`
 		Description string `json:"description"`
+		// PaymentMethod is a card token from the payment provider's SDK. Without
+		// one the booking is paid by invoice.
+		PaymentMethod string `json:"paymentMethod"`
+		// PropertyID books one of the customer's properties at its address, with
+		// a price estimate for Service (by default the property's service type).
+		// Address is only used without a property.
+		PropertyID string `json:"propertyId"`
+		Service    string `json:"service"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
@@ -36,18 +50,56 @@ func (h *BookingHandler) CreateBooking(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	request := services.BookingRequest{
+		Date:          reqBody.Date,
+		Time:          reqBody.Time,
+		Address:       reqBody.Address,
+		Description:   reqBody.Description,
+		PaymentMethod: reqBody.PaymentMethod,
+		Service:       reqBody.Service,
+	}
+	if reqBody.PropertyID != "" {
+		propertyID, err := primitive.ObjectIDFromHex(reqBody.PropertyID)
+		if err != nil {
+			httpresponse.JSONError(w, http.StatusBadRequest, "Invalid property ID")
+			return
+		}
+		request.PropertyID = propertyID
+	}
+
 	customerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
 
-	booking, err := h.BookingService.CreateBooking(r.Context(), customerID, reqBody.Date, reqBody.Time, reqBody.Address, reqBody.Description)
+	booking, err := h.BookingService.CreateBooking(r.Context(), customerID, request)
 	if err != nil {
+		switch err.(type) {
+		case apperror.PaymentRequired:
+			httpresponse.JSONError(w, http.StatusPaymentRequired, err.Error())
+			return
+		case apperror.CustomError:
+			httpresponse.JSONError(w, http.StatusBadRequest, err.Error())
+			return
+		case apperror.NotFound:
+			httpresponse.JSONError(w, http.StatusNotFound, err.Error())
+			return
+		case apperror.InvalidResource:
+			httpresponse.JSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("creating booking failed", "error", err)
 		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to create booking")
 		return
 	}
 
+	if booking.CustomerOverdue {
+		httpresponse.JSONSuccess(w, http.StatusCreated, "Booking created, but you have overdue invoices. Please pay them soon.", booking)
+		return
+	}
 	httpresponse.JSONSuccess(w, http.StatusCreated, "Booking created successfully", booking)
 }
 
-// GetBookingByID retrieves a single booking by its ID.
+// GetBookingByID retrieves a single booking by its ID for its customer, its
+// assigned mower, admins, and the mowers who may quote for it while it is
+// pending.
 func (h *BookingHandler) GetBookingByID(w http.ResponseWriter, r *http.Request) {
 	bookingIDStr := chi.URLParam(r, "bookingID")
 	bookingID, err := primitive.ObjectIDFromHex(bookingIDStr)
@@ -56,9 +108,12 @@ func (h *BookingHandler) GetBookingByID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	booking, err := h.BookingService.GetBookingByID(r.Context(), bookingID)
+	userID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+	role, _ := r.Context().Value("userRole").(string)
+
+	booking, err := h.BookingService.GetBookingByID(r.Context(), bookingID, userID, role)
 	if err != nil {
-		log.Printf("Error getting booking: %v", err)
+		logging.FromContext(r.Context()).Error("getting booking failed", "booking_id", bookingID.Hex(), "error", err)
 		if _, ok := err.(apperror.NotFound); ok {
 			httpresponse.JSONError(w, http.StatusNotFound, "Booking not found")
 			return
@@ -70,7 +125,32 @@ func (h *BookingHandler) GetBookingByID(w http.ResponseWriter, r *http.Request)
 	httpresponse.JSONSuccess(w, http.StatusOK, "Booking retrieved successfully", booking)
 }
 
-// AcceptBooking handles a mower accepting a booking.
+// GetBookingProperty returns the booked property, with its access notes, to the
+// customer and the assigned mower.
+func (h *BookingHandler) GetBookingProperty(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "bookingID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid booking ID")
+		return
+	}
+
+	userID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	property, err := h.BookingService.GetBookingProperty(r.Context(), bookingID, userID)
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			httpresponse.JSONError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("getting booking property failed", "booking_id", bookingID.Hex(), "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to retrieve property")
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Property retrieved successfully", property)
+}
+
+// AcceptBooking handles a mower accepting a booking with a quote.
 func (h *BookingHandler) AcceptBooking(w http.ResponseWriter, r *http.Request) {
 	bookingIDStr := chi.URLParam(r, "bookingID")
 	bookingID, err := primitive.ObjectIDFromHex(bookingIDStr)
@@ -79,20 +159,113 @@ func (h *BookingHandler) AcceptBooking(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var reqBody struct {
+		// Type is "fixed", with Price, or "hourly", with EstimatedHours.
+		Type           string  `json:"type"`
+		Price          float64 `json:"price"`
+		EstimatedHours float64 `json:"estimatedHours"`
+		Note           string  `json:"note"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	switch {
+	case reqBody.Type == domain.QuoteFixed && reqBody.Price <= 0:
+		httpresponse.JSONError(w, http.StatusBadRequest, "Fixed quotes need a positive price")
+		return
+	case reqBody.Type == domain.QuoteHourly && reqBody.EstimatedHours <= 0:
+		httpresponse.JSONError(w, http.StatusBadRequest, "Hourly quotes need positive estimated hours")
+		return
+	case reqBody.Type != domain.QuoteFixed && reqBody.Type != domain.QuoteHourly:
+		httpresponse.JSONError(w, http.StatusBadRequest, "Quote type must be fixed or hourly")
+		return
+	}
+
 	// Get mower ID from context
 	mowerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
 
-	err = h.BookingService.AcceptBooking(r.Context(), bookingID, mowerID)
+	quote := services.QuoteRequest{Type: reqBody.Type, Price: reqBody.Price, EstimatedHours: reqBody.EstimatedHours, Note: reqBody.Note}
+	err = h.BookingService.AcceptBooking(r.Context(), bookingID, mowerID, quote)
 	if err != nil {
-		log.Printf("Error accepting booking: %v", err)
+		logging.FromContext(r.Context()).Warn("accepting booking failed", "booking_id", bookingID.Hex(), "error", err)
+		switch err.(type) {
+		case apperror.NotFound:
+			httpresponse.JSONError(w, http.StatusNotFound, "Booking not found")
+			return
+		case apperror.CustomError:
+			httpresponse.JSONError(w, http.StatusConflict, err.Error())
+			return
+		}
 		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to accept booking")
 		return
 	}
 
-	httpresponse.JSONSuccess(w, http.StatusOK, "Booking accepted successfully", nil)
+	httpresponse.JSONSuccess(w, http.StatusOK, "Booking accepted; the quote awaits the customer's approval", nil)
 }
 
-// CompleteBooking handles a mower completing a booking, setting the final price.
+// ApproveQuote handles a customer approving the quote on their booking.
+func (h *BookingHandler) ApproveQuote(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "bookingID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid booking ID")
+		return
+	}
+
+	customerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	if err := h.BookingService.ApproveQuote(r.Context(), bookingID, customerID); err != nil {
+		h.quoteError(w, r, bookingID, "approving quote failed", err)
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Quote approved", nil)
+}
+
+// DeclineQuote handles a customer declining the quote on their booking, which
+// returns it to the pending pool.
+func (h *BookingHandler) DeclineQuote(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "bookingID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid booking ID")
+		return
+	}
+
+	// The reason is optional, so an empty body is allowed.
+	var reqBody struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil && err != io.EOF {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	customerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	if err := h.BookingService.DeclineQuote(r.Context(), bookingID, customerID, strings.TrimSpace(reqBody.Reason)); err != nil {
+		h.quoteError(w, r, bookingID, "declining quote failed", err)
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Quote declined; the booking is open for new quotes", nil)
+}
+
+func (h *BookingHandler) quoteError(w http.ResponseWriter, r *http.Request, bookingID primitive.ObjectID, msg string, err error) {
+	switch err.(type) {
+	case apperror.NotFound:
+		httpresponse.JSONError(w, http.StatusNotFound, "Booking not found")
+	case apperror.CustomError:
+		httpresponse.JSONError(w, http.StatusConflict, err.Error())
+	default:
+		logging.FromContext(r.Context()).Error(msg, "booking_id", bookingID.Hex(), "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to update quote")
+	}
+}
+
+// CompleteBooking handles a mower completing a booking at the approved quote,
+// optionally adjusting the final price.
 func (h *BookingHandler) CompleteBooking(w http.ResponseWriter, r *http.Request) {
 	bookingIDStr := chi.URLParam(r, "bookingID")
 	bookingID, err := primitive.ObjectIDFromHex(bookingIDStr)
@@ -101,31 +274,39 @@ func (h *BookingHandler) CompleteBooking(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Without a price the booking is charged at the approved quote.
 	var reqBody struct {
-		Price float64 `json:"price"`
+		Price         float64 `json:"price"`
+		Justification string  `json:"justification"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil && err != io.EOF {
 		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid request payload")
 		return
 	}
 
-	if reqBody.Price <= 0 {
+	if reqBody.Price < 0 {
 		httpresponse.JSONError(w, http.StatusBadRequest, "Price must be a positive number")
 		return
 	}
 
-	err = h.BookingService.CompleteBooking(r.Context(), bookingID, reqBody.Price)
+	mowerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	err = h.BookingService.CompleteBooking(r.Context(), bookingID, mowerID, reqBody.Price, reqBody.Justification)
 	if err != nil {
-		if _, ok := err.(apperror.CustomError); ok {
+		switch err.(type) {
+		case apperror.NotFound:
+			httpresponse.JSONError(w, http.StatusNotFound, err.Error())
+		case apperror.CustomError:
 			httpresponse.JSONError(w, http.StatusConflict, err.Error())
-			return
+		default:
+			logging.FromContext(r.Context()).Error("completing booking failed", "booking_id", bookingID.Hex(), "error", err)
+			httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to complete booking")
 		}
-		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to complete booking")
 		return
 	}
 
-	httpresponse.JSONSuccess(w, http.StatusOK, "Booking completed and payment simulated successfully", nil)
+	httpresponse.JSONSuccess(w, http.StatusOK, "Booking completed and mower credited successfully", nil)
 }
 
 // ListBookings retrieves a list of bookings for the authenticated user.
@@ -134,7 +315,7 @@ func (h *BookingHandler) ListBookings(w http.ResponseWriter, r *http.Request) {
 
 	bookings, err := h.BookingService.ListBookings(r.Context(), userID)
 	if err != nil {
-		log.Printf("Error listing bookings: %v", err)
+		logging.FromContext(r.Context()).Error("listing bookings failed", "error", err)
 		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to retrieve bookings")
 		return
 	}
@@ -142,11 +323,18 @@ func (h *BookingHandler) ListBookings(w http.ResponseWriter, r *http.Request) {
 	httpresponse.JSONSuccess(w, http.StatusOK, "Bookings retrieved successfully", bookings)
 }
 
-// ListPendingBookings handles listing all pending bookings for mowers.
+// ListPendingBookings handles listing the pending bookings within a mower's
+// service radius, nearest first.
 func (h *BookingHandler) ListPendingBookings(w http.ResponseWriter, r *http.Request) {
-	bookings, err := h.BookingService.ListPendingBookings(r.Context())
+	mowerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	bookings, err := h.BookingService.ListPendingBookings(r.Context(), mowerID)
 	if err != nil {
-		log.Printf("Error listing pending bookings: %v", err)
+		if _, ok := err.(apperror.CustomError); ok {
+			httpresponse.JSONError(w, http.StatusConflict, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("listing pending bookings failed", "error", err)
 		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to retrieve pending bookings")
 		return
 	}
@@ -167,10 +355,15 @@ func (h *BookingHandler) CancelBooking(w http.ResponseWriter, r *http.Request) {
 
 	err = h.BookingService.CancelBooking(r.Context(), bookingID, customerID)
 	if err != nil {
-		if _, ok := err.(apperror.CustomError); ok {
-			httpresponse.JSONError(w, http.StatusForbidden, err.Error())
+		switch err.(type) {
+		case apperror.NotFound:
+			httpresponse.JSONError(w, http.StatusNotFound, "Booking not found")
+			return
+		case apperror.CustomError:
+			httpresponse.JSONError(w, http.StatusConflict, err.Error())
 			return
 		}
+		logging.FromContext(r.Context()).Error("cancelling booking failed", "booking_id", bookingID.Hex(), "error", err)
 		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to cancel booking")
 		return
 	}
@@ -191,10 +384,15 @@ func (h *BookingHandler) RejectBooking(w http.ResponseWriter, r *http.Request) {
 
 	err = h.BookingService.RejectBooking(r.Context(), bookingID, mowerID)
 	if err != nil {
-		if _, ok := err.(apperror.CustomError); ok {
-			httpresponse.JSONError(w, http.StatusForbidden, err.Error())
+		switch err.(type) {
+		case apperror.NotFound:
+			httpresponse.JSONError(w, http.StatusNotFound, "Booking not found")
+			return
+		case apperror.CustomError:
+			httpresponse.JSONError(w, http.StatusConflict, err.Error())
 			return
 		}
+		logging.FromContext(r.Context()).Error("rejecting booking failed", "booking_id", bookingID.Hex(), "error", err)
 		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to reject booking")
 		return
 	}
@@ -202,17 +400,275 @@ func (h *BookingHandler) RejectBooking(w http.ResponseWriter, r *http.Request) {
 	httpresponse.JSONSuccess(w, http.StatusOK, "Booking rejected successfully", nil)
 }
 
-// Routes mounts the booking-related routes to a chi router.
+// CreateReview handles a customer rating their completed booking.
+func (h *BookingHandler) CreateReview(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "bookingID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid booking ID")
+		return
+	}
+
+	var reqBody struct {
+		Rating  int    `json:"rating"`
+		Comment string `json:"comment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	customerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	review, err := h.ReviewService.CreateReview(r.Context(), customerID, bookingID, reqBody.Rating, reqBody.Comment)
+	if err != nil {
+		h.reviewError(w, r, bookingID, "Failed to create review", err)
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusCreated, "Review created successfully", review)
+}
+
+// GetReview returns the review of a booking to its customer or mower.
+func (h *BookingHandler) GetReview(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "bookingID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid booking ID")
+		return
+	}
+
+	userID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	review, err := h.ReviewService.GetBookingReview(r.Context(), userID, bookingID)
+	if err != nil {
+		h.reviewError(w, r, bookingID, "Failed to retrieve review", err)
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Review retrieved successfully", review)
+}
+
+// ReplyToReview handles the mower answering the review of their booking.
+func (h *BookingHandler) ReplyToReview(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "bookingID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid booking ID")
+		return
+	}
+
+	var reqBody struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	mowerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	review, err := h.ReviewService.ReplyToReview(r.Context(), mowerID, bookingID, reqBody.Text)
+	if err != nil {
+		h.reviewError(w, r, bookingID, "Failed to reply to review", err)
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Reply added successfully", review)
+}
+
+// reviewError writes the response for a failed review request.
+func (h *BookingHandler) reviewError(w http.ResponseWriter, r *http.Request, bookingID primitive.ObjectID, msg string, err error) {
+	switch err.(type) {
+	case apperror.NotFound:
+		httpresponse.JSONError(w, http.StatusNotFound, err.Error())
+	case apperror.InvalidResource:
+		httpresponse.JSONError(w, http.StatusBadRequest, err.Error())
+	case apperror.CustomError, apperror.DuplicateError:
+		httpresponse.JSONError(w, http.StatusConflict, err.Error())
+	default:
+		logging.FromContext(r.Context()).Error("review request failed", "booking_id", bookingID.Hex(), "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, msg)
+	}
+}
+
+// ListComments returns the comment thread of a booking.
+func (h *BookingHandler) ListComments(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "bookingID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid booking ID")
+		return
+	}
+
+	userID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	comments, err := h.CommentService.ListComments(r.Context(), bookingID, userID)
+	if err != nil {
+		h.commentError(w, r, bookingID, "Failed to retrieve comments", err)
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Comments retrieved successfully", comments)
+}
+
+// PostComment adds a comment to a booking's thread. The body is JSON with a
+// `text` field, or a multipart form with a `text` field and up to
+// services.MaxCommentPhotos JPEG, PNG or WebP `photo` files.
+func (h *BookingHandler) PostComment(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "bookingID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid booking ID")
+		return
+	}
+
+	var (
+		text   string
+		photos []services.CommentPhoto
+	)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		r.Body = http.MaxBytesReader(w, r.Body, services.MaxCommentPhotos*maxPhotoBytes+(1<<20))
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			httpresponse.JSONError(w, http.StatusBadRequest, "Invalid multipart form")
+			return
+		}
+		text = r.FormValue("text")
+		for _, header := range r.MultipartForm.File["photo"] {
+			file, err := header.Open()
+			if err != nil {
+				httpresponse.JSONError(w, http.StatusBadRequest, "Invalid photo")
+				return
+			}
+			content, err := io.ReadAll(io.LimitReader(file, maxPhotoBytes+1))
+			file.Close()
+			if err != nil || len(content) > maxPhotoBytes {
+				httpresponse.JSONError(w, http.StatusBadRequest, "Photos must be at most 10 MB each")
+				return
+			}
+			if !photoTypes[http.DetectContentType(content)] {
+				httpresponse.JSONError(w, http.StatusBadRequest, "Photos must be JPEG, PNG or WebP images")
+				return
+			}
+			photos = append(photos, services.CommentPhoto{Content: bytes.NewReader(content), Filename: header.Filename})
+		}
+	} else {
+		var reqBody struct {
+			Text string `json:"text"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			httpresponse.JSONError(w, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+		text = reqBody.Text
+	}
+
+	userID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	comment, err := h.CommentService.PostComment(r.Context(), bookingID, userID, text, photos)
+	if err != nil {
+		h.commentError(w, r, bookingID, "Failed to post comment", err)
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusCreated, "Comment posted successfully", comment)
+}
+
+// EditComment replaces the text of the user's own comment.
+func (h *BookingHandler) EditComment(w http.ResponseWriter, r *http.Request) {
+	bookingID, commentID, ok := commentParams(w, r)
+	if !ok {
+		return
+	}
+
+	var reqBody struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	userID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	comment, err := h.CommentService.EditComment(r.Context(), bookingID, commentID, userID, reqBody.Text)
+	if err != nil {
+		h.commentError(w, r, bookingID, "Failed to edit comment", err)
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Comment updated successfully", comment)
+}
+
+// DeleteComment removes a comment from a booking's thread.
+func (h *BookingHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	bookingID, commentID, ok := commentParams(w, r)
+	if !ok {
+		return
+	}
+
+	userID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	if err := h.CommentService.DeleteComment(r.Context(), bookingID, commentID, userID); err != nil {
+		h.commentError(w, r, bookingID, "Failed to delete comment", err)
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Comment deleted successfully", nil)
+}
+
+// commentError writes the response for a failed comment request.
+func (h *BookingHandler) commentError(w http.ResponseWriter, r *http.Request, bookingID primitive.ObjectID, msg string, err error) {
+	switch err.(type) {
+	case apperror.NotFound:
+		httpresponse.JSONError(w, http.StatusNotFound, err.Error())
+	case apperror.InvalidResource:
+		httpresponse.JSONError(w, http.StatusBadRequest, err.Error())
+	case apperror.CustomError:
+		httpresponse.JSONError(w, http.StatusForbidden, err.Error())
+	default:
+		logging.FromContext(r.Context()).Error("comment request failed", "booking_id", bookingID.Hex(), "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, msg)
+	}
+}
+
+func commentParams(w http.ResponseWriter, r *http.Request) (bookingID, commentID primitive.ObjectID, ok bool) {
+	bookingID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "bookingID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid booking ID")
+		return bookingID, commentID, false
+	}
+	commentID, err = primitive.ObjectIDFromHex(chi.URLParam(r, "commentID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid comment ID")
+		return bookingID, commentID, false
+	}
+	return bookingID, commentID, true
+}
+
+// Routes returns the booking routes, to be mounted at /bookings behind
+// AuthMiddleware. Each route is restricted to the roles allowed to use it.
 func (h *BookingHandler) Routes() chi.Router {
 	r := chi.NewRouter()
-	r.Post("/", h.CreateBooking)                      // POST /api/v1/bookings
-	r.Get("/", h.ListBookings)                        // GET /api/v1/bookings
-	r.Get("/pending", h.ListPendingBookings)          // GET /api/v1/bookings/pending
-	r.Get("/{bookingID}", h.GetBookingByID)           // GET /api/v1/bookings/{bookingID}
-	r.Put("/{bookingID}/accept", h.AcceptBooking)     // PUT /api/v1/bookings/{bookingID}/accept
-	r.Put("/{bookingID}/complete", h.CompleteBooking) // PUT /api/v1/bookings/{bookingID}/complete
-	r.Put("/{bookingID}/cancel", h.CancelBooking)     // PUT /api/v1/bookings/{bookingID}/cancel
-	r.Put("/{bookingID}/reject", h.RejectBooking)     // PUT /api/v1/bookings/{bookingID}/reject
+
+	customer := RoleMiddleware("customer")
+	mower := RoleMiddleware("mower")
+	participant := RoleMiddleware("customer", "mower")
+	commenter := RoleMiddleware("customer", "mower", "admin", "super_admin")
+
+	r.With(customer).Post("/", h.CreateBooking)                                    // POST /api/v1/bookings
+	r.With(participant).Get("/", h.ListBookings)                                   // GET /api/v1/bookings
+	r.With(mower).Get("/pending", h.ListPendingBookings)                           // GET /api/v1/bookings/pending
+	r.With(commenter).Get("/{bookingID}", h.GetBookingByID)                        // GET /api/v1/bookings/{bookingID}
+	r.With(participant).Get("/{bookingID}/property", h.GetBookingProperty)         // GET /api/v1/bookings/{bookingID}/property
+	r.With(mower).Put("/{bookingID}/accept", h.AcceptBooking)                      // PUT /api/v1/bookings/{bookingID}/accept
+	r.With(customer).Put("/{bookingID}/quote/approve", h.ApproveQuote)             // PUT /api/v1/bookings/{bookingID}/quote/approve
+	r.With(customer).Put("/{bookingID}/quote/decline", h.DeclineQuote)             // PUT /api/v1/bookings/{bookingID}/quote/decline
+	r.With(mower).Put("/{bookingID}/complete", h.CompleteBooking)                  // PUT /api/v1/bookings/{bookingID}/complete
+	r.With(customer).Put("/{bookingID}/cancel", h.CancelBooking)                   // PUT /api/v1/bookings/{bookingID}/cancel
+	r.With(mower).Put("/{bookingID}/reject", h.RejectBooking)                      // PUT /api/v1/bookings/{bookingID}/reject
+	r.With(customer).Post("/{bookingID}/review", h.CreateReview)                   // POST /api/v1/bookings/{bookingID}/review
+	r.With(participant).Get("/{bookingID}/review", h.GetReview)                    // GET /api/v1/bookings/{bookingID}/review
+	r.With(mower).Put("/{bookingID}/review/reply", h.ReplyToReview)                // PUT /api/v1/bookings/{bookingID}/review/reply
+	r.With(commenter).Get("/{bookingID}/comments", h.ListComments)                 // GET /api/v1/bookings/{bookingID}/comments
+	r.With(commenter).Post("/{bookingID}/comments", h.PostComment)                 // POST /api/v1/bookings/{bookingID}/comments
+	r.With(commenter).Put("/{bookingID}/comments/{commentID}", h.EditComment)      // PUT /api/v1/bookings/{bookingID}/comments/{commentID}
+	r.With(commenter).Delete("/{bookingID}/comments/{commentID}", h.DeleteComment) // DELETE /api/v1/bookings/{bookingID}/comments/{commentID}
 
 	return r
 }
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	httpresponse "lawnconnect-api/internal/api/http"
+	"lawnconnect-api/internal/infrastructure/health"
+)
+
+// HealthHandler serves the liveness and readiness probes.
+type HealthHandler struct {
+	Reporter *health.Reporter
+}
+
+// NewHealthHandler creates a new HealthHandler.
+func NewHealthHandler(reporter *health.Reporter) *HealthHandler {
+	return &HealthHandler{Reporter: reporter}
+}
+
+// Liveness reports that the process is up and serving requests. It does not check
+// dependencies, so a database outage does not get healthy instances restarted.
+func (h *HealthHandler) Liveness(w http.ResponseWriter, r *http.Request) {
+	httpresponse.JSONSuccess(w, http.StatusOK, "ok", nil)
+}
+
+// Readiness reports whether the instance should receive traffic, with the status
+// of each dependency.
+func (h *HealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	if h.Reporter == nil {
+		httpresponse.JSONSuccess(w, http.StatusOK, "ready", nil)
+		return
+	}
+
+	report := h.Reporter.Readiness(r.Context())
+	if !report.Ready {
+		httpresponse.JSONResponse(w, http.StatusServiceUnavailable, false, "not ready", report)
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusOK, "ready", report)
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"net/http"
+
+	httpresponse "lawnconnect-api/internal/api/http"
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/core/services"
+	"lawnconnect-api/internal/infrastructure/logging"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InvoiceHandler handles HTTP requests for booking invoices.
+type InvoiceHandler struct {
+	InvoiceService services.InvoiceService
+	DunningService services.DunningService
+}
+
+// NewInvoiceHandler creates a new InvoiceHandler.
+func NewInvoiceHandler(invoiceSrv services.InvoiceService, dunningSrv services.DunningService) *InvoiceHandler {
+	return &InvoiceHandler{InvoiceService: invoiceSrv, DunningService: dunningSrv}
+}
+
+// ListInvoices returns the authenticated customer's invoices.
+func (h *InvoiceHandler) ListInvoices(w http.ResponseWriter, r *http.Request) {
+	customerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	invoices, err := h.InvoiceService.ListInvoices(r.Context(), customerID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("listing invoices failed", "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to retrieve invoices")
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Invoices retrieved successfully", invoices)
+}
+
+// GetInvoice returns an invoice's details.
+func (h *InvoiceHandler) GetInvoice(w http.ResponseWriter, r *http.Request) {
+	invoice, ok := h.loadInvoice(w, r)
+	if !ok {
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusOK, "Invoice retrieved successfully", invoice)
+}
+
+// DownloadPDF serves the invoice as a PDF attachment.
+func (h *InvoiceHandler) DownloadPDF(w http.ResponseWriter, r *http.Request) {
+	h.download(w, r, services.InvoiceFormatPDF, "application/pdf")
+}
+
+// DownloadHTML serves the invoice as a printable HTML page.
+func (h *InvoiceHandler) DownloadHTML(w http.ResponseWriter, r *http.Request) {
+	h.download(w, r, services.InvoiceFormatHTML, "text/html; charset=utf-8")
+}
+
+func (h *InvoiceHandler) download(w http.ResponseWriter, r *http.Request, format, contentType string) {
+	invoice, ok := h.loadInvoice(w, r)
+	if !ok {
+		return
+	}
+
+	document, err := h.InvoiceService.RenderInvoice(r.Context(), invoice, format)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("rendering invoice failed", "invoice", invoice.Number, "format", format, "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to render invoice")
+		return
+	}
+
+	disposition := "inline"
+	if format == services.InvoiceFormatPDF {
+		disposition = "attachment"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", disposition+`; filename="`+invoice.Number+"."+format+`"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(document)
+}
+
+// ListReminders returns every payment reminder attempted for an invoice, oldest first.
+func (h *InvoiceHandler) ListReminders(w http.ResponseWriter, r *http.Request) {
+	invoice, ok := h.loadInvoice(w, r)
+	if !ok {
+		return
+	}
+
+	reminders, err := h.DunningService.ListReminders(r.Context(), invoice.ID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("listing payment reminders failed", "invoice", invoice.Number, "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to retrieve payment reminders")
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Payment reminders retrieved successfully", reminders)
+}
+
+// MarkPaid records that the customer has paid an invoice.
+func (h *InvoiceHandler) MarkPaid(w http.ResponseWriter, r *http.Request) {
+	invoiceID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "invoiceID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid invoice ID")
+		return
+	}
+	role, _ := r.Context().Value("userRole").(string)
+
+	invoice, err := h.InvoiceService.MarkPaid(r.Context(), invoiceID, role)
+	if err != nil {
+		switch err.(type) {
+		case apperror.NotFound:
+			httpresponse.JSONError(w, http.StatusNotFound, err.Error())
+		case apperror.CustomError:
+			httpresponse.JSONError(w, http.StatusConflict, err.Error())
+		default:
+			logging.FromContext(r.Context()).Error("marking invoice paid failed", "invoice_id", invoiceID.Hex(), "error", err)
+			httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to mark invoice paid")
+		}
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Invoice marked as paid", invoice)
+}
+
+// loadInvoice fetches the invoice named in the URL, writing the error response
+// and returning false if the caller cannot see it.
+func (h *InvoiceHandler) loadInvoice(w http.ResponseWriter, r *http.Request) (*domain.Invoice, bool) {
+	invoiceID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "invoiceID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid invoice ID")
+		return nil, false
+	}
+	userID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+	role, _ := r.Context().Value("userRole").(string)
+
+	invoice, err := h.InvoiceService.GetInvoice(r.Context(), invoiceID, userID, role)
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			httpresponse.JSONError(w, http.StatusNotFound, err.Error())
+			return nil, false
+		}
+		logging.FromContext(r.Context()).Error("loading invoice failed", "invoice_id", invoiceID.Hex(), "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to retrieve invoice")
+		return nil, false
+	}
+	return invoice, true
+}
+
+// Routes returns the invoice routes, to be mounted at /invoices behind AuthMiddleware.
+func (h *InvoiceHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	customer := RoleMiddleware("customer")
+	settler := RoleMiddleware("admin", "super_admin")
+
+	r.With(customer).Get("/", h.ListInvoices)            // GET /api/v1/invoices
+	r.Get("/{invoiceID}", h.GetInvoice)                  // GET /api/v1/invoices/{invoiceID}
+	r.Get("/{invoiceID}/pdf", h.DownloadPDF)             // GET /api/v1/invoices/{invoiceID}/pdf
+	r.Get("/{invoiceID}/html", h.DownloadHTML)           // GET /api/v1/invoices/{invoiceID}/html
+	r.Get("/{invoiceID}/reminders", h.ListReminders)     // GET /api/v1/invoices/{invoiceID}/reminders
+	r.With(settler).Put("/{invoiceID}/paid", h.MarkPaid) // PUT /api/v1/invoices/{invoiceID}/paid
+
+	return r
+}
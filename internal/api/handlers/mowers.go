@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	httpresponse "lawnconnect-api/internal/api/http"
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/services"
+	"lawnconnect-api/internal/infrastructure/logging"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MowerHandler handles HTTP requests for a mower's own work settings.
+type MowerHandler struct {
+	LocationService    services.LocationService
+	ServiceAreaService services.ServiceAreaService
+	RouteService       services.RouteService
+}
+
+// NewMowerHandler creates a new MowerHandler.
+func NewMowerHandler(locationSrv services.LocationService, serviceAreaSrv services.ServiceAreaService, routeSrv services.RouteService) *MowerHandler {
+	return &MowerHandler{LocationService: locationSrv, ServiceAreaService: serviceAreaSrv, RouteService: routeSrv}
+}
+
+// GetServiceRadius returns the authenticated mower's service radius.
+func (h *MowerHandler) GetServiceRadius(w http.ResponseWriter, r *http.Request) {
+	mowerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	radius, err := h.LocationService.GetServiceRadius(r.Context(), mowerID)
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			httpresponse.JSONError(w, http.StatusNotFound, "Service radius not set")
+			return
+		}
+		logging.FromContext(r.Context()).Error("getting service radius failed", "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to retrieve service radius")
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Service radius retrieved successfully", radius)
+}
+
+// SetServiceRadius sets the authenticated mower's base location, as a
+// `baseAddress` or `lat` and `lng`, and the `radiusKm` they travel from it.
+func (h *MowerHandler) SetServiceRadius(w http.ResponseWriter, r *http.Request) {
+	var reqBody struct {
+		BaseAddress string   `json:"baseAddress"`
+		Lat         *float64 `json:"lat"`
+		Lng         *float64 `json:"lng"`
+		RadiusKm    float64  `json:"radiusKm"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	mowerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	radius, err := h.LocationService.SetServiceRadius(r.Context(), mowerID, services.ServiceRadiusInput{
+		BaseAddress: reqBody.BaseAddress,
+		Lat:         reqBody.Lat,
+		Lng:         reqBody.Lng,
+		RadiusKm:    reqBody.RadiusKm,
+	})
+	if err != nil {
+		if _, ok := err.(apperror.CustomError); ok {
+			httpresponse.JSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("setting service radius failed", "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to set service radius")
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Service radius updated successfully", radius)
+}
+
+// ListServiceAreas returns the service areas the authenticated mower is
+// approved to work in.
+func (h *MowerHandler) ListServiceAreas(w http.ResponseWriter, r *http.Request) {
+	mowerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	areas, err := h.ServiceAreaService.MowerAreas(r.Context(), mowerID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("listing mower service areas failed", "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to retrieve service areas")
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusOK, "Service areas retrieved successfully", areas)
+}
+
+// PlanRoute returns the order in which the authenticated mower should visit
+// their jobs on the `date` query parameter, leaving their base at the optional
+// `start` time.
+func (h *MowerHandler) PlanRoute(w http.ResponseWriter, r *http.Request) {
+	mowerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	query := r.URL.Query()
+	route, err := h.RouteService.PlanRoute(r.Context(), mowerID, query.Get("date"), query.Get("start"))
+	if err != nil {
+		switch err.(type) {
+		case apperror.InvalidResource:
+			httpresponse.JSONError(w, http.StatusBadRequest, err.Error())
+		case apperror.CustomError:
+			httpresponse.JSONError(w, http.StatusConflict, err.Error())
+		default:
+			logging.FromContext(r.Context()).Error("planning route failed", "error", err)
+			httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to plan route")
+		}
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Route planned successfully", route)
+}
+
+// Routes returns the mower settings routes, to be mounted at /mower behind
+// AuthMiddleware.
+func (h *MowerHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Use(RoleMiddleware("mower"))
+
+	r.Get("/service-radius", h.GetServiceRadius) // GET /api/v1/mower/service-radius
+	r.Put("/service-radius", h.SetServiceRadius) // PUT /api/v1/mower/service-radius
+	r.Get("/service-areas", h.ListServiceAreas)  // GET /api/v1/mower/service-areas
+	r.Get("/route", h.PlanRoute)                 // GET /api/v1/mower/route
+	return r
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	httpresponse "lawnconnect-api/internal/api/http"
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/services"
+	"lawnconnect-api/internal/infrastructure/logging"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxWebhookBytes bounds the size of a payment webhook body.
+const maxWebhookBytes = 64 << 10
+
+// PaymentHandler handles callbacks from the payment provider.
+type PaymentHandler struct {
+	PaymentService services.PaymentService
+}
+
+// NewPaymentHandler creates a new PaymentHandler.
+func NewPaymentHandler(paymentSrv services.PaymentService) *PaymentHandler {
+	return &PaymentHandler{PaymentService: paymentSrv}
+}
+
+// Webhook applies a signed event from the payment provider. The provider
+// retries until it gets a 2xx, so events that cannot be processed yet return 500.
+func (h *PaymentHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid webhook payload")
+		return
+	}
+
+	if err := h.PaymentService.HandleWebhook(r.Context(), payload, r.Header); err != nil {
+		if _, ok := err.(apperror.CustomError); ok {
+			httpresponse.JSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("processing payment webhook failed", "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to process webhook")
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Webhook processed", nil)
+}
+
+// Routes returns the payment routes, to be mounted at /payments. The webhook is
+// public; it is authenticated by the provider's signature instead of a session.
+func (h *PaymentHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Post("/webhook", h.Webhook) // POST /api/v1/payments/webhook
+
+	return r
+}
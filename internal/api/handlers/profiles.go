@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	httpresponse "lawnconnect-api/internal/api/http"
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/services"
+	"lawnconnect-api/internal/infrastructure/logging"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProfileHandler serves mowers' public profiles and reviews.
+type ProfileHandler struct {
+	ReviewService services.ReviewService
+}
+
+// NewProfileHandler creates a new ProfileHandler.
+func NewProfileHandler(reviewSrv services.ReviewService) *ProfileHandler {
+	return &ProfileHandler{ReviewService: reviewSrv}
+}
+
+// GetMowerProfile returns a mower's public profile with their rating summary.
+func (h *ProfileHandler) GetMowerProfile(w http.ResponseWriter, r *http.Request) {
+	mowerID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "mowerID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid mower ID")
+		return
+	}
+
+	profile, err := h.ReviewService.MowerProfile(r.Context(), mowerID)
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			httpresponse.JSONError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("getting mower profile failed", "mower_id", mowerID.Hex(), "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to retrieve profile")
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Profile retrieved successfully", profile)
+}
+
+// ListMowerReviews returns a mower's most recent reviews. The optional limit
+// query parameter caps how many are returned.
+func (h *ProfileHandler) ListMowerReviews(w http.ResponseWriter, r *http.Request) {
+	mowerID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "mowerID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid mower ID")
+		return
+	}
+	limit := services.DefaultReviewLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 || limit > services.MaxReviewLimit {
+			httpresponse.JSONError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+	}
+
+	reviews, err := h.ReviewService.ListMowerReviews(r.Context(), mowerID, limit)
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			httpresponse.JSONError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("listing mower reviews failed", "mower_id", mowerID.Hex(), "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to retrieve reviews")
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Reviews retrieved successfully", reviews)
+}
+
+// Routes returns the profile routes, to be mounted at /mowers. They are public,
+// so customers can compare mowers before signing in.
+func (h *ProfileHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/{mowerID}", h.GetMowerProfile)          // GET /api/v1/mowers/{mowerID}
+	r.Get("/{mowerID}/reviews", h.ListMowerReviews) // GET /api/v1/mowers/{mowerID}/reviews
+
+	return r
+}
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	httpresponse "lawnconnect-api/internal/api/http"
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/core/services"
+	"lawnconnect-api/internal/infrastructure/logging"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxPhotoBytes bounds the size of an uploaded property or comment photo.
+const maxPhotoBytes = 10 << 20
+
+// photoTypes are the accepted photo content types, as sniffed from the upload.
+var photoTypes = map[string]bool{"image/jpeg": true, "image/png": true, "image/webp": true}
+
+// PropertyHandler handles HTTP requests for customers' properties.
+type PropertyHandler struct {
+	PropertyService services.PropertyService
+}
+
+// NewPropertyHandler creates a new PropertyHandler.
+func NewPropertyHandler(propertySrv services.PropertyService) *PropertyHandler {
+	return &PropertyHandler{PropertyService: propertySrv}
+}
+
+// propertyRequest is the body of property create and update requests.
+type propertyRequest struct {
+	Label       string               `json:"label"`
+	Address     domain.PostalAddress `json:"address"`
+	Region      string               `json:"region"`
+	LawnArea    float64              `json:"lawnArea"` // In square metres
+	Terrain     string               `json:"terrain"`
+	ServiceType string               `json:"serviceType"`
+	AccessNotes string               `json:"accessNotes"`
+	Pets        string               `json:"pets"`
+}
+
+func (p propertyRequest) input() services.PropertyInput {
+	return services.PropertyInput{
+		Label:       p.Label,
+		Address:     p.Address,
+		Region:      p.Region,
+		LawnArea:    p.LawnArea,
+		Terrain:     p.Terrain,
+		ServiceType: p.ServiceType,
+		AccessNotes: p.AccessNotes,
+		Pets:        p.Pets,
+	}
+}
+
+// CreateProperty registers a property for the authenticated customer.
+func (h *PropertyHandler) CreateProperty(w http.ResponseWriter, r *http.Request) {
+	var reqBody propertyRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	customerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	property, err := h.PropertyService.CreateProperty(r.Context(), customerID, reqBody.input())
+	if err != nil {
+		h.propertyError(w, r, "creating property failed", err)
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusCreated, "Property created successfully", property)
+}
+
+// ListProperties lists the authenticated customer's properties.
+func (h *PropertyHandler) ListProperties(w http.ResponseWriter, r *http.Request) {
+	customerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	properties, err := h.PropertyService.ListProperties(r.Context(), customerID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("listing properties failed", "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to retrieve properties")
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Properties retrieved successfully", properties)
+}
+
+// GetProperty returns one of the authenticated customer's properties.
+func (h *PropertyHandler) GetProperty(w http.ResponseWriter, r *http.Request) {
+	propertyID, ok := propertyIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	customerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	property, err := h.PropertyService.GetProperty(r.Context(), customerID, propertyID)
+	if err != nil {
+		h.propertyError(w, r, "getting property failed", err)
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Property retrieved successfully", property)
+}
+
+// UpdateProperty replaces the details of one of the customer's properties.
+func (h *PropertyHandler) UpdateProperty(w http.ResponseWriter, r *http.Request) {
+	propertyID, ok := propertyIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var reqBody propertyRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	customerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	property, err := h.PropertyService.UpdateProperty(r.Context(), customerID, propertyID, reqBody.input())
+	if err != nil {
+		h.propertyError(w, r, "updating property failed", err)
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Property updated successfully", property)
+}
+
+// DeleteProperty archives one of the customer's properties.
+func (h *PropertyHandler) DeleteProperty(w http.ResponseWriter, r *http.Request) {
+	propertyID, ok := propertyIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	customerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	if err := h.PropertyService.ArchiveProperty(r.Context(), customerID, propertyID); err != nil {
+		h.propertyError(w, r, "deleting property failed", err)
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Property deleted successfully", nil)
+}
+
+// EstimatePrice returns the price range for a visit to one of the customer's
+// properties on the `date` query parameter (YYYY-MM-DD), for the `service` query
+// parameter or the property's service type.
+func (h *PropertyHandler) EstimatePrice(w http.ResponseWriter, r *http.Request) {
+	propertyID, ok := propertyIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	customerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	query := r.URL.Query()
+	estimate, err := h.PropertyService.EstimatePrice(r.Context(), customerID, propertyID, query.Get("service"), query.Get("date"))
+	if err != nil {
+		h.propertyError(w, r, "estimating price failed", err)
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Price estimated successfully", estimate)
+}
+
+// AddPhoto uploads a JPEG, PNG or WebP photo of the property, sent as the
+// `photo` field of a multipart form.
+func (h *PropertyHandler) AddPhoto(w http.ResponseWriter, r *http.Request) {
+	propertyID, ok := propertyIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxPhotoBytes+(1<<20))
+	file, header, err := r.FormFile("photo")
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "A photo of at most 10 MB is required")
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, maxPhotoBytes+1))
+	if err != nil || len(content) > maxPhotoBytes {
+		httpresponse.JSONError(w, http.StatusBadRequest, "A photo of at most 10 MB is required")
+		return
+	}
+	if !photoTypes[http.DetectContentType(content)] {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Photos must be JPEG, PNG or WebP images")
+		return
+	}
+
+	customerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	property, err := h.PropertyService.AddPhoto(r.Context(), customerID, propertyID, bytes.NewReader(content), header.Filename)
+	if err != nil {
+		h.propertyError(w, r, "adding property photo failed", err)
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusCreated, "Photo added successfully", property)
+}
+
+// RemovePhoto removes the property photo at the `index` path parameter.
+func (h *PropertyHandler) RemovePhoto(w http.ResponseWriter, r *http.Request) {
+	propertyID, ok := propertyIDParam(w, r)
+	if !ok {
+		return
+	}
+	index, err := strconv.Atoi(chi.URLParam(r, "index"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid photo index")
+		return
+	}
+
+	customerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	property, err := h.PropertyService.RemovePhoto(r.Context(), customerID, propertyID, index)
+	if err != nil {
+		h.propertyError(w, r, "removing property photo failed", err)
+		return
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Photo removed successfully", property)
+}
+
+func propertyIDParam(w http.ResponseWriter, r *http.Request) (primitive.ObjectID, bool) {
+	propertyID, err := primitive.ObjectIDFromHex(chi.URLParam(r, "propertyID"))
+	if err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid property ID")
+		return primitive.NilObjectID, false
+	}
+	return propertyID, true
+}
+
+func (h *PropertyHandler) propertyError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	switch err.(type) {
+	case apperror.NotFound:
+		httpresponse.JSONError(w, http.StatusNotFound, err.Error())
+	case apperror.CustomError, apperror.InvalidResource:
+		httpresponse.JSONError(w, http.StatusBadRequest, err.Error())
+	default:
+		logging.FromContext(r.Context()).Error(msg, "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to process property")
+	}
+}
+
+// Routes returns the property routes, to be mounted at /properties behind
+// AuthMiddleware. Only customers have properties.
+func (h *PropertyHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Use(RoleMiddleware("customer"))
+
+	r.Post("/", h.CreateProperty)                           // POST /api/v1/properties
+	r.Get("/", h.ListProperties)                            // GET /api/v1/properties
+	r.Get("/{propertyID}", h.GetProperty)                   // GET /api/v1/properties/{propertyID}
+	r.Put("/{propertyID}", h.UpdateProperty)                // PUT /api/v1/properties/{propertyID}
+	r.Delete("/{propertyID}", h.DeleteProperty)             // DELETE /api/v1/properties/{propertyID}
+	r.Get("/{propertyID}/estimate", h.EstimatePrice)        // GET /api/v1/properties/{propertyID}/estimate
+	r.Post("/{propertyID}/photos", h.AddPhoto)              // POST /api/v1/properties/{propertyID}/photos
+	r.Delete("/{propertyID}/photos/{index}", h.RemovePhoto) // DELETE /api/v1/properties/{propertyID}/photos/{index}
+	return r
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	httpresponse "lawnconnect-api/internal/api/http"
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/core/services"
+	"lawnconnect-api/internal/infrastructure/logging"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WalletHandler handles HTTP requests for mower wallets and payouts.
+type WalletHandler struct {
+	LedgerService services.LedgerService
+	PayoutService services.PayoutService
+}
+
+// NewWalletHandler creates a new WalletHandler.
+func NewWalletHandler(ledgerSrv services.LedgerService, payoutSrv services.PayoutService) *WalletHandler {
+	return &WalletHandler{LedgerService: ledgerSrv, PayoutService: payoutSrv}
+}
+
+// Statement returns the authenticated mower's ledger entries and balance.
+func (h *WalletHandler) Statement(w http.ResponseWriter, r *http.Request) {
+	mowerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	statement, err := h.LedgerService.Statement(r.Context(), mowerID)
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			httpresponse.JSONError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("loading wallet statement failed", "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to retrieve wallet statement")
+		return
+	}
+
+	if !statement.Reconciled {
+		logging.FromContext(r.Context()).Warn("wallet balance does not match ledger",
+			"ledger_balance", statement.Balance, "cached_balance", statement.CachedBalance)
+	}
+
+	httpresponse.JSONSuccess(w, http.StatusOK, "Wallet statement retrieved successfully", statement)
+}
+
+// SetPayoutDetails registers the bank account the mower's payouts are sent to.
+func (h *WalletHandler) SetPayoutDetails(w http.ResponseWriter, r *http.Request) {
+	mowerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	var reqBody struct {
+		AccountHolder string `json:"accountHolder"`
+		RoutingNumber string `json:"routingNumber"`
+		AccountNumber string `json:"accountNumber"`
+		AccountType   string `json:"accountType"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	details, err := h.PayoutService.SetPayoutDetails(r.Context(), mowerID, domain.PayoutDetails{
+		AccountHolder: reqBody.AccountHolder,
+		RoutingNumber: reqBody.RoutingNumber,
+		AccountNumber: reqBody.AccountNumber,
+		AccountType:   reqBody.AccountType,
+	})
+	if err != nil {
+		if _, ok := err.(apperror.CustomError); ok {
+			httpresponse.JSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("saving payout details failed", "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to save payout details")
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusOK, "Payout details saved successfully", details)
+}
+
+// PayoutBalance returns how much of the mower's wallet can be withdrawn now.
+func (h *WalletHandler) PayoutBalance(w http.ResponseWriter, r *http.Request) {
+	mowerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	balance, err := h.PayoutService.Balance(r.Context(), mowerID, time.Now())
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			httpresponse.JSONError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("computing payout balance failed", "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to retrieve payout balance")
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusOK, "Payout balance retrieved successfully", balance)
+}
+
+// RequestPayout asks for part of the wallet to be paid out. The amount is in
+// minor units, like the wallet balance.
+func (h *WalletHandler) RequestPayout(w http.ResponseWriter, r *http.Request) {
+	mowerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	var reqBody struct {
+		Amount int64 `json:"amount"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+		httpresponse.JSONError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	request, err := h.PayoutService.RequestPayout(r.Context(), mowerID, reqBody.Amount)
+	if err != nil {
+		if _, ok := err.(apperror.CustomError); ok {
+			httpresponse.JSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("requesting payout failed", "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to request payout")
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusCreated, "Payout requested successfully", request)
+}
+
+// ListPayouts returns the mower's payout requests, newest first.
+func (h *WalletHandler) ListPayouts(w http.ResponseWriter, r *http.Request) {
+	mowerID := r.Context().Value(UserContextKey).(primitive.ObjectID)
+
+	requests, err := h.PayoutService.ListMowerPayouts(r.Context(), mowerID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("listing payouts failed", "error", err)
+		httpresponse.JSONError(w, http.StatusInternalServerError, "Failed to retrieve payouts")
+		return
+	}
+	httpresponse.JSONSuccess(w, http.StatusOK, "Payouts retrieved successfully", requests)
+}
+
+// Routes returns the wallet routes, to be mounted at /wallet behind AuthMiddleware.
+func (h *WalletHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Use(RoleMiddleware("mower"))
+
+	r.Get("/statement", h.Statement)             // GET /api/v1/wallet/statement
+	r.Put("/payout-details", h.SetPayoutDetails) // PUT /api/v1/wallet/payout-details
+	r.Get("/payout-balance", h.PayoutBalance)    // GET /api/v1/wallet/payout-balance
+	r.Post("/payouts", h.RequestPayout)          // POST /api/v1/wallet/payouts
+	r.Get("/payouts", h.ListPayouts)             // GET /api/v1/wallet/payouts
+
+	return r
+}
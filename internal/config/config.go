@@ -0,0 +1,460 @@
+// Package config loads and validates the API's configuration.
+//
+// Values are resolved with the following precedence, highest first:
+//
+//  1. process environment variables
+//  2. variables from the .env file (never overriding the process environment)
+//  3. the optional YAML config file named by CONFIG_FILE
+//  4. defaults declared on the Config fields
+//
+// Each setting is a field tagged with its YAML key, its environment variable and,
+// optionally, a default value, whether it is required and whether it is a secret
+// that must be redacted when the configuration is printed.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"lawnconnect-api/internal/core/domain"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the complete configuration of the API.
+type Config struct {
+	Server     ServerConfig     `yaml:"server"`
+	Mongo      MongoConfig      `yaml:"mongo"`
+	Auth       AuthConfig       `yaml:"auth"`
+	SMTP       SMTPConfig       `yaml:"smtp"`
+	Cloudinary CloudinaryConfig `yaml:"cloudinary"`
+	App        AppConfig        `yaml:"app"`
+	Log        LogConfig        `yaml:"log"`
+	Tracing    TracingConfig    `yaml:"tracing"`
+	Invoice    InvoiceConfig    `yaml:"invoice"`
+	Dunning    DunningConfig    `yaml:"dunning"`
+	Payment    PaymentConfig    `yaml:"payment"`
+	Payout     PayoutConfig     `yaml:"payout"`
+	Pricing    PricingConfig    `yaml:"pricing"`
+	Geocoding  GeocodingConfig  `yaml:"geocoding"`
+	Routing    RoutingConfig    `yaml:"routing"`
+}
+
+// ServerConfig configures the HTTP server.
+type ServerConfig struct {
+	Port              string        `yaml:"port" env:"PORT" default:"8080"`
+	StaticDir         string        `yaml:"staticDir" env:"STATIC_DIR" default:"web"`
+	RequestTimeout    time.Duration `yaml:"requestTimeout" env:"REQUEST_TIMEOUT" default:"60s"`
+	ReadTimeout       time.Duration `yaml:"readTimeout" env:"HTTP_READ_TIMEOUT" default:"30s"`
+	ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout" env:"HTTP_READ_HEADER_TIMEOUT" default:"5s"`
+	WriteTimeout      time.Duration `yaml:"writeTimeout" env:"HTTP_WRITE_TIMEOUT" default:"75s"`
+	IdleTimeout       time.Duration `yaml:"idleTimeout" env:"HTTP_IDLE_TIMEOUT" default:"120s"`
+	MaxHeaderBytes    int           `yaml:"maxHeaderBytes" env:"HTTP_MAX_HEADER_BYTES" default:"1048576"`
+	ShutdownTimeout   time.Duration `yaml:"shutdownTimeout" env:"SHUTDOWN_TIMEOUT" default:"30s"`
+	DrainDelay        time.Duration `yaml:"drainDelay" env:"SHUTDOWN_DRAIN_DELAY" default:"0s"`
+	TLSCertFile       string        `yaml:"tlsCertFile" env:"TLS_CERT_FILE"`
+	TLSKeyFile        string        `yaml:"tlsKeyFile" env:"TLS_KEY_FILE"`
+	// MetricsEnabled serves Prometheus metrics at /metrics.
+	MetricsEnabled bool `yaml:"metricsEnabled" env:"METRICS_ENABLED" default:"true"`
+}
+
+// MongoConfig configures the MongoDB connection.
+type MongoConfig struct {
+	URI              string        `yaml:"uri" env:"MONGO_URI" required:"true" secret:"true"`
+	Database         string        `yaml:"database" env:"MONGO_DB_NAME" required:"true"`
+	ConnectTimeout   time.Duration `yaml:"connectTimeout" env:"MONGO_CONNECT_TIMEOUT" default:"10s"`
+	MigrateOnStartup bool          `yaml:"migrateOnStartup" env:"MIGRATE_ON_STARTUP" default:"true"`
+	HealthTimeout    time.Duration `yaml:"healthTimeout" env:"MONGO_HEALTH_TIMEOUT" default:"2s"`
+	// AllowNonAtomic lets the API run against a standalone server, where ledger
+	// postings and other multi-document writes are not atomic. Development only.
+	AllowNonAtomic bool `yaml:"allowNonAtomic" env:"MONGO_ALLOW_NON_ATOMIC" default:"false"`
+}
+
+// AuthConfig configures authentication tokens.
+type AuthConfig struct {
+	JWTSecret string        `yaml:"jwtSecret" env:"JWT_SECRET" required:"true" secret:"true"`
+	TokenTTL  time.Duration `yaml:"tokenTTL" env:"JWT_TTL" default:"24h"`
+}
+
+// SMTPConfig configures outgoing email. Email is disabled when Host is empty.
+type SMTPConfig struct {
+	Host     string `yaml:"host" env:"SMTP_HOST"`
+	Port     int    `yaml:"port" env:"SMTP_PORT" default:"465"`
+	User     string `yaml:"user" env:"SMTP_USER"`
+	Password string `yaml:"password" env:"SMTP_PASS" secret:"true"`
+	From     string `yaml:"from" env:"FROM_EMAIL"`
+	// HealthTimeout bounds the SMTP reachability check of the readiness probe.
+	HealthTimeout time.Duration `yaml:"healthTimeout" env:"SMTP_HEALTH_TIMEOUT" default:"3s"`
+}
+
+// Enabled reports whether outgoing email is configured.
+func (c SMTPConfig) Enabled() bool {
+	return c.Host != ""
+}
+
+// CloudinaryConfig configures file uploads.
+type CloudinaryConfig struct {
+	URL           string        `yaml:"url" env:"CLOUDINARY_URL" secret:"true"`
+	HealthTimeout time.Duration `yaml:"healthTimeout" env:"CLOUDINARY_HEALTH_TIMEOUT" default:"3s"`
+}
+
+// AppConfig holds application-level settings.
+type AppConfig struct {
+	TemplatesPath string `yaml:"templatesPath" env:"TEMPLATES_PATH" default:"./templates"`
+	LoginURL      string `yaml:"loginUrl" env:"LOGIN_URL" default:"http://localhost:8080/login"`
+	// Currency is the ISO 4217 code of every ledger amount.
+	Currency string `yaml:"currency" env:"CURRENCY" default:"USD"`
+	// CommissionDefaultBps is the platform's cut, in basis points, when no
+	// commission rule applies.
+	CommissionDefaultBps int64 `yaml:"commissionDefaultBps" env:"COMMISSION_DEFAULT_BPS" default:"0"`
+	// QuoteToleranceBps is how far, in basis points of the approved quote, a
+	// mower may adjust the price when completing a booking.
+	QuoteToleranceBps int64 `yaml:"quoteToleranceBps" env:"QUOTE_TOLERANCE_BPS" default:"1000"`
+	// ReviewWindow is how long after completion a customer may review a
+	// booking.
+	ReviewWindow time.Duration `yaml:"reviewWindow" env:"REVIEW_WINDOW" default:"336h"`
+	// CommentEditWindow and CommentDeleteWindow are how long after posting the
+	// author may edit or delete a booking comment.
+	CommentEditWindow   time.Duration `yaml:"commentEditWindow" env:"COMMENT_EDIT_WINDOW" default:"15m"`
+	CommentDeleteWindow time.Duration `yaml:"commentDeleteWindow" env:"COMMENT_DELETE_WINDOW" default:"1h"`
+}
+
+// LogConfig configures structured logging.
+type LogConfig struct {
+	// Level is one of debug, info, warn or error.
+	Level string `yaml:"level" env:"LOG_LEVEL" default:"info"`
+	// Format is "text" for humans or "json" for log shippers.
+	Format string `yaml:"format" env:"LOG_FORMAT" default:"text"`
+}
+
+// TracingConfig configures OpenTelemetry trace export. The environment variables
+// follow the OpenTelemetry SDK conventions.
+type TracingConfig struct {
+	// Exporter is none, otlp or stdout.
+	Exporter    string  `yaml:"exporter" env:"OTEL_TRACES_EXPORTER" default:"none"`
+	Endpoint    string  `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	ServiceName string  `yaml:"serviceName" env:"OTEL_SERVICE_NAME" default:"lawnconnect-api"`
+	SampleRatio float64 `yaml:"sampleRatio" env:"OTEL_TRACES_SAMPLER_ARG" default:"1"`
+}
+
+// InvoiceConfig configures the invoices emailed to customers.
+type InvoiceConfig struct {
+	// Prefix starts every invoice number, e.g. INV-2026-000042.
+	Prefix        string `yaml:"prefix" env:"INVOICE_PREFIX" default:"INV"`
+	IssuerName    string `yaml:"issuerName" env:"INVOICE_ISSUER_NAME" default:"LawnConnect"`
+	IssuerAddress string `yaml:"issuerAddress" env:"INVOICE_ISSUER_ADDRESS"`
+}
+
+// DunningConfig configures the payment reminder job.
+type DunningConfig struct {
+	Enabled bool `yaml:"enabled" env:"DUNNING_ENABLED" default:"true"`
+	// Interval is how often the job looks for due reminders.
+	Interval time.Duration `yaml:"interval" env:"DUNNING_INTERVAL" default:"1h"`
+	// Thresholds are the ages of an unpaid invoice at which each reminder is sent;
+	// the last one is the final notice and flags the customer as overdue.
+	Thresholds []time.Duration `yaml:"thresholds" env:"DUNNING_THRESHOLDS" default:"72h,168h,336h"`
+	// OverduePolicy is "warn" to accept and flag bookings from overdue customers,
+	// or "block" to refuse them.
+	OverduePolicy string `yaml:"overduePolicy" env:"DUNNING_OVERDUE_POLICY" default:"warn"`
+	// RetryBackoff is how long a failed reminder waits before it is retried,
+	// doubling with each further failure.
+	RetryBackoff time.Duration `yaml:"retryBackoff" env:"DUNNING_RETRY_BACKOFF" default:"1h"`
+	// MaxFailedSends stops reminding an invoice after this many failed sends in a row.
+	MaxFailedSends int `yaml:"maxFailedSends" env:"DUNNING_MAX_FAILED_SENDS" default:"5"`
+}
+
+// PaymentConfig configures card payments.
+type PaymentConfig struct {
+	// Provider is the payment processor: "none", which disables card payments so
+	// every booking is paid by invoice, or "fake", which keeps payments in the
+	// memory of one instance and never charges a card.
+	Provider string `yaml:"provider" env:"PAYMENT_PROVIDER" default:"none"`
+	// WebhookSecret verifies the signatures of the provider's webhooks. It is
+	// required when card payments are enabled.
+	WebhookSecret string `yaml:"webhookSecret" env:"PAYMENT_WEBHOOK_SECRET" secret:"true"`
+	// AuthorizationAmount is the hold placed on a card when a booking is made, in
+	// minor units. The price set on completion may not exceed it.
+	AuthorizationAmount int64 `yaml:"authorizationAmount" env:"PAYMENT_AUTHORIZATION_AMOUNT" default:"15000"`
+}
+
+// GeocodingConfig configures how addresses are located, how far mowers look for
+// work and what happens to bookings outside every service area.
+type GeocodingConfig struct {
+	// Provider is "fake", which places unknown addresses at stable points around
+	// FakeCenterLat/FakeCenterLng, or "file", which looks addresses up in the CSV
+	// table at File.
+	Provider      string  `yaml:"provider" env:"GEOCODER_PROVIDER" default:"fake"`
+	File          string  `yaml:"file" env:"GEOCODER_FILE"`
+	FakeCenterLat float64 `yaml:"fakeCenterLat" env:"GEOCODER_FAKE_CENTER_LAT" default:"40.7128"`
+	FakeCenterLng float64 `yaml:"fakeCenterLng" env:"GEOCODER_FAKE_CENTER_LNG" default:"-74.006"`
+	// MaxRadiusKm bounds the service radius a mower may set.
+	MaxRadiusKm float64 `yaml:"maxRadiusKm" env:"MOWER_MAX_RADIUS_KM" default:"100"`
+	// OutOfAreaPolicy is "waitlist" to accept bookings outside every service
+	// area onto a waitlist, released when an area covering them is created, or
+	// "reject" to refuse them.
+	OutOfAreaPolicy string `yaml:"outOfAreaPolicy" env:"OUT_OF_AREA_POLICY" default:"waitlist"`
+}
+
+// RoutingConfig configures the daily routes planned for mowers.
+type RoutingConfig struct {
+	// Provider is "haversine", which estimates travel offline from great-circle
+	// distances lengthened by DetourFactor and driven at AverageSpeedKmh.
+	Provider        string  `yaml:"provider" env:"ROUTING_PROVIDER" default:"haversine"`
+	AverageSpeedKmh float64 `yaml:"averageSpeedKmh" env:"ROUTING_AVERAGE_SPEED_KMH" default:"30"`
+	DetourFactor    float64 `yaml:"detourFactor" env:"ROUTING_DETOUR_FACTOR" default:"1.3"`
+	// DayStart is when a route leaves the mower's base unless the mower asks
+	// for another time, as HH:MM.
+	DayStart string `yaml:"dayStart" env:"ROUTING_DAY_START" default:"08:00"`
+	// JobDuration is how long a job takes when its quote gives no estimate.
+	JobDuration time.Duration `yaml:"jobDuration" env:"ROUTING_JOB_DURATION" default:"1h"`
+	// ArrivalWindow is how long after a booking's time the mower may arrive
+	// without being late.
+	ArrivalWindow time.Duration `yaml:"arrivalWindow" env:"ROUTING_ARRIVAL_WINDOW" default:"2h"`
+}
+
+// PayoutConfig configures mower payouts and the batch payout job.
+type PayoutConfig struct {
+	// MinimumAmount is the smallest payout a mower may request, in minor units.
+	MinimumAmount int64 `yaml:"minimumAmount" env:"PAYOUT_MINIMUM_AMOUNT" default:"2000"`
+	// HoldPeriod is how long new earnings are held before they can be withdrawn.
+	HoldPeriod    time.Duration `yaml:"holdPeriod" env:"PAYOUT_HOLD_PERIOD" default:"72h"`
+	BatchEnabled  bool          `yaml:"batchEnabled" env:"PAYOUT_BATCH_ENABLED" default:"true"`
+	BatchInterval time.Duration `yaml:"batchInterval" env:"PAYOUT_BATCH_INTERVAL" default:"24h"`
+	// The originator fields name the platform in NACHA files. NACHA export is
+	// unavailable until CompanyID and BankRouting are set.
+	CompanyName string `yaml:"companyName" env:"PAYOUT_COMPANY_NAME" default:"LawnConnect"`
+	CompanyID   string `yaml:"companyId" env:"PAYOUT_COMPANY_ID"`
+	BankRouting string `yaml:"bankRouting" env:"PAYOUT_BANK_ROUTING"`
+	BankName    string `yaml:"bankName" env:"PAYOUT_BANK_NAME"`
+}
+
+// Options controls where Load reads configuration from.
+type Options struct {
+	// File is a YAML config file. When empty, the CONFIG_FILE environment variable is used.
+	File string
+	// EnvFiles are dotenv files to load. When nil, ".env" is loaded if present.
+	EnvFiles []string
+}
+
+// ValidationError lists every problem found in a configuration.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Load resolves the configuration from all sources and validates it. When
+// validation fails, the loaded configuration is returned together with a
+// ValidationError so callers can still report on it.
+func Load(opts Options) (*Config, error) {
+	envFiles := opts.EnvFiles
+	if envFiles == nil {
+		envFiles = []string{".env"}
+	}
+	for _, file := range envFiles {
+		if err := godotenv.Load(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to load %s: %w", file, err)
+		}
+	}
+
+	cfg := &Config{}
+	if err := applyDefaults(cfg); err != nil {
+		return nil, err
+	}
+
+	file := opts.File
+	if file == "" {
+		file = os.Getenv("CONFIG_FILE")
+	}
+	if file != "" {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(raw, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", file, err)
+		}
+	}
+	cfg.Pricing.applyDefaultTables()
+
+	var problems []string
+	problems = append(problems, applyEnv(cfg)...)
+	problems = append(problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return cfg, ValidationError{Problems: problems}
+	}
+	return cfg, nil
+}
+
+// validate checks required fields and cross-field rules, collecting every problem.
+func (c *Config) validate() []string {
+	problems := missingRequired(c)
+
+	if c.Server.RequestTimeout <= 0 {
+		problems = append(problems, "server.requestTimeout (REQUEST_TIMEOUT) must be positive")
+	}
+	if c.Server.WriteTimeout > 0 && c.Server.WriteTimeout <= c.Server.RequestTimeout {
+		problems = append(problems, "server.writeTimeout (HTTP_WRITE_TIMEOUT) must be longer than server.requestTimeout so timed-out requests can still be answered")
+	}
+	if c.Server.ReadHeaderTimeout <= 0 || c.Server.ShutdownTimeout <= 0 {
+		problems = append(problems, "server.readHeaderTimeout and server.shutdownTimeout must be positive")
+	}
+	if c.Server.MaxHeaderBytes <= 0 {
+		problems = append(problems, "server.maxHeaderBytes (HTTP_MAX_HEADER_BYTES) must be positive")
+	}
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		problems = append(problems, "server.tlsCertFile (TLS_CERT_FILE) and server.tlsKeyFile (TLS_KEY_FILE) must be set together")
+	}
+	if c.Auth.JWTSecret != "" && len(c.Auth.JWTSecret) < 32 {
+		problems = append(problems, "auth.jwtSecret (JWT_SECRET) must be at least 32 characters")
+	}
+	if len(c.App.Currency) != 3 {
+		problems = append(problems, "app.currency (CURRENCY) must be a three-letter ISO 4217 code")
+	}
+	if c.App.CommissionDefaultBps < 0 || c.App.CommissionDefaultBps > 10000 {
+		problems = append(problems, "app.commissionDefaultBps (COMMISSION_DEFAULT_BPS) must be between 0 and 10000")
+	}
+	if c.App.QuoteToleranceBps < 0 || c.App.QuoteToleranceBps > 10000 {
+		problems = append(problems, "app.quoteToleranceBps (QUOTE_TOLERANCE_BPS) must be between 0 and 10000")
+	}
+	if c.App.ReviewWindow <= 0 {
+		problems = append(problems, "app.reviewWindow (REVIEW_WINDOW) must be positive")
+	}
+	if c.App.CommentEditWindow < 0 {
+		problems = append(problems, "app.commentEditWindow (COMMENT_EDIT_WINDOW) must not be negative")
+	}
+	if c.App.CommentDeleteWindow < 0 {
+		problems = append(problems, "app.commentDeleteWindow (COMMENT_DELETE_WINDOW) must not be negative")
+	}
+	if c.Auth.TokenTTL <= 0 {
+		problems = append(problems, "auth.tokenTTL (JWT_TTL) must be positive")
+	}
+	if c.SMTP.Enabled() {
+		if c.SMTP.Port <= 0 || c.SMTP.Port > 65535 {
+			problems = append(problems, "smtp.port (SMTP_PORT) must be a valid port when SMTP_HOST is set")
+		}
+		if c.SMTP.From == "" {
+			problems = append(problems, "smtp.from (FROM_EMAIL) is required when SMTP_HOST is set")
+		}
+	}
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		problems = append(problems, "log.level (LOG_LEVEL) must be one of debug, info, warn or error")
+	}
+	switch strings.ToLower(c.Log.Format) {
+	case "text", "json":
+	default:
+		problems = append(problems, "log.format (LOG_FORMAT) must be text or json")
+	}
+	switch strings.ToLower(c.Tracing.Exporter) {
+	case "none", "otlp", "stdout":
+	default:
+		problems = append(problems, "tracing.exporter (OTEL_TRACES_EXPORTER) must be none, otlp or stdout")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		problems = append(problems, "tracing.sampleRatio (OTEL_TRACES_SAMPLER_ARG) must be between 0 and 1")
+	}
+	if c.Invoice.Prefix == "" || strings.ContainsAny(c.Invoice.Prefix, " /") {
+		problems = append(problems, "invoice.prefix (INVOICE_PREFIX) must be set and contain no spaces or slashes")
+	}
+	if c.Invoice.IssuerName == "" {
+		problems = append(problems, "invoice.issuerName (INVOICE_ISSUER_NAME) must be set")
+	}
+	if c.Dunning.Enabled && c.Dunning.Interval <= 0 {
+		problems = append(problems, "dunning.interval (DUNNING_INTERVAL) must be positive")
+	}
+	for i, threshold := range c.Dunning.Thresholds {
+		if threshold <= 0 || (i > 0 && threshold <= c.Dunning.Thresholds[i-1]) {
+			problems = append(problems, "dunning.thresholds (DUNNING_THRESHOLDS) must be positive and increasing")
+			break
+		}
+	}
+	if c.Dunning.RetryBackoff <= 0 {
+		problems = append(problems, "dunning.retryBackoff (DUNNING_RETRY_BACKOFF) must be positive")
+	}
+	if c.Dunning.MaxFailedSends < 1 {
+		problems = append(problems, "dunning.maxFailedSends (DUNNING_MAX_FAILED_SENDS) must be at least 1")
+	}
+	switch c.Dunning.OverduePolicy {
+	case "warn", "block":
+	default:
+		problems = append(problems, "dunning.overduePolicy (DUNNING_OVERDUE_POLICY) must be warn or block")
+	}
+	switch c.Payment.Provider {
+	case "none":
+	case "fake":
+		if c.Payment.WebhookSecret == "" {
+			problems = append(problems, "payment.webhookSecret (PAYMENT_WEBHOOK_SECRET) must be set when card payments are enabled")
+		}
+	default:
+		problems = append(problems, "payment.provider (PAYMENT_PROVIDER) must be none or fake")
+	}
+	if c.Payment.WebhookSecret != "" && len(c.Payment.WebhookSecret) < 16 {
+		problems = append(problems, "payment.webhookSecret (PAYMENT_WEBHOOK_SECRET) must be at least 16 characters")
+	}
+	if c.Payment.AuthorizationAmount <= 0 {
+		problems = append(problems, "payment.authorizationAmount (PAYMENT_AUTHORIZATION_AMOUNT) must be positive")
+	}
+	if c.Payout.MinimumAmount <= 0 {
+		problems = append(problems, "payout.minimumAmount (PAYOUT_MINIMUM_AMOUNT) must be positive")
+	}
+	if c.Payout.HoldPeriod < 0 {
+		problems = append(problems, "payout.holdPeriod (PAYOUT_HOLD_PERIOD) must not be negative")
+	}
+	if c.Payout.BatchEnabled && c.Payout.BatchInterval <= 0 {
+		problems = append(problems, "payout.batchInterval (PAYOUT_BATCH_INTERVAL) must be positive")
+	}
+	if len(c.Payout.CompanyID) > 10 {
+		problems = append(problems, "payout.companyId (PAYOUT_COMPANY_ID) must be at most 10 characters")
+	}
+	if c.Payout.BankRouting != "" && !domain.ValidRoutingNumber(c.Payout.BankRouting) {
+		problems = append(problems, "payout.bankRouting (PAYOUT_BANK_ROUTING) must be a valid nine-digit routing number")
+	}
+	if (c.Payout.CompanyID == "") != (c.Payout.BankRouting == "") {
+		problems = append(problems, "payout.companyId (PAYOUT_COMPANY_ID) and payout.bankRouting (PAYOUT_BANK_ROUTING) must be set together")
+	}
+	problems = append(problems, c.Pricing.validate()...)
+	switch c.Geocoding.Provider {
+	case "fake":
+	case "file":
+		if c.Geocoding.File == "" {
+			problems = append(problems, "geocoding.file (GEOCODER_FILE) must be set for the file provider")
+		}
+	default:
+		problems = append(problems, "geocoding.provider (GEOCODER_PROVIDER) must be fake or file")
+	}
+	if c.Geocoding.MaxRadiusKm <= 0 {
+		problems = append(problems, "geocoding.maxRadiusKm (MOWER_MAX_RADIUS_KM) must be positive")
+	}
+	switch c.Geocoding.OutOfAreaPolicy {
+	case "waitlist", "reject":
+	default:
+		problems = append(problems, "geocoding.outOfAreaPolicy (OUT_OF_AREA_POLICY) must be waitlist or reject")
+	}
+	if c.Routing.Provider != "haversine" {
+		problems = append(problems, "routing.provider (ROUTING_PROVIDER) must be haversine")
+	}
+	if c.Routing.AverageSpeedKmh <= 0 {
+		problems = append(problems, "routing.averageSpeedKmh (ROUTING_AVERAGE_SPEED_KMH) must be positive")
+	}
+	if c.Routing.DetourFactor < 1 {
+		problems = append(problems, "routing.detourFactor (ROUTING_DETOUR_FACTOR) must be at least 1")
+	}
+	if _, err := time.Parse("15:04", c.Routing.DayStart); err != nil {
+		problems = append(problems, "routing.dayStart (ROUTING_DAY_START) must be a time of day as HH:MM")
+	}
+	if c.Routing.JobDuration <= 0 {
+		problems = append(problems, "routing.jobDuration (ROUTING_JOB_DURATION) must be positive")
+	}
+	if c.Routing.ArrivalWindow < 0 {
+		problems = append(problems, "routing.arrivalWindow (ROUTING_ARRIVAL_WINDOW) must not be negative")
+	}
+	return problems
+}
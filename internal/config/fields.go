@@ -0,0 +1,168 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field is a leaf setting discovered by walking the Config struct.
+type field struct {
+	path  string // dotted YAML path, e.g. "smtp.port"
+	env   string
+	tag   reflect.StructTag
+	value reflect.Value
+}
+
+// fields returns every leaf setting of cfg in declaration order.
+func fields(cfg *Config) []field {
+	var out []field
+	var walk func(prefix string, v reflect.Value)
+	walk = func(prefix string, v reflect.Value) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			name := strings.Split(sf.Tag.Get("yaml"), ",")[0]
+			if name == "" {
+				name = sf.Name
+			}
+			if prefix != "" {
+				name = prefix + "." + name
+			}
+
+			fv := v.Field(i)
+			if sf.Type.Kind() == reflect.Struct && sf.Type != reflect.TypeOf(time.Duration(0)) {
+				walk(name, fv)
+				continue
+			}
+			out = append(out, field{path: name, env: sf.Tag.Get("env"), tag: sf.Tag, value: fv})
+		}
+	}
+	walk("", reflect.ValueOf(cfg).Elem())
+	return out
+}
+
+// applyDefaults sets every field that declares a default.
+func applyDefaults(cfg *Config) error {
+	for _, f := range fields(cfg) {
+		def, ok := f.tag.Lookup("default")
+		if !ok {
+			continue
+		}
+		if err := setValue(f.value, def); err != nil {
+			return fmt.Errorf("invalid default for %s: %w", f.path, err)
+		}
+	}
+	return nil
+}
+
+// applyEnv overrides fields from the environment and reports unparsable values.
+func applyEnv(cfg *Config) []string {
+	var problems []string
+	for _, f := range fields(cfg) {
+		if f.env == "" {
+			continue
+		}
+		raw, ok := os.LookupEnv(f.env)
+		if !ok {
+			continue
+		}
+		if err := setValue(f.value, raw); err != nil {
+			problems = append(problems, fmt.Sprintf("%s (%s): %v", f.path, f.env, err))
+		}
+	}
+	return problems
+}
+
+// missingRequired lists required fields that are still empty.
+func missingRequired(cfg *Config) []string {
+	var problems []string
+	for _, f := range fields(cfg) {
+		if f.tag.Get("required") == "true" && f.value.IsZero() {
+			problems = append(problems, fmt.Sprintf("%s (%s) is required", f.path, f.env))
+		}
+	}
+	return problems
+}
+
+// setValue parses raw into the field according to its type.
+func setValue(v reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration", raw)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", raw)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", raw)
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Slice {
+			return fmt.Errorf("unsupported list type %s", v.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		if len(items) == 0 {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		list := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setValue(list.Index(i), item); err != nil {
+				return err
+			}
+		}
+		v.Set(list)
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
+
+// String renders the configuration one setting per line with secrets redacted,
+// so it is safe to print in logs and from `config check`.
+func (c *Config) String() string {
+	var b strings.Builder
+	for _, f := range fields(c) {
+		value := fmt.Sprint(f.value.Interface())
+		if f.tag.Get("secret") == "true" && !f.value.IsZero() {
+			value = "[REDACTED]"
+		}
+		if f.env != "" {
+			fmt.Fprintf(&b, "%-26s %-24s %s\n", f.path, f.env, value)
+		} else {
+			fmt.Fprintf(&b, "%-26s %-24s %s\n", f.path, "-", value)
+		}
+	}
+	return b.String()
+}
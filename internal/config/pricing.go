@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"lawnconnect-api/internal/core/domain"
+)
+
+// PricingConfig configures the price estimates shown to customers when they
+// book. The rate tables (services, regions and terrain) can only be set in the
+// YAML config file; the built-in tables are used for any that are left out.
+type PricingConfig struct {
+	// RangeBps widens each estimate into a range, in basis points either side
+	// of the most likely price.
+	RangeBps          int64   `yaml:"rangeBps" env:"PRICING_RANGE_BPS" default:"1500"`
+	WeekendMultiplier float64 `yaml:"weekendMultiplier" env:"PRICING_WEEKEND_MULTIPLIER" default:"1.25"`
+	HolidayMultiplier float64 `yaml:"holidayMultiplier" env:"PRICING_HOLIDAY_MULTIPLIER" default:"1.5"`
+	// Holidays are the dates, as YYYY-MM-DD, that HolidayMultiplier applies to.
+	Holidays []string `yaml:"holidays" env:"PRICING_HOLIDAYS"`
+
+	Services []ServiceRateConfig `yaml:"services"`
+	// Regions and Terrain map a property's region or terrain to a price
+	// multiplier. Properties may only name listed regions.
+	Regions map[string]float64 `yaml:"regions"`
+	Terrain map[string]float64 `yaml:"terrain"`
+}
+
+// ServiceRateConfig prices one service by lawn area.
+type ServiceRateConfig struct {
+	Name string `yaml:"name"`
+	// BaseFee is charged once per visit, in minor units.
+	BaseFee int64 `yaml:"baseFee"`
+	// Bands price the lawn tier by tier, in ascending order of UpTo. The last
+	// band leaves UpTo out and covers any larger lawn.
+	Bands []AreaBandConfig `yaml:"bands"`
+}
+
+// AreaBandConfig is one tier of a service's area pricing.
+type AreaBandConfig struct {
+	UpTo      float64 `yaml:"upTo"`      // In square metres
+	Per100Sqm int64   `yaml:"per100Sqm"` // In minor units per 100 square metres
+}
+
+// defaultServiceRates are used when the config file lists no services.
+var defaultServiceRates = []ServiceRateConfig{
+	{Name: "mowing", BaseFee: 2000, Bands: []AreaBandConfig{{UpTo: 500, Per100Sqm: 600}, {UpTo: 2000, Per100Sqm: 400}, {Per100Sqm: 250}}},
+	{Name: "edging", BaseFee: 1000, Bands: []AreaBandConfig{{UpTo: 500, Per100Sqm: 200}, {Per100Sqm: 100}}},
+	{Name: "aeration", BaseFee: 3000, Bands: []AreaBandConfig{{UpTo: 500, Per100Sqm: 500}, {UpTo: 2000, Per100Sqm: 350}, {Per100Sqm: 200}}},
+}
+
+// defaultTerrain is used when the config file sets no terrain multipliers.
+var defaultTerrain = map[string]float64{
+	domain.TerrainFlat:   1,
+	domain.TerrainSloped: 1.15,
+	domain.TerrainSteep:  1.35,
+}
+
+// applyDefaultTables fills in the built-in rate tables the config file left out.
+func (p *PricingConfig) applyDefaultTables() {
+	if len(p.Services) == 0 {
+		p.Services = defaultServiceRates
+	}
+	if len(p.Terrain) == 0 {
+		p.Terrain = defaultTerrain
+	}
+}
+
+func (p PricingConfig) validate() []string {
+	var problems []string
+	if p.RangeBps < 0 || p.RangeBps >= 10000 {
+		problems = append(problems, "pricing.rangeBps (PRICING_RANGE_BPS) must be between 0 and 9999")
+	}
+	if p.WeekendMultiplier <= 0 {
+		problems = append(problems, "pricing.weekendMultiplier (PRICING_WEEKEND_MULTIPLIER) must be positive")
+	}
+	if p.HolidayMultiplier <= 0 {
+		problems = append(problems, "pricing.holidayMultiplier (PRICING_HOLIDAY_MULTIPLIER) must be positive")
+	}
+	for _, day := range p.Holidays {
+		if _, err := time.Parse("2006-01-02", day); err != nil {
+			problems = append(problems, fmt.Sprintf("pricing.holidays (PRICING_HOLIDAYS) has %q, which is not a YYYY-MM-DD date", day))
+		}
+	}
+
+	seen := make(map[string]bool, len(p.Services))
+	for i, service := range p.Services {
+		path := fmt.Sprintf("pricing.services[%d]", i)
+		switch {
+		case service.Name == "":
+			problems = append(problems, path+" needs a name")
+		case seen[service.Name]:
+			problems = append(problems, fmt.Sprintf("%s repeats service %q", path, service.Name))
+		}
+		seen[service.Name] = true
+		if service.BaseFee < 0 {
+			problems = append(problems, path+".baseFee must not be negative")
+		}
+		if len(service.Bands) == 0 {
+			problems = append(problems, path+".bands must have at least one band")
+		}
+		var previous float64
+		for j, band := range service.Bands {
+			last := j == len(service.Bands)-1
+			if band.Per100Sqm < 0 {
+				problems = append(problems, fmt.Sprintf("%s.bands[%d].per100Sqm must not be negative", path, j))
+			}
+			if last && band.UpTo != 0 {
+				problems = append(problems, fmt.Sprintf("%s.bands[%d] is the last band and must leave upTo out", path, j))
+			}
+			if !last && band.UpTo <= previous {
+				problems = append(problems, fmt.Sprintf("%s.bands[%d].upTo must be larger than the previous band's", path, j))
+			}
+			previous = band.UpTo
+		}
+	}
+
+	for region, m := range p.Regions {
+		if region == "" || m <= 0 {
+			problems = append(problems, fmt.Sprintf("pricing.regions needs a name and a positive multiplier, got %q: %v", region, m))
+		}
+	}
+	for terrain, m := range p.Terrain {
+		switch terrain {
+		case domain.TerrainFlat, domain.TerrainSloped, domain.TerrainSteep:
+		default:
+			problems = append(problems, fmt.Sprintf("pricing.terrain has unknown terrain %q; use flat, sloped or steep", terrain))
+		}
+		if m <= 0 {
+			problems = append(problems, fmt.Sprintf("pricing.terrain.%s must be positive", terrain))
+		}
+	}
+	return problems
+}
@@ -59,6 +59,12 @@ type (
 	NotFound struct {
 		Resource string
 	}
+
+	// PaymentRequired represents a request refused until the user pays, such as
+	// a declined card.
+	PaymentRequired struct {
+		Message string
+	}
 )
 
 func (e UserError) Error() string {
@@ -93,6 +99,10 @@ func (e NotFound) Error() string {
 	return fmt.Sprintf("%s not found", e.Resource)
 }
 
+func (e PaymentRequired) Error() string {
+	return e.Message
+}
+
 func (e InvalidResource) Error() string {
 	return fmt.Sprintf("your %s is invalid", e.Resource)
 }
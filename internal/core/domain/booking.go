@@ -14,29 +14,46 @@ type Booking struct {
 	Date                 string             `bson:"date" json:"date" validate:"required"`       // YYYY-MM-DD
 	Time                 string             `bson:"time" json:"time" validate:"required"`       // HH:MM
 	Address              string             `bson:"address" json:"address" validate:"required"`
+	Location             *GeoPoint          `bson:"location,omitempty" json:"location,omitempty"`           // Geocoded from Address
+	DistanceKm           float64            `bson:"distanceKm,omitempty" json:"distanceKm,omitempty"`       // From the mower's base; set only in pending job lists
+	ServiceAreaID        primitive.ObjectID `bson:"serviceAreaId,omitempty" json:"serviceAreaId,omitempty"` // Unset while waitlisted outside every area
 	Description          string             `bson:"description,omitempty" json:"description,omitempty"`
-	Status               string             `bson:"status" json:"status" validate:"required,oneof=pending accepted ongoing completed cancelled rejected"`
+	Status               string             `bson:"status" json:"status" validate:"required,oneof=waitlisted pending accepted ongoing completed cancelled rejected"`
 	Price                float64            `bson:"price" json:"price"`
 	BillingStatus        string             `bson:"billingStatus" json:"billingStatus" validate:"required,oneof=pending billed paid"`
 	Rating               int                `bson:"rating,omitempty" json:"rating,omitempty"` // Overall rating for the booking
-	Comments             []BookingComment   `bson:"comments,omitempty" json:"comments,omitempty"` // New array for all comments
+	Comments             []BookingComment   `bson:"comments,omitempty" json:"-"`              // Read through the comment thread only
 	AcceptedTime         *time.Time         `bson:"acceptedTime,omitempty" json:"acceptedTime,omitempty"`
 	OngoingTime          *time.Time         `bson:"ongoingTime,omitempty" json:"ongoingTime,omitempty"`
 	CompletedTime        *time.Time         `bson:"completedTime,omitempty" json:"completedTime,omitempty"`
 	ProofOfCompletionURL string             `bson:"proofOfCompletionUrl,omitempty" json:"proofOfCompletionUrl,omitempty"`
 	CompletionComment    string             `bson:"completionComment,omitempty" json:"completionComment,omitempty"`
 	RejectionReason      string             `bson:"rejectionReason,omitempty" json:"rejectionReason,omitempty"`
-	PaymentReminderSent  bool               `bson:"paymentReminderSent" json:"paymentReminderSent"` // For invoice simulation
+	PaymentReminderSent  bool               `bson:"paymentReminderSent" json:"paymentReminderSent"`     // For invoice simulation
+	PlatformFee          int64              `bson:"platformFee,omitempty" json:"platformFee,omitempty"` // Commission in minor units, set on completion
+	CommissionRuleID     primitive.ObjectID `bson:"commissionRuleId,omitempty" json:"commissionRuleId,omitempty"`
+	InvoiceID            primitive.ObjectID `bson:"invoiceId,omitempty" json:"invoiceId,omitempty"`             // Set when billed
+	CustomerOverdue      bool               `bson:"customerOverdue,omitempty" json:"customerOverdue,omitempty"` // Customer had overdue invoices when booking
+	Payment              *BookingPayment    `bson:"payment,omitempty" json:"payment,omitempty"`                 // Card payment; nil when paid by invoice
+	PropertyID           primitive.ObjectID `bson:"propertyId,omitempty" json:"propertyId,omitempty"`
+	Estimate             *PriceEstimate     `bson:"estimate,omitempty" json:"estimate,omitempty"`               // Shown to the customer when booking a property
+	Pets                 string             `bson:"pets,omitempty" json:"pets,omitempty"`                       // Copied from the property when booking
+	Quote                *BookingQuote      `bson:"quote,omitempty" json:"quote,omitempty"`                     // Latest quote from a mower
+	PriceAdjustment      string             `bson:"priceAdjustment,omitempty" json:"priceAdjustment,omitempty"` // Why the final price differs from the quote
 	CreatedAt            time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt            time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
 
 // BookingComment represents a single comment on a booking.
 type BookingComment struct {
+	ID          primitive.ObjectID `bson:"id,omitempty" json:"id,omitempty"` // Unset on comments posted before the comment thread
 	UserID      primitive.ObjectID `bson:"userId" json:"userId"`
 	UserName    string             `bson:"userName" json:"userName"`
+	UserRole    string             `bson:"userRole,omitempty" json:"userRole,omitempty"`
 	CommentText string             `bson:"commentText" json:"commentText"`
+	PhotoURLs   []string           `bson:"photoUrls,omitempty" json:"photoUrls,omitempty"`
 	Timestamp   time.Time          `bson:"timestamp" json:"timestamp"`
+	EditedAt    *time.Time         `bson:"editedAt,omitempty" json:"editedAt,omitempty"`
 	IsRating    bool               `bson:"isRating,omitempty" json:"isRating,omitempty"` // Indicates if this comment is also a rating comment
 	Rating      int                `bson:"rating,omitempty" json:"rating,omitempty"`     // Rating if IsRating is true
-}
\ No newline at end of file
+}
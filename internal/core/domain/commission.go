@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CommissionRule sets the platform's share of a completed booking. A rule charges
+// PercentageBps of the booking amount plus FlatFee; either may be zero, so a rule
+// with both at zero is a zero-commission promotion.
+type CommissionRule struct {
+	ID   primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name string             `bson:"name" json:"name"`
+	// PercentageBps is the percentage in basis points: 1500 is 15%.
+	PercentageBps int64 `bson:"percentageBps" json:"percentageBps"`
+	// FlatFee is charged per booking, in minor units.
+	FlatFee int64 `bson:"flatFee" json:"flatFee"`
+	// MowerID limits the rule to one mower; unset applies to every mower.
+	MowerID primitive.ObjectID `bson:"mowerId,omitempty" json:"mowerId,omitempty"`
+	// ValidFrom and ValidUntil bound a promotional rule; a rule without either is
+	// a standing rule.
+	ValidFrom  *time.Time         `bson:"validFrom,omitempty" json:"validFrom,omitempty"`
+	ValidUntil *time.Time         `bson:"validUntil,omitempty" json:"validUntil,omitempty"`
+	Active     bool               `bson:"active" json:"active"`
+	CreatedBy  primitive.ObjectID `bson:"createdBy,omitempty" json:"createdBy,omitempty"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt  time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// IsPromotion reports whether the rule only applies within a time window.
+func (r *CommissionRule) IsPromotion() bool {
+	return r.ValidFrom != nil || r.ValidUntil != nil
+}
+
+// AppliesTo reports whether the rule is in force for the mower at time t.
+func (r *CommissionRule) AppliesTo(mowerID primitive.ObjectID, t time.Time) bool {
+	if !r.Active {
+		return false
+	}
+	if !r.MowerID.IsZero() && r.MowerID != mowerID {
+		return false
+	}
+	if r.ValidFrom != nil && t.Before(*r.ValidFrom) {
+		return false
+	}
+	if r.ValidUntil != nil && !t.Before(*r.ValidUntil) {
+		return false
+	}
+	return true
+}
+
+// Fee returns the commission on amount, rounded half up and never more than amount.
+func (r *CommissionRule) Fee(amount int64) int64 {
+	fee := (amount*r.PercentageBps+5000)/10000 + r.FlatFee
+	if fee > amount {
+		return amount
+	}
+	if fee < 0 {
+		return 0
+	}
+	return fee
+}
@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeEmail returns the canonical form of an email address used for storage
+// and lookups: surrounding whitespace is trimmed, the address is converted to
+// Unicode NFC so visually identical addresses compare equal, and it is lowercased.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(norm.NFC.String(strings.TrimSpace(email)))
+}
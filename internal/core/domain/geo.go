@@ -0,0 +1,94 @@
+package domain
+
+import "math"
+
+// earthRadiusKm is the mean radius of the Earth, as used by MongoDB's spherical
+// geometry.
+const earthRadiusKm = 6378.1
+
+// GeoPoint is a GeoJSON point, stored so MongoDB can index it with 2dsphere.
+// Coordinates are longitude then latitude, as GeoJSON requires.
+type GeoPoint struct {
+	Type        string    `bson:"type" json:"type"`
+	Coordinates []float64 `bson:"coordinates" json:"coordinates"`
+}
+
+// NewGeoPoint returns the point at lat, lng in degrees.
+func NewGeoPoint(lat, lng float64) *GeoPoint {
+	return &GeoPoint{Type: "Point", Coordinates: []float64{lng, lat}}
+}
+
+// Lat returns the point's latitude in degrees.
+func (p GeoPoint) Lat() float64 {
+	if len(p.Coordinates) < 2 {
+		return 0
+	}
+	return p.Coordinates[1]
+}
+
+// Lng returns the point's longitude in degrees.
+func (p GeoPoint) Lng() float64 {
+	if len(p.Coordinates) < 1 {
+		return 0
+	}
+	return p.Coordinates[0]
+}
+
+// DistanceKm returns the great-circle distance between two points.
+func DistanceKm(a, b GeoPoint) float64 {
+	lat1, lat2 := radians(a.Lat()), radians(b.Lat())
+	dLat, dLng := lat2-lat1, radians(b.Lng()-a.Lng())
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+// ServiceRadius is where a mower looks for work: jobs within RadiusKm of
+// BaseLocation.
+type ServiceRadius struct {
+	BaseAddress  string    `bson:"baseAddress,omitempty" json:"baseAddress,omitempty"`
+	BaseLocation *GeoPoint `bson:"baseLocation" json:"baseLocation"`
+	RadiusKm     float64   `bson:"radiusKm" json:"radiusKm"`
+}
+
+// GeoPolygon is a GeoJSON polygon: an outer ring followed by any holes. Each
+// ring is closed, its last position repeating the first, and positions are
+// longitude then latitude.
+type GeoPolygon struct {
+	Type        string        `bson:"type" json:"type"`
+	Coordinates [][][]float64 `bson:"coordinates" json:"coordinates"`
+}
+
+// Contains reports whether p lies inside the polygon's outer ring and outside
+// its holes. Edges are treated as straight lines in longitude and latitude,
+// which matches MongoDB's spherical edges closely for areas the size of a city.
+func (g GeoPolygon) Contains(p GeoPoint) bool {
+	if len(g.Coordinates) == 0 || !ringContains(g.Coordinates[0], p) {
+		return false
+	}
+	for _, hole := range g.Coordinates[1:] {
+		if ringContains(hole, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// ringContains casts a ray east from p and counts the ring edges it crosses.
+func ringContains(ring [][]float64, p GeoPoint) bool {
+	x, y := p.Lng(), p.Lat()
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		if len(ring[i]) < 2 || len(ring[j]) < 2 {
+			continue
+		}
+		xi, yi, xj, yj := ring[i][0], ring[i][1], ring[j][0], ring[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Booking billing statuses.
+const (
+	BillingPending = "pending"
+	BillingBilled  = "billed"
+	BillingPaid    = "paid"
+)
+
+// Invoice statuses.
+const (
+	InvoiceIssued = "issued"
+	InvoicePaid   = "paid"
+)
+
+// Invoice is the customer's bill for a completed booking. Invoices are numbered
+// sequentially within each calendar year (UTC) and are never edited after issue,
+// apart from being marked paid, so they can be re-rendered at any time.
+type Invoice struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	// Number is the human-readable invoice number, e.g. INV-2026-000042.
+	Number    string             `bson:"number" json:"number"`
+	Year      int                `bson:"year" json:"year"`
+	Sequence  int64              `bson:"sequence" json:"sequence"`
+	BookingID primitive.ObjectID `bson:"bookingId" json:"bookingId"`
+	Status    string             `bson:"status" json:"status"`
+
+	CustomerID     primitive.ObjectID `bson:"customerId" json:"customerId"`
+	CustomerName   string             `bson:"customerName" json:"customerName"`
+	MowerID        primitive.ObjectID `bson:"mowerId" json:"mowerId"`
+	MowerName      string             `bson:"mowerName" json:"mowerName"`
+	ServiceDate    string             `bson:"serviceDate" json:"serviceDate"`
+	ServiceAddress string             `bson:"serviceAddress" json:"serviceAddress"`
+	Description    string             `bson:"description,omitempty" json:"description,omitempty"`
+	// Amount is the total due, in minor units of Currency.
+	Amount   int64  `bson:"amount" json:"amount"`
+	Currency string `bson:"currency" json:"currency"`
+
+	// RemindersSent is the highest payment reminder level sent so far.
+	RemindersSent  int        `bson:"remindersSent" json:"remindersSent"`
+	LastReminderAt *time.Time `bson:"lastReminderAt,omitempty" json:"lastReminderAt,omitempty"`
+	// ReminderFailures counts failed sends since the last successful reminder;
+	// the next attempt waits until NextReminderAt.
+	ReminderFailures int        `bson:"reminderFailures" json:"reminderFailures"`
+	NextReminderAt   *time.Time `bson:"nextReminderAt,omitempty" json:"nextReminderAt,omitempty"`
+	// Overdue is set once the final reminder has been sent.
+	Overdue bool `bson:"overdue" json:"overdue"`
+
+	IssuedAt  time.Time  `bson:"issuedAt" json:"issuedAt"`
+	PaidAt    *time.Time `bson:"paidAt,omitempty" json:"paidAt,omitempty"`
+	CreatedAt time.Time  `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time  `bson:"updatedAt" json:"updatedAt"`
+}
+
+// PaymentReminder records one attempt to remind a customer about an unpaid
+// invoice. Failed attempts are recorded with Error and retried with a backoff.
+type PaymentReminder struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	InvoiceID  primitive.ObjectID `bson:"invoiceId" json:"invoiceId"`
+	BookingID  primitive.ObjectID `bson:"bookingId" json:"bookingId"`
+	CustomerID primitive.ObjectID `bson:"customerId" json:"customerId"`
+	// Level counts up from 1; the last level is the final notice.
+	Level     int       `bson:"level" json:"level"`
+	Final     bool      `bson:"final" json:"final"`
+	Error     string    `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+}
+
+// InvoiceNumber formats an invoice number from its prefix, year and sequence.
+func InvoiceNumber(prefix string, year int, sequence int64) string {
+	return fmt.Sprintf("%s-%d-%06d", prefix, year, sequence)
+}
+
+// FormatMinorUnits renders an amount in minor units as a decimal with the
+// currency's number of decimal places, e.g. 4550 as "45.50 USD" and 4550 as
+// "4550 JPY".
+func FormatMinorUnits(amount int64, currency string) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	exponent := CurrencyExponent(currency)
+	if exponent == 0 {
+		return fmt.Sprintf("%s%d %s", sign, amount, currency)
+	}
+	units := MinorUnitsPerMajor(currency)
+	return fmt.Sprintf("%s%d.%0*d %s", sign, amount/units, exponent, amount%units, currency)
+}
@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LedgerEntryType classifies why money moved.
+type LedgerEntryType string
+
+const (
+	LedgerBookingPayment     LedgerEntryType = "booking_payment"
+	LedgerPlatformCommission LedgerEntryType = "platform_commission"
+	LedgerPayout             LedgerEntryType = "payout"
+	LedgerAdjustment         LedgerEntryType = "adjustment"
+	LedgerRefund             LedgerEntryType = "refund"
+)
+
+// Ledger accounts. Every mower has a wallet account (see MowerAccount); the
+// platform accounts are the other side of the mowers' entries.
+const (
+	// AccountCustomerPayments is money collected from customers.
+	AccountCustomerPayments = "platform:customer_payments"
+	// AccountPlatformRevenue is the platform's earned commission.
+	AccountPlatformRevenue = "platform:revenue"
+	// AccountPayouts is money paid out of the platform to mowers.
+	AccountPayouts = "platform:payouts"
+	// AccountOpeningBalances offsets balances that existed before the ledger.
+	AccountOpeningBalances = "platform:opening_balances"
+
+	mowerAccountPrefix = "mower:"
+)
+
+// MowerAccount is the ledger account holding a mower's wallet.
+func MowerAccount(mowerID primitive.ObjectID) string {
+	return mowerAccountPrefix + mowerID.Hex()
+}
+
+// MowerFromAccount returns the mower owning a wallet account, if it is one.
+func MowerFromAccount(account string) (primitive.ObjectID, bool) {
+	if !strings.HasPrefix(account, mowerAccountPrefix) {
+		return primitive.NilObjectID, false
+	}
+	id, err := primitive.ObjectIDFromHex(strings.TrimPrefix(account, mowerAccountPrefix))
+	return id, err == nil
+}
+
+// LedgerEntry is one immutable posting to an account. Entries are written in
+// balanced groups sharing a TransactionID whose amounts sum to zero, and an
+// account's balance is the sum of its entries' amounts.
+type LedgerEntry struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TransactionID primitive.ObjectID `bson:"transactionId" json:"transactionId"`
+	Account       string             `bson:"account" json:"account"`
+	Type          LedgerEntryType    `bson:"type" json:"type"`
+	// Amount is in minor currency units (cents); positive amounts increase the balance.
+	Amount    int64              `bson:"amount" json:"amount"`
+	Currency  string             `bson:"currency" json:"currency"`
+	BookingID primitive.ObjectID `bson:"bookingId,omitempty" json:"bookingId,omitempty"`
+	Memo      string             `bson:"memo,omitempty" json:"memo,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// WalletStatement is a mower's ledger entries and balance.
+type WalletStatement struct {
+	MowerID  primitive.ObjectID `json:"mowerId"`
+	Currency string             `json:"currency"`
+	// Balance is derived from the entries, in minor units.
+	Balance int64 `json:"balance"`
+	// CachedBalance is User.WalletBalance; it differs from Balance only if the
+	// cached value has drifted from the ledger.
+	CachedBalance int64          `json:"cachedBalance"`
+	Reconciled    bool           `json:"reconciled"`
+	Entries       []*LedgerEntry `json:"entries"`
+}
+
+// Revenue report periods.
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// TruncateToPeriod returns the start of the UTC day, ISO week (Monday) or month
+// containing t.
+func TruncateToPeriod(t time.Time, period string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch period {
+	case PeriodWeek:
+		offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+		return day.AddDate(0, 0, -offset)
+	case PeriodMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// RevenueBucket is the total of an account's entries within one period.
+type RevenueBucket struct {
+	PeriodStart time.Time `bson:"_id" json:"periodStart"`
+	Amount      int64     `bson:"amount" json:"amount"`
+	Entries     int64     `bson:"entries" json:"entries"`
+}
+
+// RevenueReport is the platform's commission revenue aggregated by period.
+type RevenueReport struct {
+	Period   string    `json:"period"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Currency string    `json:"currency"`
+	// Total is the revenue within [From, To).
+	Total int64 `json:"total"`
+	// AllTime is the system_revenue running total.
+	AllTime int64           `json:"allTime"`
+	Buckets []RevenueBucket `json:"buckets"`
+}
+
+// currencyExponents lists the ISO 4217 currencies whose minor unit is not a
+// hundredth. Every other currency has two decimal places.
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// CurrencyExponent returns the number of decimal places in a currency's minor
+// unit, e.g. 2 for USD and 0 for JPY.
+func CurrencyExponent(currency string) int {
+	if exponent, ok := currencyExponents[strings.ToUpper(currency)]; ok {
+		return exponent
+	}
+	return 2
+}
+
+// MinorUnitsPerMajor returns how many minor units make one major unit of a
+// currency, e.g. 100 for USD and 1 for JPY.
+func MinorUnitsPerMajor(currency string) int64 {
+	units := int64(1)
+	for i := 0; i < CurrencyExponent(currency); i++ {
+		units *= 10
+	}
+	return units
+}
+
+// ToMinorUnits converts a decimal amount to minor units of currency, rounding
+// half away from zero. It is only for amounts arriving as decimals at the API
+// boundary.
+func ToMinorUnits(amount float64, currency string) int64 {
+	return int64(math.Round(amount * float64(MinorUnitsPerMajor(currency))))
+}
+
+// FromMinorUnits converts an amount in minor units of currency to a decimal,
+// for the legacy decimal fields the API still returns.
+func FromMinorUnits(amount int64, currency string) float64 {
+	return float64(amount) / float64(MinorUnitsPerMajor(currency))
+}
@@ -4,9 +4,12 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// SystemRevenueMetric is the running total of platform commission, in minor units.
+const SystemRevenueMetric = "system_revenue"
+
 // SystemMetrics is a document to store global metrics like total platform revenue.
 type SystemMetrics struct {
 	ID    primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	Name  string             `bson:"name" json:"name"`   // e.g., "system_revenue"
-	Value float64            `bson:"value" json:"value"` // The actual revenue amount
-}
\ No newline at end of file
+	Value int64              `bson:"value" json:"value"` // The running total, in minor units for money
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Card payment states, as stored on Booking.Payment.Status.
+const (
+	PaymentAuthorized = "authorized"
+	PaymentCaptured   = "captured"
+	PaymentRefunded   = "refunded"
+	PaymentVoided     = "voided" // Authorization released before capture
+	PaymentFailed     = "failed"
+)
+
+// BookingPayment is the card payment behind a booking: authorized when the
+// booking is made, captured when it is completed, and refunded or voided when it
+// is cancelled. Amounts are in minor units.
+type BookingPayment struct {
+	Provider         string    `bson:"provider" json:"provider"`
+	PaymentID        string    `bson:"paymentId" json:"paymentId"`
+	Status           string    `bson:"status" json:"status"`
+	AuthorizedAmount int64     `bson:"authorizedAmount" json:"authorizedAmount"`
+	CapturedAmount   int64     `bson:"capturedAmount,omitempty" json:"capturedAmount,omitempty"`
+	RefundedAmount   int64     `bson:"refundedAmount,omitempty" json:"refundedAmount,omitempty"`
+	Currency         string    `bson:"currency" json:"currency"`
+	FailureReason    string    `bson:"failureReason,omitempty" json:"failureReason,omitempty"`
+	UpdatedAt        time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// PaymentEvent records a processed payment webhook so redelivered events are
+// ignored. ID is the provider name and the provider's event ID.
+type PaymentEvent struct {
+	ID         string             `bson:"_id" json:"id"`
+	Type       string             `bson:"type" json:"type"`
+	PaymentID  string             `bson:"paymentId" json:"paymentId"`
+	BookingID  primitive.ObjectID `bson:"bookingId,omitempty" json:"bookingId,omitempty"`
+	ReceivedAt time.Time          `bson:"receivedAt" json:"receivedAt"`
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Bank account types for payouts.
+const (
+	BankAccountChecking = "checking"
+	BankAccountSavings  = "savings"
+)
+
+// Payout request statuses.
+const (
+	PayoutRequested = "requested"
+	PayoutApproved  = "approved"
+	PayoutRejected  = "rejected"
+	PayoutPaid      = "paid"
+)
+
+// PayoutDetails is the bank account a mower is paid into. The full account
+// number is never returned by the API; AccountLast4 identifies it instead.
+type PayoutDetails struct {
+	AccountHolder string `bson:"accountHolder" json:"accountHolder"`
+	// RoutingNumber is the nine-digit ABA routing number of the mower's bank.
+	RoutingNumber string    `bson:"routingNumber" json:"routingNumber"`
+	AccountNumber string    `bson:"accountNumber" json:"-"`
+	AccountLast4  string    `bson:"accountLast4" json:"accountLast4"`
+	AccountType   string    `bson:"accountType" json:"accountType"`
+	UpdatedAt     time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// ValidRoutingNumber reports whether s is nine digits with a valid ABA check digit.
+func ValidRoutingNumber(s string) bool {
+	if len(s) != 9 {
+		return false
+	}
+	weights := [9]int{3, 7, 1, 3, 7, 1, 3, 7, 1}
+	sum := 0
+	for i := 0; i < 9; i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+		sum += int(s[i]-'0') * weights[i]
+	}
+	return sum%10 == 0
+}
+
+// PayoutRequest is a mower's request to withdraw part of their wallet. The bank
+// details are copied from the mower's profile when the request is made, so a
+// later change of account does not redirect an approved payout.
+type PayoutRequest struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	MowerID     primitive.ObjectID `bson:"mowerId" json:"mowerId"`
+	MowerName   string             `bson:"mowerName" json:"mowerName"`
+	Amount      int64              `bson:"amount" json:"amount"` // In minor units of Currency
+	Currency    string             `bson:"currency" json:"currency"`
+	Destination PayoutDetails      `bson:"destination" json:"destination"`
+	Status      string             `bson:"status" json:"status"`
+	// Open is set while the request is requested or approved; a mower may have
+	// only one open request at a time.
+	Open            bool               `bson:"open,omitempty" json:"-"`
+	ReviewedBy      primitive.ObjectID `bson:"reviewedBy,omitempty" json:"reviewedBy,omitempty"`
+	ReviewedAt      *time.Time         `bson:"reviewedAt,omitempty" json:"reviewedAt,omitempty"`
+	RejectionReason string             `bson:"rejectionReason,omitempty" json:"rejectionReason,omitempty"`
+	// BatchID and TransactionID are set when the request is paid: the batch whose
+	// export carries the transfer, and the ledger transaction debiting the wallet.
+	BatchID       primitive.ObjectID `bson:"batchId,omitempty" json:"batchId,omitempty"`
+	TransactionID primitive.ObjectID `bson:"transactionId,omitempty" json:"transactionId,omitempty"`
+	PaidAt        *time.Time         `bson:"paidAt,omitempty" json:"paidAt,omitempty"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// PayoutBatch groups the payout requests paid together and exported as one
+// bank transfer file.
+type PayoutBatch struct {
+	ID         primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	RequestIDs []primitive.ObjectID `bson:"requestIds" json:"requestIds"`
+	Count      int                  `bson:"count" json:"count"`
+	Total      int64                `bson:"total" json:"total"` // In minor units of Currency
+	Currency   string               `bson:"currency" json:"currency"`
+	// CreatedBy is the admin who ran the batch, or zero for the scheduled job.
+	CreatedBy primitive.ObjectID `bson:"createdBy,omitempty" json:"createdBy,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// PayoutBalance is how much of a mower's wallet can be withdrawn now.
+type PayoutBalance struct {
+	Currency string `json:"currency"`
+	Balance  int64  `json:"balance"`
+	// Held is recent earnings still within the hold period.
+	Held int64 `json:"held"`
+	// Unsettled is earnings from bookings whose invoice is not paid yet. They
+	// are held until it is, however old they are.
+	Unsettled int64 `json:"unsettled"`
+	// Pending is the amount of the mower's open payout request.
+	Pending       int64 `json:"pending"`
+	Available     int64 `json:"available"`
+	MinimumAmount int64 `json:"minimumAmount"`
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Lawn terrains, from cheapest to most expensive to mow.
+const (
+	TerrainFlat   = "flat"
+	TerrainSloped = "sloped"
+	TerrainSteep  = "steep"
+)
+
+// Property is a lawn a customer books work for.
+type Property struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CustomerID primitive.ObjectID `bson:"customerId" json:"customerId"`
+	Label      string             `bson:"label" json:"label"` // The customer's name for the property, e.g. "Elm Street rental"
+	Address    PostalAddress      `bson:"address" json:"address"`
+	Location   *GeoPoint          `bson:"location,omitempty" json:"location,omitempty"` // Geocoded from Address
+	// Region is one of the regions in the pricing rate table, or empty for the
+	// default rates.
+	Region   string  `bson:"region,omitempty" json:"region,omitempty"`
+	LawnArea float64 `bson:"lawnArea" json:"lawnArea"` // In square metres
+	Terrain  string  `bson:"terrain" json:"terrain"`
+	// ServiceType is the service usually booked for the property, as listed in a
+	// mower's User.Services.
+	ServiceType string `bson:"serviceType" json:"serviceType"`
+	// AccessNotes hold gate codes and directions. Mowers only see them once
+	// they are assigned to a booking for the property.
+	AccessNotes string `bson:"accessNotes,omitempty" json:"accessNotes,omitempty"`
+	// Pets warns mowers about animals on the property. It is copied onto
+	// bookings, so mowers see it before accepting.
+	Pets   string   `bson:"pets,omitempty" json:"pets,omitempty"`
+	Photos []string `bson:"photos,omitempty" json:"photos,omitempty"` // Uploaded photo URLs
+	// Archived properties are hidden from the customer but kept for the bookings
+	// that refer to them.
+	Archived  bool      `bson:"archived,omitempty" json:"-"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// PostalAddress is a property's structured address.
+type PostalAddress struct {
+	Line1      string `bson:"line1" json:"line1"`
+	Line2      string `bson:"line2,omitempty" json:"line2,omitempty"`
+	City       string `bson:"city" json:"city"`
+	State      string `bson:"state,omitempty" json:"state,omitempty"`
+	PostalCode string `bson:"postalCode" json:"postalCode"`
+	Country    string `bson:"country,omitempty" json:"country,omitempty"`
+}
+
+// String formats the address on one line, as stored on Booking.Address.
+func (a PostalAddress) String() string {
+	var parts []string
+	for _, part := range []string{a.Line1, a.Line2, a.City, strings.TrimSpace(a.State + " " + a.PostalCode), a.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// PriceEstimate is the price range shown to a customer before booking. Amounts
+// are in minor units of Currency.
+type PriceEstimate struct {
+	Service  string  `bson:"service" json:"service"`
+	Currency string  `bson:"currency" json:"currency"`
+	Low      int64   `bson:"low" json:"low"`
+	High     int64   `bson:"high" json:"high"`
+	Amount   int64   `bson:"amount" json:"amount"` // Most likely price, between Low and High
+	LawnArea float64 `bson:"lawnArea" json:"lawnArea"`
+	Region   string  `bson:"region,omitempty" json:"region,omitempty"`
+	// ServiceArea names the service area the property is in, if any.
+	ServiceArea string `bson:"serviceArea,omitempty" json:"serviceArea,omitempty"`
+	// The multipliers applied to the service's area price.
+	RegionMultiplier  float64   `bson:"regionMultiplier" json:"regionMultiplier"`
+	AreaMultiplier    float64   `bson:"areaMultiplier" json:"areaMultiplier"`
+	TerrainMultiplier float64   `bson:"terrainMultiplier" json:"terrainMultiplier"`
+	DayMultiplier     float64   `bson:"dayMultiplier" json:"dayMultiplier"` // Weekend or holiday surcharge
+	CalculatedAt      time.Time `bson:"calculatedAt" json:"calculatedAt"`
+}
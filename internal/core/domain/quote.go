@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Quote types: a fixed price, or estimated hours at the mower's hourly rate.
+const (
+	QuoteFixed  = "fixed"
+	QuoteHourly = "hourly"
+)
+
+// Quote states, as stored on Booking.Quote.Status.
+const (
+	QuoteProposed = "proposed"
+	QuoteApproved = "approved"
+	QuoteDeclined = "declined"
+)
+
+// BookingQuote is the price a mower offers when accepting a booking. Once the
+// customer approves it, it becomes the booking's price, and completion may only
+// adjust it within the configured tolerance.
+type BookingQuote struct {
+	MowerID primitive.ObjectID `bson:"mowerId" json:"mowerId"`
+	Type    string             `bson:"type" json:"type"`
+	Amount  int64              `bson:"amount" json:"amount"` // In minor units
+	// EstimatedHours and HourlyRate are set for hourly quotes. The rate is copied
+	// from the mower's profile when quoting.
+	EstimatedHours float64    `bson:"estimatedHours,omitempty" json:"estimatedHours,omitempty"`
+	HourlyRate     float64    `bson:"hourlyRate,omitempty" json:"hourlyRate,omitempty"`
+	Note           string     `bson:"note,omitempty" json:"note,omitempty"`
+	Status         string     `bson:"status" json:"status"`
+	DeclineReason  string     `bson:"declineReason,omitempty" json:"declineReason,omitempty"`
+	CreatedAt      time.Time  `bson:"createdAt" json:"createdAt"`
+	RespondedAt    *time.Time `bson:"respondedAt,omitempty" json:"respondedAt,omitempty"`
+}
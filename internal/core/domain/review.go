@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Review is a customer's rating of a completed booking, with an optional reply
+// from the mower who did the work. A booking has at most one review.
+type Review struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	BookingID  primitive.ObjectID `bson:"bookingId" json:"bookingId"`
+	CustomerID primitive.ObjectID `bson:"customerId" json:"customerId"`
+	// CustomerName is the customer's first name. Reviews are public, so the
+	// rest of the name is not kept.
+	CustomerName string             `bson:"customerName" json:"customerName"`
+	MowerID      primitive.ObjectID `bson:"mowerId" json:"mowerId"`
+	Rating       int                `bson:"rating" json:"rating" validate:"min=1,max=5"`
+	Comment      string             `bson:"comment,omitempty" json:"comment,omitempty"`
+	Reply        *ReviewReply       `bson:"reply,omitempty" json:"reply,omitempty"`
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// ReviewReply is the mower's public answer to a review.
+type ReviewReply struct {
+	Text      string    `bson:"text" json:"text"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+}
+
+// RatingSummary aggregates the ratings a mower has received. It is stored on
+// the mower and updated atomically with each new rating.
+type RatingSummary struct {
+	Count   int64   `bson:"count" json:"count"`
+	Total   int64   `bson:"total" json:"-"`         // Sum of the ratings, from which Average is kept
+	Average float64 `bson:"average" json:"average"` // Rounded to two decimals
+	// Distribution counts the ratings by stars, keyed "1" to "5".
+	Distribution map[string]int64 `bson:"distribution" json:"distribution"`
+}
+
+// Add counts rating into the summary.
+func (s *RatingSummary) Add(rating int) {
+	if s.Distribution == nil {
+		s.Distribution = make(map[string]int64)
+	}
+	s.Count++
+	s.Total += int64(rating)
+	s.Distribution[strconv.Itoa(rating)]++
+	// MongoDB's $round rounds halves to even, so the stored average agrees
+	// whichever repository computed it.
+	s.Average = math.RoundToEven(float64(s.Total)/float64(s.Count)*100) / 100
+}
+
+// MowerProfile is the public view of a mower: who they are, what they offer
+// and how customers rated them. It holds no contact details.
+type MowerProfile struct {
+	ID          primitive.ObjectID `json:"id"`
+	Name        string             `json:"name"`
+	ImageUrl    string             `json:"imageUrl,omitempty"`
+	Services    []string           `json:"services,omitempty"`
+	HourlyRate  float64            `json:"hourlyRate"`
+	Ratings     RatingSummary      `json:"ratings"`
+	MemberSince time.Time          `json:"memberSince"`
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Route is the order in which a mower visits their jobs on one day, leaving
+// from and returning to their base. Routes are planned on request and never
+// stored. Times are wall-clock HH:MM on Date, like booking times, and may pass
+// 24:00 when a day runs late.
+type Route struct {
+	Date     string      `json:"date"`
+	Base     *GeoPoint   `json:"base"`
+	DepartAt string      `json:"departAt"`
+	ReturnAt string      `json:"returnAt"`
+	Stops    []RouteStop `json:"stops"`
+	// Unlocated lists the day's bookings that have no geocoded location and so
+	// could not be routed.
+	Unlocated          []primitive.ObjectID `json:"unlocated,omitempty"`
+	TotalDistanceKm    float64              `json:"totalDistanceKm"`
+	TotalTravelMinutes int                  `json:"totalTravelMinutes"`
+	LateStops          int                  `json:"lateStops"` // Stops reached after their window closes
+	Provider           string               `json:"provider"`  // Distance matrix that estimated travel
+}
+
+// RouteStop is one job on a Route. The mower may arrive from WindowStart until
+// WindowEnd; arriving earlier means waiting until WindowStart.
+type RouteStop struct {
+	BookingID     primitive.ObjectID `json:"bookingId"`
+	Address       string             `json:"address"`
+	Location      *GeoPoint          `json:"location"`
+	WindowStart   string             `json:"windowStart"`
+	WindowEnd     string             `json:"windowEnd"`
+	ArriveAt      string             `json:"arriveAt"`
+	StartAt       string             `json:"startAt"`
+	FinishAt      string             `json:"finishAt"`
+	TravelMinutes int                `json:"travelMinutes"` // From the previous stop or the base
+	DistanceKm    float64            `json:"distanceKm"`
+	LateMinutes   int                `json:"lateMinutes,omitempty"`
+}
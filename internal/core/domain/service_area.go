@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ServiceArea is a region the marketplace serves. Bookings are only offered to
+// mowers approved for the area they fall in, and are priced and charged
+// commission at the area's rates.
+type ServiceArea struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name     string             `bson:"name" json:"name"`
+	Boundary GeoPolygon         `bson:"boundary" json:"boundary"`
+	// PriceMultiplier scales price estimates for properties in the area.
+	PriceMultiplier float64 `bson:"priceMultiplier" json:"priceMultiplier"`
+	// CommissionBps, when set, replaces the platform's default commission and
+	// standing rules for everyone on bookings in the area.
+	CommissionBps *int64             `bson:"commissionBps,omitempty" json:"commissionBps,omitempty"`
+	Active        bool               `bson:"active" json:"active"`
+	CreatedBy     primitive.ObjectID `bson:"createdBy,omitempty" json:"createdBy,omitempty"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// Commission returns the area's commission as a rule, or nil if it has none.
+func (a *ServiceArea) Commission() *CommissionRule {
+	if a == nil || a.CommissionBps == nil {
+		return nil
+	}
+	return &CommissionRule{Name: a.Name, PercentageBps: *a.CommissionBps, Active: true}
+}
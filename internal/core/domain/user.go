@@ -8,31 +8,44 @@ import (
 
 // User represents a user in the system (customer, mower, admin, super_admin).
 type User struct {
-	ID                  primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name                string             `bson:"name" json:"name" validate:"required"`
-	Email               string             `bson:"email" json:"email" validate:"required,email"`
-	Password            string             `bson:"password" json:"-" validate:"required,min=6"` // "-" omits from JSON output
-	Role                string             `bson:"role" json:"role" validate:"required,oneof=customer mower admin super_admin"`
-	IsVerified          bool               `bson:"isVerified" json:"isVerified"`
-	DefaultPassword     bool               `bson:"defaultPassword,omitempty" json:"defaultPassword,omitempty"` // For admins
-	ImageUrl            string             `bson:"imageUrl,omitempty" json:"imageUrl,omitempty"`
-	PhoneNumber         string             `bson:"phoneNumber,omitempty" json:"phoneNumber,omitempty"`
-	BusinessAddress     string             `bson:"businessAddress,omitempty" json:"businessAddress,omitempty"`
-	ContactPerson       string             `bson:"contactPerson,omitempty" json:"contactPerson,omitempty"`
-	ContactPersonEmail  string             `bson:"contactPersonEmail,omitempty" json:"contactPersonEmail,omitempty"`
-	ContactPersonPhone  string             `bson:"contactPersonPhone,omitempty" json:"contactPersonPhone,omitempty"`
-	BusinessPhoneNumber string             `bson:"businessPhoneNumber,omitempty" json:"businessPhoneNumber,omitempty"`
-	BusinessEmail       string             `bson:"businessEmail,omitempty" json:"businessEmail,omitempty"`
-	IsApproved          bool               `bson:"isApproved" json:"isApproved"`   // For 'mower' role
-	IsAvailable         bool               `bson:"isAvailable" json:"isAvailable"` // For 'mower' role
-	Services            []string           `bson:"services,omitempty" json:"services,omitempty"`
-	Availability        []UserAvailability `bson:"availability,omitempty" json:"availability,omitempty"`
-	HourlyRate          float64            `bson:"hourlyRate" json:"hourlyRate"` // For 'mower' role
-	Ratings             []UserRating       `bson:"ratings,omitempty" json:"ratings,omitempty"`
-	WalletBalance       float64            `bson:"walletBalance" json:"walletBalance"` // For 'mower' role
-	CreatedAt           time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt           time.Time          `bson:"updatedAt" json:"updatedAt"`
-	ResetTokenExpiresAt time.Time          `bson:"resetTokenExpiresAt,omitempty" json:"resetTokenExpiresAt,omitempty"` // For password reset
+	ID                  primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Name                string               `bson:"name" json:"name" validate:"required"`
+	Email               string               `bson:"email" json:"email" validate:"required,email"`
+	Password            string               `bson:"password" json:"-" validate:"required,min=6"` // "-" omits from JSON output
+	Role                string               `bson:"role" json:"role" validate:"required,oneof=customer mower admin super_admin"`
+	IsVerified          bool                 `bson:"isVerified" json:"isVerified"`
+	DefaultPassword     bool                 `bson:"defaultPassword,omitempty" json:"defaultPassword,omitempty"` // For admins
+	ImageUrl            string               `bson:"imageUrl,omitempty" json:"imageUrl,omitempty"`
+	PhoneNumber         string               `bson:"phoneNumber,omitempty" json:"phoneNumber,omitempty"`
+	BusinessAddress     string               `bson:"businessAddress,omitempty" json:"businessAddress,omitempty"`
+	ContactPerson       string               `bson:"contactPerson,omitempty" json:"contactPerson,omitempty"`
+	ContactPersonEmail  string               `bson:"contactPersonEmail,omitempty" json:"contactPersonEmail,omitempty"`
+	ContactPersonPhone  string               `bson:"contactPersonPhone,omitempty" json:"contactPersonPhone,omitempty"`
+	BusinessPhoneNumber string               `bson:"businessPhoneNumber,omitempty" json:"businessPhoneNumber,omitempty"`
+	BusinessEmail       string               `bson:"businessEmail,omitempty" json:"businessEmail,omitempty"`
+	IsApproved          bool                 `bson:"isApproved" json:"isApproved"`   // For 'mower' role
+	IsAvailable         bool                 `bson:"isAvailable" json:"isAvailable"` // For 'mower' role
+	Services            []string             `bson:"services,omitempty" json:"services,omitempty"`
+	Availability        []UserAvailability   `bson:"availability,omitempty" json:"availability,omitempty"`
+	HourlyRate          float64              `bson:"hourlyRate" json:"hourlyRate"` // For 'mower' role
+	Ratings             []UserRating         `bson:"ratings,omitempty" json:"ratings,omitempty"`
+	RatingSummary       *RatingSummary       `bson:"ratingSummary,omitempty" json:"ratingSummary,omitempty"`   // For 'mower' role; aggregated from reviews
+	WalletBalance       int64                `bson:"walletBalance" json:"walletBalance"`                       // For 'mower' role, in minor units; cached from the ledger
+	PaymentOverdue      bool                 `bson:"paymentOverdue,omitempty" json:"paymentOverdue,omitempty"` // For 'customer' role; set by the final payment reminder
+	PayoutDetails       *PayoutDetails       `bson:"payoutDetails,omitempty" json:"payoutDetails,omitempty"`   // For 'mower' role; the bank account payouts are sent to
+	ServiceRadius       *ServiceRadius       `bson:"serviceRadius,omitempty" json:"serviceRadius,omitempty"`   // For 'mower' role; where they look for work
+	ServiceAreaIDs      []primitive.ObjectID `bson:"serviceAreaIds,omitempty" json:"serviceAreaIds,omitempty"` // For 'mower' role; the areas an admin approved them for
+	CreatedAt           time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt           time.Time            `bson:"updatedAt" json:"updatedAt"`
+}
+
+// PasswordResetToken is a single-use token emailed to a user who forgot their
+// password. Tokens are removed by a TTL index once they expire.
+type PasswordResetToken struct {
+	Token     string             `bson:"_id"`
+	UserID    primitive.ObjectID `bson:"userId"`
+	ExpiresAt time.Time          `bson:"expiresAt"`
+	CreatedAt time.Time          `bson:"createdAt"`
 }
 
 // UserAvailability represents a time slot a mower is available.
@@ -5,13 +5,12 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
-	"log"
-	"os"
 	"time"
 
 	"lawnconnect-api/internal/core/apperror"
 	"lawnconnect-api/internal/core/domain"
 	"lawnconnect-api/internal/infrastructure/database/repositories"
+	"lawnconnect-api/internal/infrastructure/logging"
 	infrastructureServices "lawnconnect-api/internal/infrastructure/services"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -20,8 +19,6 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-var jwtKey = []byte(os.Getenv("JWT_SECRET"))
-
 // Claims represents the JWT claims.
 type Claims struct {
 	UserID primitive.ObjectID `json:"userId"`
@@ -39,24 +36,28 @@ type AuthService interface {
 
 type authService struct {
 	userRepo     repositories.UserRepository
+	resetTokens  repositories.PasswordResetTokenRepository
 	emailService infrastructureServices.EmailService
+	jwtSecret    []byte
+	tokenTTL     time.Duration
+	resetURL     string
 }
 
-// NewAuthService creates a new AuthService instance.
-func NewAuthService(userRepo repositories.UserRepository, emailService infrastructureServices.EmailService) AuthService {
-	return &authService{userRepo: userRepo, emailService: emailService}
+// NewAuthService creates a new AuthService instance. Tokens are signed with
+// jwtSecret and expire after tokenTTL; password reset links point at resetURL.
+func NewAuthService(userRepo repositories.UserRepository, resetTokens repositories.PasswordResetTokenRepository, emailService infrastructureServices.EmailService, jwtSecret []byte, tokenTTL time.Duration, resetURL string) AuthService {
+	return &authService{
+		userRepo:     userRepo,
+		resetTokens:  resetTokens,
+		emailService: emailService,
+		jwtSecret:    jwtSecret,
+		tokenTTL:     tokenTTL,
+		resetURL:     resetURL,
+	}
 }
 
 // Register handles user registration logic.
 func (s *authService) Register(ctx context.Context, name, email, password, role string) (*domain.User, error) {
-	_, err := s.userRepo.FindUserByEmail(ctx, email)
-	if err == nil {
-		return nil, apperror.DuplicateError{Resource: "User with this email"}
-	}
-	if _, ok := err.(apperror.NotFound); !ok {
-		return nil, fmt.Errorf("error checking for existing user: %w", err)
-	}
-
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
@@ -65,7 +66,7 @@ func (s *authService) Register(ctx context.Context, name, email, password, role
 	user := &domain.User{
 		ID:        primitive.NewObjectID(),
 		Name:      name,
-		Email:     email,
+		Email:     domain.NormalizeEmail(email),
 		Password:  string(hashedPassword),
 		Role:      role,
 		CreatedAt: time.Now(),
@@ -74,6 +75,9 @@ func (s *authService) Register(ctx context.Context, name, email, password, role
 
 	err = s.userRepo.CreateUser(ctx, user)
 	if err != nil {
+		if _, ok := err.(apperror.DuplicateError); ok {
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to save user to database: %w", err)
 	}
 
@@ -82,7 +86,7 @@ func (s *authService) Register(ctx context.Context, name, email, password, role
 
 // Login handles user login and JWT token generation.
 func (s *authService) Login(ctx context.Context, email, password string) (*domain.User, string, error) {
-	user, err := s.userRepo.FindUserByEmail(ctx, email)
+	user, err := s.userRepo.FindUserByEmail(ctx, domain.NormalizeEmail(email))
 	if err != nil {
 		return nil, "", apperror.InvalidLoginCredentials{}
 	}
@@ -91,7 +95,7 @@ func (s *authService) Login(ctx context.Context, email, password string) (*domai
 		return nil, "", apperror.InvalidLoginCredentials{}
 	}
 
-	expirationTime := time.Now().Add(24 * time.Hour)
+	expirationTime := time.Now().Add(s.tokenTTL)
 	claims := &Claims{
 		UserID: user.ID,
 		Role:   user.Role,
@@ -102,7 +106,7 @@ func (s *authService) Login(ctx context.Context, email, password string) (*domai
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtKey)
+	tokenString, err := token.SignedString(s.jwtSecret)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to sign JWT token: %w", err)
 	}
@@ -112,11 +116,11 @@ func (s *authService) Login(ctx context.Context, email, password string) (*domai
 
 // ForgotPassword handles the logic for a user requesting a password reset.
 func (s *authService) ForgotPassword(ctx context.Context, email string) error {
-	user, err := s.userRepo.FindUserByEmail(ctx, email)
+	user, err := s.userRepo.FindUserByEmail(ctx, domain.NormalizeEmail(email))
 	if err != nil {
 		if _, ok := err.(apperror.NotFound); ok {
 			// Fail silently to prevent email enumeration attacks.
-			log.Printf("Password reset request for non-existent email: %s", email)
+			logging.FromContext(ctx).Info("password reset requested for unknown account")
 			return nil
 		}
 		return fmt.Errorf("error finding user: %w", err)
@@ -127,42 +131,41 @@ func (s *authService) ForgotPassword(ctx context.Context, email string) error {
 		return fmt.Errorf("failed to generate reset token: %w", err)
 	}
 
-	resetTokenExpiresAt := time.Now().Add(1 * time.Hour)
-	update := bson.M{
-		"$set": bson.M{
-			"resetToken":          resetToken,
-			"resetTokenExpiresAt": resetTokenExpiresAt,
-			"updatedAt":           time.Now(),
-		},
-	}
-	err = s.userRepo.UpdateUser(ctx, user.ID, update)
+	err = s.resetTokens.CreateResetToken(ctx, &domain.PasswordResetToken{
+		Token:     resetToken,
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(1 * time.Hour),
+		CreatedAt: time.Now(),
+	})
 	if err != nil {
 		return fmt.Errorf("failed to save reset token: %w", err)
 	}
 
-	resetURL := fmt.Sprintf("%s?token=%s", os.Getenv("LOGIN_URL"), resetToken)
+	resetURL := fmt.Sprintf("%s?token=%s", s.resetURL, resetToken)
 	templateData := map[string]interface{}{
 		"Name":     user.Name,
 		"ResetURL": resetURL,
 	}
 
-	log.Printf("Password reset email sent to %s with reset link: %s", user.Email, resetURL)
 	err = s.emailService.SendEmail(ctx, user.Email, "Password Reset Request", "password-reset.html", templateData)
 	if err != nil {
-		log.Printf("Failed to send password reset email to %s: %v", user.Email, err)
+		logging.FromContext(ctx).Error("sending password reset email failed", "user_id", user.ID.Hex(), "error", err)
+		return nil
 	}
+	logging.FromContext(ctx).Info("password reset email sent", "user_id", user.ID.Hex())
 
 	return nil
 }
 
 // ResetPassword handles the logic for a user resetting their password with a valid token.
 func (s *authService) ResetPassword(ctx context.Context, token, newPassword string) error {
-	user, err := s.userRepo.FindUserByResetToken(ctx, token)
+	resetToken, err := s.resetTokens.FindResetToken(ctx, token)
 	if err != nil {
 		return apperror.CustomError{Message: "Invalid or expired token"}
 	}
 
-	if time.Now().After(user.ResetTokenExpiresAt) {
+	// The TTL index removes expired tokens only periodically.
+	if time.Now().After(resetToken.ExpiresAt) {
 		return apperror.CustomError{Message: "Invalid or expired token"}
 	}
 
@@ -173,18 +176,21 @@ func (s *authService) ResetPassword(ctx context.Context, token, newPassword stri
 
 	update := bson.M{
 		"$set": bson.M{
-			"password":            string(hashedPassword),
-			"resetToken":          "", // Clear the token after use
-			"resetTokenExpiresAt": time.Time{},
-			"updatedAt":           time.Now(),
+			"password":  string(hashedPassword),
+			"updatedAt": time.Now(),
 		},
 	}
 
-	err = s.userRepo.UpdateUser(ctx, user.ID, update)
+	err = s.userRepo.UpdateUser(ctx, resetToken.UserID, update)
 	if err != nil {
 		return fmt.Errorf("failed to update password: %w", err)
 	}
 
+	// Every outstanding token is spent once the password has changed.
+	if err := s.resetTokens.DeleteUserResetTokens(ctx, resetToken.UserID); err != nil {
+		return fmt.Errorf("failed to clear reset tokens: %w", err)
+	}
+
 	return nil
 }
 
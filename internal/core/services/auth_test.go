@@ -0,0 +1,218 @@
+package services_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/core/services"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+	"lawnconnect-api/internal/infrastructure/database/repositories/memory"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testJWTSecret = "services-test-secret"
+
+// sentEmail is an email recorded by recordingEmailService.
+type sentEmail struct {
+	To       string
+	Subject  string
+	Template string
+	Data     map[string]interface{}
+}
+
+// recordingEmailService records every email instead of sending it. While err
+// is set, sends fail with it and nothing is recorded.
+type recordingEmailService struct {
+	mu   sync.Mutex
+	sent []sentEmail
+	err  error
+}
+
+func (s *recordingEmailService) SendEmail(ctx context.Context, to, subject, templateName string, replacements map[string]interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, sentEmail{To: to, Subject: subject, Template: templateName, Data: replacements})
+	return nil
+}
+
+func (s *recordingEmailService) SendEmailWithAttachment(ctx context.Context, to, subject, templateName string, replacements map[string]interface{}, attachmentFilename string, attachmentContent []byte) error {
+	return s.SendEmail(ctx, to, subject, templateName, replacements)
+}
+
+func (s *recordingEmailService) SendBulkEmail(ctx context.Context, toEmails []string, subject, templateName string, replacements map[string]interface{}) error {
+	for _, to := range toEmails {
+		s.SendEmail(ctx, to, subject, templateName, replacements)
+	}
+	return nil
+}
+
+// Fail makes every following send fail with err, or succeed again when err is nil.
+func (s *recordingEmailService) Fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// Sent returns the emails sent with templateName.
+func (s *recordingEmailService) Sent(templateName string) []sentEmail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var sent []sentEmail
+	for _, email := range s.sent {
+		if email.Template == templateName {
+			sent = append(sent, email)
+		}
+	}
+	return sent
+}
+
+type authFixture struct {
+	service     services.AuthService
+	users       repositories.UserRepository
+	resetTokens repositories.PasswordResetTokenRepository
+	emails      *recordingEmailService
+}
+
+func newAuthFixture() *authFixture {
+	f := &authFixture{
+		users:       memory.NewUserRepository(),
+		resetTokens: memory.NewPasswordResetTokenRepository(),
+		emails:      &recordingEmailService{},
+	}
+	f.service = services.NewAuthService(f.users, f.resetTokens, f.emails, []byte(testJWTSecret), time.Hour, "http://app.test/reset-password")
+	return f
+}
+
+func TestRegisterNormalizesEmail(t *testing.T) {
+	f := newAuthFixture()
+	ctx := context.Background()
+
+	user, err := f.service.Register(ctx, "Bob", "  Bob@Example.COM ", "secret1", "customer")
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if user.Email != "bob@example.com" {
+		t.Errorf("registered email = %q, want bob@example.com", user.Email)
+	}
+	if user.Password == "secret1" {
+		t.Error("password was stored in plain text")
+	}
+
+	_, err = f.service.Register(ctx, "Bobby", "bob@example.com", "secret2", "customer")
+	if _, ok := err.(apperror.DuplicateError); !ok {
+		t.Errorf("registering the same email differing in case returned %v, want apperror.DuplicateError", err)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	f := newAuthFixture()
+	ctx := context.Background()
+
+	registered, err := f.service.Register(ctx, "Mia", "mia@example.com", "secret1", "mower")
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	user, token, err := f.service.Login(ctx, "MIA@example.com", "secret1")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if user.ID != registered.ID {
+		t.Errorf("logged in as %s, want %s", user.ID.Hex(), registered.ID.Hex())
+	}
+	claims := &services.Claims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) { return []byte(testJWTSecret), nil })
+	if err != nil {
+		t.Fatalf("parsing session token: %v", err)
+	}
+	if claims.UserID != registered.ID || claims.Role != "mower" {
+		t.Errorf("token claims = %+v", claims)
+	}
+
+	for _, tc := range []struct{ email, password string }{
+		{"mia@example.com", "wrong"},
+		{"nobody@example.com", "secret1"},
+	} {
+		if _, _, err := f.service.Login(ctx, tc.email, tc.password); err != (apperror.InvalidLoginCredentials{}) {
+			t.Errorf("Login(%s, %s) returned %v, want apperror.InvalidLoginCredentials", tc.email, tc.password, err)
+		}
+	}
+}
+
+func TestPasswordReset(t *testing.T) {
+	f := newAuthFixture()
+	ctx := context.Background()
+
+	if _, err := f.service.Register(ctx, "Ana", "ana@example.com", "old-secret", "customer"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := f.service.ForgotPassword(ctx, "nobody@example.com"); err != nil {
+		t.Errorf("ForgotPassword for an unknown email returned %v, want nil", err)
+	}
+	if err := f.service.ForgotPassword(ctx, "Ana@Example.com"); err != nil {
+		t.Fatalf("ForgotPassword: %v", err)
+	}
+
+	sent := f.emails.Sent("password-reset.html")
+	if len(sent) != 1 || sent[0].To != "ana@example.com" {
+		t.Fatalf("password reset emails = %+v, want one to ana@example.com", sent)
+	}
+	resetURL, err := url.Parse(sent[0].Data["ResetURL"].(string))
+	if err != nil {
+		t.Fatalf("parsing reset URL: %v", err)
+	}
+	token := resetURL.Query().Get("token")
+	if token == "" || !strings.HasPrefix(resetURL.String(), "http://app.test/reset-password?") {
+		t.Fatalf("reset URL = %s", resetURL)
+	}
+
+	if err := f.service.ResetPassword(ctx, token, "new-secret"); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	if _, _, err := f.service.Login(ctx, "ana@example.com", "new-secret"); err != nil {
+		t.Errorf("Login with the new password: %v", err)
+	}
+	if _, _, err := f.service.Login(ctx, "ana@example.com", "old-secret"); err == nil {
+		t.Error("Login with the old password succeeded")
+	}
+	if _, ok := f.service.ResetPassword(ctx, token, "another-secret").(apperror.CustomError); !ok {
+		t.Error("a reset token could be used twice")
+	}
+}
+
+func TestPasswordResetTokenExpires(t *testing.T) {
+	f := newAuthFixture()
+	ctx := context.Background()
+
+	user, err := f.service.Register(ctx, "Ola", "ola@example.com", "old-secret", "customer")
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	// The TTL index may not have removed an expired token yet.
+	err = f.resetTokens.CreateResetToken(ctx, &domain.PasswordResetToken{
+		Token:     "expired-token",
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(-time.Minute),
+		CreatedAt: time.Now().Add(-time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("CreateResetToken: %v", err)
+	}
+
+	if _, ok := f.service.ResetPassword(ctx, "expired-token", "new-secret").(apperror.CustomError); !ok {
+		t.Error("an expired reset token was accepted")
+	}
+	if _, _, err := f.service.Login(ctx, "ola@example.com", "old-secret"); err != nil {
+		t.Errorf("the password changed with an expired token: %v", err)
+	}
+}
@@ -6,63 +6,241 @@ import (
 	"fmt"
 	"lawnconnect-api/internal/core/apperror"
 	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database"
 	"lawnconnect-api/internal/infrastructure/database/repositories"
+	"lawnconnect-api/internal/infrastructure/logging"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// BookingSettings configures bookings.
+type BookingSettings struct {
+	// QuoteToleranceBps is how far, in basis points of the approved quote, the
+	// mower may adjust the price on completion.
+	QuoteToleranceBps int64
+	// WaitlistOutsideAreas accepts bookings outside every service area onto the
+	// waitlist instead of refusing them.
+	WaitlistOutsideAreas bool
+	// Currency is the ISO 4217 code quotes and prices are in.
+	Currency string
+}
+
+// BookingRequest is what a customer submits to create a booking.
+type BookingRequest struct {
+	Date string // YYYY-MM-DD
+	Time string // HH:MM
+	// Address is a free-form address for bookings without a property.
+	Address     string
+	Description string
+	// PaymentMethod is a card token from the payment provider. Without one the
+	// booking is paid by invoice.
+	PaymentMethod string
+	// PropertyID, when set, books one of the customer's properties: the
+	// booking takes the property's address and pets warning, and a price
+	// estimate for Service (by default the property's service type).
+	PropertyID primitive.ObjectID
+	Service    string
+}
+
+// QuoteRequest is the quote a mower submits when accepting a booking. Fixed
+// quotes set Price; hourly quotes set EstimatedHours, priced at the mower's
+// hourly rate.
+type QuoteRequest struct {
+	Type           string
+	Price          float64
+	EstimatedHours float64
+	Note           string
+}
+
 // BookingService defines the service interface for bookings.
 type BookingService interface {
-	CreateBooking(ctx context.Context, customerID primitive.ObjectID, date, bookingTime, address, description string) (*domain.Booking, error)
-	GetBookingByID(ctx context.Context, bookingID primitive.ObjectID) (*domain.Booking, error)
+	// CreateBooking creates a booking. When a payment method is set the
+	// customer's card is authorized; otherwise the booking is paid by invoice.
+	// Another customer's property is returned as apperror.NotFound, and an
+	// unpriced service or an address that cannot be located as
+	// apperror.InvalidResource. An address outside every service area is
+	// waitlisted or, if the settings refuse such bookings, returned as
+	// apperror.NotFound.
+	CreateBooking(ctx context.Context, customerID primitive.ObjectID, request BookingRequest) (*domain.Booking, error)
+	// GetBookingByID returns a booking to its customer, its assigned mower and
+	// admins, and a pending booking to the mowers approved for its service area
+	// so they can look at the job before quoting. Anyone else gets
+	// apperror.NotFound.
+	GetBookingByID(ctx context.Context, bookingID, userID primitive.ObjectID, role string) (*domain.Booking, error)
+	// GetBookingProperty returns the property a booking was made for, with its
+	// access notes. Only the customer and, once they have accepted, the assigned
+	// mower can see it; anyone else gets apperror.NotFound.
+	GetBookingProperty(ctx context.Context, bookingID, userID primitive.ObjectID) (*domain.Property, error)
 	ListBookings(ctx context.Context, userID primitive.ObjectID) ([]*domain.Booking, error)
-	ListPendingBookings(ctx context.Context) ([]*domain.Booking, error)
-	AcceptBooking(ctx context.Context, bookingID, mowerID primitive.ObjectID) error
+	// ListPendingBookings lists the pending bookings within the mower's service
+	// radius in the service areas they are approved for, nearest first. A mower
+	// without a service radius or approved areas gets apperror.CustomError.
+	ListPendingBookings(ctx context.Context, mowerID primitive.ObjectID) ([]*domain.Booking, error)
+	// AcceptBooking assigns a pending booking to the mower with their quote, which
+	// the customer must approve before the work can be completed. Mowers can only
+	// accept bookings in the service areas they are approved for.
+	AcceptBooking(ctx context.Context, bookingID, mowerID primitive.ObjectID, quote QuoteRequest) error
+	// ApproveQuote accepts the mower's quote, making it the booking's price.
+	ApproveQuote(ctx context.Context, bookingID, customerID primitive.ObjectID) error
+	// DeclineQuote refuses the mower's quote and returns the booking to the
+	// pending pool for another quote.
+	DeclineQuote(ctx context.Context, bookingID, customerID primitive.ObjectID, reason string) error
 	RejectBooking(ctx context.Context, bookingID, mowerID primitive.ObjectID) error
-	CompleteBooking(ctx context.Context, bookingID primitive.ObjectID, price float64) error
+	// CompleteBooking completes the booking at the approved quote. A non-zero
+	// price adjusts it within the quote tolerance and needs a justification.
+	CompleteBooking(ctx context.Context, bookingID, mowerID primitive.ObjectID, price float64, justification string) error
+	// CancelBooking cancels the customer's booking and releases its card
+	// payment. Another customer's booking is returned as apperror.NotFound, and
+	// a booking that is past cancelling, or changed meanwhile, as
+	// apperror.CustomError.
 	CancelBooking(ctx context.Context, bookingID, customerID primitive.ObjectID) error
 }
 
 type bookingService struct {
 	bookingRepo repositories.BookingRepository
+	userRepo    repositories.UserRepository
+	properties  PropertyService
+	pricing     PricingService
+	locations   LocationService
+	areas       ServiceAreaService
+	ledger      LedgerService
+	commission  CommissionService
+	invoices    InvoiceService
+	dunning     DunningService
+	payments    PaymentService
+	tx          database.Transactor
+	settings    BookingSettings
 }
 
 // NewBookingService creates a new BookingService.
-func NewBookingService(bookingRepo repositories.BookingRepository) BookingService {
-	return &bookingService{bookingRepo: bookingRepo}
+func NewBookingService(bookingRepo repositories.BookingRepository, userRepo repositories.UserRepository, properties PropertyService, pricing PricingService, locations LocationService, areas ServiceAreaService, ledger LedgerService, commission CommissionService, invoices InvoiceService, dunning DunningService, payments PaymentService, tx database.Transactor, settings BookingSettings) BookingService {
+	return &bookingService{bookingRepo: bookingRepo, userRepo: userRepo, properties: properties, pricing: pricing, locations: locations, areas: areas, ledger: ledger, commission: commission, invoices: invoices, dunning: dunning, payments: payments, tx: tx, settings: settings}
 }
 
-// CreateBooking creates a new booking.
-func (s *bookingService) CreateBooking(ctx context.Context, customerID primitive.ObjectID, date, bookingTime, address, description string) (*domain.Booking, error) {
+// CreateBooking creates a new booking. Customers with overdue invoices are
+// either refused or have the booking flagged, depending on the dunning settings.
+// The card hold is placed before the booking is stored and released again if
+// storing it fails.
+func (s *bookingService) CreateBooking(ctx context.Context, customerID primitive.ObjectID, request BookingRequest) (*domain.Booking, error) {
+	var property *domain.Property
+	var location *domain.GeoPoint
+	var pets string
+	if !request.PropertyID.IsZero() {
+		var err error
+		property, err = s.properties.GetProperty(ctx, customerID, request.PropertyID)
+		if err != nil {
+			return nil, err
+		}
+		request.Address = property.Address.String()
+		location = property.Location
+		pets = property.Pets
+	}
+
 	// Simple validation
-	if date == "" || bookingTime == "" || address == "" {
+	if request.Date == "" || request.Time == "" || request.Address == "" {
 		return nil, errors.New("date, time, and address are required")
 	}
 
+	if location == nil {
+		var err error
+		if location, err = s.locations.Locate(ctx, request.Address); err != nil {
+			return nil, err
+		}
+	}
+
+	status := "pending"
+	area, err := s.areas.AreaAt(ctx, *location)
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); !ok {
+			return nil, err
+		}
+		if !s.settings.WaitlistOutsideAreas {
+			return nil, apperror.NotFound{Resource: "Service area for this address"}
+		}
+		status = "waitlisted"
+	}
+
+	var estimate *domain.PriceEstimate
+	if property != nil {
+		if estimate, err = s.pricing.Estimate(ctx, property, area, request.Service, request.Date); err != nil {
+			return nil, err
+		}
+	}
+
+	overdue, err := s.dunning.ScreenCustomer(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
 	booking := &domain.Booking{
-		ID:          primitive.NewObjectID(),
-		CustomerID:  customerID,
-		Date:        date,
-		Time:        bookingTime,
-		Address:     address,
-		Description: description,
-		Status:      "pending",
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		ID:              primitive.NewObjectID(),
+		CustomerID:      customerID,
+		Date:            request.Date,
+		Time:            request.Time,
+		Address:         request.Address,
+		Location:        location,
+		Description:     request.Description,
+		Status:          status,
+		BillingStatus:   domain.BillingPending,
+		CustomerOverdue: overdue,
+		PropertyID:      request.PropertyID,
+		Estimate:        estimate,
+		Pets:            pets,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	if area != nil {
+		booking.ServiceAreaID = area.ID
 	}
 
-	err := s.bookingRepo.CreateBooking(ctx, booking)
+	if request.PaymentMethod != "" {
+		booking.Payment, err = s.payments.Authorize(ctx, booking.ID, request.PaymentMethod)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.bookingRepo.CreateBooking(ctx, booking)
 	if err != nil {
+		if booking.Payment != nil {
+			if _, releaseErr := s.payments.Release(ctx, booking); releaseErr != nil {
+				logging.FromContext(ctx).Error("releasing payment for unsaved booking failed", "booking_id", booking.ID.Hex(), "error", releaseErr)
+			}
+		}
 		return nil, fmt.Errorf("service failed to create booking: %w", err)
 	}
 
 	return booking, nil
 }
 
-// GetBookingByID retrieves a single booking by ID.
-func (s *bookingService) GetBookingByID(ctx context.Context, bookingID primitive.ObjectID) (*domain.Booking, error) {
+// GetBookingByID retrieves a single booking by ID for a user who may see it.
+func (s *bookingService) GetBookingByID(ctx context.Context, bookingID, userID primitive.ObjectID, role string) (*domain.Booking, error) {
+	booking, err := s.findBooking(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case isAdmin(role), booking.CustomerID == userID, booking.MowerID == userID:
+		return booking, nil
+	case role == "mower" && booking.Status == "pending" && booking.MowerID.IsZero():
+		err := s.checkApprovedForArea(ctx, userID, booking.ServiceAreaID)
+		if _, ok := err.(apperror.CustomError); ok {
+			return nil, apperror.NotFound{Resource: "Booking"}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return booking, nil
+	}
+	return nil, apperror.NotFound{Resource: "Booking"}
+}
+
+// findBooking retrieves a booking by ID without checking who is asking.
+func (s *bookingService) findBooking(ctx context.Context, bookingID primitive.ObjectID) (*domain.Booking, error) {
 	booking, err := s.bookingRepo.FindBookingByID(ctx, bookingID)
 	if err != nil {
 		if _, ok := err.(apperror.NotFound); ok {
@@ -83,43 +261,236 @@ func (s *bookingService) ListBookings(ctx context.Context, userID primitive.Obje
 }
 
 // ListPendingBookings retrieves all bookings with a pending status.
-func (s *bookingService) ListPendingBookings(ctx context.Context) ([]*domain.Booking, error) {
-	bookings, err := s.bookingRepo.FindPendingBookings(ctx)
+func (s *bookingService) ListPendingBookings(ctx context.Context, mowerID primitive.ObjectID) ([]*domain.Booking, error) {
+	radius, err := s.locations.GetServiceRadius(ctx, mowerID)
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			return nil, apperror.CustomError{Message: "Set your base location and service radius to see jobs near you"}
+		}
+		return nil, err
+	}
+	mower, err := s.userRepo.FindUserByID(ctx, mowerID)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get mower: %w", err)
+	}
+	if len(mower.ServiceAreaIDs) == 0 {
+		return nil, apperror.CustomError{Message: "You are not approved for any service area yet"}
+	}
+	bookings, err := s.bookingRepo.FindPendingBookingsNear(ctx, *radius.BaseLocation, radius.RadiusKm, mower.ServiceAreaIDs)
 	if err != nil {
 		return nil, fmt.Errorf("service failed to list pending bookings: %w", err)
 	}
 	return bookings, nil
 }
 
-// AcceptBooking handles a mower accepting a booking.
-func (s *bookingService) AcceptBooking(ctx context.Context, bookingID, mowerID primitive.ObjectID) error {
+// GetBookingProperty retrieves the property of a booking for its customer or
+// assigned mower.
+func (s *bookingService) GetBookingProperty(ctx context.Context, bookingID, userID primitive.ObjectID) (*domain.Property, error) {
+	booking, err := s.findBooking(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.PropertyID.IsZero() {
+		return nil, apperror.NotFound{Resource: "Property"}
+	}
+	switch {
+	case booking.CustomerID == userID:
+	case booking.MowerID == userID && (booking.Status == "accepted" || booking.Status == "ongoing" || booking.Status == "completed"):
+	default:
+		return nil, apperror.NotFound{Resource: "Property"}
+	}
+	return s.properties.GetBookedProperty(ctx, booking.PropertyID)
+}
+
+// AcceptBooking handles a mower accepting a booking with a quote.
+func (s *bookingService) AcceptBooking(ctx context.Context, bookingID, mowerID primitive.ObjectID, quote QuoteRequest) error {
 	booking, err := s.bookingRepo.FindBookingByID(ctx, bookingID)
 	if err != nil {
 		return err
 	}
 
 	if booking.Status != "pending" {
-		return errors.New("booking is not pending and cannot be accepted")
+		return apperror.CustomError{Message: "Booking is not pending and cannot be accepted"}
 	}
 	if booking.MowerID != primitive.NilObjectID && booking.MowerID != mowerID {
 		return apperror.CustomError{Message: "This booking has already been accepted by another mower"}
 	}
+	if err := s.checkApprovedForArea(ctx, mowerID, booking.ServiceAreaID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	proposed, err := s.priceQuote(ctx, mowerID, quote, now)
+	if err != nil {
+		return err
+	}
+	if err := s.checkHold(booking, proposed.Amount); err != nil {
+		return err
+	}
 
 	update := bson.M{
 		"$set": bson.M{
-			"mowerId":   mowerID,
-			"status":    "accepted",
-			"updatedAt": time.Now(),
+			"mowerId":      mowerID,
+			"status":       "accepted",
+			"quote":        proposed,
+			"acceptedTime": now,
+			"updatedAt":    now,
 		},
 	}
-	err = s.bookingRepo.UpdateBooking(ctx, bookingID, update)
+	err = s.bookingRepo.UpdateBookingIfStatus(ctx, bookingID, "pending", "", update)
 	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			return apperror.CustomError{Message: "Booking is not pending and cannot be accepted"}
+		}
 		return fmt.Errorf("service failed to accept booking: %w", err)
 	}
 	return nil
 }
 
-// RejectBooking handles a mower rejecting a booking.
+// checkHold returns an apperror.CustomError if amount is more than the card hold
+// on the booking can cover. Holds are not resized, and capturing more than was
+// authorized would fail, so card bookings are never priced above their hold.
+func (s *bookingService) checkHold(booking *domain.Booking, amount int64) error {
+	if booking.Payment == nil || booking.Payment.Status != domain.PaymentAuthorized {
+		return nil
+	}
+	if amount > booking.Payment.AuthorizedAmount {
+		return apperror.CustomError{Message: "The price exceeds the " +
+			domain.FormatMinorUnits(booking.Payment.AuthorizedAmount, booking.Payment.Currency) + " held on the customer's card"}
+	}
+	return nil
+}
+
+// checkApprovedForArea returns an apperror.CustomError unless the mower is
+// approved for the service area.
+func (s *bookingService) checkApprovedForArea(ctx context.Context, mowerID, areaID primitive.ObjectID) error {
+	mower, err := s.userRepo.FindUserByID(ctx, mowerID)
+	if err != nil {
+		return fmt.Errorf("service failed to find mower: %w", err)
+	}
+	for _, approved := range mower.ServiceAreaIDs {
+		if approved == areaID {
+			return nil
+		}
+	}
+	return apperror.CustomError{Message: "You are not approved to work in this booking's service area"}
+}
+
+// priceQuote turns a mower's quote request into a proposed quote. Hourly quotes
+// are priced at the hourly rate on the mower's profile.
+func (s *bookingService) priceQuote(ctx context.Context, mowerID primitive.ObjectID, request QuoteRequest, now time.Time) (*domain.BookingQuote, error) {
+	quote := &domain.BookingQuote{
+		MowerID:   mowerID,
+		Type:      request.Type,
+		Note:      request.Note,
+		Status:    domain.QuoteProposed,
+		CreatedAt: now,
+	}
+	switch request.Type {
+	case domain.QuoteFixed:
+		quote.Amount = domain.ToMinorUnits(request.Price, s.settings.Currency)
+	case domain.QuoteHourly:
+		if request.EstimatedHours <= 0 {
+			return nil, apperror.CustomError{Message: "Estimated hours must be positive"}
+		}
+		mower, err := s.userRepo.FindUserByID(ctx, mowerID)
+		if err != nil {
+			return nil, fmt.Errorf("service failed to find mower: %w", err)
+		}
+		if mower.HourlyRate <= 0 {
+			return nil, apperror.CustomError{Message: "Set an hourly rate on your profile before quoting by the hour"}
+		}
+		quote.EstimatedHours = request.EstimatedHours
+		quote.HourlyRate = mower.HourlyRate
+		quote.Amount = domain.ToMinorUnits(request.EstimatedHours*mower.HourlyRate, s.settings.Currency)
+	default:
+		return nil, apperror.CustomError{Message: "Quote type must be fixed or hourly"}
+	}
+	if quote.Amount <= 0 {
+		return nil, apperror.CustomError{Message: "Quoted price must be a positive amount"}
+	}
+	return quote, nil
+}
+
+// ApproveQuote handles the customer approving the mower's quote.
+func (s *bookingService) ApproveQuote(ctx context.Context, bookingID, customerID primitive.ObjectID) error {
+	booking, err := s.findQuotedBooking(ctx, bookingID, customerID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkHold(booking, booking.Quote.Amount); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"quote.status":      domain.QuoteApproved,
+			"quote.respondedAt": now,
+			"price":             domain.FromMinorUnits(booking.Quote.Amount, s.settings.Currency),
+			"updatedAt":         now,
+		},
+	}
+	return s.updateQuotedBooking(ctx, bookingID, update)
+}
+
+// DeclineQuote handles the customer declining the mower's quote. The mower is
+// unassigned so the booking can be quoted again; a card hold stays in place.
+// Bookings from before service areas, which belong to none, go to the waitlist.
+func (s *bookingService) DeclineQuote(ctx context.Context, bookingID, customerID primitive.ObjectID, reason string) error {
+	booking, err := s.findQuotedBooking(ctx, bookingID, customerID)
+	if err != nil {
+		return err
+	}
+	status := "pending"
+	if booking.ServiceAreaID.IsZero() {
+		status = "waitlisted"
+	}
+
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"status":              status,
+			"quote.status":        domain.QuoteDeclined,
+			"quote.declineReason": reason,
+			"quote.respondedAt":   now,
+			"updatedAt":           now,
+		},
+		"$unset": bson.M{"mowerId": "", "acceptedTime": ""},
+	}
+	return s.updateQuotedBooking(ctx, bookingID, update)
+}
+
+// updateQuotedBooking applies update while the booking's quote still awaits an
+// answer, so an approval and a decline cannot both apply.
+func (s *bookingService) updateQuotedBooking(ctx context.Context, bookingID primitive.ObjectID, update bson.M) error {
+	err := s.bookingRepo.UpdateBookingIfStatus(ctx, bookingID, "accepted", domain.QuoteProposed, update)
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			return apperror.CustomError{Message: "Booking has no quote awaiting approval"}
+		}
+		return fmt.Errorf("service failed to update quote: %w", err)
+	}
+	return nil
+}
+
+// findQuotedBooking returns the customer's booking if it has a quote awaiting
+// their answer.
+func (s *bookingService) findQuotedBooking(ctx context.Context, bookingID, customerID primitive.ObjectID) (*domain.Booking, error) {
+	booking, err := s.bookingRepo.FindBookingByID(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.CustomerID != customerID {
+		return nil, apperror.NotFound{Resource: "Booking"}
+	}
+	if booking.Status != "accepted" || booking.Quote == nil || booking.Quote.Status != domain.QuoteProposed {
+		return nil, apperror.CustomError{Message: "Booking has no quote awaiting approval"}
+	}
+	return booking, nil
+}
+
+// RejectBooking handles a mower rejecting a booking. Its card payment is released.
 func (s *bookingService) RejectBooking(ctx context.Context, bookingID, mowerID primitive.ObjectID) error {
 	booking, err := s.bookingRepo.FindBookingByID(ctx, bookingID)
 	if err != nil {
@@ -127,7 +498,7 @@ func (s *bookingService) RejectBooking(ctx context.Context, bookingID, mowerID p
 	}
 
 	if booking.Status != "pending" {
-		return errors.New("booking is not pending and cannot be rejected")
+		return apperror.CustomError{Message: "Booking is not pending and cannot be rejected"}
 	}
 
 	// This check ensures a mower can only reject jobs that haven't been accepted by another mower.
@@ -135,69 +506,252 @@ func (s *bookingService) RejectBooking(ctx context.Context, bookingID, mowerID p
 		return apperror.CustomError{Message: "This booking has already been accepted by another mower"}
 	}
 
-	update := bson.M{
-		"$set": bson.M{
-			"status":    "rejected",
-			"updatedAt": time.Now(),
-		},
+	return s.closeBooking(ctx, booking, "rejected")
+}
+
+// CompleteBooking handles the assigned mower completing a booking. The booking
+// update, the credit to the mower's wallet, the platform's commission and the
+// customer's invoice are committed atomically; the invoice is emailed afterwards.
+// A card payment is captured first and, if the capture succeeds, the invoice is
+// issued already paid. If the transaction then fails, the capture is refunded.
+func (s *bookingService) CompleteBooking(ctx context.Context, bookingID, mowerID primitive.ObjectID, price float64, justification string) error {
+	// The card is charged before the transaction, since the charge cannot be
+	// rolled back with it. Its idempotency key makes a retried completion reuse
+	// the same charge.
+	booking, err := s.bookingRepo.FindBookingByID(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+	if err := checkCompletable(booking, mowerID); err != nil {
+		return err
+	}
+	amount, err := s.finalPrice(booking.Quote, price, justification)
+	if err != nil {
+		return err
+	}
+	if err := s.checkHold(booking, amount); err != nil {
+		return err
 	}
-	err = s.bookingRepo.UpdateBooking(ctx, bookingID, update)
+	var payment *domain.BookingPayment
+	if booking.Payment != nil && booking.Payment.Status == domain.PaymentAuthorized {
+		payment = s.payments.Capture(ctx, booking, amount)
+	}
+
+	var invoice *domain.Invoice
+	err = s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		booking, err := s.bookingRepo.FindBookingByID(ctx, bookingID)
+		if err != nil {
+			return err
+		}
+		if err := checkCompletable(booking, mowerID); err != nil {
+			return err
+		}
+
+		var area *domain.ServiceArea
+		if !booking.ServiceAreaID.IsZero() {
+			if area, err = s.areas.GetArea(ctx, booking.ServiceAreaID); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		fee, rule, err := s.commission.FeeFor(ctx, mowerID, area, amount, now)
+		if err != nil {
+			return err
+		}
+
+		invoice, err = s.invoices.IssueForBooking(ctx, booking, amount)
+		if err != nil {
+			if _, ok := err.(apperror.DuplicateError); ok {
+				return apperror.CustomError{Message: "Booking has already been invoiced"}
+			}
+			return fmt.Errorf("service failed to issue invoice: %w", err)
+		}
+
+		set := bson.M{
+			"status":        "completed",
+			"price":         domain.FromMinorUnits(amount, s.settings.Currency),
+			"platformFee":   fee,
+			"billingStatus": domain.BillingBilled,
+			"invoiceId":     invoice.ID,
+			"completedTime": now,
+			"updatedAt":     now,
+		}
+		if rule != nil {
+			set["commissionRuleId"] = rule.ID
+		}
+		if amount != booking.Quote.Amount {
+			set["priceAdjustment"] = justification
+		}
+		if payment != nil {
+			set["payment"] = payment
+		}
+		if err := s.bookingRepo.UpdateBooking(ctx, bookingID, bson.M{"$set": set}); err != nil {
+			return fmt.Errorf("service failed to complete booking: %w", err)
+		}
+		if payment != nil && payment.Status == domain.PaymentCaptured {
+			if invoice, err = s.invoices.SettleInvoice(ctx, invoice.ID, now); err != nil {
+				return err
+			}
+		}
+
+		if err := s.ledger.RecordBookingPayment(ctx, booking, amount); err != nil {
+			if _, ok := err.(apperror.DuplicateError); ok {
+				return apperror.CustomError{Message: "Booking has already been paid"}
+			}
+			return fmt.Errorf("service failed to credit mower: %w", err)
+		}
+		if err := s.ledger.RecordCommission(ctx, booking, fee); err != nil {
+			return fmt.Errorf("service failed to record commission: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("service failed to reject booking: %w", err)
+		if payment != nil && payment.Status == domain.PaymentCaptured {
+			s.refundCapture(ctx, bookingID)
+		}
+		return err
+	}
+
+	// The booking is billed either way; the customer can still download the
+	// invoice if the email fails.
+	if err := s.invoices.SendInvoice(ctx, invoice); err != nil {
+		logging.FromContext(ctx).Error("sending invoice failed", "booking_id", bookingID.Hex(), "invoice", invoice.Number, "error", err)
 	}
 	return nil
 }
 
-// CompleteBooking handles a mower completing a booking.
-func (s *bookingService) CompleteBooking(ctx context.Context, bookingID primitive.ObjectID, price float64) error {
+// refundCapture refunds a capture whose completion was not committed. A
+// concurrent completion of the same booking reuses the capture, so nothing is
+// refunded once the booking has left "accepted". The refund is recorded on the
+// booking, so a retried completion does not capture again and the customer pays
+// the invoice instead. Failures are logged for support to settle by hand.
+func (s *bookingService) refundCapture(ctx context.Context, bookingID primitive.ObjectID) {
+	log := logging.FromContext(ctx).With("booking_id", bookingID.Hex())
 	booking, err := s.bookingRepo.FindBookingByID(ctx, bookingID)
 	if err != nil {
-		return err
+		log.Error("refunding an uncommitted capture failed", "error", err)
+		return
 	}
+	if booking.Status != "accepted" {
+		return
+	}
+	payment, err := s.payments.Release(ctx, booking)
+	if err != nil {
+		log.Error("refunding an uncommitted capture failed", "error", err)
+		return
+	}
+	err = s.bookingRepo.UpdateBookingIfStatus(ctx, bookingID, "accepted", "", bson.M{
+		"$set": bson.M{"payment": payment, "updatedAt": time.Now()},
+	})
+	if err != nil {
+		log.Error("recording the refund of an uncommitted capture failed", "payment_id", payment.PaymentID, "error", err)
+	}
+}
 
+// checkCompletable returns an apperror.CustomError unless mowerID may complete
+// the booking.
+func checkCompletable(booking *domain.Booking, mowerID primitive.ObjectID) error {
 	if booking.Status != "accepted" {
 		return apperror.CustomError{Message: "Booking is not accepted and cannot be completed"}
 	}
-
-	update := bson.M{
-		"$set": bson.M{
-			"status":    "completed",
-			"price":     price,
-			"updatedAt": time.Now(),
-		},
+	if booking.MowerID != mowerID {
+		return apperror.CustomError{Message: "Only the assigned mower can complete this booking"}
 	}
-	err = s.bookingRepo.UpdateBooking(ctx, bookingID, update)
-	if err != nil {
-		return fmt.Errorf("service failed to complete booking: %w", err)
+	if booking.Quote == nil || booking.Quote.Status != domain.QuoteApproved {
+		return apperror.CustomError{Message: "The customer has not approved a quote for this booking"}
 	}
 	return nil
 }
 
-// CancelBooking handles a customer cancelling a booking.
+// finalPrice returns the amount to charge on completion: the approved quote, or
+// price if the mower adjusts it. Adjustments must stay within the quote tolerance
+// and be justified.
+func (s *bookingService) finalPrice(quote *domain.BookingQuote, price float64, justification string) (int64, error) {
+	if price == 0 {
+		return quote.Amount, nil
+	}
+	amount := domain.ToMinorUnits(price, s.settings.Currency)
+	if amount <= 0 {
+		return 0, apperror.CustomError{Message: "Price must be a positive amount"}
+	}
+	if amount == quote.Amount {
+		return amount, nil
+	}
+
+	diff := amount - quote.Amount
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff*10000 > quote.Amount*s.settings.QuoteToleranceBps {
+		return 0, apperror.CustomError{Message: fmt.Sprintf("Price may differ from the approved quote of %s by at most %d.%02d%%",
+			domain.FormatMinorUnits(quote.Amount, s.settings.Currency), s.settings.QuoteToleranceBps/100, s.settings.QuoteToleranceBps%100)}
+	}
+	if strings.TrimSpace(justification) == "" {
+		return 0, apperror.CustomError{Message: "A justification is required when the price differs from the approved quote"}
+	}
+	return amount, nil
+}
+
+// CancelBooking handles a customer cancelling a booking. Its card payment is
+// released.
 func (s *bookingService) CancelBooking(ctx context.Context, bookingID, customerID primitive.ObjectID) error {
 	booking, err := s.bookingRepo.FindBookingByID(ctx, bookingID)
 	if err != nil {
 		return err
 	}
 
-	if booking.Status != "pending" && booking.Status != "accepted" {
+	if booking.Status != "waitlisted" && booking.Status != "pending" && booking.Status != "accepted" {
 		return apperror.CustomError{Message: "Booking cannot be cancelled in its current state"}
 	}
 
 	// Ensure the user cancelling is the original customer
 	if booking.CustomerID != customerID {
-		return apperror.CustomError{Message: "Unauthorized to cancel this booking"}
+		return apperror.NotFound{Resource: "Booking"}
 	}
 
-	update := bson.M{
-		"$set": bson.M{
-			"status":    "cancelled",
-			"updatedAt": time.Now(),
-		},
+	return s.closeBooking(ctx, booking, "cancelled")
+}
+
+// closeBooking moves the booking from the status it was read in to status and
+// releases its card payment. The status is claimed first, so a booking that was
+// completed meanwhile is never refunded. If the release fails the status is put
+// back, so the caller can retry.
+func (s *bookingService) closeBooking(ctx context.Context, booking *domain.Booking, status string) error {
+	claim := bson.M{"$set": bson.M{"status": status, "updatedAt": time.Now()}}
+	if err := s.bookingRepo.UpdateBookingIfStatus(ctx, booking.ID, booking.Status, "", claim); err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			return apperror.CustomError{Message: "Booking was changed before it could be " + status}
+		}
+		return fmt.Errorf("service failed to close booking: %w", err)
 	}
-	err = s.bookingRepo.UpdateBooking(ctx, bookingID, update)
+
+	payment, err := s.releasePayment(ctx, booking)
 	if err != nil {
-		return fmt.Errorf("service failed to cancel booking: %w", err)
+		restore := bson.M{"$set": bson.M{"status": booking.Status, "updatedAt": time.Now()}}
+		if restoreErr := s.bookingRepo.UpdateBookingIfStatus(ctx, booking.ID, status, "", restore); restoreErr != nil {
+			logging.FromContext(ctx).Error("restoring booking after a failed payment release failed", "booking_id", booking.ID.Hex(), "error", restoreErr)
+		}
+		return err
+	}
+	if payment == nil {
+		return nil
+	}
+	if err := s.bookingRepo.UpdateBooking(ctx, booking.ID, bson.M{"$set": bson.M{"payment": payment}}); err != nil {
+		return fmt.Errorf("service failed to record released payment: %w", err)
 	}
 	return nil
 }
+
+// releasePayment refunds or voids the booking's card payment, if it holds any
+// money, and returns the resulting payment. It returns nil if there was nothing
+// to release.
+func (s *bookingService) releasePayment(ctx context.Context, booking *domain.Booking) (*domain.BookingPayment, error) {
+	if booking.Payment == nil {
+		return nil, nil
+	}
+	if booking.Payment.Status != domain.PaymentAuthorized && booking.Payment.Status != domain.PaymentCaptured {
+		return nil, nil
+	}
+	return s.payments.Release(ctx, booking)
+}
@@ -0,0 +1,349 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/core/services"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+	"lawnconnect-api/internal/infrastructure/database/repositories/memory"
+	"lawnconnect-api/internal/infrastructure/documents"
+	infrastructureServices "lawnconnect-api/internal/infrastructure/services"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// testArea surrounds the fake geocoder's centre, so every address the fixture
+// books is inside it.
+var testArea = domain.GeoPolygon{Type: "Polygon", Coordinates: [][][]float64{{
+	{-74.5, 40.3}, {-73.5, 40.3}, {-73.5, 41.1}, {-74.5, 41.1}, {-74.5, 40.3},
+}}}
+
+// bookingFixture is a BookingService over in-memory repositories and fake
+// providers, with a customer and two mowers approved for one service area.
+type bookingFixture struct {
+	service    services.BookingService
+	bookings   repositories.BookingRepository
+	users      repositories.UserRepository
+	payments   *infrastructureServices.FakePaymentProvider
+	invoices   repositories.InvoiceRepository
+	areaID     primitive.ObjectID
+	customer   *domain.User
+	mower      *domain.User
+	otherMower *domain.User
+}
+
+func newBookingFixture(t *testing.T) *bookingFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	f := &bookingFixture{
+		bookings: memory.NewBookingRepository(),
+		users:    memory.NewUserRepository(),
+		payments: infrastructureServices.NewFakePaymentProvider("services-test-webhook"),
+		areaID:   primitive.NewObjectID(),
+	}
+	areaRepo := memory.NewServiceAreaRepository()
+	err := areaRepo.CreateArea(ctx, &domain.ServiceArea{ID: f.areaID, Name: "Test", Boundary: testArea, PriceMultiplier: 1, Active: true, CreatedAt: time.Now(), UpdatedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("creating service area: %v", err)
+	}
+	f.customer = f.createUser(t, "customer")
+	f.mower = f.createUser(t, "mower")
+	f.otherMower = f.createUser(t, "mower")
+
+	emails := &recordingEmailService{}
+	tx := memory.NewTransactor()
+	invoiceRepo := memory.NewInvoiceRepository()
+	f.invoices = invoiceRepo
+	geocoder := infrastructureServices.NewFakeGeocoder(infrastructureServices.Location{Lat: 40.7128, Lng: -74.006})
+	locations := services.NewLocationService(geocoder, f.users, services.LocationSettings{MaxRadiusKm: 100})
+	pricing := services.NewPricingService(services.PricingSettings{Currency: "USD"})
+	areas := services.NewServiceAreaService(areaRepo, f.bookings, f.users, tx)
+	properties := services.NewPropertyService(memory.NewPropertyRepository(), pricing, locations, areas, nil)
+	ledger := services.NewLedgerService(memory.NewLedgerRepository(), f.users, memory.NewSystemMetricsRepository(), "USD")
+	commission := services.NewCommissionService(memory.NewCommissionRuleRepository(), 1000)
+	invoices := services.NewInvoiceService(invoiceRepo, f.bookings, f.users, emails, tx, services.InvoiceSettings{
+		Prefix:   "INV",
+		Currency: "USD",
+		Issuer:   documents.Issuer{Name: "LawnConnect"},
+	})
+	dunning := services.NewDunningService(invoiceRepo, memory.NewPaymentReminderRepository(), f.bookings, f.users, emails, tx, services.DunningSettings{
+		Thresholds: []time.Duration{72 * time.Hour},
+	})
+	payments := services.NewPaymentService(f.payments, f.bookings, memory.NewPaymentEventRepository(), invoices, tx, services.PaymentSettings{
+		Currency:            "USD",
+		AuthorizationAmount: 15000,
+	})
+	f.service = services.NewBookingService(f.bookings, f.users, properties, pricing, locations, areas, ledger, commission, invoices, dunning, payments, tx, services.BookingSettings{
+		QuoteToleranceBps: 1000,
+		Currency:          "USD",
+	})
+	return f
+}
+
+func (f *bookingFixture) createUser(t *testing.T, role string) *domain.User {
+	t.Helper()
+	id := primitive.NewObjectID()
+	user := &domain.User{
+		ID:        id,
+		Name:      role + " " + id.Hex(),
+		Email:     id.Hex() + "@example.com",
+		Role:      role,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if role == "mower" {
+		user.ServiceAreaIDs = []primitive.ObjectID{f.areaID}
+	}
+	if err := f.users.CreateUser(context.Background(), user); err != nil {
+		t.Fatalf("creating %s: %v", role, err)
+	}
+	return user
+}
+
+// book creates a booking for the fixture's customer, paid by card when
+// paymentMethod is set.
+func (f *bookingFixture) book(t *testing.T, paymentMethod string) *domain.Booking {
+	t.Helper()
+	booking, err := f.service.CreateBooking(context.Background(), f.customer.ID, services.BookingRequest{
+		Date:          "2030-06-03",
+		Time:          "09:00",
+		Address:       "1 Lawn Street",
+		PaymentMethod: paymentMethod,
+	})
+	if err != nil {
+		t.Fatalf("CreateBooking: %v", err)
+	}
+	return booking
+}
+
+// quoted books a job that the fixture's mower has quoted at price and the
+// customer has approved.
+func (f *bookingFixture) quoted(t *testing.T, paymentMethod string, price float64) *domain.Booking {
+	t.Helper()
+	ctx := context.Background()
+	booking := f.book(t, paymentMethod)
+	if err := f.service.AcceptBooking(ctx, booking.ID, f.mower.ID, services.QuoteRequest{Type: domain.QuoteFixed, Price: price}); err != nil {
+		t.Fatalf("AcceptBooking: %v", err)
+	}
+	if err := f.service.ApproveQuote(ctx, booking.ID, f.customer.ID); err != nil {
+		t.Fatalf("ApproveQuote: %v", err)
+	}
+	return booking
+}
+
+func (f *bookingFixture) reload(t *testing.T, bookingID primitive.ObjectID) *domain.Booking {
+	t.Helper()
+	booking, err := f.bookings.FindBookingByID(context.Background(), bookingID)
+	if err != nil {
+		t.Fatalf("FindBookingByID: %v", err)
+	}
+	return booking
+}
+
+func isCustomError(err error) bool {
+	_, ok := err.(apperror.CustomError)
+	return ok
+}
+
+func TestBookingLifecycle(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+
+	booking := f.book(t, "")
+	if booking.Status != "pending" || booking.ServiceAreaID != f.areaID || booking.Location == nil {
+		t.Fatalf("created booking = %+v", booking)
+	}
+
+	err := f.service.CompleteBooking(ctx, booking.ID, f.mower.ID, 0, "")
+	if !isCustomError(err) {
+		t.Errorf("completing a pending booking returned %v, want apperror.CustomError", err)
+	}
+	if err := f.service.AcceptBooking(ctx, booking.ID, f.mower.ID, services.QuoteRequest{Type: domain.QuoteFixed, Price: 50}); err != nil {
+		t.Fatalf("AcceptBooking: %v", err)
+	}
+	err = f.service.CompleteBooking(ctx, booking.ID, f.mower.ID, 0, "")
+	if !isCustomError(err) {
+		t.Errorf("completing before the quote is approved returned %v, want apperror.CustomError", err)
+	}
+	if err := f.service.ApproveQuote(ctx, booking.ID, f.customer.ID); err != nil {
+		t.Fatalf("ApproveQuote: %v", err)
+	}
+	if err := f.service.CompleteBooking(ctx, booking.ID, f.otherMower.ID, 0, ""); !isCustomError(err) {
+		t.Errorf("completion by another mower returned %v, want apperror.CustomError", err)
+	}
+	if err := f.service.CompleteBooking(ctx, booking.ID, f.mower.ID, 0, ""); err != nil {
+		t.Fatalf("CompleteBooking: %v", err)
+	}
+
+	completed := f.reload(t, booking.ID)
+	if completed.Status != "completed" || completed.Price != 50 || completed.BillingStatus != domain.BillingBilled || completed.InvoiceID.IsZero() {
+		t.Errorf("completed booking = %+v", completed)
+	}
+	// The default commission is 10%.
+	mower, err := f.users.FindUserByID(ctx, f.mower.ID)
+	if err != nil {
+		t.Fatalf("FindUserByID: %v", err)
+	}
+	if mower.WalletBalance != 4500 {
+		t.Errorf("mower wallet balance = %d, want 4500", mower.WalletBalance)
+	}
+	if err := f.service.CompleteBooking(ctx, booking.ID, f.mower.ID, 0, ""); !isCustomError(err) {
+		t.Errorf("completing twice returned %v, want apperror.CustomError", err)
+	}
+}
+
+func TestAcceptBookingOnce(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+
+	booking := f.book(t, "")
+	if err := f.service.AcceptBooking(ctx, booking.ID, f.mower.ID, services.QuoteRequest{Type: domain.QuoteFixed, Price: 40}); err != nil {
+		t.Fatalf("AcceptBooking: %v", err)
+	}
+	err := f.service.AcceptBooking(ctx, booking.ID, f.otherMower.ID, services.QuoteRequest{Type: domain.QuoteFixed, Price: 30})
+	if !isCustomError(err) {
+		t.Errorf("a second mower accepting returned %v, want apperror.CustomError", err)
+	}
+	if got := f.reload(t, booking.ID); got.MowerID != f.mower.ID || got.Quote.Amount != 4000 {
+		t.Errorf("booking after the second accept = %+v", got)
+	}
+}
+
+func TestDeclineQuoteReopensBooking(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+
+	booking := f.book(t, "")
+	if err := f.service.AcceptBooking(ctx, booking.ID, f.mower.ID, services.QuoteRequest{Type: domain.QuoteFixed, Price: 90}); err != nil {
+		t.Fatalf("AcceptBooking: %v", err)
+	}
+	if err := f.service.DeclineQuote(ctx, booking.ID, f.customer.ID, "Too expensive"); err != nil {
+		t.Fatalf("DeclineQuote: %v", err)
+	}
+	declined := f.reload(t, booking.ID)
+	if declined.Status != "pending" || !declined.MowerID.IsZero() || declined.Quote.Status != domain.QuoteDeclined {
+		t.Errorf("declined booking = %+v", declined)
+	}
+	if err := f.service.AcceptBooking(ctx, booking.ID, f.otherMower.ID, services.QuoteRequest{Type: domain.QuoteFixed, Price: 60}); err != nil {
+		t.Errorf("another mower accepting the reopened booking: %v", err)
+	}
+}
+
+func TestCompleteBookingPriceTolerance(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+
+	booking := f.quoted(t, "", 100)
+	if err := f.service.CompleteBooking(ctx, booking.ID, f.mower.ID, 111, "Extra edging"); !isCustomError(err) {
+		t.Errorf("completing 11%% over the quote returned %v, want apperror.CustomError", err)
+	}
+	if err := f.service.CompleteBooking(ctx, booking.ID, f.mower.ID, 110, ""); !isCustomError(err) {
+		t.Errorf("adjusting the price without a justification returned %v, want apperror.CustomError", err)
+	}
+	if err := f.service.CompleteBooking(ctx, booking.ID, f.mower.ID, 110, "Extra edging"); err != nil {
+		t.Fatalf("CompleteBooking within the tolerance: %v", err)
+	}
+	if got := f.reload(t, booking.ID); got.Price != 110 || got.PriceAdjustment != "Extra edging" {
+		t.Errorf("adjusted booking = %+v", got)
+	}
+}
+
+func TestCancelBooking(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+
+	booking := f.book(t, infrastructureServices.FakeCardVisa)
+	if booking.Payment == nil || booking.Payment.Status != domain.PaymentAuthorized {
+		t.Fatalf("card booking payment = %+v", booking.Payment)
+	}
+	other := f.createUser(t, "customer")
+	if _, ok := f.service.CancelBooking(ctx, booking.ID, other.ID).(apperror.NotFound); !ok {
+		t.Errorf("cancelling another customer's booking did not return apperror.NotFound")
+	}
+	if err := f.service.CancelBooking(ctx, booking.ID, f.customer.ID); err != nil {
+		t.Fatalf("CancelBooking: %v", err)
+	}
+	cancelled := f.reload(t, booking.ID)
+	if cancelled.Status != "cancelled" || cancelled.Payment.Status != domain.PaymentVoided {
+		t.Errorf("cancelled booking = %+v, payment %+v", cancelled, cancelled.Payment)
+	}
+	if err := f.service.CancelBooking(ctx, booking.ID, f.customer.ID); !isCustomError(err) {
+		t.Errorf("cancelling twice returned %v, want apperror.CustomError", err)
+	}
+}
+
+func TestGetBookingByIDForApprovedMowers(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+
+	booking := f.book(t, "")
+	if _, err := f.service.GetBookingByID(ctx, booking.ID, f.otherMower.ID, "mower"); err != nil {
+		t.Errorf("approved mower reading a pending booking: %v", err)
+	}
+	unapproved := &domain.User{ID: primitive.NewObjectID(), Email: "unapproved@example.com", Role: "mower"}
+	if err := f.users.CreateUser(ctx, unapproved); err != nil {
+		t.Fatalf("creating an unapproved mower: %v", err)
+	}
+	if _, ok := getBookingErr(f, booking.ID, unapproved.ID, "mower").(apperror.NotFound); !ok {
+		t.Errorf("a mower not approved for the area did not get apperror.NotFound")
+	}
+
+	if err := f.service.AcceptBooking(ctx, booking.ID, f.mower.ID, services.QuoteRequest{Type: domain.QuoteFixed, Price: 40}); err != nil {
+		t.Fatalf("AcceptBooking: %v", err)
+	}
+	if _, err := f.service.GetBookingByID(ctx, booking.ID, f.mower.ID, "mower"); err != nil {
+		t.Errorf("assigned mower reading the booking: %v", err)
+	}
+	if _, ok := getBookingErr(f, booking.ID, f.otherMower.ID, "mower").(apperror.NotFound); !ok {
+		t.Errorf("another mower reading an accepted booking did not get apperror.NotFound")
+	}
+}
+
+func TestGetBookingByIDForAdmins(t *testing.T) {
+	f := newBookingFixture(t)
+	booking := f.quoted(t, "", 40)
+
+	for _, role := range []string{"admin", "super_admin"} {
+		admin := f.createUser(t, role)
+		if _, err := f.service.GetBookingByID(context.Background(), booking.ID, admin.ID, role); err != nil {
+			t.Errorf("%s reading a booking: %v", role, err)
+		}
+	}
+	if _, ok := getBookingErr(f, booking.ID, f.createUser(t, "customer").ID, "customer").(apperror.NotFound); !ok {
+		t.Errorf("another customer did not get apperror.NotFound")
+	}
+}
+
+func getBookingErr(f *bookingFixture, bookingID, userID primitive.ObjectID, role string) error {
+	_, err := f.service.GetBookingByID(context.Background(), bookingID, userID, role)
+	return err
+}
+
+func TestCompleteBookingRefundsUncommittedCapture(t *testing.T) {
+	f := newBookingFixture(t)
+	ctx := context.Background()
+
+	booking := f.quoted(t, infrastructureServices.FakeCardVisa, 40)
+	// An invoice already issued for the booking makes the completion fail
+	// after the card was captured.
+	err := f.invoices.CreateInvoice(ctx, &domain.Invoice{ID: primitive.NewObjectID(), BookingID: booking.ID, Number: "INV-CONFLICT"})
+	if err != nil {
+		t.Fatalf("CreateInvoice: %v", err)
+	}
+	if err := f.service.CompleteBooking(ctx, booking.ID, f.mower.ID, 0, ""); !isCustomError(err) {
+		t.Fatalf("completing an invoiced booking returned %v, want apperror.CustomError", err)
+	}
+
+	if got := f.payments.Payment(booking.Payment.PaymentID); got.Status != infrastructureServices.PaymentStatusRefunded || got.Refunded != 4000 {
+		t.Errorf("provider payment after the failed completion = %+v, want refunded", got)
+	}
+	reloaded := f.reload(t, booking.ID)
+	if reloaded.Status != "accepted" || reloaded.Payment.Status != domain.PaymentRefunded {
+		t.Errorf("booking after the failed completion = %+v, payment %+v", reloaded, reloaded.Payment)
+	}
+}
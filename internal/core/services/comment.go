@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+	"lawnconnect-api/internal/infrastructure/logging"
+	infrastructureServices "lawnconnect-api/internal/infrastructure/services"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxCommentPhotos is how many photos can be attached to one comment.
+const MaxCommentPhotos = 4
+
+// maxCommentLength bounds the text of a booking comment.
+const maxCommentLength = 2000
+
+// maxComments bounds a booking's thread. Comments are embedded in the booking
+// document, which must stay under MongoDB's document size limit.
+const maxComments = 500
+
+// CommentSettings configure booking comments.
+type CommentSettings struct {
+	// EditWindow is how long after posting the author may edit a comment.
+	EditWindow time.Duration
+	// DeleteWindow is how long after posting the author may delete a comment.
+	// Admins may delete comments at any time.
+	DeleteWindow time.Duration
+}
+
+// CommentPhoto is a photo attached to a new comment.
+type CommentPhoto struct {
+	Content  io.Reader
+	Filename string
+}
+
+// CommentService manages the comment thread on a booking between its customer,
+// its assigned mower and admins. Anyone else gets apperror.NotFound for the
+// booking.
+type CommentService interface {
+	// ListComments returns the booking's comments, oldest first.
+	ListComments(ctx context.Context, bookingID, userID primitive.ObjectID) ([]domain.BookingComment, error)
+	// PostComment adds a comment with up to MaxCommentPhotos photos and emails
+	// it to the other party. A comment needs text or a photo; an empty or
+	// overlong comment, or too many photos, is returned as
+	// apperror.InvalidResource. A thread that is full is returned as
+	// apperror.CustomError.
+	PostComment(ctx context.Context, bookingID, userID primitive.ObjectID, text string, photos []CommentPhoto) (*domain.BookingComment, error)
+	// EditComment replaces the text of the user's own comment within the edit
+	// window. Comments of other users, rating comments and comments past the
+	// window are returned as apperror.CustomError.
+	EditComment(ctx context.Context, bookingID, commentID, userID primitive.ObjectID, text string) (*domain.BookingComment, error)
+	// DeleteComment removes the user's own comment within the delete window,
+	// or any comment other than a rating comment for admins. Other deletions
+	// are returned as apperror.CustomError.
+	DeleteComment(ctx context.Context, bookingID, commentID, userID primitive.ObjectID) error
+}
+
+type commentService struct {
+	bookingRepo  repositories.BookingRepository
+	userRepo     repositories.UserRepository
+	uploads      infrastructureServices.UploadService
+	emailService infrastructureServices.EmailService
+	settings     CommentSettings
+}
+
+// NewCommentService creates a new CommentService.
+func NewCommentService(bookingRepo repositories.BookingRepository, userRepo repositories.UserRepository, uploads infrastructureServices.UploadService, emailService infrastructureServices.EmailService, settings CommentSettings) CommentService {
+	return &commentService{bookingRepo: bookingRepo, userRepo: userRepo, uploads: uploads, emailService: emailService, settings: settings}
+}
+
+// ListComments retrieves the comments on a booking.
+func (s *commentService) ListComments(ctx context.Context, bookingID, userID primitive.ObjectID) ([]domain.BookingComment, error) {
+	booking, _, err := s.thread(ctx, bookingID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.Comments == nil {
+		return []domain.BookingComment{}, nil
+	}
+	return booking.Comments, nil
+}
+
+// PostComment uploads the photos, appends the comment and notifies the other
+// party. A failed email is logged rather than returned, as the comment is
+// already posted.
+func (s *commentService) PostComment(ctx context.Context, bookingID, userID primitive.ObjectID, text string, photos []CommentPhoto) (*domain.BookingComment, error) {
+	text = strings.TrimSpace(text)
+	if (text == "" && len(photos) == 0) || len(text) > maxCommentLength {
+		return nil, apperror.InvalidResource{Resource: "comment"}
+	}
+	if len(photos) > MaxCommentPhotos {
+		return nil, apperror.InvalidResource{Resource: "photos"}
+	}
+	booking, author, err := s.thread(ctx, bookingID, userID)
+	if err != nil {
+		return nil, err
+	}
+	full := apperror.CustomError{Message: fmt.Sprintf("This booking has reached the limit of %d comments", maxComments)}
+	if len(booking.Comments) >= maxComments {
+		return nil, full
+	}
+
+	comment := &domain.BookingComment{
+		ID:          primitive.NewObjectID(),
+		UserID:      author.ID,
+		UserName:    author.Name,
+		UserRole:    author.Role,
+		CommentText: text,
+		Timestamp:   time.Now(),
+	}
+	for _, photo := range photos {
+		url, err := s.uploads.UploadFile(ctx, photo.Content, photo.Filename)
+		if err != nil {
+			return nil, fmt.Errorf("service failed to upload comment photo: %w", err)
+		}
+		comment.PhotoURLs = append(comment.PhotoURLs, url)
+	}
+
+	if err := s.bookingRepo.AddBookingComment(ctx, booking.ID, comment, maxComments); err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			return nil, full
+		}
+		return nil, fmt.Errorf("service failed to post comment: %w", err)
+	}
+
+	s.notify(ctx, booking, comment)
+	return comment, nil
+}
+
+// EditComment sets the new text and when it was edited.
+func (s *commentService) EditComment(ctx context.Context, bookingID, commentID, userID primitive.ObjectID, text string) (*domain.BookingComment, error) {
+	text = strings.TrimSpace(text)
+	if len(text) > maxCommentLength {
+		return nil, apperror.InvalidResource{Resource: "comment"}
+	}
+	booking, _, err := s.thread(ctx, bookingID, userID)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := findComment(booking, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if text == "" && len(comment.PhotoURLs) == 0 {
+		return nil, apperror.InvalidResource{Resource: "comment"}
+	}
+	switch {
+	case comment.IsRating:
+		return nil, apperror.CustomError{Message: "Rating comments cannot be changed"}
+	case comment.UserID != userID:
+		return nil, apperror.CustomError{Message: "You can only edit your own comments"}
+	case time.Since(comment.Timestamp) > s.settings.EditWindow:
+		return nil, apperror.CustomError{Message: "This comment can no longer be edited"}
+	}
+
+	now := time.Now()
+	if err := s.bookingRepo.UpdateBookingComment(ctx, booking.ID, commentID, bson.M{"commentText": text, "editedAt": now}); err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service failed to edit comment: %w", err)
+	}
+	comment.CommentText = text
+	comment.EditedAt = &now
+	return comment, nil
+}
+
+// DeleteComment removes the comment from the booking.
+func (s *commentService) DeleteComment(ctx context.Context, bookingID, commentID, userID primitive.ObjectID) error {
+	booking, user, err := s.thread(ctx, bookingID, userID)
+	if err != nil {
+		return err
+	}
+	comment, err := findComment(booking, commentID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case comment.IsRating:
+		return apperror.CustomError{Message: "Rating comments cannot be changed"}
+	case isAdmin(user.Role):
+	case comment.UserID != userID:
+		return apperror.CustomError{Message: "You can only delete your own comments"}
+	case time.Since(comment.Timestamp) > s.settings.DeleteWindow:
+		return apperror.CustomError{Message: "This comment can no longer be deleted"}
+	}
+
+	if err := s.bookingRepo.DeleteBookingComment(ctx, booking.ID, commentID); err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			return err
+		}
+		return fmt.Errorf("service failed to delete comment: %w", err)
+	}
+	return nil
+}
+
+// thread loads the booking and the user, checking that the user may take
+// part in the booking's comment thread.
+func (s *commentService) thread(ctx context.Context, bookingID, userID primitive.ObjectID) (*domain.Booking, *domain.User, error) {
+	booking, err := s.bookingRepo.FindBookingByID(ctx, bookingID)
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("service failed to get booking: %w", err)
+	}
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("service failed to get user: %w", err)
+	}
+	if !isAdmin(user.Role) && booking.CustomerID != userID && booking.MowerID != userID {
+		return nil, nil, apperror.NotFound{Resource: "Booking"}
+	}
+	return booking, user, nil
+}
+
+// notify emails the comment to the booking's customer and assigned mower,
+// except its author.
+func (s *commentService) notify(ctx context.Context, booking *domain.Booking, comment *domain.BookingComment) {
+	for _, recipientID := range []primitive.ObjectID{booking.CustomerID, booking.MowerID} {
+		if recipientID.IsZero() || recipientID == comment.UserID {
+			continue
+		}
+		recipient, err := s.userRepo.FindUserByID(ctx, recipientID)
+		if err == nil {
+			templateData := map[string]interface{}{
+				"Name":        recipient.Name,
+				"AuthorName":  comment.UserName,
+				"Comment":     comment.CommentText,
+				"PhotoCount":  len(comment.PhotoURLs),
+				"BookingID":   booking.ID.Hex(),
+				"BookingDate": booking.Date,
+				"BookingTime": booking.Time,
+				"Address":     booking.Address,
+			}
+			subject := fmt.Sprintf("New message about your booking on %s", booking.Date)
+			err = s.emailService.SendEmail(ctx, recipient.Email, subject, "booking-comment.html", templateData)
+		}
+		if err != nil {
+			logging.FromContext(ctx).Error("sending comment notification failed", "booking_id", booking.ID.Hex(), "recipient_id", recipientID.Hex(), "error", err)
+		}
+	}
+}
+
+// findComment returns a copy of the booking's comment with commentID.
+func findComment(booking *domain.Booking, commentID primitive.ObjectID) (*domain.BookingComment, error) {
+	for _, comment := range booking.Comments {
+		if comment.ID == commentID {
+			return &comment, nil
+		}
+	}
+	return nil, apperror.NotFound{Resource: "Comment"}
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CommissionService manages commission rules and works out the platform's share
+// of a booking.
+type CommissionService interface {
+	CreateRule(ctx context.Context, rule *domain.CommissionRule) (*domain.CommissionRule, error)
+	ListRules(ctx context.Context) ([]*domain.CommissionRule, error)
+	DeactivateRule(ctx context.Context, ruleID primitive.ObjectID) error
+	// FeeFor returns the commission on amount for a booking in area, which may be
+	// nil, completed by the mower at time at, and the stored rule that set it (nil
+	// when the area's rate or the default applied).
+	FeeFor(ctx context.Context, mowerID primitive.ObjectID, area *domain.ServiceArea, amount int64, at time.Time) (int64, *domain.CommissionRule, error)
+}
+
+type commissionService struct {
+	ruleRepo repositories.CommissionRuleRepository
+	// defaultRule applies when no stored rule matches.
+	defaultRule domain.CommissionRule
+}
+
+// NewCommissionService creates a new CommissionService. defaultPercentageBps is
+// charged when no rule applies.
+func NewCommissionService(ruleRepo repositories.CommissionRuleRepository, defaultPercentageBps int64) CommissionService {
+	return &commissionService{
+		ruleRepo:    ruleRepo,
+		defaultRule: domain.CommissionRule{Name: "default", PercentageBps: defaultPercentageBps, Active: true},
+	}
+}
+
+// CreateRule validates and stores a new active rule.
+func (s *commissionService) CreateRule(ctx context.Context, rule *domain.CommissionRule) (*domain.CommissionRule, error) {
+	if rule.Name == "" {
+		return nil, apperror.CustomError{Message: "Rule name is required"}
+	}
+	if rule.PercentageBps < 0 || rule.PercentageBps > 10000 {
+		return nil, apperror.CustomError{Message: "percentageBps must be between 0 and 10000"}
+	}
+	if rule.FlatFee < 0 {
+		return nil, apperror.CustomError{Message: "flatFee cannot be negative"}
+	}
+	if rule.ValidFrom != nil && rule.ValidUntil != nil && !rule.ValidUntil.After(*rule.ValidFrom) {
+		return nil, apperror.CustomError{Message: "validUntil must be after validFrom"}
+	}
+
+	now := time.Now()
+	rule.ID = primitive.NewObjectID()
+	rule.Active = true
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	if err := s.ruleRepo.CreateRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("service failed to create commission rule: %w", err)
+	}
+	return rule, nil
+}
+
+// ListRules returns every rule, including deactivated ones, newest first.
+func (s *commissionService) ListRules(ctx context.Context) ([]*domain.CommissionRule, error) {
+	rules, err := s.ruleRepo.ListRules(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to list commission rules: %w", err)
+	}
+	return rules, nil
+}
+
+// DeactivateRule stops a rule from applying. Rules are never deleted, so bookings
+// keep pointing at the rule that priced them.
+func (s *commissionService) DeactivateRule(ctx context.Context, ruleID primitive.ObjectID) error {
+	if _, err := s.ruleRepo.FindRuleByID(ctx, ruleID); err != nil {
+		return err
+	}
+	update := bson.M{"$set": bson.M{"active": false, "updatedAt": time.Now()}}
+	if err := s.ruleRepo.UpdateRule(ctx, ruleID, update); err != nil {
+		return fmt.Errorf("service failed to deactivate commission rule: %w", err)
+	}
+	return nil
+}
+
+// FeeFor picks the applicable rule in this order of precedence: a promotion for
+// the mower, a promotion for everyone, a standing rule for the mower, the
+// service area's rate, a standing rule for everyone, then the default. Among
+// equals the newest rule wins.
+func (s *commissionService) FeeFor(ctx context.Context, mowerID primitive.ObjectID, area *domain.ServiceArea, amount int64, at time.Time) (int64, *domain.CommissionRule, error) {
+	rules, err := s.ruleRepo.ListRules(ctx, true)
+	if err != nil {
+		return 0, nil, fmt.Errorf("service failed to load commission rules: %w", err)
+	}
+
+	var best *domain.CommissionRule
+	bestRank := -1
+	for _, rule := range rules {
+		if !rule.AppliesTo(mowerID, at) {
+			continue
+		}
+		rank := 0
+		if !rule.MowerID.IsZero() {
+			rank++
+		}
+		if rule.IsPromotion() {
+			rank += 2
+		}
+		// Rules are listed newest first, so only a strictly better rank replaces.
+		if rank > bestRank {
+			best, bestRank = rule, rank
+		}
+	}
+
+	if areaRule := area.Commission(); areaRule != nil && bestRank < 1 {
+		return areaRule.Fee(amount), nil, nil
+	}
+	if best == nil {
+		return s.defaultRule.Fee(amount), nil, nil
+	}
+	return best.Fee(amount), best, nil
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+	"lawnconnect-api/internal/infrastructure/logging"
+	infrastructureServices "lawnconnect-api/internal/infrastructure/services"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DunningSettings configures payment reminders.
+type DunningSettings struct {
+	// Thresholds are how long after an invoice is issued each reminder level is
+	// due, in increasing order. The last level is the final notice, after which
+	// the customer is flagged as overdue.
+	Thresholds []time.Duration
+	// BlockOverdue rejects bookings from overdue customers. Otherwise their
+	// bookings are accepted and marked CustomerOverdue.
+	BlockOverdue bool
+	// RetryBackoff is how long after a failed send the reminder is retried. It
+	// doubles with each further failure.
+	RetryBackoff time.Duration
+	// MaxFailedSends stops reminding an invoice after this many consecutive
+	// failed sends. Zero retries forever.
+	MaxFailedSends int
+}
+
+// DunningService chases unpaid invoices with escalating reminders.
+type DunningService interface {
+	// SendDueReminders sends every reminder that is due at now and returns how
+	// many were sent. Failures for one invoice are logged and retried after a
+	// backoff.
+	SendDueReminders(ctx context.Context, now time.Time) (int, error)
+	// ScreenCustomer reports whether a customer is overdue. When overdue
+	// customers are blocked it returns an apperror.PaymentRequired instead.
+	ScreenCustomer(ctx context.Context, customerID primitive.ObjectID) (bool, error)
+	ListReminders(ctx context.Context, invoiceID primitive.ObjectID) ([]*domain.PaymentReminder, error)
+}
+
+type dunningService struct {
+	invoiceRepo  repositories.InvoiceRepository
+	reminderRepo repositories.PaymentReminderRepository
+	bookingRepo  repositories.BookingRepository
+	userRepo     repositories.UserRepository
+	emailService infrastructureServices.EmailService
+	tx           database.Transactor
+	settings     DunningSettings
+}
+
+// NewDunningService creates a new DunningService.
+func NewDunningService(invoiceRepo repositories.InvoiceRepository, reminderRepo repositories.PaymentReminderRepository, bookingRepo repositories.BookingRepository, userRepo repositories.UserRepository, emailService infrastructureServices.EmailService, tx database.Transactor, settings DunningSettings) DunningService {
+	return &dunningService{
+		invoiceRepo:  invoiceRepo,
+		reminderRepo: reminderRepo,
+		bookingRepo:  bookingRepo,
+		userRepo:     userRepo,
+		emailService: emailService,
+		tx:           tx,
+		settings:     settings,
+	}
+}
+
+// SendDueReminders finds unpaid invoices old enough for a reminder and sends
+// each the highest level now due, so a job that was down catches up with one
+// email rather than several.
+func (s *dunningService) SendDueReminders(ctx context.Context, now time.Time) (int, error) {
+	if len(s.settings.Thresholds) == 0 {
+		return 0, nil
+	}
+
+	invoices, err := s.invoiceRepo.FindUnpaidInvoicesIssuedBefore(ctx, now.Add(-s.settings.Thresholds[0]))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, invoice := range invoices {
+		if ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+		level := s.dueLevel(invoice, now)
+		if level <= invoice.RemindersSent || !s.retryDue(invoice, now) {
+			continue
+		}
+		if err := s.remind(ctx, invoice, level, now); err != nil {
+			logging.FromContext(ctx).Error("sending payment reminder failed",
+				"invoice", invoice.Number, "level", level, "error", err)
+			continue
+		}
+		sent++
+	}
+	if sent > 0 {
+		logging.FromContext(ctx).Info("payment reminders sent", "count", sent)
+	}
+	return sent, nil
+}
+
+// dueLevel is the number of thresholds the invoice has passed at now.
+func (s *dunningService) dueLevel(invoice *domain.Invoice, now time.Time) int {
+	level := 0
+	for _, threshold := range s.settings.Thresholds {
+		if now.Sub(invoice.IssuedAt) >= threshold {
+			level++
+		}
+	}
+	return level
+}
+
+// retryDue reports whether an invoice whose last sends failed may be tried again.
+func (s *dunningService) retryDue(invoice *domain.Invoice, now time.Time) bool {
+	if s.settings.MaxFailedSends > 0 && invoice.ReminderFailures >= s.settings.MaxFailedSends {
+		return false
+	}
+	return invoice.NextReminderAt == nil || !now.Before(*invoice.NextReminderAt)
+}
+
+// retryDelay is the backoff after the given number of consecutive failures.
+func (s *dunningService) retryDelay(failures int) time.Duration {
+	delay := s.settings.RetryBackoff
+	for i := 1; i < failures && delay < 24*time.Hour; i++ {
+		delay *= 2
+	}
+	return delay
+}
+
+// remind emails one reminder and records the attempt. Only a successful send
+// advances the invoice's reminder level. The invoice is updated only if it is
+// still unpaid and nobody recorded a reminder for it since it was read; if
+// either changed, the attempt is not recorded. The invoice, the reminder, the
+// booking and the customer are written in one transaction, so a failed write
+// leaves the level where it was and the reminder is sent again on the next run.
+func (s *dunningService) remind(ctx context.Context, invoice *domain.Invoice, level int, now time.Time) error {
+	final := level == len(s.settings.Thresholds)
+	reminder := &domain.PaymentReminder{
+		ID:         primitive.NewObjectID(),
+		InvoiceID:  invoice.ID,
+		BookingID:  invoice.BookingID,
+		CustomerID: invoice.CustomerID,
+		Level:      level,
+		Final:      final,
+		CreatedAt:  now,
+	}
+
+	if sendErr := s.sendReminder(ctx, invoice, level, final, now); sendErr != nil {
+		reminder.Error = sendErr.Error()
+		return s.recordFailure(ctx, invoice, reminder, sendErr, now)
+	}
+
+	set := bson.M{"remindersSent": level, "lastReminderAt": now, "reminderFailures": 0, "updatedAt": now}
+	if final {
+		set["overdue"] = true
+	}
+	err := s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		err := s.invoiceRepo.UpdateInvoiceReminders(ctx, invoice, bson.M{"$set": set, "$unset": bson.M{"nextReminderAt": ""}})
+		if err != nil {
+			return err
+		}
+		if err := s.reminderRepo.CreateReminder(ctx, reminder); err != nil {
+			return err
+		}
+		err = s.bookingRepo.UpdateBooking(ctx, invoice.BookingID, bson.M{
+			"$set": bson.M{"paymentReminderSent": true, "updatedAt": now},
+		})
+		if err != nil {
+			return err
+		}
+		if final {
+			err := s.userRepo.UpdateUser(ctx, invoice.CustomerID, bson.M{
+				"$set": bson.M{"paymentOverdue": true, "updatedAt": now},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to flag customer as overdue: %w", err)
+			}
+		}
+		return nil
+	})
+	if _, ok := err.(apperror.NotFound); ok {
+		logging.FromContext(ctx).Info("invoice changed while sending a payment reminder; not recording it",
+			"invoice", invoice.Number, "level", level)
+		return nil
+	}
+	return err
+}
+
+// recordFailure counts a failed send on the invoice and schedules the retry, or
+// gives up once MaxFailedSends is reached. It returns sendErr.
+func (s *dunningService) recordFailure(ctx context.Context, invoice *domain.Invoice, reminder *domain.PaymentReminder, sendErr error, now time.Time) error {
+	failures := invoice.ReminderFailures + 1
+	set := bson.M{"reminderFailures": failures, "updatedAt": now}
+	giveUp := s.settings.MaxFailedSends > 0 && failures >= s.settings.MaxFailedSends
+	if !giveUp {
+		set["nextReminderAt"] = now.Add(s.retryDelay(failures))
+	}
+
+	err := s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.invoiceRepo.UpdateInvoiceReminders(ctx, invoice, bson.M{"$set": set}); err != nil {
+			return err
+		}
+		return s.reminderRepo.CreateReminder(ctx, reminder)
+	})
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			return sendErr
+		}
+		return err
+	}
+	if giveUp {
+		logging.FromContext(ctx).Error("giving up on payment reminders after repeated failures",
+			"invoice", invoice.Number, "failures", failures)
+	}
+	return sendErr
+}
+
+func (s *dunningService) sendReminder(ctx context.Context, invoice *domain.Invoice, level int, final bool, now time.Time) error {
+	customer, err := s.userRepo.FindUserByID(ctx, invoice.CustomerID)
+	if err != nil {
+		return fmt.Errorf("failed to load customer: %w", err)
+	}
+
+	subject := fmt.Sprintf("Payment reminder: invoice %s", invoice.Number)
+	switch {
+	case final:
+		subject = fmt.Sprintf("Final notice: invoice %s is overdue", invoice.Number)
+	case level > 1:
+		subject = fmt.Sprintf("Reminder %d: invoice %s is unpaid", level, invoice.Number)
+	}
+
+	templateData := map[string]interface{}{
+		"Name":            customer.Name,
+		"InvoiceNumber":   invoice.Number,
+		"Amount":          domain.FormatMinorUnits(invoice.Amount, invoice.Currency),
+		"IssuedAt":        invoice.IssuedAt.Format("Jan 2, 2006"),
+		"DaysOutstanding": int(now.Sub(invoice.IssuedAt).Hours() / 24),
+		"Level":           level,
+		"Final":           final,
+	}
+	return s.emailService.SendEmail(ctx, customer.Email, subject, "payment-reminder.html", templateData)
+}
+
+// ScreenCustomer checks the customer's overdue flag.
+func (s *dunningService) ScreenCustomer(ctx context.Context, customerID primitive.ObjectID) (bool, error) {
+	customer, err := s.userRepo.FindUserByID(ctx, customerID)
+	if err != nil {
+		return false, fmt.Errorf("failed to load customer: %w", err)
+	}
+	if customer.PaymentOverdue && s.settings.BlockOverdue {
+		return true, apperror.PaymentRequired{Message: "You have overdue invoices. Please pay them before booking again."}
+	}
+	return customer.PaymentOverdue, nil
+}
+
+// ListReminders returns the reminder attempts for an invoice, oldest first.
+func (s *dunningService) ListReminders(ctx context.Context, invoiceID primitive.ObjectID) ([]*domain.PaymentReminder, error) {
+	reminders, err := s.reminderRepo.FindRemindersByInvoiceID(ctx, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to list payment reminders: %w", err)
+	}
+	return reminders, nil
+}
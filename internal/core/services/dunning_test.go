@@ -0,0 +1,133 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/core/services"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+	"lawnconnect-api/internal/infrastructure/database/repositories/memory"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type dunningFixture struct {
+	service   services.DunningService
+	invoices  repositories.InvoiceRepository
+	reminders repositories.PaymentReminderRepository
+	emails    *recordingEmailService
+	invoice   *domain.Invoice
+}
+
+// newDunningFixture has one unpaid invoice issued at issuedAt, reminded at 72h
+// and finally at 168h.
+func newDunningFixture(t *testing.T, issuedAt time.Time) *dunningFixture {
+	t.Helper()
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	bookings := memory.NewBookingRepository()
+	f := &dunningFixture{
+		invoices:  memory.NewInvoiceRepository(),
+		reminders: memory.NewPaymentReminderRepository(),
+		emails:    &recordingEmailService{},
+	}
+	f.service = services.NewDunningService(f.invoices, f.reminders, bookings, users, f.emails, memory.NewTransactor(), services.DunningSettings{
+		Thresholds:     []time.Duration{72 * time.Hour, 168 * time.Hour},
+		RetryBackoff:   time.Hour,
+		MaxFailedSends: 3,
+	})
+
+	customer := &domain.User{ID: primitive.NewObjectID(), Name: "Casey", Email: "casey@example.com", Role: "customer"}
+	if err := users.CreateUser(ctx, customer); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	booking := &domain.Booking{ID: primitive.NewObjectID(), CustomerID: customer.ID, Status: "completed"}
+	if err := bookings.CreateBooking(ctx, booking); err != nil {
+		t.Fatalf("CreateBooking: %v", err)
+	}
+	f.invoice = &domain.Invoice{
+		ID:         primitive.NewObjectID(),
+		Number:     "INV-2030-000001",
+		BookingID:  booking.ID,
+		CustomerID: customer.ID,
+		Status:     domain.InvoiceIssued,
+		Amount:     4500,
+		Currency:   "USD",
+		IssuedAt:   issuedAt,
+	}
+	if err := f.invoices.CreateInvoice(ctx, f.invoice); err != nil {
+		t.Fatalf("CreateInvoice: %v", err)
+	}
+	return f
+}
+
+func (f *dunningFixture) send(t *testing.T, now time.Time) int {
+	t.Helper()
+	sent, err := f.service.SendDueReminders(context.Background(), now)
+	if err != nil {
+		t.Fatalf("SendDueReminders: %v", err)
+	}
+	return sent
+}
+
+func (f *dunningFixture) attempts(t *testing.T) []*domain.PaymentReminder {
+	t.Helper()
+	reminders, err := f.reminders.FindRemindersByInvoiceID(context.Background(), f.invoice.ID)
+	if err != nil {
+		t.Fatalf("FindRemindersByInvoiceID: %v", err)
+	}
+	return reminders
+}
+
+func TestDunningRetriesFailedSendsWithBackoff(t *testing.T) {
+	issuedAt := time.Date(2030, 6, 3, 9, 0, 0, 0, time.UTC)
+	f := newDunningFixture(t, issuedAt)
+	due := issuedAt.Add(72*time.Hour + time.Minute)
+
+	f.emails.Fail(errors.New("smtp unavailable"))
+	f.send(t, due)
+	// Runs within the backoff do not try again or record anything.
+	f.send(t, due.Add(30*time.Minute))
+	if got := len(f.attempts(t)); got != 1 {
+		t.Fatalf("recorded %d attempts after one failure, want 1", got)
+	}
+
+	// The second failure doubles the backoff to two hours.
+	f.send(t, due.Add(time.Hour))
+	f.send(t, due.Add(2*time.Hour))
+	if got := len(f.attempts(t)); got != 2 {
+		t.Fatalf("recorded %d attempts within the doubled backoff, want 2", got)
+	}
+
+	f.emails.Fail(nil)
+	if sent := f.send(t, due.Add(3*time.Hour)); sent != 1 {
+		t.Fatalf("sent %d reminders once email recovered, want 1", sent)
+	}
+	invoice, err := f.invoices.FindInvoiceByID(context.Background(), f.invoice.ID)
+	if err != nil {
+		t.Fatalf("FindInvoiceByID: %v", err)
+	}
+	if invoice.RemindersSent != 1 || invoice.ReminderFailures != 0 || invoice.NextReminderAt != nil {
+		t.Errorf("invoice after a successful reminder = %+v", invoice)
+	}
+	attempts := f.attempts(t)
+	if len(attempts) != 3 || attempts[0].Error == "" || attempts[2].Error != "" {
+		t.Errorf("attempts = %+v", attempts)
+	}
+}
+
+func TestDunningGivesUpAfterMaxFailedSends(t *testing.T) {
+	issuedAt := time.Date(2030, 6, 3, 9, 0, 0, 0, time.UTC)
+	f := newDunningFixture(t, issuedAt)
+	f.emails.Fail(errors.New("mailbox unavailable"))
+
+	for day := 3; day < 10; day++ {
+		f.send(t, issuedAt.Add(time.Duration(day)*24*time.Hour))
+	}
+	if got := len(f.attempts(t)); got != 3 {
+		t.Errorf("recorded %d attempts, want the 3 allowed", got)
+	}
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+	"lawnconnect-api/internal/infrastructure/documents"
+	"lawnconnect-api/internal/infrastructure/logging"
+	infrastructureServices "lawnconnect-api/internal/infrastructure/services"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Invoice document formats.
+const (
+	InvoiceFormatPDF  = "pdf"
+	InvoiceFormatHTML = "html"
+)
+
+// InvoiceSettings configures how invoices are numbered and presented.
+type InvoiceSettings struct {
+	// Prefix starts every invoice number, e.g. "INV" in INV-2026-000042.
+	Prefix   string
+	Currency string
+	Issuer   documents.Issuer
+}
+
+// InvoiceService issues, renders and settles invoices for completed bookings.
+type InvoiceService interface {
+	// IssueForBooking numbers and stores the invoice for a booking being completed
+	// for amount minor units. It is called inside the completion transaction.
+	IssueForBooking(ctx context.Context, booking *domain.Booking, amount int64) (*domain.Invoice, error)
+	// SendInvoice emails the invoice to the customer with the PDF attached.
+	SendInvoice(ctx context.Context, invoice *domain.Invoice) error
+	ListInvoices(ctx context.Context, customerID primitive.ObjectID) ([]*domain.Invoice, error)
+	// GetInvoice returns an invoice the user may see: their own as customer or
+	// mower, or any invoice for admins. Others get apperror.NotFound.
+	GetInvoice(ctx context.Context, invoiceID, userID primitive.ObjectID, role string) (*domain.Invoice, error)
+	RenderInvoice(ctx context.Context, invoice *domain.Invoice, format string) ([]byte, error)
+	// MarkPaid settles an invoice and its booking. Only an admin may do so: the
+	// mower's earnings become withdrawable once the invoice is paid.
+	MarkPaid(ctx context.Context, invoiceID primitive.ObjectID, role string) (*domain.Invoice, error)
+	// SettleInvoice marks an issued invoice and its booking paid without a
+	// permission check, when the customer's card payment is captured. It runs in
+	// the caller's transaction; an invoice that is already paid is left alone.
+	SettleInvoice(ctx context.Context, invoiceID primitive.ObjectID, paidAt time.Time) (*domain.Invoice, error)
+}
+
+type invoiceService struct {
+	invoiceRepo  repositories.InvoiceRepository
+	bookingRepo  repositories.BookingRepository
+	userRepo     repositories.UserRepository
+	emailService infrastructureServices.EmailService
+	tx           database.Transactor
+	settings     InvoiceSettings
+}
+
+// NewInvoiceService creates a new InvoiceService.
+func NewInvoiceService(invoiceRepo repositories.InvoiceRepository, bookingRepo repositories.BookingRepository, userRepo repositories.UserRepository, emailService infrastructureServices.EmailService, tx database.Transactor, settings InvoiceSettings) InvoiceService {
+	return &invoiceService{
+		invoiceRepo:  invoiceRepo,
+		bookingRepo:  bookingRepo,
+		userRepo:     userRepo,
+		emailService: emailService,
+		tx:           tx,
+		settings:     settings,
+	}
+}
+
+// IssueForBooking allocates the next number for the current year and stores the
+// invoice. Names are copied onto the invoice so it reads the same if the
+// accounts change later.
+func (s *invoiceService) IssueForBooking(ctx context.Context, booking *domain.Booking, amount int64) (*domain.Invoice, error) {
+	customer, err := s.userRepo.FindUserByID(ctx, booking.CustomerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load customer for invoice: %w", err)
+	}
+	mower, err := s.userRepo.FindUserByID(ctx, booking.MowerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mower for invoice: %w", err)
+	}
+
+	now := time.Now()
+	year := now.UTC().Year()
+	sequence, err := s.invoiceRepo.NextInvoiceSequence(ctx, year)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := &domain.Invoice{
+		ID:             primitive.NewObjectID(),
+		Number:         domain.InvoiceNumber(s.settings.Prefix, year, sequence),
+		Year:           year,
+		Sequence:       sequence,
+		BookingID:      booking.ID,
+		Status:         domain.InvoiceIssued,
+		CustomerID:     customer.ID,
+		CustomerName:   customer.Name,
+		MowerID:        mower.ID,
+		MowerName:      mower.Name,
+		ServiceDate:    booking.Date,
+		ServiceAddress: booking.Address,
+		Description:    booking.Description,
+		Amount:         amount,
+		Currency:       s.settings.Currency,
+		IssuedAt:       now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.invoiceRepo.CreateInvoice(ctx, invoice); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// SendInvoice emails the invoice PDF to the customer's current address.
+func (s *invoiceService) SendInvoice(ctx context.Context, invoice *domain.Invoice) error {
+	customer, err := s.userRepo.FindUserByID(ctx, invoice.CustomerID)
+	if err != nil {
+		return fmt.Errorf("failed to load customer for invoice email: %w", err)
+	}
+	pdf, err := documents.InvoicePDF(invoice, s.settings.Issuer)
+	if err != nil {
+		return err
+	}
+
+	templateData := map[string]interface{}{
+		"Name":          customer.Name,
+		"InvoiceNumber": invoice.Number,
+		"Amount":        domain.FormatMinorUnits(invoice.Amount, invoice.Currency),
+		"ServiceDate":   invoice.ServiceDate,
+	}
+	subject := fmt.Sprintf("Your %s invoice %s", s.settings.Issuer.Name, invoice.Number)
+	err = s.emailService.SendEmailWithAttachment(ctx, customer.Email, subject, "invoice.html", templateData, invoice.Number+".pdf", pdf)
+	if err != nil {
+		return fmt.Errorf("failed to email invoice %s: %w", invoice.Number, err)
+	}
+	logging.FromContext(ctx).Info("invoice emailed", "invoice", invoice.Number, "user_id", customer.ID.Hex())
+	return nil
+}
+
+// ListInvoices returns a customer's invoices, newest first.
+func (s *invoiceService) ListInvoices(ctx context.Context, customerID primitive.ObjectID) ([]*domain.Invoice, error) {
+	invoices, err := s.invoiceRepo.FindInvoicesByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to list invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+// GetInvoice retrieves an invoice if the user is allowed to see it.
+func (s *invoiceService) GetInvoice(ctx context.Context, invoiceID, userID primitive.ObjectID, role string) (*domain.Invoice, error) {
+	invoice, err := s.invoiceRepo.FindInvoiceByID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin(role) && invoice.CustomerID != userID && invoice.MowerID != userID {
+		// Don't reveal that the invoice exists.
+		return nil, apperror.NotFound{Resource: "Invoice"}
+	}
+	return invoice, nil
+}
+
+// RenderInvoice renders the invoice as a PDF or HTML document.
+func (s *invoiceService) RenderInvoice(ctx context.Context, invoice *domain.Invoice, format string) ([]byte, error) {
+	switch format {
+	case InvoiceFormatPDF:
+		return documents.InvoicePDF(invoice, s.settings.Issuer)
+	case InvoiceFormatHTML:
+		return documents.InvoiceHTML(invoice, s.settings.Issuer)
+	default:
+		return nil, apperror.CustomError{Message: "Unsupported invoice format " + format}
+	}
+}
+
+// MarkPaid sets the invoice and its booking to paid in one transaction.
+func (s *invoiceService) MarkPaid(ctx context.Context, invoiceID primitive.ObjectID, role string) (*domain.Invoice, error) {
+	if !isAdmin(role) {
+		return nil, apperror.NotFound{Resource: "Invoice"}
+	}
+	var invoice *domain.Invoice
+	err := s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		invoice, err = s.invoiceRepo.FindInvoiceByID(ctx, invoiceID)
+		if err != nil {
+			return err
+		}
+		if invoice.Status == domain.InvoicePaid {
+			return apperror.CustomError{Message: "Invoice is already paid"}
+		}
+		return s.settle(ctx, invoice, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// SettleInvoice settles the invoice if it is still unpaid.
+func (s *invoiceService) SettleInvoice(ctx context.Context, invoiceID primitive.ObjectID, paidAt time.Time) (*domain.Invoice, error) {
+	invoice, err := s.invoiceRepo.FindInvoiceByID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Status == domain.InvoicePaid {
+		return invoice, nil
+	}
+	if err := s.settle(ctx, invoice, paidAt); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// settle sets the invoice and its booking to paid, clears the customer's overdue
+// flag if this was their last overdue invoice, and updates invoice in place.
+func (s *invoiceService) settle(ctx context.Context, invoice *domain.Invoice, now time.Time) error {
+	err := s.invoiceRepo.UpdateInvoice(ctx, invoice.ID, bson.M{
+		"$set": bson.M{"status": domain.InvoicePaid, "paidAt": now, "updatedAt": now},
+	})
+	if err != nil {
+		return fmt.Errorf("service failed to mark invoice paid: %w", err)
+	}
+	err = s.bookingRepo.UpdateBooking(ctx, invoice.BookingID, bson.M{
+		"$set": bson.M{"billingStatus": domain.BillingPaid, "updatedAt": now},
+	})
+	if err != nil {
+		return fmt.Errorf("service failed to mark booking paid: %w", err)
+	}
+	if invoice.Overdue {
+		if err := s.clearOverdueFlag(ctx, invoice.CustomerID, now); err != nil {
+			return err
+		}
+	}
+
+	invoice.Status = domain.InvoicePaid
+	invoice.PaidAt = &now
+	invoice.UpdatedAt = now
+	return nil
+}
+
+// clearOverdueFlag lifts the customer's overdue flag once none of their unpaid
+// invoices is overdue.
+func (s *invoiceService) clearOverdueFlag(ctx context.Context, customerID primitive.ObjectID, now time.Time) error {
+	remaining, err := s.invoiceRepo.CountOverdueInvoices(ctx, customerID)
+	if err != nil {
+		return err
+	}
+	if remaining > 0 {
+		return nil
+	}
+	err = s.userRepo.UpdateUser(ctx, customerID, bson.M{"$set": bson.M{"paymentOverdue": false, "updatedAt": now}})
+	if err != nil {
+		return fmt.Errorf("service failed to clear overdue flag: %w", err)
+	}
+	return nil
+}
+
+func isAdmin(role string) bool {
+	return role == "admin" || role == "super_admin"
+}
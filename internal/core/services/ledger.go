@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Posting is one side of a ledger transaction: an amount, in minor units, added
+// to an account's balance.
+type Posting struct {
+	Account string
+	Amount  int64
+}
+
+// LedgerService records money movements as balanced, immutable ledger entries and
+// keeps each mower's cached User.WalletBalance in step with them. Its methods do
+// not start transactions; callers run them inside database.Transactor so the
+// entries, the cached balance and the caller's own writes commit together.
+type LedgerService interface {
+	// Post records one balanced transaction. The postings must sum to zero.
+	Post(ctx context.Context, entryType domain.LedgerEntryType, bookingID primitive.ObjectID, memo string, postings ...Posting) ([]*domain.LedgerEntry, error)
+	// RecordBookingPayment credits the booking's mower with the amount the customer paid.
+	RecordBookingPayment(ctx context.Context, booking *domain.Booking, amount int64) error
+	// RecordCommission moves the platform's fee for a booking from the mower's
+	// wallet to platform revenue and adds it to the system_revenue total.
+	RecordCommission(ctx context.Context, booking *domain.Booking, fee int64) error
+	// Statement returns a mower's entries and balance, reconciled against the cached balance.
+	Statement(ctx context.Context, mowerID primitive.ObjectID) (*domain.WalletStatement, error)
+	// Revenue aggregates platform revenue in [from, to) by day, week or month.
+	Revenue(ctx context.Context, period string, from, to time.Time) (*domain.RevenueReport, error)
+}
+
+type ledgerService struct {
+	ledgerRepo  repositories.LedgerRepository
+	userRepo    repositories.UserRepository
+	metricsRepo repositories.SystemMetricsRepository
+	currency    string
+}
+
+// NewLedgerService creates a new LedgerService that books amounts in currency.
+func NewLedgerService(ledgerRepo repositories.LedgerRepository, userRepo repositories.UserRepository, metricsRepo repositories.SystemMetricsRepository, currency string) LedgerService {
+	return &ledgerService{ledgerRepo: ledgerRepo, userRepo: userRepo, metricsRepo: metricsRepo, currency: currency}
+}
+
+// Post validates and stores a transaction, then applies it to the cached wallet
+// balance of every mower account it touches.
+func (s *ledgerService) Post(ctx context.Context, entryType domain.LedgerEntryType, bookingID primitive.ObjectID, memo string, postings ...Posting) ([]*domain.LedgerEntry, error) {
+	if len(postings) < 2 {
+		return nil, fmt.Errorf("ledger transaction needs at least two postings, got %d", len(postings))
+	}
+	var sum int64
+	for _, p := range postings {
+		if p.Amount == 0 || p.Account == "" {
+			return nil, fmt.Errorf("ledger posting %+v must have an account and a non-zero amount", p)
+		}
+		sum += p.Amount
+	}
+	if sum != 0 {
+		return nil, fmt.Errorf("ledger transaction is unbalanced by %d", sum)
+	}
+
+	now := time.Now()
+	transactionID := primitive.NewObjectID()
+	entries := make([]*domain.LedgerEntry, len(postings))
+	for i, p := range postings {
+		entries[i] = &domain.LedgerEntry{
+			ID:            primitive.NewObjectID(),
+			TransactionID: transactionID,
+			Account:       p.Account,
+			Type:          entryType,
+			Amount:        p.Amount,
+			Currency:      s.currency,
+			BookingID:     bookingID,
+			Memo:          memo,
+			CreatedAt:     now,
+		}
+	}
+
+	if err := s.ledgerRepo.InsertEntries(ctx, entries); err != nil {
+		if _, ok := err.(apperror.DuplicateError); ok {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to record ledger entries: %w", err)
+	}
+
+	for _, p := range postings {
+		mowerID, ok := domain.MowerFromAccount(p.Account)
+		if !ok {
+			continue
+		}
+		update := bson.M{
+			"$inc": bson.M{"walletBalance": p.Amount},
+			"$set": bson.M{"updatedAt": now},
+		}
+		if err := s.userRepo.UpdateUser(ctx, mowerID, update); err != nil {
+			return nil, fmt.Errorf("failed to update wallet balance: %w", err)
+		}
+	}
+	return entries, nil
+}
+
+// RecordBookingPayment moves the booking's price from customer payments into the
+// assigned mower's wallet.
+func (s *ledgerService) RecordBookingPayment(ctx context.Context, booking *domain.Booking, amount int64) error {
+	if booking.MowerID.IsZero() {
+		return apperror.CustomError{Message: "Booking has no assigned mower to pay"}
+	}
+	_, err := s.Post(ctx, domain.LedgerBookingPayment, booking.ID, "Payment for booking "+booking.ID.Hex(),
+		Posting{Account: domain.AccountCustomerPayments, Amount: -amount},
+		Posting{Account: domain.MowerAccount(booking.MowerID), Amount: amount},
+	)
+	return err
+}
+
+// RecordCommission posts the platform's fee and updates system_revenue.
+func (s *ledgerService) RecordCommission(ctx context.Context, booking *domain.Booking, fee int64) error {
+	if fee <= 0 {
+		return nil
+	}
+	_, err := s.Post(ctx, domain.LedgerPlatformCommission, booking.ID, "Commission on booking "+booking.ID.Hex(),
+		Posting{Account: domain.MowerAccount(booking.MowerID), Amount: -fee},
+		Posting{Account: domain.AccountPlatformRevenue, Amount: fee},
+	)
+	if err != nil {
+		return err
+	}
+	if err := s.metricsRepo.IncrementMetric(ctx, domain.SystemRevenueMetric, fee); err != nil {
+		return fmt.Errorf("failed to update system revenue: %w", err)
+	}
+	return nil
+}
+
+// Statement derives the mower's balance from the ledger.
+func (s *ledgerService) Statement(ctx context.Context, mowerID primitive.ObjectID) (*domain.WalletStatement, error) {
+	user, err := s.userRepo.FindUserByID(ctx, mowerID)
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to find mower: %w", err)
+	}
+
+	entries, err := s.ledgerRepo.FindEntriesByAccount(ctx, domain.MowerAccount(mowerID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load wallet entries: %w", err)
+	}
+	if entries == nil {
+		entries = []*domain.LedgerEntry{}
+	}
+
+	var balance int64
+	for _, entry := range entries {
+		balance += entry.Amount
+	}
+
+	return &domain.WalletStatement{
+		MowerID:       mowerID,
+		Currency:      s.currency,
+		Balance:       balance,
+		CachedBalance: user.WalletBalance,
+		Reconciled:    balance == user.WalletBalance,
+		Entries:       entries,
+	}, nil
+}
+
+// Revenue totals the platform revenue account per period.
+func (s *ledgerService) Revenue(ctx context.Context, period string, from, to time.Time) (*domain.RevenueReport, error) {
+	switch period {
+	case domain.PeriodDay, domain.PeriodWeek, domain.PeriodMonth:
+	default:
+		return nil, apperror.CustomError{Message: "period must be day, week or month"}
+	}
+	if !to.After(from) {
+		return nil, apperror.CustomError{Message: "to must be after from"}
+	}
+
+	buckets, err := s.ledgerRepo.SumByPeriod(ctx, domain.AccountPlatformRevenue, period, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate revenue: %w", err)
+	}
+	if buckets == nil {
+		buckets = []domain.RevenueBucket{}
+	}
+	allTime, err := s.metricsRepo.FindMetric(ctx, domain.SystemRevenueMetric)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load system revenue: %w", err)
+	}
+
+	report := &domain.RevenueReport{
+		Period:   period,
+		From:     from,
+		To:       to,
+		Currency: s.currency,
+		AllTime:  allTime.Value,
+		Buckets:  buckets,
+	}
+	for _, bucket := range buckets {
+		report.Total += bucket.Amount
+	}
+	return report, nil
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+	infrastructureServices "lawnconnect-api/internal/infrastructure/services"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LocationSettings bound mowers' service radius.
+type LocationSettings struct {
+	MaxRadiusKm float64
+}
+
+// ServiceRadiusInput is what a mower sets as their service radius. The base is
+// BaseAddress, geocoded, or the Lat and Lng given directly.
+type ServiceRadiusInput struct {
+	BaseAddress string
+	Lat, Lng    *float64
+	RadiusKm    float64
+}
+
+// LocationService geocodes addresses and manages where mowers look for work.
+type LocationService interface {
+	// Locate geocodes an address. An address the geocoder cannot find is
+	// returned as apperror.InvalidResource.
+	Locate(ctx context.Context, address string) (*domain.GeoPoint, error)
+	// GetServiceRadius returns the mower's service radius, or apperror.NotFound
+	// if they have not set one.
+	GetServiceRadius(ctx context.Context, mowerID primitive.ObjectID) (*domain.ServiceRadius, error)
+	// SetServiceRadius validates and stores the mower's service radius. Invalid
+	// input is returned as apperror.CustomError.
+	SetServiceRadius(ctx context.Context, mowerID primitive.ObjectID, input ServiceRadiusInput) (*domain.ServiceRadius, error)
+}
+
+type locationService struct {
+	geocoder infrastructureServices.Geocoder
+	userRepo repositories.UserRepository
+	settings LocationSettings
+}
+
+// NewLocationService creates a new LocationService.
+func NewLocationService(geocoder infrastructureServices.Geocoder, userRepo repositories.UserRepository, settings LocationSettings) LocationService {
+	return &locationService{geocoder: geocoder, userRepo: userRepo, settings: settings}
+}
+
+// Locate geocodes address into a GeoJSON point.
+func (s *locationService) Locate(ctx context.Context, address string) (*domain.GeoPoint, error) {
+	location, err := s.geocoder.Geocode(ctx, address)
+	if err != nil {
+		if errors.Is(err, infrastructureServices.ErrAddressNotFound) {
+			return nil, apperror.InvalidResource{Resource: "address"}
+		}
+		return nil, fmt.Errorf("service failed to geocode address: %w", err)
+	}
+	return domain.NewGeoPoint(location.Lat, location.Lng), nil
+}
+
+// GetServiceRadius retrieves the mower's service radius.
+func (s *locationService) GetServiceRadius(ctx context.Context, mowerID primitive.ObjectID) (*domain.ServiceRadius, error) {
+	mower, err := s.userRepo.FindUserByID(ctx, mowerID)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get mower: %w", err)
+	}
+	if mower.ServiceRadius == nil {
+		return nil, apperror.NotFound{Resource: "Service radius"}
+	}
+	return mower.ServiceRadius, nil
+}
+
+// SetServiceRadius geocodes the mower's base, if given as an address, and
+// stores it with the radius.
+func (s *locationService) SetServiceRadius(ctx context.Context, mowerID primitive.ObjectID, input ServiceRadiusInput) (*domain.ServiceRadius, error) {
+	if input.RadiusKm <= 0 || input.RadiusKm > s.settings.MaxRadiusKm {
+		return nil, apperror.CustomError{Message: fmt.Sprintf("Radius must be more than 0 and at most %g km", s.settings.MaxRadiusKm)}
+	}
+
+	radius := &domain.ServiceRadius{BaseAddress: strings.TrimSpace(input.BaseAddress), RadiusKm: input.RadiusKm}
+	switch {
+	case input.Lat != nil && input.Lng != nil:
+		if *input.Lat < -90 || *input.Lat > 90 || *input.Lng < -180 || *input.Lng > 180 {
+			return nil, apperror.CustomError{Message: "Latitude must be between -90 and 90 and longitude between -180 and 180"}
+		}
+		radius.BaseLocation = domain.NewGeoPoint(*input.Lat, *input.Lng)
+	case radius.BaseAddress != "":
+		location, err := s.Locate(ctx, radius.BaseAddress)
+		if err != nil {
+			if _, ok := err.(apperror.InvalidResource); ok {
+				return nil, apperror.CustomError{Message: "We could not locate your base address"}
+			}
+			return nil, err
+		}
+		radius.BaseLocation = location
+	default:
+		return nil, apperror.CustomError{Message: "A base address or latitude and longitude are required"}
+	}
+
+	update := bson.M{"$set": bson.M{"serviceRadius": radius, "updatedAt": time.Now()}}
+	if err := s.userRepo.UpdateUser(ctx, mowerID, update); err != nil {
+		return nil, fmt.Errorf("service failed to set service radius: %w", err)
+	}
+	return radius, nil
+}
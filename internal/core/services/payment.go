@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+	"lawnconnect-api/internal/infrastructure/logging"
+	infrastructureServices "lawnconnect-api/internal/infrastructure/services"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PaymentSettings configures card payments.
+type PaymentSettings struct {
+	Currency string
+	// AuthorizationAmount is the hold placed on the customer's card when a booking
+	// is made, in minor units. The price set on completion is captured from it
+	// and may not exceed it.
+	AuthorizationAmount int64
+}
+
+// PaymentService takes card payments for bookings through a PaymentProvider.
+type PaymentService interface {
+	// Authorize places the booking's hold on a payment method. Declined cards are
+	// returned as apperror.PaymentRequired.
+	Authorize(ctx context.Context, bookingID primitive.ObjectID, paymentMethod string) (*domain.BookingPayment, error)
+	// Capture charges amount for a booking being completed. A failed capture does
+	// not stop the completion: the hold is voided, the returned payment is marked
+	// failed and the customer pays the invoice instead.
+	Capture(ctx context.Context, booking *domain.Booking, amount int64) *domain.BookingPayment
+	// Release refunds the booking's payment, or voids it if it was never
+	// captured, when the booking is cancelled or rejected.
+	Release(ctx context.Context, booking *domain.Booking) (*domain.BookingPayment, error)
+	// HandleWebhook verifies a provider webhook and applies its event to the
+	// booking. Redelivered events are ignored. An invalid signature is returned as
+	// apperror.CustomError.
+	HandleWebhook(ctx context.Context, payload []byte, header http.Header) error
+}
+
+type paymentService struct {
+	provider    infrastructureServices.PaymentProvider
+	bookingRepo repositories.BookingRepository
+	eventRepo   repositories.PaymentEventRepository
+	invoices    InvoiceService
+	tx          database.Transactor
+	settings    PaymentSettings
+}
+
+// NewPaymentService creates a new PaymentService.
+func NewPaymentService(provider infrastructureServices.PaymentProvider, bookingRepo repositories.BookingRepository, eventRepo repositories.PaymentEventRepository, invoices InvoiceService, tx database.Transactor, settings PaymentSettings) PaymentService {
+	return &paymentService{
+		provider:    provider,
+		bookingRepo: bookingRepo,
+		eventRepo:   eventRepo,
+		invoices:    invoices,
+		tx:          tx,
+		settings:    settings,
+	}
+}
+
+// Idempotency keys are derived from the booking, so a retried request never
+// authorizes, captures or refunds a booking twice.
+func paymentKey(bookingID primitive.ObjectID, operation string) string {
+	return "booking-" + bookingID.Hex() + "-" + operation
+}
+
+// Authorize holds the configured authorization amount.
+func (s *paymentService) Authorize(ctx context.Context, bookingID primitive.ObjectID, paymentMethod string) (*domain.BookingPayment, error) {
+	result, err := s.provider.Authorize(ctx, infrastructureServices.AuthorizeRequest{
+		PaymentMethod:  paymentMethod,
+		Amount:         s.settings.AuthorizationAmount,
+		Currency:       s.settings.Currency,
+		Reference:      bookingID.Hex(),
+		IdempotencyKey: paymentKey(bookingID, "authorize"),
+	})
+	if err != nil {
+		var declined infrastructureServices.PaymentDeclinedError
+		if errors.As(err, &declined) {
+			return nil, apperror.PaymentRequired{Message: declined.Message}
+		}
+		return nil, fmt.Errorf("failed to authorize payment: %w", err)
+	}
+	return s.fromResult(result, time.Now()), nil
+}
+
+// Capture charges the booking's authorized payment. When the processor declines
+// the capture the hold is voided, so the customer's funds are not tied up while
+// they pay the invoice. A call that failed in transit may still have captured,
+// so its hold is left for the processor's verdict to arrive by webhook.
+func (s *paymentService) Capture(ctx context.Context, booking *domain.Booking, amount int64) *domain.BookingPayment {
+	payment := *booking.Payment
+	payment.UpdatedAt = time.Now()
+	log := logging.FromContext(ctx).With("booking_id", booking.ID.Hex(), "payment_id", payment.PaymentID)
+
+	result, err := s.provider.Capture(ctx, payment.PaymentID, amount, paymentKey(booking.ID, "capture"))
+	if err != nil {
+		log.Warn("capturing payment failed", "error", err)
+		payment.Status = domain.PaymentFailed
+		payment.FailureReason = err.Error()
+		var declined infrastructureServices.PaymentDeclinedError
+		if errors.As(err, &declined) {
+			payment.FailureReason = declined.Message
+			if _, err := s.provider.Refund(ctx, payment.PaymentID, 0, paymentKey(booking.ID, "void")); err != nil {
+				log.Error("voiding the hold after a declined capture failed", "error", err)
+			}
+		}
+		return &payment
+	}
+	return s.fromResult(result, payment.UpdatedAt)
+}
+
+// Release refunds or voids the booking's payment through the provider.
+func (s *paymentService) Release(ctx context.Context, booking *domain.Booking) (*domain.BookingPayment, error) {
+	result, err := s.provider.Refund(ctx, booking.Payment.PaymentID, 0, paymentKey(booking.ID, "refund"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to release payment %s: %w", booking.Payment.PaymentID, err)
+	}
+	return s.fromResult(result, time.Now()), nil
+}
+
+func (s *paymentService) fromResult(result *infrastructureServices.PaymentResult, now time.Time) *domain.BookingPayment {
+	return &domain.BookingPayment{
+		Provider:         s.provider.Name(),
+		PaymentID:        result.ID,
+		Status:           result.Status,
+		AuthorizedAmount: result.Amount,
+		CapturedAmount:   result.Captured,
+		RefundedAmount:   result.Refunded,
+		Currency:         result.Currency,
+		UpdatedAt:        now,
+	}
+}
+
+// errEventSeen aborts the webhook transaction for an event already processed.
+var errEventSeen = errors.New("payment event already processed")
+
+// HandleWebhook records the event and applies it in one transaction, so an
+// event is either fully applied or retried on redelivery.
+func (s *paymentService) HandleWebhook(ctx context.Context, payload []byte, header http.Header) error {
+	event, err := s.provider.VerifyWebhook(payload, header)
+	if err != nil {
+		logging.FromContext(ctx).Warn("rejected payment webhook", "error", err)
+		return apperror.CustomError{Message: "Invalid webhook signature"}
+	}
+	log := logging.FromContext(ctx).With("event_id", event.ID, "event_type", event.Type, "payment_id", event.PaymentID)
+
+	bookingID, err := primitive.ObjectIDFromHex(event.Reference)
+	if err != nil {
+		log.Warn("ignoring payment webhook without a booking reference")
+		return nil
+	}
+
+	err = s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		err := s.eventRepo.RecordEvent(ctx, &domain.PaymentEvent{
+			ID:         s.provider.Name() + ":" + event.ID,
+			Type:       event.Type,
+			PaymentID:  event.PaymentID,
+			BookingID:  bookingID,
+			ReceivedAt: time.Now(),
+		})
+		if err != nil {
+			if _, ok := err.(apperror.DuplicateError); ok {
+				return errEventSeen
+			}
+			return err
+		}
+
+		booking, err := s.bookingRepo.FindBookingByID(ctx, bookingID)
+		if err != nil {
+			if _, ok := err.(apperror.NotFound); ok {
+				log.Warn("ignoring payment webhook for unknown booking", "booking_id", bookingID.Hex())
+				return nil
+			}
+			return err
+		}
+		if booking.Payment == nil || booking.Payment.PaymentID != event.PaymentID {
+			log.Warn("ignoring payment webhook for another payment", "booking_id", bookingID.Hex())
+			return nil
+		}
+		return s.applyEvent(ctx, booking, event)
+	})
+	if errors.Is(err, errEventSeen) {
+		log.Info("ignoring redelivered payment webhook")
+		return nil
+	}
+	return err
+}
+
+// applyEvent moves the booking's payment forward. Events that would move it
+// backwards, such as a late failure for a payment already captured, are ignored.
+func (s *paymentService) applyEvent(ctx context.Context, booking *domain.Booking, event *infrastructureServices.PaymentEvent) error {
+	payment := *booking.Payment
+	now := time.Now()
+
+	switch event.Type {
+	case infrastructureServices.PaymentEventCaptured:
+		if payment.Status != domain.PaymentAuthorized && payment.Status != domain.PaymentFailed {
+			return nil
+		}
+		payment.Status = domain.PaymentCaptured
+		payment.CapturedAmount = event.Amount
+		payment.FailureReason = ""
+	case infrastructureServices.PaymentEventFailed:
+		if payment.Status != domain.PaymentAuthorized {
+			return nil
+		}
+		payment.Status = domain.PaymentFailed
+		payment.FailureReason = event.Reason
+	case infrastructureServices.PaymentEventRefunded:
+		switch payment.Status {
+		case domain.PaymentAuthorized:
+			payment.Status = domain.PaymentVoided
+		case domain.PaymentCaptured:
+			payment.RefundedAmount += event.Amount
+			if payment.RefundedAmount >= payment.CapturedAmount {
+				payment.Status = domain.PaymentRefunded
+			}
+		default:
+			return nil
+		}
+	default:
+		logging.FromContext(ctx).Info("ignoring unhandled payment webhook", "event_type", event.Type)
+		return nil
+	}
+	payment.UpdatedAt = now
+
+	err := s.bookingRepo.UpdateBooking(ctx, booking.ID, bson.M{"$set": bson.M{"payment": payment, "updatedAt": now}})
+	if err != nil {
+		return fmt.Errorf("failed to update booking payment: %w", err)
+	}
+
+	// A capture confirmed after completion settles the invoice issued then.
+	if payment.Status == domain.PaymentCaptured && !booking.InvoiceID.IsZero() {
+		if _, err := s.invoices.SettleInvoice(ctx, booking.InvoiceID, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,431 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+	"lawnconnect-api/internal/infrastructure/documents"
+	"lawnconnect-api/internal/infrastructure/logging"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Payout batch export formats.
+const (
+	PayoutExportCSV   = "csv"
+	PayoutExportNACHA = "nacha"
+)
+
+// PayoutSettings configures mower payouts.
+type PayoutSettings struct {
+	Currency string
+	// MinimumAmount is the smallest payout a mower may request, in minor units.
+	MinimumAmount int64
+	// HoldPeriod is how long earnings stay in the wallet before they can be
+	// withdrawn, leaving time for refunds and disputes.
+	HoldPeriod time.Duration
+	Originator documents.Originator
+}
+
+// PayoutService lets mowers withdraw their wallet balance. Mowers request a
+// payout, an admin approves it, and a batch run debits the approved payouts
+// from the mowers' wallets and produces the bank transfer file.
+type PayoutService interface {
+	// SetPayoutDetails validates and stores the bank account a mower is paid into.
+	SetPayoutDetails(ctx context.Context, mowerID primitive.ObjectID, details domain.PayoutDetails) (*domain.PayoutDetails, error)
+	// Balance reports how much of a mower's wallet can be withdrawn at now.
+	Balance(ctx context.Context, mowerID primitive.ObjectID, now time.Time) (*domain.PayoutBalance, error)
+	// RequestPayout asks for amount minor units to be paid out. Requests below the
+	// minimum, above the available balance, without payout details or while
+	// another request is open are returned as apperror.CustomError.
+	RequestPayout(ctx context.Context, mowerID primitive.ObjectID, amount int64) (*domain.PayoutRequest, error)
+	ListMowerPayouts(ctx context.Context, mowerID primitive.ObjectID) ([]*domain.PayoutRequest, error)
+	// ListPayouts returns the payout requests in status, oldest first, for review.
+	ListPayouts(ctx context.Context, status string) ([]*domain.PayoutRequest, error)
+	ApprovePayout(ctx context.Context, requestID, adminID primitive.ObjectID) (*domain.PayoutRequest, error)
+	RejectPayout(ctx context.Context, requestID, adminID primitive.ObjectID, reason string) (*domain.PayoutRequest, error)
+	// RunBatch pays every approved request the mower's wallet still covers and
+	// records them as one batch. It returns nil when there was nothing to pay.
+	// createdBy is the admin running it, or zero for the scheduled job.
+	RunBatch(ctx context.Context, createdBy primitive.ObjectID, now time.Time) (*domain.PayoutBatch, error)
+	ListBatches(ctx context.Context) ([]*domain.PayoutBatch, error)
+	// ExportBatch renders a batch as a CSV or NACHA file.
+	ExportBatch(ctx context.Context, batchID primitive.ObjectID, format string) (*domain.PayoutBatch, []byte, error)
+}
+
+type payoutService struct {
+	payoutRepo  repositories.PayoutRepository
+	userRepo    repositories.UserRepository
+	invoiceRepo repositories.InvoiceRepository
+	ledger      LedgerService
+	tx          database.Transactor
+	settings    PayoutSettings
+}
+
+// NewPayoutService creates a new PayoutService.
+func NewPayoutService(payoutRepo repositories.PayoutRepository, userRepo repositories.UserRepository, invoiceRepo repositories.InvoiceRepository, ledger LedgerService, tx database.Transactor, settings PayoutSettings) PayoutService {
+	return &payoutService{
+		payoutRepo:  payoutRepo,
+		userRepo:    userRepo,
+		invoiceRepo: invoiceRepo,
+		ledger:      ledger,
+		tx:          tx,
+		settings:    settings,
+	}
+}
+
+// SetPayoutDetails stores the account on the mower's profile. Open requests keep
+// the account they were made with.
+func (s *payoutService) SetPayoutDetails(ctx context.Context, mowerID primitive.ObjectID, details domain.PayoutDetails) (*domain.PayoutDetails, error) {
+	details.AccountHolder = strings.TrimSpace(details.AccountHolder)
+	details.RoutingNumber = strings.TrimSpace(details.RoutingNumber)
+	details.AccountNumber = strings.TrimSpace(details.AccountNumber)
+	if details.AccountType == "" {
+		details.AccountType = domain.BankAccountChecking
+	}
+
+	switch {
+	case details.AccountHolder == "":
+		return nil, apperror.CustomError{Message: "Account holder name is required"}
+	case !domain.ValidRoutingNumber(details.RoutingNumber):
+		return nil, apperror.CustomError{Message: "Routing number must be a valid nine-digit ABA routing number"}
+	case len(details.AccountNumber) < 4 || len(details.AccountNumber) > 17 || strings.Trim(details.AccountNumber, "0123456789") != "":
+		return nil, apperror.CustomError{Message: "Account number must be 4 to 17 digits"}
+	case details.AccountType != domain.BankAccountChecking && details.AccountType != domain.BankAccountSavings:
+		return nil, apperror.CustomError{Message: "Account type must be checking or savings"}
+	}
+	details.AccountLast4 = details.AccountNumber[len(details.AccountNumber)-4:]
+	details.UpdatedAt = time.Now()
+
+	err := s.userRepo.UpdateUser(ctx, mowerID, bson.M{"$set": bson.M{"payoutDetails": details, "updatedAt": details.UpdatedAt}})
+	if err != nil {
+		return nil, fmt.Errorf("service failed to save payout details: %w", err)
+	}
+	return &details, nil
+}
+
+// Balance derives the withdrawable amount from the mower's ledger entries: the
+// balance, less earnings from bookings whose invoice is unpaid, earnings booked
+// within the hold period and the open request.
+func (s *payoutService) Balance(ctx context.Context, mowerID primitive.ObjectID, now time.Time) (*domain.PayoutBalance, error) {
+	statement, err := s.ledger.Statement(ctx, mowerID)
+	if err != nil {
+		return nil, err
+	}
+	unpaid, err := s.invoiceRepo.FindUnpaidInvoicesByMowerID(ctx, mowerID)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to find unpaid invoices: %w", err)
+	}
+	unsettled := make(map[primitive.ObjectID]bool, len(unpaid))
+	for _, invoice := range unpaid {
+		unsettled[invoice.BookingID] = true
+	}
+
+	balance := &domain.PayoutBalance{
+		Currency:      s.settings.Currency,
+		Balance:       statement.Balance,
+		MinimumAmount: s.settings.MinimumAmount,
+	}
+	cutoff := now.Add(-s.settings.HoldPeriod)
+	for _, entry := range statement.Entries {
+		if entry.Type != domain.LedgerBookingPayment && entry.Type != domain.LedgerPlatformCommission {
+			continue
+		}
+		switch {
+		case unsettled[entry.BookingID]:
+			balance.Unsettled += entry.Amount
+		case entry.CreatedAt.After(cutoff):
+			balance.Held += entry.Amount
+		}
+	}
+	if balance.Held < 0 {
+		balance.Held = 0
+	}
+	if balance.Unsettled < 0 {
+		balance.Unsettled = 0
+	}
+
+	open, err := s.payoutRepo.FindOpenPayoutRequest(ctx, mowerID)
+	switch err.(type) {
+	case nil:
+		balance.Pending = open.Amount
+	case apperror.NotFound:
+	default:
+		return nil, fmt.Errorf("service failed to find open payout request: %w", err)
+	}
+
+	balance.Available = balance.Balance - balance.Held - balance.Unsettled - balance.Pending
+	if balance.Available < 0 {
+		balance.Available = 0
+	}
+	return balance, nil
+}
+
+// RequestPayout records the request with a copy of the mower's current payout details.
+func (s *payoutService) RequestPayout(ctx context.Context, mowerID primitive.ObjectID, amount int64) (*domain.PayoutRequest, error) {
+	if amount < s.settings.MinimumAmount {
+		return nil, apperror.CustomError{Message: "Payouts must be at least " + domain.FormatMinorUnits(s.settings.MinimumAmount, s.settings.Currency)}
+	}
+
+	mower, err := s.userRepo.FindUserByID(ctx, mowerID)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to find mower: %w", err)
+	}
+	if mower.PayoutDetails == nil {
+		return nil, apperror.CustomError{Message: "Add your payout details before requesting a payout"}
+	}
+
+	now := time.Now()
+	balance, err := s.Balance(ctx, mowerID, now)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Pending > 0 {
+		return nil, apperror.CustomError{Message: "You already have a payout request in progress"}
+	}
+	if amount > balance.Available {
+		return nil, apperror.CustomError{Message: "Only " + domain.FormatMinorUnits(balance.Available, s.settings.Currency) + " is available to withdraw; recent earnings are held for " + s.settings.HoldPeriod.String() + " and earnings from unpaid invoices until they are paid"}
+	}
+
+	request := &domain.PayoutRequest{
+		ID:          primitive.NewObjectID(),
+		MowerID:     mowerID,
+		MowerName:   mower.Name,
+		Amount:      amount,
+		Currency:    s.settings.Currency,
+		Destination: *mower.PayoutDetails,
+		Status:      domain.PayoutRequested,
+		Open:        true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.payoutRepo.CreatePayoutRequest(ctx, request); err != nil {
+		if _, ok := err.(apperror.DuplicateError); ok {
+			return nil, apperror.CustomError{Message: "You already have a payout request in progress"}
+		}
+		return nil, fmt.Errorf("service failed to create payout request: %w", err)
+	}
+	return request, nil
+}
+
+// ListMowerPayouts returns a mower's payout requests, newest first.
+func (s *payoutService) ListMowerPayouts(ctx context.Context, mowerID primitive.ObjectID) ([]*domain.PayoutRequest, error) {
+	requests, err := s.payoutRepo.FindPayoutRequestsByMowerID(ctx, mowerID)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to list payout requests: %w", err)
+	}
+	return requests, nil
+}
+
+// ListPayouts returns the payout requests in status.
+func (s *payoutService) ListPayouts(ctx context.Context, status string) ([]*domain.PayoutRequest, error) {
+	switch status {
+	case domain.PayoutRequested, domain.PayoutApproved, domain.PayoutRejected, domain.PayoutPaid:
+	default:
+		return nil, apperror.CustomError{Message: "status must be requested, approved, rejected or paid"}
+	}
+	requests, err := s.payoutRepo.FindPayoutRequestsByStatus(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to list payout requests: %w", err)
+	}
+	return requests, nil
+}
+
+// ApprovePayout queues a requested payout for the next batch.
+func (s *payoutService) ApprovePayout(ctx context.Context, requestID, adminID primitive.ObjectID) (*domain.PayoutRequest, error) {
+	return s.review(ctx, requestID, func(request *domain.PayoutRequest, now time.Time) (bson.M, error) {
+		if request.Status != domain.PayoutRequested {
+			return nil, apperror.CustomError{Message: "Payout request is " + request.Status + " and cannot be approved"}
+		}
+		request.Status = domain.PayoutApproved
+		request.ReviewedBy = adminID
+		request.ReviewedAt = &now
+		return bson.M{"$set": bson.M{"status": request.Status, "reviewedBy": adminID, "reviewedAt": now, "updatedAt": now}}, nil
+	})
+}
+
+// RejectPayout closes a payout request that has not been paid yet.
+func (s *payoutService) RejectPayout(ctx context.Context, requestID, adminID primitive.ObjectID, reason string) (*domain.PayoutRequest, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, apperror.CustomError{Message: "A reason is required to reject a payout"}
+	}
+	return s.review(ctx, requestID, func(request *domain.PayoutRequest, now time.Time) (bson.M, error) {
+		if request.Status != domain.PayoutRequested && request.Status != domain.PayoutApproved {
+			return nil, apperror.CustomError{Message: "Payout request is " + request.Status + " and cannot be rejected"}
+		}
+		request.Status = domain.PayoutRejected
+		request.Open = false
+		request.ReviewedBy = adminID
+		request.ReviewedAt = &now
+		request.RejectionReason = reason
+		return bson.M{
+			"$set":   bson.M{"status": request.Status, "reviewedBy": adminID, "reviewedAt": now, "rejectionReason": reason, "updatedAt": now},
+			"$unset": bson.M{"open": ""},
+		}, nil
+	})
+}
+
+// review loads a request and applies the update built by decide in one
+// transaction, so concurrent reviews and batch runs see a consistent status.
+func (s *payoutService) review(ctx context.Context, requestID primitive.ObjectID, decide func(request *domain.PayoutRequest, now time.Time) (bson.M, error)) (*domain.PayoutRequest, error) {
+	var request *domain.PayoutRequest
+	err := s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		request, err = s.payoutRepo.FindPayoutRequestByID(ctx, requestID)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		update, err := decide(request, now)
+		if err != nil {
+			return err
+		}
+		request.UpdatedAt = now
+		return s.payoutRepo.UpdatePayoutRequest(ctx, request.ID, update)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// RunBatch pays the approved requests in one transaction, so the ledger entries,
+// the paid requests and the batch are recorded together or not at all. A
+// request the wallet no longer covers, for example after an adjustment, stays
+// approved for an admin to reject.
+func (s *payoutService) RunBatch(ctx context.Context, createdBy primitive.ObjectID, now time.Time) (*domain.PayoutBatch, error) {
+	var batch *domain.PayoutBatch
+	err := s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		batch = nil
+		approved, err := s.payoutRepo.FindPayoutRequestsByStatus(ctx, domain.PayoutApproved)
+		if err != nil {
+			return err
+		}
+
+		pending := &domain.PayoutBatch{
+			ID:         primitive.NewObjectID(),
+			RequestIDs: []primitive.ObjectID{},
+			Currency:   s.settings.Currency,
+			CreatedBy:  createdBy,
+			CreatedAt:  now,
+		}
+		for _, request := range approved {
+			paid, err := s.pay(ctx, pending, request, now)
+			if err != nil {
+				return err
+			}
+			if paid {
+				pending.RequestIDs = append(pending.RequestIDs, request.ID)
+				pending.Count++
+				pending.Total += request.Amount
+			}
+		}
+		if pending.Count == 0 {
+			return nil
+		}
+
+		if err := s.payoutRepo.CreatePayoutBatch(ctx, pending); err != nil {
+			return err
+		}
+		batch = pending
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if batch != nil {
+		logging.FromContext(ctx).Info("payout batch created", "batch_id", batch.ID.Hex(), "count", batch.Count, "total", batch.Total)
+	}
+	return batch, nil
+}
+
+// pay debits one request from the mower's wallet and marks it paid in batch.
+// It reports false if the wallet does not cover the request.
+func (s *payoutService) pay(ctx context.Context, batch *domain.PayoutBatch, request *domain.PayoutRequest, now time.Time) (bool, error) {
+	statement, err := s.ledger.Statement(ctx, request.MowerID)
+	if err != nil {
+		return false, err
+	}
+	if statement.Balance < request.Amount {
+		logging.FromContext(ctx).Warn("payout exceeds wallet balance, leaving it approved",
+			"payout_id", request.ID.Hex(), "amount", request.Amount, "balance", statement.Balance)
+		return false, nil
+	}
+
+	entries, err := s.ledger.Post(ctx, domain.LedgerPayout, primitive.NilObjectID,
+		"Payout "+request.ID.Hex()+" in batch "+batch.ID.Hex(),
+		Posting{Account: domain.MowerAccount(request.MowerID), Amount: -request.Amount},
+		Posting{Account: domain.AccountPayouts, Amount: request.Amount},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	err = s.payoutRepo.UpdatePayoutRequest(ctx, request.ID, bson.M{
+		"$set": bson.M{
+			"status":        domain.PayoutPaid,
+			"batchId":       batch.ID,
+			"transactionId": entries[0].TransactionID,
+			"paidAt":        now,
+			"updatedAt":     now,
+		},
+		"$unset": bson.M{"open": ""},
+	})
+	if err != nil {
+		return false, fmt.Errorf("service failed to mark payout paid: %w", err)
+	}
+	return true, nil
+}
+
+// ListBatches returns every payout batch, newest first.
+func (s *payoutService) ListBatches(ctx context.Context) ([]*domain.PayoutBatch, error) {
+	batches, err := s.payoutRepo.FindPayoutBatches(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to list payout batches: %w", err)
+	}
+	return batches, nil
+}
+
+// ExportBatch renders the batch's paid requests. The file is dated when the
+// batch ran, so downloading it again produces the same file.
+func (s *payoutService) ExportBatch(ctx context.Context, batchID primitive.ObjectID, format string) (*domain.PayoutBatch, []byte, error) {
+	if format != PayoutExportCSV && format != PayoutExportNACHA {
+		return nil, nil, apperror.CustomError{Message: "format must be csv or nacha"}
+	}
+	if format == PayoutExportNACHA {
+		if !s.settings.Originator.Configured() {
+			return nil, nil, apperror.CustomError{Message: "NACHA export is not configured"}
+		}
+		if s.settings.Currency != "USD" {
+			return nil, nil, apperror.CustomError{Message: "NACHA export is only available for USD payouts"}
+		}
+	}
+
+	batch, err := s.payoutRepo.FindPayoutBatchByID(ctx, batchID)
+	if err != nil {
+		return nil, nil, err
+	}
+	requests, err := s.payoutRepo.FindPayoutRequestsByBatchID(ctx, batchID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("service failed to load payout batch requests: %w", err)
+	}
+
+	var file []byte
+	if format == PayoutExportNACHA {
+		file, err = documents.PayoutNACHA(batch, requests, s.settings.Originator, batch.CreatedAt)
+	} else {
+		file, err = documents.PayoutCSV(batch, requests)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return batch, file, nil
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+)
+
+// AreaBand is one tier of a service's area pricing.
+type AreaBand struct {
+	// UpTo is the lawn area, in square metres, where the band ends. The last band
+	// has zero and covers any larger lawn.
+	UpTo float64
+	// Per100Sqm is the price of each 100 square metres within the band, in minor
+	// units.
+	Per100Sqm int64
+}
+
+// ServiceRate prices one service: a base fee per visit plus the lawn area priced
+// band by band, so each square metre is charged at the rate of the band it
+// falls in.
+type ServiceRate struct {
+	Name    string
+	BaseFee int64 // In minor units
+	Bands   []AreaBand
+}
+
+// PricingSettings are the rate tables behind price estimates.
+type PricingSettings struct {
+	Currency string
+	Services []ServiceRate
+	// Regions and Terrain map a property's region or terrain to a multiplier.
+	// Anything not listed is priced at 1.
+	Regions map[string]float64
+	Terrain map[string]float64
+	// WeekendMultiplier applies on Saturdays and Sundays and HolidayMultiplier on
+	// Holidays (YYYY-MM-DD). When both apply, the larger is used.
+	WeekendMultiplier float64
+	HolidayMultiplier float64
+	Holidays          []string
+	// RangeBps widens the estimate into a range, in basis points either side of
+	// the most likely price.
+	RangeBps int64
+}
+
+// PricingService estimates prices from the configured rate tables.
+type PricingService interface {
+	// Estimate prices service for the property, in area if it is in one, on a
+	// YYYY-MM-DD date. An empty service uses the property's ServiceType. An
+	// unknown service or a malformed date is returned as
+	// apperror.InvalidResource.
+	Estimate(ctx context.Context, property *domain.Property, area *domain.ServiceArea, service, date string) (*domain.PriceEstimate, error)
+	// Services returns the names of the priced services, sorted.
+	Services() []string
+	// HasRegion reports whether the rate tables list region.
+	HasRegion(region string) bool
+}
+
+type pricingService struct {
+	settings PricingSettings
+	services map[string]ServiceRate
+	holidays map[string]bool
+}
+
+// NewPricingService creates a new PricingService.
+func NewPricingService(settings PricingSettings) PricingService {
+	s := &pricingService{
+		settings: settings,
+		services: make(map[string]ServiceRate, len(settings.Services)),
+		holidays: make(map[string]bool, len(settings.Holidays)),
+	}
+	for _, rate := range settings.Services {
+		s.services[rate.Name] = rate
+	}
+	for _, day := range settings.Holidays {
+		s.holidays[day] = true
+	}
+	return s
+}
+
+// Estimate applies the region, service area, terrain and day multipliers to the
+// service's area price and widens the result by RangeBps, rounding the bounds
+// outwards to whole currency units.
+func (s *pricingService) Estimate(ctx context.Context, property *domain.Property, area *domain.ServiceArea, service, date string) (*domain.PriceEstimate, error) {
+	if service == "" {
+		service = property.ServiceType
+	}
+	rate, ok := s.services[service]
+	if !ok {
+		return nil, apperror.InvalidResource{Resource: "service"}
+	}
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil, apperror.InvalidResource{Resource: "booking date"}
+	}
+
+	estimate := &domain.PriceEstimate{
+		Service:           service,
+		Currency:          s.settings.Currency,
+		LawnArea:          property.LawnArea,
+		Region:            property.Region,
+		RegionMultiplier:  multiplier(s.settings.Regions, property.Region),
+		AreaMultiplier:    1,
+		TerrainMultiplier: multiplier(s.settings.Terrain, property.Terrain),
+		DayMultiplier:     s.dayMultiplier(day, date),
+		CalculatedAt:      time.Now(),
+	}
+
+	if area != nil {
+		estimate.ServiceArea = area.Name
+		if area.PriceMultiplier > 0 {
+			estimate.AreaMultiplier = area.PriceMultiplier
+		}
+	}
+
+	price := float64(rate.BaseFee + areaPrice(rate.Bands, property.LawnArea))
+	price *= estimate.RegionMultiplier * estimate.AreaMultiplier * estimate.TerrainMultiplier * estimate.DayMultiplier
+	estimate.Amount = int64(math.Round(price))
+
+	estimate.Low, estimate.High = estimate.Amount, estimate.Amount
+	if s.settings.RangeBps > 0 {
+		spread := float64(s.settings.RangeBps) / 10000
+		unit := float64(domain.MinorUnitsPerMajor(s.settings.Currency))
+		estimate.Low = int64(math.Floor(price*(1-spread)/unit) * unit)
+		estimate.High = int64(math.Ceil(price*(1+spread)/unit) * unit)
+	}
+	return estimate, nil
+}
+
+// areaPrice prices area square metres across the bands.
+func areaPrice(bands []AreaBand, area float64) int64 {
+	var price, from float64
+	for _, band := range bands {
+		if area <= from {
+			break
+		}
+		to := area
+		if band.UpTo > 0 && band.UpTo < area {
+			to = band.UpTo
+		}
+		price += (to - from) / 100 * float64(band.Per100Sqm)
+		from = to
+	}
+	return int64(math.Round(price))
+}
+
+func (s *pricingService) dayMultiplier(day time.Time, date string) float64 {
+	m := 1.0
+	if weekday := day.Weekday(); (weekday == time.Saturday || weekday == time.Sunday) && s.settings.WeekendMultiplier > m {
+		m = s.settings.WeekendMultiplier
+	}
+	if s.holidays[date] && s.settings.HolidayMultiplier > m {
+		m = s.settings.HolidayMultiplier
+	}
+	return m
+}
+
+// multiplier returns table[key], or 1 if the key is not listed.
+func multiplier(table map[string]float64, key string) float64 {
+	if m, ok := table[key]; ok {
+		return m
+	}
+	return 1
+}
+
+// Services returns the names of the priced services.
+func (s *pricingService) Services() []string {
+	names := make([]string, 0, len(s.services))
+	for name := range s.services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasRegion reports whether region has its own multiplier.
+func (s *pricingService) HasRegion(region string) bool {
+	_, ok := s.settings.Regions[region]
+	return ok
+}
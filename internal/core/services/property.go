@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+	infrastructureServices "lawnconnect-api/internal/infrastructure/services"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxPropertyPhotos is how many photos a property can have.
+const MaxPropertyPhotos = 10
+
+// maxAccessNotesLength bounds a property's access notes and pets warning.
+const maxAccessNotesLength = 1000
+
+// PropertyInput is what a customer sets on a property.
+type PropertyInput struct {
+	Label       string // Defaults to the first address line
+	Address     domain.PostalAddress
+	Region      string
+	LawnArea    float64 // In square metres
+	Terrain     string  // Defaults to flat
+	ServiceType string
+	AccessNotes string
+	Pets        string
+}
+
+// PropertyService manages customers' properties and their price estimates.
+type PropertyService interface {
+	// CreateProperty validates input against the pricing rate tables, geocodes
+	// its address and stores it. Invalid input, including an address that cannot
+	// be located, is returned as apperror.CustomError.
+	CreateProperty(ctx context.Context, customerID primitive.ObjectID, input PropertyInput) (*domain.Property, error)
+	ListProperties(ctx context.Context, customerID primitive.ObjectID) ([]*domain.Property, error)
+	// GetProperty returns one of the customer's properties. Other customers' and
+	// archived properties are returned as apperror.NotFound.
+	GetProperty(ctx context.Context, customerID, propertyID primitive.ObjectID) (*domain.Property, error)
+	// UpdateProperty replaces the property's details with input.
+	UpdateProperty(ctx context.Context, customerID, propertyID primitive.ObjectID, input PropertyInput) (*domain.Property, error)
+	// ArchiveProperty hides the property from the customer. Bookings made for it
+	// keep their reference.
+	ArchiveProperty(ctx context.Context, customerID, propertyID primitive.ObjectID) error
+	// EstimatePrice prices a service for the property on a YYYY-MM-DD date, at
+	// the rates of the service area it is in. An empty service uses the
+	// property's ServiceType.
+	EstimatePrice(ctx context.Context, customerID, propertyID primitive.ObjectID, service, date string) (*domain.PriceEstimate, error)
+	// AddPhoto uploads a photo of the property. A property has at most
+	// MaxPropertyPhotos; adding more is returned as apperror.CustomError.
+	AddPhoto(ctx context.Context, customerID, propertyID primitive.ObjectID, photo io.Reader, filename string) (*domain.Property, error)
+	// RemovePhoto removes the photo at index from the property.
+	RemovePhoto(ctx context.Context, customerID, propertyID primitive.ObjectID, index int) (*domain.Property, error)
+	// GetBookedProperty returns a property, archived or not, without checking
+	// who owns it. It is for the parties to a booking of the property.
+	GetBookedProperty(ctx context.Context, propertyID primitive.ObjectID) (*domain.Property, error)
+}
+
+type propertyService struct {
+	propertyRepo repositories.PropertyRepository
+	pricing      PricingService
+	locations    LocationService
+	areas        ServiceAreaService
+	uploads      infrastructureServices.UploadService
+}
+
+// NewPropertyService creates a new PropertyService.
+func NewPropertyService(propertyRepo repositories.PropertyRepository, pricing PricingService, locations LocationService, areas ServiceAreaService, uploads infrastructureServices.UploadService) PropertyService {
+	return &propertyService{propertyRepo: propertyRepo, pricing: pricing, locations: locations, areas: areas, uploads: uploads}
+}
+
+// CreateProperty stores a new property for the customer.
+func (s *propertyService) CreateProperty(ctx context.Context, customerID primitive.ObjectID, input PropertyInput) (*domain.Property, error) {
+	input, err := s.checkInput(input)
+	if err != nil {
+		return nil, err
+	}
+	location, err := s.locate(ctx, input.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	property := &domain.Property{
+		ID:          primitive.NewObjectID(),
+		CustomerID:  customerID,
+		Label:       input.Label,
+		Address:     input.Address,
+		Location:    location,
+		Region:      input.Region,
+		LawnArea:    input.LawnArea,
+		Terrain:     input.Terrain,
+		ServiceType: input.ServiceType,
+		AccessNotes: input.AccessNotes,
+		Pets:        input.Pets,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.propertyRepo.CreateProperty(ctx, property); err != nil {
+		return nil, fmt.Errorf("service failed to create property: %w", err)
+	}
+	return property, nil
+}
+
+// locate geocodes a property's address.
+func (s *propertyService) locate(ctx context.Context, address domain.PostalAddress) (*domain.GeoPoint, error) {
+	location, err := s.locations.Locate(ctx, address.String())
+	if err != nil {
+		if _, ok := err.(apperror.InvalidResource); ok {
+			return nil, apperror.CustomError{Message: "We could not locate this address"}
+		}
+		return nil, err
+	}
+	return location, nil
+}
+
+// checkInput trims input, fills in the default terrain and checks it against
+// the rate tables.
+func (s *propertyService) checkInput(input PropertyInput) (PropertyInput, error) {
+	address := &input.Address
+	for _, field := range []*string{&address.Line1, &address.Line2, &address.City, &address.State, &address.PostalCode, &address.Country} {
+		*field = strings.TrimSpace(*field)
+	}
+	input.Label = strings.TrimSpace(input.Label)
+	if input.Label == "" {
+		input.Label = address.Line1
+	}
+	input.Region = strings.TrimSpace(input.Region)
+	input.ServiceType = strings.TrimSpace(input.ServiceType)
+	input.AccessNotes = strings.TrimSpace(input.AccessNotes)
+	input.Pets = strings.TrimSpace(input.Pets)
+	if input.Terrain == "" {
+		input.Terrain = domain.TerrainFlat
+	}
+
+	if address.Line1 == "" || address.City == "" || address.PostalCode == "" {
+		return input, apperror.CustomError{Message: "Address line 1, city and postal code are required"}
+	}
+	if len(input.AccessNotes) > maxAccessNotesLength || len(input.Pets) > maxAccessNotesLength {
+		return input, apperror.CustomError{Message: fmt.Sprintf("Access notes and pets warning must be at most %d characters", maxAccessNotesLength)}
+	}
+	if input.LawnArea <= 0 {
+		return input, apperror.CustomError{Message: "Lawn area must be a positive number of square metres"}
+	}
+	switch input.Terrain {
+	case domain.TerrainFlat, domain.TerrainSloped, domain.TerrainSteep:
+	default:
+		return input, apperror.CustomError{Message: "Terrain must be flat, sloped or steep"}
+	}
+	services := s.pricing.Services()
+	known := false
+	for _, service := range services {
+		known = known || service == input.ServiceType
+	}
+	if !known {
+		return input, apperror.CustomError{Message: "Service type must be one of: " + strings.Join(services, ", ")}
+	}
+	if input.Region != "" && !s.pricing.HasRegion(input.Region) {
+		return input, apperror.CustomError{Message: fmt.Sprintf("Unknown region %q", input.Region)}
+	}
+	return input, nil
+}
+
+// ListProperties retrieves the customer's properties, oldest first.
+func (s *propertyService) ListProperties(ctx context.Context, customerID primitive.ObjectID) ([]*domain.Property, error) {
+	properties, err := s.propertyRepo.FindPropertiesByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to list properties: %w", err)
+	}
+	return properties, nil
+}
+
+// GetProperty retrieves one of the customer's properties.
+func (s *propertyService) GetProperty(ctx context.Context, customerID, propertyID primitive.ObjectID) (*domain.Property, error) {
+	property, err := s.propertyRepo.FindPropertyByID(ctx, propertyID)
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service failed to get property: %w", err)
+	}
+	if property.CustomerID != customerID || property.Archived {
+		return nil, apperror.NotFound{Resource: "Property"}
+	}
+	return property, nil
+}
+
+// UpdateProperty replaces the details of one of the customer's properties.
+func (s *propertyService) UpdateProperty(ctx context.Context, customerID, propertyID primitive.ObjectID, input PropertyInput) (*domain.Property, error) {
+	property, err := s.GetProperty(ctx, customerID, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	input, err = s.checkInput(input)
+	if err != nil {
+		return nil, err
+	}
+	if input.Address != property.Address || property.Location == nil {
+		if property.Location, err = s.locate(ctx, input.Address); err != nil {
+			return nil, err
+		}
+	}
+
+	property.Label = input.Label
+	property.Address = input.Address
+	property.Region = input.Region
+	property.LawnArea = input.LawnArea
+	property.Terrain = input.Terrain
+	property.ServiceType = input.ServiceType
+	property.AccessNotes = input.AccessNotes
+	property.Pets = input.Pets
+	property.UpdatedAt = time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"label":       property.Label,
+			"address":     property.Address,
+			"location":    property.Location,
+			"region":      property.Region,
+			"lawnArea":    property.LawnArea,
+			"terrain":     property.Terrain,
+			"serviceType": property.ServiceType,
+			"accessNotes": property.AccessNotes,
+			"pets":        property.Pets,
+			"updatedAt":   property.UpdatedAt,
+		},
+	}
+	if err := s.propertyRepo.UpdateProperty(ctx, propertyID, update); err != nil {
+		return nil, fmt.Errorf("service failed to update property: %w", err)
+	}
+	return property, nil
+}
+
+// ArchiveProperty archives one of the customer's properties.
+func (s *propertyService) ArchiveProperty(ctx context.Context, customerID, propertyID primitive.ObjectID) error {
+	if _, err := s.GetProperty(ctx, customerID, propertyID); err != nil {
+		return err
+	}
+	update := bson.M{"$set": bson.M{"archived": true, "updatedAt": time.Now()}}
+	if err := s.propertyRepo.UpdateProperty(ctx, propertyID, update); err != nil {
+		return fmt.Errorf("service failed to archive property: %w", err)
+	}
+	return nil
+}
+
+// EstimatePrice estimates the price of a visit to one of the customer's properties.
+func (s *propertyService) EstimatePrice(ctx context.Context, customerID, propertyID primitive.ObjectID, service, date string) (*domain.PriceEstimate, error) {
+	property, err := s.GetProperty(ctx, customerID, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	var area *domain.ServiceArea
+	if property.Location != nil {
+		area, err = s.areas.AreaAt(ctx, *property.Location)
+		if _, ok := err.(apperror.NotFound); ok {
+			area, err = nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.pricing.Estimate(ctx, property, area, service, date)
+}
+
+// AddPhoto uploads a photo and appends it to the property's photos.
+func (s *propertyService) AddPhoto(ctx context.Context, customerID, propertyID primitive.ObjectID, photo io.Reader, filename string) (*domain.Property, error) {
+	property, err := s.GetProperty(ctx, customerID, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(property.Photos) >= MaxPropertyPhotos {
+		return nil, apperror.CustomError{Message: fmt.Sprintf("A property can have at most %d photos", MaxPropertyPhotos)}
+	}
+
+	url, err := s.uploads.UploadFile(ctx, photo, filename)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to upload property photo: %w", err)
+	}
+	property.Photos = append(property.Photos, url)
+	if err := s.savePhotos(ctx, property); err != nil {
+		return nil, err
+	}
+	return property, nil
+}
+
+// RemovePhoto removes one of the property's photos.
+func (s *propertyService) RemovePhoto(ctx context.Context, customerID, propertyID primitive.ObjectID, index int) (*domain.Property, error) {
+	property, err := s.GetProperty(ctx, customerID, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(property.Photos) {
+		return nil, apperror.NotFound{Resource: "Photo"}
+	}
+
+	property.Photos = append(property.Photos[:index:index], property.Photos[index+1:]...)
+	if err := s.savePhotos(ctx, property); err != nil {
+		return nil, err
+	}
+	return property, nil
+}
+
+func (s *propertyService) savePhotos(ctx context.Context, property *domain.Property) error {
+	property.UpdatedAt = time.Now()
+	update := bson.M{"$set": bson.M{"photos": property.Photos, "updatedAt": property.UpdatedAt}}
+	if err := s.propertyRepo.UpdateProperty(ctx, property.ID, update); err != nil {
+		return fmt.Errorf("service failed to update property photos: %w", err)
+	}
+	return nil
+}
+
+// GetBookedProperty retrieves a property by ID.
+func (s *propertyService) GetBookedProperty(ctx context.Context, propertyID primitive.ObjectID) (*domain.Property, error) {
+	property, err := s.propertyRepo.FindPropertyByID(ctx, propertyID)
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service failed to get property: %w", err)
+	}
+	return property, nil
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxReviewLength bounds review comments and mowers' replies.
+const maxReviewLength = 2000
+
+// DefaultReviewLimit and MaxReviewLimit bound how many reviews a mower's
+// profile lists at once.
+const (
+	DefaultReviewLimit = 20
+	MaxReviewLimit     = 100
+)
+
+// ReviewSettings configure reviews.
+type ReviewSettings struct {
+	// Window is how long after completion a customer may review a booking.
+	Window time.Duration
+}
+
+// ReviewService manages customers' reviews of completed bookings and the
+// public mower profiles built from them.
+type ReviewService interface {
+	// CreateReview rates a booking the customer made, once it is completed and
+	// within the review window, and counts the rating into the mower's summary.
+	// Other customers' bookings are returned as apperror.NotFound, a rating
+	// outside 1 to 5 or an overlong comment as apperror.InvalidResource, a
+	// booking that cannot be reviewed as apperror.CustomError and a second
+	// review as apperror.DuplicateError.
+	CreateReview(ctx context.Context, customerID, bookingID primitive.ObjectID, rating int, comment string) (*domain.Review, error)
+	// GetBookingReview returns the review of a booking to its customer or
+	// mower. Anyone else gets apperror.NotFound.
+	GetBookingReview(ctx context.Context, userID, bookingID primitive.ObjectID) (*domain.Review, error)
+	// ReplyToReview adds the mower's reply to the review of their booking. A
+	// review can be replied to once; another reply is returned as
+	// apperror.DuplicateError.
+	ReplyToReview(ctx context.Context, mowerID, bookingID primitive.ObjectID, text string) (*domain.Review, error)
+	// MowerProfile returns the public profile of a mower. Users who are not
+	// mowers are returned as apperror.NotFound.
+	MowerProfile(ctx context.Context, mowerID primitive.ObjectID) (*domain.MowerProfile, error)
+	// ListMowerReviews returns up to limit of a mower's reviews, newest first.
+	// A limit outside 1 to MaxReviewLimit lists DefaultReviewLimit.
+	ListMowerReviews(ctx context.Context, mowerID primitive.ObjectID, limit int) ([]*domain.Review, error)
+}
+
+type reviewService struct {
+	reviewRepo  repositories.ReviewRepository
+	bookingRepo repositories.BookingRepository
+	userRepo    repositories.UserRepository
+	tx          database.Transactor
+	settings    ReviewSettings
+}
+
+// NewReviewService creates a new ReviewService.
+func NewReviewService(reviewRepo repositories.ReviewRepository, bookingRepo repositories.BookingRepository, userRepo repositories.UserRepository, tx database.Transactor, settings ReviewSettings) ReviewService {
+	return &reviewService{reviewRepo: reviewRepo, bookingRepo: bookingRepo, userRepo: userRepo, tx: tx, settings: settings}
+}
+
+// CreateReview stores the review, sets the booking's rating and adds the
+// comment to its thread, and updates the mower's rating summary, all in one
+// transaction.
+func (s *reviewService) CreateReview(ctx context.Context, customerID, bookingID primitive.ObjectID, rating int, comment string) (*domain.Review, error) {
+	if rating < 1 || rating > 5 {
+		return nil, apperror.InvalidResource{Resource: "rating"}
+	}
+	comment = strings.TrimSpace(comment)
+	if len(comment) > maxReviewLength {
+		return nil, apperror.InvalidResource{Resource: "comment"}
+	}
+
+	booking, err := s.bookingRepo.FindBookingByID(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.CustomerID != customerID {
+		return nil, apperror.NotFound{Resource: "Booking"}
+	}
+	if booking.Status != "completed" || booking.CompletedTime == nil {
+		return nil, apperror.CustomError{Message: "Only completed bookings can be reviewed"}
+	}
+	now := time.Now()
+	if now.After(booking.CompletedTime.Add(s.settings.Window)) {
+		return nil, apperror.CustomError{Message: "The review window for this booking has closed"}
+	}
+	customer, err := s.userRepo.FindUserByID(ctx, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get customer: %w", err)
+	}
+
+	review := &domain.Review{
+		ID:           primitive.NewObjectID(),
+		BookingID:    booking.ID,
+		CustomerID:   customerID,
+		CustomerName: firstName(customer.Name),
+		MowerID:      booking.MowerID,
+		Rating:       rating,
+		Comment:      comment,
+		CreatedAt:    now,
+	}
+	err = s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.reviewRepo.CreateReview(ctx, review); err != nil {
+			return err
+		}
+		update := bson.M{"$set": bson.M{"rating": rating, "updatedAt": now}}
+		if comment != "" {
+			update["$push"] = bson.M{"comments": domain.BookingComment{
+				ID:          primitive.NewObjectID(),
+				UserID:      customerID,
+				UserName:    customer.Name,
+				UserRole:    customer.Role,
+				CommentText: comment,
+				Timestamp:   now,
+				IsRating:    true,
+				Rating:      rating,
+			}}
+		}
+		if err := s.bookingRepo.UpdateBooking(ctx, booking.ID, update); err != nil {
+			return fmt.Errorf("service failed to rate booking: %w", err)
+		}
+		if err := s.userRepo.RecordRating(ctx, booking.MowerID, rating); err != nil {
+			return fmt.Errorf("service failed to update mower rating: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// GetBookingReview retrieves the review of a booking for one of its parties.
+func (s *reviewService) GetBookingReview(ctx context.Context, userID, bookingID primitive.ObjectID) (*domain.Review, error) {
+	booking, err := s.bookingRepo.FindBookingByID(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.CustomerID != userID && booking.MowerID != userID {
+		return nil, apperror.NotFound{Resource: "Booking"}
+	}
+	return s.reviewRepo.FindReviewByBookingID(ctx, bookingID)
+}
+
+// ReplyToReview sets the mower's reply on the review.
+func (s *reviewService) ReplyToReview(ctx context.Context, mowerID, bookingID primitive.ObjectID, text string) (*domain.Review, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || len(text) > maxReviewLength {
+		return nil, apperror.InvalidResource{Resource: "reply"}
+	}
+	review, err := s.reviewRepo.FindReviewByBookingID(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if review.MowerID != mowerID {
+		return nil, apperror.NotFound{Resource: "Review"}
+	}
+	if review.Reply != nil {
+		return nil, apperror.DuplicateError{Resource: "Reply to this review"}
+	}
+
+	reply := &domain.ReviewReply{Text: text, CreatedAt: time.Now()}
+	if err := s.reviewRepo.SetReviewReply(ctx, review.ID, reply); err != nil {
+		if _, ok := err.(apperror.DuplicateError); ok {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service failed to reply to review: %w", err)
+	}
+	review.Reply = reply
+	return review, nil
+}
+
+// MowerProfile builds the public profile of a mower.
+func (s *reviewService) MowerProfile(ctx context.Context, mowerID primitive.ObjectID) (*domain.MowerProfile, error) {
+	mower, err := s.userRepo.FindUserByID(ctx, mowerID)
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			return nil, apperror.NotFound{Resource: "Mower"}
+		}
+		return nil, fmt.Errorf("service failed to get mower: %w", err)
+	}
+	if mower.Role != "mower" {
+		return nil, apperror.NotFound{Resource: "Mower"}
+	}
+
+	profile := &domain.MowerProfile{
+		ID:          mower.ID,
+		Name:        mower.Name,
+		ImageUrl:    mower.ImageUrl,
+		Services:    mower.Services,
+		HourlyRate:  mower.HourlyRate,
+		Ratings:     domain.RatingSummary{Distribution: map[string]int64{}},
+		MemberSince: mower.CreatedAt,
+	}
+	if summary := mower.RatingSummary; summary != nil {
+		profile.Ratings.Count = summary.Count
+		profile.Ratings.Total = summary.Total
+		profile.Ratings.Average = summary.Average
+		for stars, count := range summary.Distribution {
+			profile.Ratings.Distribution[stars] = count
+		}
+	}
+	// Every star rating is listed, so clients can draw the distribution
+	// without filling in gaps.
+	for stars := 1; stars <= 5; stars++ {
+		profile.Ratings.Distribution[strconv.Itoa(stars)] += 0
+	}
+	return profile, nil
+}
+
+// ListMowerReviews retrieves a mower's most recent reviews.
+func (s *reviewService) ListMowerReviews(ctx context.Context, mowerID primitive.ObjectID, limit int) ([]*domain.Review, error) {
+	if _, err := s.MowerProfile(ctx, mowerID); err != nil {
+		return nil, err
+	}
+	if limit < 1 || limit > MaxReviewLimit {
+		limit = DefaultReviewLimit
+	}
+	reviews, err := s.reviewRepo.FindReviewsByMowerID(ctx, mowerID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to list reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// firstName returns the first word of name, which reviews show publicly in
+// place of the full name.
+func firstName(name string) string {
+	if fields := strings.Fields(name); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+	infrastructureServices "lawnconnect-api/internal/infrastructure/services"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RouteSettings configure how mowers' daily routes are planned.
+type RouteSettings struct {
+	// DayStart is when a route leaves the base unless the mower asks for
+	// another time, as HH:MM.
+	DayStart string
+	// JobDuration is how long a job takes when its quote gives no estimate.
+	JobDuration time.Duration
+	// ArrivalWindow is how long after a booking's time the mower may arrive
+	// without being late.
+	ArrivalWindow time.Duration
+}
+
+// RouteService plans the order in which mowers visit their jobs.
+type RouteService interface {
+	// PlanRoute orders the mower's accepted and ongoing jobs on date
+	// (YYYY-MM-DD) into a round trip from their base, leaving at start (HH:MM,
+	// or the configured day start when empty). Jobs in progress come first; the
+	// rest are ordered to reach as many as possible within their arrival window
+	// and then to get back soonest. A malformed date or start is returned as
+	// apperror.InvalidResource, and a mower who has not set a base as
+	// apperror.CustomError.
+	PlanRoute(ctx context.Context, mowerID primitive.ObjectID, date, start string) (*domain.Route, error)
+}
+
+type routeService struct {
+	bookingRepo repositories.BookingRepository
+	locations   LocationService
+	matrix      infrastructureServices.DistanceMatrix
+	settings    RouteSettings
+}
+
+// NewRouteService creates a new RouteService.
+func NewRouteService(bookingRepo repositories.BookingRepository, locations LocationService, matrix infrastructureServices.DistanceMatrix, settings RouteSettings) RouteService {
+	return &routeService{bookingRepo: bookingRepo, locations: locations, matrix: matrix, settings: settings}
+}
+
+// PlanRoute builds a nearest-neighbour route over the day's jobs and improves
+// it with 2-opt.
+func (s *routeService) PlanRoute(ctx context.Context, mowerID primitive.ObjectID, date, start string) (*domain.Route, error) {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, apperror.InvalidResource{Resource: "date"}
+	}
+	if start == "" {
+		start = s.settings.DayStart
+	}
+	depart, ok := clockOffset(start)
+	if !ok {
+		return nil, apperror.InvalidResource{Resource: "start time"}
+	}
+	radius, err := s.locations.GetServiceRadius(ctx, mowerID)
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			return nil, apperror.CustomError{Message: "Set your base location to plan a route"}
+		}
+		return nil, err
+	}
+	bookings, err := s.bookingRepo.FindScheduledBookingsForMower(ctx, mowerID, date)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to find scheduled bookings: %w", err)
+	}
+
+	route := &domain.Route{
+		Date:     date,
+		Base:     radius.BaseLocation,
+		DepartAt: formatClock(depart),
+		ReturnAt: formatClock(depart),
+		Stops:    []domain.RouteStop{},
+		Provider: s.matrix.Name(),
+	}
+	plan := &routePlan{depart: depart}
+	points := []infrastructureServices.Location{{Lat: radius.BaseLocation.Lat(), Lng: radius.BaseLocation.Lng()}}
+	for _, booking := range bookings {
+		if booking.Location == nil {
+			route.Unlocated = append(route.Unlocated, booking.ID)
+			continue
+		}
+		// Booking times are validated when booking, so this only guards
+		// documents written before that.
+		windowStart, ok := clockOffset(booking.Time)
+		if !ok {
+			windowStart = depart
+		}
+		job := routeJob{
+			booking:     booking,
+			windowStart: windowStart,
+			windowEnd:   windowStart + s.settings.ArrivalWindow,
+			duration:    s.jobDuration(booking),
+			inProgress:  booking.Status == "ongoing",
+		}
+		plan.jobs = append(plan.jobs, job)
+		points = append(points, infrastructureServices.Location{Lat: booking.Location.Lat(), Lng: booking.Location.Lng()})
+	}
+	if len(plan.jobs) == 0 {
+		return route, nil
+	}
+
+	plan.legs, err = s.matrix.Matrix(ctx, points)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to estimate travel times: %w", err)
+	}
+	plan.fill(route, plan.twoOpt(plan.nearestNeighbour()))
+	return route, nil
+}
+
+// jobDuration is the hours quoted for an hourly job, or the configured
+// default.
+func (s *routeService) jobDuration(booking *domain.Booking) time.Duration {
+	if quote := booking.Quote; quote != nil && quote.Type == domain.QuoteHourly && quote.EstimatedHours > 0 {
+		return time.Duration(quote.EstimatedHours * float64(time.Hour))
+	}
+	return s.settings.JobDuration
+}
+
+// routeJob is a booking to visit, with its times as offsets from midnight.
+type routeJob struct {
+	booking     *domain.Booking
+	windowStart time.Duration
+	windowEnd   time.Duration
+	duration    time.Duration
+	inProgress  bool
+}
+
+// routePlan holds the jobs and the legs between them. Point 0 of legs is the
+// base and point i+1 is jobs[i]; an order lists job indexes.
+type routePlan struct {
+	jobs   []routeJob
+	legs   [][]infrastructureServices.Leg
+	depart time.Duration
+}
+
+// routeCost ranks orders: the least total lateness wins, then the earliest
+// return to base, then the least travel.
+type routeCost struct {
+	late   time.Duration
+	end    time.Duration
+	travel time.Duration
+}
+
+func (c routeCost) less(other routeCost) bool {
+	if c.late != other.late {
+		return c.late < other.late
+	}
+	if c.end != other.end {
+		return c.end < other.end
+	}
+	return c.travel < other.travel
+}
+
+// visit drives from point from, free at at, to job j, and returns the leg,
+// when the mower arrives, starts work after waiting for the window to open,
+// and finishes.
+func (p *routePlan) visit(at time.Duration, from, j int) (leg infrastructureServices.Leg, arrive, start, finish time.Duration) {
+	job := p.jobs[j]
+	leg = p.legs[from][j+1]
+	arrive = at + leg.Duration
+	start = max(arrive, job.windowStart)
+	return leg, arrive, start, start + job.duration
+}
+
+// cost drives order and back to base.
+func (p *routePlan) cost(order []int) routeCost {
+	var c routeCost
+	at, from := p.depart, 0
+	for _, j := range order {
+		leg, arrive, _, finish := p.visit(at, from, j)
+		c.travel += leg.Duration
+		c.late += max(0, arrive-p.jobs[j].windowEnd)
+		at, from = finish, j+1
+	}
+	back := p.legs[from][0]
+	c.travel += back.Duration
+	c.end = at + back.Duration
+	return c
+}
+
+// nearestNeighbour visits the jobs in progress first, then repeatedly the job
+// that can be reached with the least lateness, and among those the one where
+// work can start soonest.
+func (p *routePlan) nearestNeighbour() []int {
+	order := make([]int, 0, len(p.jobs))
+	visited := make([]bool, len(p.jobs))
+	at, from := p.depart, 0
+	next := func(j int) {
+		_, _, _, finish := p.visit(at, from, j)
+		order = append(order, j)
+		visited[j] = true
+		at, from = finish, j+1
+	}
+	for j, job := range p.jobs {
+		if job.inProgress {
+			next(j)
+		}
+	}
+	for len(order) < len(p.jobs) {
+		best, bestLate, bestStart := -1, time.Duration(0), time.Duration(0)
+		for j := range p.jobs {
+			if visited[j] {
+				continue
+			}
+			_, arrive, start, _ := p.visit(at, from, j)
+			late := max(0, arrive-p.jobs[j].windowEnd)
+			if best < 0 || late < bestLate || (late == bestLate && start < bestStart) {
+				best, bestLate, bestStart = j, late, start
+			}
+		}
+		next(best)
+	}
+	return order
+}
+
+// twoOpt reverses segments of order while that lowers its cost, keeping the
+// jobs in progress first.
+func (p *routePlan) twoOpt(order []int) []int {
+	fixed := 0
+	for fixed < len(order) && p.jobs[order[fixed]].inProgress {
+		fixed++
+	}
+	best := p.cost(order)
+	for improved := true; improved; {
+		improved = false
+		for i := fixed; i < len(order)-1; i++ {
+			for k := i + 1; k < len(order); k++ {
+				candidate := make([]int, len(order))
+				copy(candidate, order)
+				for a, b := i, k; a < b; a, b = a+1, b-1 {
+					candidate[a], candidate[b] = candidate[b], candidate[a]
+				}
+				if c := p.cost(candidate); c.less(best) {
+					order, best, improved = candidate, c, true
+				}
+			}
+		}
+	}
+	return order
+}
+
+// fill sets route's stops and totals from order.
+func (p *routePlan) fill(route *domain.Route, order []int) {
+	var (
+		distanceKm float64
+		travel     time.Duration
+	)
+	at, from := p.depart, 0
+	for _, j := range order {
+		job := p.jobs[j]
+		leg, arrive, start, finish := p.visit(at, from, j)
+		stop := domain.RouteStop{
+			BookingID:     job.booking.ID,
+			Address:       job.booking.Address,
+			Location:      job.booking.Location,
+			WindowStart:   formatClock(job.windowStart),
+			WindowEnd:     formatClock(job.windowEnd),
+			ArriveAt:      formatClock(arrive),
+			StartAt:       formatClock(start),
+			FinishAt:      formatClock(finish),
+			TravelMinutes: roundMinutes(leg.Duration),
+			DistanceKm:    roundKm(leg.DistanceKm),
+		}
+		if arrive > job.windowEnd {
+			stop.LateMinutes = int(math.Ceil((arrive - job.windowEnd).Minutes()))
+			route.LateStops++
+		}
+		route.Stops = append(route.Stops, stop)
+		distanceKm += leg.DistanceKm
+		travel += leg.Duration
+		at, from = finish, j+1
+	}
+	back := p.legs[from][0]
+	route.TotalDistanceKm = roundKm(distanceKm + back.DistanceKm)
+	route.TotalTravelMinutes = roundMinutes(travel + back.Duration)
+	route.ReturnAt = formatClock(at + back.Duration)
+}
+
+// clockOffset parses an HH:MM time of day into its offset from midnight.
+func clockOffset(clock string) (time.Duration, bool) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, true
+}
+
+// formatClock formats an offset from midnight as HH:MM, rounded to the minute.
+// Offsets past midnight keep counting hours, e.g. 25:30.
+func formatClock(offset time.Duration) string {
+	offset = offset.Round(time.Minute)
+	return fmt.Sprintf("%02d:%02d", int(offset/time.Hour), int(offset%time.Hour/time.Minute))
+}
+
+func roundMinutes(d time.Duration) int {
+	return int(d.Round(time.Minute) / time.Minute)
+}
+
+func roundKm(km float64) float64 {
+	return math.Round(km*10) / 10
+}
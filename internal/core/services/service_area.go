@@ -0,0 +1,398 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+	"lawnconnect-api/internal/infrastructure/logging"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ServiceAreaInput is what an admin sets on a service area.
+type ServiceAreaInput struct {
+	Name     string
+	Boundary domain.GeoPolygon
+	// PriceMultiplier scales estimates in the area. Zero means 1.
+	PriceMultiplier float64
+	// CommissionBps, when set, is the area's commission in basis points.
+	CommissionBps *int64
+}
+
+// ServiceAreaService manages the areas the marketplace serves and which mowers
+// may work in them.
+type ServiceAreaService interface {
+	// CreateArea validates and stores an active area and, in the same
+	// transaction, moves the waitlisted bookings it covers into the pending
+	// pool. Invalid input is returned as apperror.CustomError.
+	CreateArea(ctx context.Context, adminID primitive.ObjectID, input ServiceAreaInput) (*domain.ServiceArea, error)
+	// ListAreas returns every area, including deactivated ones, oldest first.
+	ListAreas(ctx context.Context) ([]*domain.ServiceArea, error)
+	GetArea(ctx context.Context, areaID primitive.ObjectID) (*domain.ServiceArea, error)
+	// UpdateArea replaces the area's details with input. Waitlisted bookings the
+	// new boundary covers move into the pending pool; bookings already in the
+	// area stay in it.
+	UpdateArea(ctx context.Context, areaID primitive.ObjectID, input ServiceAreaInput) (*domain.ServiceArea, error)
+	// DeactivateArea stops the area from taking new bookings. Its open bookings
+	// stay with the mowers approved for it.
+	DeactivateArea(ctx context.Context, areaID primitive.ObjectID) error
+	// AreaAt returns the active area covering point, or apperror.NotFound.
+	AreaAt(ctx context.Context, point domain.GeoPoint) (*domain.ServiceArea, error)
+	// SetMowerAreas replaces the areas the mower is approved to work in. An
+	// unknown mower or area is returned as apperror.NotFound, and a deactivated
+	// area as apperror.CustomError.
+	SetMowerAreas(ctx context.Context, mowerID primitive.ObjectID, areaIDs []primitive.ObjectID) ([]*domain.ServiceArea, error)
+	// MowerAreas returns the areas the mower is approved to work in.
+	MowerAreas(ctx context.Context, mowerID primitive.ObjectID) ([]*domain.ServiceArea, error)
+}
+
+type serviceAreaService struct {
+	areaRepo    repositories.ServiceAreaRepository
+	bookingRepo repositories.BookingRepository
+	userRepo    repositories.UserRepository
+	tx          database.Transactor
+}
+
+// NewServiceAreaService creates a new ServiceAreaService.
+func NewServiceAreaService(areaRepo repositories.ServiceAreaRepository, bookingRepo repositories.BookingRepository, userRepo repositories.UserRepository, tx database.Transactor) ServiceAreaService {
+	return &serviceAreaService{areaRepo: areaRepo, bookingRepo: bookingRepo, userRepo: userRepo, tx: tx}
+}
+
+// CreateArea stores a new service area.
+func (s *serviceAreaService) CreateArea(ctx context.Context, adminID primitive.ObjectID, input ServiceAreaInput) (*domain.ServiceArea, error) {
+	input, err := checkAreaInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	area := &domain.ServiceArea{
+		ID:              primitive.NewObjectID(),
+		Name:            input.Name,
+		Boundary:        input.Boundary,
+		PriceMultiplier: input.PriceMultiplier,
+		CommissionBps:   input.CommissionBps,
+		Active:          true,
+		CreatedBy:       adminID,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	var released int
+	err = s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.areaRepo.CreateArea(ctx, area); err != nil {
+			return fmt.Errorf("service failed to create service area: %w", err)
+		}
+		released, err = s.releaseWaitlist(ctx, area)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	logReleased(ctx, area, released)
+	return area, nil
+}
+
+// checkAreaInput validates input and fills in defaults.
+func checkAreaInput(input ServiceAreaInput) (ServiceAreaInput, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return input, apperror.CustomError{Message: "Service area name is required"}
+	}
+	if err := checkBoundary(input.Boundary); err != nil {
+		return input, err
+	}
+	if input.PriceMultiplier == 0 {
+		input.PriceMultiplier = 1
+	}
+	if input.PriceMultiplier < 0 {
+		return input, apperror.CustomError{Message: "priceMultiplier must be positive"}
+	}
+	if input.CommissionBps != nil && (*input.CommissionBps < 0 || *input.CommissionBps > 10000) {
+		return input, apperror.CustomError{Message: "commissionBps must be between 0 and 10000"}
+	}
+	return input, nil
+}
+
+// checkBoundary checks that boundary is a GeoJSON polygon MongoDB can index:
+// closed, non-self-intersecting rings of at least four positions with valid
+// coordinates.
+func checkBoundary(boundary domain.GeoPolygon) error {
+	if boundary.Type != "Polygon" {
+		return apperror.CustomError{Message: "boundary must be a GeoJSON Polygon"}
+	}
+	if len(boundary.Coordinates) == 0 {
+		return apperror.CustomError{Message: "boundary must have an outer ring"}
+	}
+	for _, ring := range boundary.Coordinates {
+		if len(ring) < 4 {
+			return apperror.CustomError{Message: "Each boundary ring needs at least four positions"}
+		}
+		for _, position := range ring {
+			if len(position) != 2 || position[0] < -180 || position[0] > 180 || position[1] < -90 || position[1] > 90 {
+				return apperror.CustomError{Message: "Boundary positions must be [longitude, latitude] pairs"}
+			}
+		}
+		first, last := ring[0], ring[len(ring)-1]
+		if first[0] != last[0] || first[1] != last[1] {
+			return apperror.CustomError{Message: "Each boundary ring must end where it starts"}
+		}
+		if !isSimpleRing(ring) {
+			return apperror.CustomError{Message: "Boundary rings must not cross or touch themselves"}
+		}
+	}
+	return nil
+}
+
+// isSimpleRing reports whether a closed ring has no edges that cross, touch or
+// overlap other than neighbours meeting at their shared corner. Repeated
+// consecutive positions are ignored. Coordinates are treated as planar, which
+// is close enough for areas the size of a city.
+func isSimpleRing(ring [][]float64) bool {
+	points := make([][]float64, 0, len(ring))
+	for _, position := range ring {
+		if n := len(points); n > 0 && points[n-1][0] == position[0] && points[n-1][1] == position[1] {
+			continue
+		}
+		points = append(points, position)
+	}
+	edges := len(points) - 1
+	if edges < 3 {
+		return false
+	}
+	for i := 0; i < edges; i++ {
+		for j := i + 1; j < edges; j++ {
+			a, b, c, d := points[i], points[i+1], points[j], points[j+1]
+			switch {
+			case j == i+1:
+				// b == c: the edges may only overlap by turning back.
+				if overlapsAt(b, a, d) {
+					return false
+				}
+			case i == 0 && j == edges-1:
+				// a == d, where the ring closes.
+				if overlapsAt(a, b, c) {
+					return false
+				}
+			case segmentsIntersect(a, b, c, d):
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// overlapsAt reports whether the edges from p to u and from p to v lie along
+// each other.
+func overlapsAt(p, u, v []float64) bool {
+	return cross(p, u, v) == 0 && (u[0]-p[0])*(v[0]-p[0])+(u[1]-p[1])*(v[1]-p[1]) > 0
+}
+
+// segmentsIntersect reports whether the segments ab and cd share any point.
+func segmentsIntersect(a, b, c, d []float64) bool {
+	d1, d2 := cross(c, d, a), cross(c, d, b)
+	d3, d4 := cross(a, b, c), cross(a, b, d)
+	if (d1 > 0 && d2 < 0 || d1 < 0 && d2 > 0) && (d3 > 0 && d4 < 0 || d3 < 0 && d4 > 0) {
+		return true
+	}
+	return d1 == 0 && onSegment(c, d, a) || d2 == 0 && onSegment(c, d, b) ||
+		d3 == 0 && onSegment(a, b, c) || d4 == 0 && onSegment(a, b, d)
+}
+
+// cross returns the z component of (b - o) x (c - o): positive when c is to the
+// left of the line from o to b, negative to the right and zero on it.
+func cross(o, b, c []float64) float64 {
+	return (b[0]-o[0])*(c[1]-o[1]) - (b[1]-o[1])*(c[0]-o[0])
+}
+
+// onSegment reports whether p, known to be on the line through a and b, lies
+// between them.
+func onSegment(a, b, p []float64) bool {
+	return math.Min(a[0], b[0]) <= p[0] && p[0] <= math.Max(a[0], b[0]) &&
+		math.Min(a[1], b[1]) <= p[1] && p[1] <= math.Max(a[1], b[1])
+}
+
+// releaseWaitlist moves the waitlisted bookings inside area into its pending
+// pool and returns how many it moved. It runs inside the caller's transaction,
+// and a booking is only moved while it is still waitlisted, so one cancelled
+// in the meantime stays cancelled.
+func (s *serviceAreaService) releaseWaitlist(ctx context.Context, area *domain.ServiceArea) (int, error) {
+	bookings, err := s.bookingRepo.FindWaitlistedBookingsWithin(ctx, area.Boundary)
+	if err != nil {
+		return 0, fmt.Errorf("service failed to find waitlisted bookings: %w", err)
+	}
+	released := 0
+	for _, booking := range bookings {
+		update := bson.M{"$set": bson.M{"status": "pending", "serviceAreaId": area.ID, "updatedAt": time.Now()}}
+		err := s.bookingRepo.UpdateBookingIfStatus(ctx, booking.ID, "waitlisted", "", update)
+		switch err.(type) {
+		case nil:
+			released++
+		case apperror.NotFound:
+		default:
+			return 0, fmt.Errorf("service failed to release waitlisted booking: %w", err)
+		}
+	}
+	return released, nil
+}
+
+// logReleased logs the bookings released into area once they are committed.
+func logReleased(ctx context.Context, area *domain.ServiceArea, released int) {
+	if released > 0 {
+		logging.FromContext(ctx).Info("released waitlisted bookings", "service_area_id", area.ID.Hex(), "count", released)
+	}
+}
+
+// ListAreas returns every service area.
+func (s *serviceAreaService) ListAreas(ctx context.Context) ([]*domain.ServiceArea, error) {
+	areas, err := s.areaRepo.ListAreas(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to list service areas: %w", err)
+	}
+	return areas, nil
+}
+
+// GetArea retrieves a service area by ID.
+func (s *serviceAreaService) GetArea(ctx context.Context, areaID primitive.ObjectID) (*domain.ServiceArea, error) {
+	area, err := s.areaRepo.FindAreaByID(ctx, areaID)
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service failed to get service area: %w", err)
+	}
+	return area, nil
+}
+
+// UpdateArea replaces a service area's details.
+func (s *serviceAreaService) UpdateArea(ctx context.Context, areaID primitive.ObjectID, input ServiceAreaInput) (*domain.ServiceArea, error) {
+	area, err := s.GetArea(ctx, areaID)
+	if err != nil {
+		return nil, err
+	}
+	input, err = checkAreaInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	area.Name = input.Name
+	area.Boundary = input.Boundary
+	area.PriceMultiplier = input.PriceMultiplier
+	area.CommissionBps = input.CommissionBps
+	area.UpdatedAt = time.Now()
+	update := bson.M{"$set": bson.M{
+		"name":            area.Name,
+		"boundary":        area.Boundary,
+		"priceMultiplier": area.PriceMultiplier,
+		"updatedAt":       area.UpdatedAt,
+	}}
+	if area.CommissionBps != nil {
+		update["$set"].(bson.M)["commissionBps"] = *area.CommissionBps
+	} else {
+		update["$unset"] = bson.M{"commissionBps": ""}
+	}
+	var released int
+	err = s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.areaRepo.UpdateArea(ctx, areaID, update); err != nil {
+			return fmt.Errorf("service failed to update service area: %w", err)
+		}
+		if !area.Active {
+			return nil
+		}
+		released, err = s.releaseWaitlist(ctx, area)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	logReleased(ctx, area, released)
+	return area, nil
+}
+
+// DeactivateArea deactivates a service area. Areas are never deleted, so
+// bookings keep pointing at the area they were made in.
+func (s *serviceAreaService) DeactivateArea(ctx context.Context, areaID primitive.ObjectID) error {
+	if _, err := s.GetArea(ctx, areaID); err != nil {
+		return err
+	}
+	update := bson.M{"$set": bson.M{"active": false, "updatedAt": time.Now()}}
+	if err := s.areaRepo.UpdateArea(ctx, areaID, update); err != nil {
+		return fmt.Errorf("service failed to deactivate service area: %w", err)
+	}
+	return nil
+}
+
+// AreaAt finds the active service area covering point.
+func (s *serviceAreaService) AreaAt(ctx context.Context, point domain.GeoPoint) (*domain.ServiceArea, error) {
+	area, err := s.areaRepo.FindAreaContaining(ctx, point)
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service failed to find service area: %w", err)
+	}
+	return area, nil
+}
+
+// SetMowerAreas approves the mower for exactly the given areas.
+func (s *serviceAreaService) SetMowerAreas(ctx context.Context, mowerID primitive.ObjectID, areaIDs []primitive.ObjectID) ([]*domain.ServiceArea, error) {
+	mower, err := s.userRepo.FindUserByID(ctx, mowerID)
+	if err != nil {
+		if _, ok := err.(apperror.NotFound); ok {
+			return nil, apperror.NotFound{Resource: "Mower"}
+		}
+		return nil, fmt.Errorf("service failed to find mower: %w", err)
+	}
+	if mower.Role != "mower" {
+		return nil, apperror.NotFound{Resource: "Mower"}
+	}
+
+	areas := make([]*domain.ServiceArea, 0, len(areaIDs))
+	ids := make([]primitive.ObjectID, 0, len(areaIDs))
+	seen := make(map[primitive.ObjectID]bool, len(areaIDs))
+	for _, areaID := range areaIDs {
+		if seen[areaID] {
+			continue
+		}
+		seen[areaID] = true
+		area, err := s.GetArea(ctx, areaID)
+		if err != nil {
+			return nil, err
+		}
+		if !area.Active {
+			return nil, apperror.CustomError{Message: fmt.Sprintf("Service area %s is not active", area.Name)}
+		}
+		areas = append(areas, area)
+		ids = append(ids, areaID)
+	}
+
+	update := bson.M{"$set": bson.M{"serviceAreaIds": ids, "updatedAt": time.Now()}}
+	if err := s.userRepo.UpdateUser(ctx, mowerID, update); err != nil {
+		return nil, fmt.Errorf("service failed to approve mower for service areas: %w", err)
+	}
+	return areas, nil
+}
+
+// MowerAreas returns the service areas the mower is approved for.
+func (s *serviceAreaService) MowerAreas(ctx context.Context, mowerID primitive.ObjectID) ([]*domain.ServiceArea, error) {
+	mower, err := s.userRepo.FindUserByID(ctx, mowerID)
+	if err != nil {
+		return nil, fmt.Errorf("service failed to get mower: %w", err)
+	}
+	areas := make([]*domain.ServiceArea, 0, len(mower.ServiceAreaIDs))
+	for _, areaID := range mower.ServiceAreaIDs {
+		area, err := s.GetArea(ctx, areaID)
+		if err != nil {
+			return nil, err
+		}
+		areas = append(areas, area)
+	}
+	return areas, nil
+}
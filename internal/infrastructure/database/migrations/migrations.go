@@ -0,0 +1,292 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// migrationsCollection stores one document per applied migration, keyed by version.
+	migrationsCollection = "schema_migrations"
+	// lockID is the document in migrationsCollection used to serialize concurrent runners.
+	lockID = "lock"
+	// lockTTL bounds how long a crashed runner can hold the lock. A live runner
+	// renews it every lockHeartbeat, so migrations may run longer than lockTTL.
+	lockTTL       = time.Minute
+	lockHeartbeat = lockTTL / 3
+	// lockPollInterval is how often UpWhenUnlocked checks a lock held elsewhere.
+	lockPollInterval = 2 * time.Second
+)
+
+// ErrLocked is returned by Up when another runner holds the migration lock.
+var ErrLocked = errors.New("migrations are already running on another instance")
+
+// Migration is a single, versioned schema or data change.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+}
+
+// AppliedMigration is the record stored for each migration that has run.
+type AppliedMigration struct {
+	Version     int       `bson:"_id" json:"version"`
+	Description string    `bson:"description" json:"description"`
+	AppliedAt   time.Time `bson:"appliedAt" json:"appliedAt"`
+	DurationMS  int64     `bson:"durationMs" json:"durationMs"`
+}
+
+// MigrationStatus reports whether a known migration has been applied.
+type MigrationStatus struct {
+	Version     int        `json:"version"`
+	Description string     `json:"description"`
+	Applied     bool       `json:"applied"`
+	AppliedAt   *time.Time `json:"appliedAt,omitempty"`
+}
+
+// Runner applies migrations to a database and records the ones it applied.
+type Runner struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+	migrations []Migration
+}
+
+// NewRunner creates a Runner for the given migrations. Migrations are sorted by version.
+func NewRunner(db *mongo.Database, migrations []Migration) *Runner {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	return &Runner{
+		db:         db,
+		collection: db.Collection(migrationsCollection),
+		migrations: sorted,
+	}
+}
+
+// Up applies every migration that has not been recorded yet, in version order.
+// It returns the migrations applied by this call.
+func (r *Runner) Up(ctx context.Context) ([]AppliedMigration, error) {
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+
+	owner, err := r.acquireLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.releaseLock(context.Background(), owner)
+
+	// Migrations stop if the lock is lost, so two runners never apply the same
+	// migration at once.
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go r.heartbeat(ctx, owner, cancel)
+
+	applied, err := r.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []AppliedMigration
+	for _, m := range r.migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		slog.Info("applying migration", "version", m.Version, "description", m.Description)
+		start := time.Now()
+		if err := m.Up(ctx, r.db); err != nil {
+			if cause := context.Cause(ctx); cause != nil && ctx.Err() != nil {
+				err = cause
+			}
+			return ran, fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Description, err)
+		}
+
+		record := AppliedMigration{
+			Version:     m.Version,
+			Description: m.Description,
+			AppliedAt:   time.Now(),
+			DurationMS:  time.Since(start).Milliseconds(),
+		}
+		if _, err := r.collection.InsertOne(ctx, record); err != nil {
+			return ran, fmt.Errorf("failed to record migration %d: %w", m.Version, err)
+		}
+		ran = append(ran, record)
+	}
+
+	return ran, nil
+}
+
+// UpWhenUnlocked is Up for instances that migrate on startup. While another
+// instance holds the lock it polls until the lock is released, then applies
+// whatever is still pending, or returns as soon as every migration has been
+// applied. It gives up when ctx is done.
+func (r *Runner) UpWhenUnlocked(ctx context.Context) ([]AppliedMigration, error) {
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		ran, err := r.Up(ctx)
+		if !errors.Is(err, ErrLocked) {
+			return ran, err
+		}
+
+		applied, err := r.appliedVersions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if r.allApplied(applied) {
+			return nil, nil
+		}
+		slog.Info("waiting for migrations running on another instance")
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for the migration lock: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) allApplied(applied map[int]AppliedMigration) bool {
+	for _, m := range r.migrations {
+		if _, ok := applied[m.Version]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Status lists every known migration along with whether it has been applied.
+func (r *Runner) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := r.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(r.migrations))
+	for _, m := range r.migrations {
+		status := MigrationStatus{Version: m.Version, Description: m.Description}
+		if record, ok := applied[m.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// validate rejects duplicate or non-positive versions before anything runs.
+func (r *Runner) validate() error {
+	seen := make(map[int]bool, len(r.migrations))
+	for _, m := range r.migrations {
+		if m.Version <= 0 {
+			return fmt.Errorf("migration %q has invalid version %d", m.Description, m.Version)
+		}
+		if seen[m.Version] {
+			return fmt.Errorf("duplicate migration version %d", m.Version)
+		}
+		if m.Up == nil {
+			return fmt.Errorf("migration %d has no Up function", m.Version)
+		}
+		seen[m.Version] = true
+	}
+	return nil
+}
+
+func (r *Runner) appliedVersions(ctx context.Context) (map[int]AppliedMigration, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$type": "number"}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []AppliedMigration
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode applied migrations: %w", err)
+	}
+
+	applied := make(map[int]AppliedMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// acquireLock takes the migration lock so only one API instance migrates at a time.
+// A lock older than lockTTL is considered abandoned and can be taken over.
+func (r *Runner) acquireLock(ctx context.Context) (string, error) {
+	hostname, _ := os.Hostname()
+	owner := fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), time.Now().UnixNano())
+
+	filter := bson.M{
+		"_id":         lockID,
+		"lockedUntil": bson.M{"$lt": time.Now()},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"owner":       owner,
+			"lockedUntil": time.Now().Add(lockTTL),
+		},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return "", ErrLocked
+		}
+		return "", fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	return owner, nil
+}
+
+// heartbeat extends the lock held by owner until ctx is done. If the lock has
+// been taken over, or cannot be renewed before it expires, it cancels ctx.
+func (r *Runner) heartbeat(ctx context.Context, owner string, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(lockHeartbeat)
+	defer ticker.Stop()
+
+	renewedAt := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		filter := bson.M{"_id": lockID, "owner": owner}
+		update := bson.M{"$set": bson.M{"lockedUntil": time.Now().Add(lockTTL)}}
+		result, err := r.collection.UpdateOne(ctx, filter, update)
+		switch {
+		case err == nil && result.MatchedCount == 0:
+			cancel(errors.New("migration lock was taken over by another instance"))
+			return
+		case err == nil:
+			renewedAt = time.Now()
+		case time.Since(renewedAt) >= lockTTL:
+			cancel(fmt.Errorf("failed to renew migration lock: %w", err))
+			return
+		default:
+			slog.Warn("renewing migration lock failed", "error", err)
+		}
+	}
+}
+
+func (r *Runner) releaseLock(ctx context.Context, owner string) {
+	filter := bson.M{"_id": lockID, "owner": owner}
+	update := bson.M{"$set": bson.M{"lockedUntil": time.Time{}}}
+	if _, err := r.collection.UpdateOne(ctx, filter, update); err != nil {
+		slog.Error("releasing migration lock failed", "error", err)
+	}
+}
@@ -0,0 +1,542 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"lawnconnect-api/internal/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// All returns every migration known to the API, in the order they must be applied.
+// New migrations are appended here with the next free version number; applied
+// migrations must never be edited or renumbered. currency is the configured
+// ISO 4217 code, stamped on the ledger entries migrations create.
+func All(currency string) []Migration {
+	return []Migration{
+		{
+			Version:     1,
+			Description: "unique index on users.email",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				if err := setAsideDuplicateEmails(ctx, db); err != nil {
+					return err
+				}
+				return createIndexes(ctx, db.Collection("users"), mongo.IndexModel{
+					Keys:    bson.D{{Key: "email", Value: 1}},
+					Options: options.Index().SetName("email_unique").SetUnique(true),
+				})
+			},
+		},
+		{
+			Version:     2,
+			Description: "booking lookup indexes by customer, mower, status and date",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return createIndexes(ctx, db.Collection("bookings"),
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "customerId", Value: 1}, {Key: "date", Value: -1}},
+						Options: options.Index().SetName("customer_date"),
+					},
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "mowerId", Value: 1}, {Key: "date", Value: -1}},
+						Options: options.Index().SetName("mower_date"),
+					},
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "status", Value: 1}, {Key: "date", Value: 1}},
+						Options: options.Index().SetName("status_date"),
+					},
+				)
+			},
+		},
+		{
+			Version:     3,
+			Description: "expired password reset token cleanup",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				_, err := db.Collection("users").UpdateMany(ctx,
+					bson.M{"resetTokenExpiresAt": bson.M{"$lt": time.Now()}, "resetToken": bson.M{"$exists": true}},
+					bson.M{"$unset": bson.M{"resetToken": ""}},
+				)
+				if err != nil {
+					return fmt.Errorf("failed to clear expired reset tokens: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Version:     4,
+			Description: "normalize stored user emails",
+			Up:          normalizeUserEmails,
+		},
+		{
+			Version:     5,
+			Description: "ledger indexes and wallet balances in minor units",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				err := createIndexes(ctx, db.Collection("ledger_entries"),
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "account", Value: 1}, {Key: "createdAt", Value: 1}},
+						Options: options.Index().SetName("account_created"),
+					},
+					mongo.IndexModel{
+						Keys: bson.D{{Key: "bookingId", Value: 1}, {Key: "type", Value: 1}, {Key: "account", Value: 1}},
+						Options: options.Index().
+							SetName("booking_type_account_unique").
+							SetUnique(true).
+							SetPartialFilterExpression(bson.M{"bookingId": bson.M{"$exists": true}}),
+					},
+				)
+				if err != nil {
+					return err
+				}
+				return convertWalletBalances(ctx, db, currency)
+			},
+		},
+		{
+			Version:     6,
+			Description: "commission rule and system metric indexes",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				err := createIndexes(ctx, db.Collection("commission_rules"), mongo.IndexModel{
+					Keys:    bson.D{{Key: "active", Value: 1}, {Key: "createdAt", Value: -1}},
+					Options: options.Index().SetName("active_created"),
+				})
+				if err != nil {
+					return err
+				}
+				return createIndexes(ctx, db.Collection("system_metrics"), mongo.IndexModel{
+					Keys:    bson.D{{Key: "name", Value: 1}},
+					Options: options.Index().SetName("name_unique").SetUnique(true),
+				})
+			},
+		},
+		{
+			Version:     7,
+			Description: "invoice indexes and default booking billing status",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				err := createIndexes(ctx, db.Collection("invoices"),
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "number", Value: 1}},
+						Options: options.Index().SetName("number_unique").SetUnique(true),
+					},
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "bookingId", Value: 1}},
+						Options: options.Index().SetName("booking_unique").SetUnique(true),
+					},
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "customerId", Value: 1}, {Key: "issuedAt", Value: -1}},
+						Options: options.Index().SetName("customer_issued"),
+					},
+				)
+				if err != nil {
+					return err
+				}
+
+				_, err = db.Collection("bookings").UpdateMany(ctx,
+					bson.M{"billingStatus": bson.M{"$in": bson.A{nil, ""}}},
+					bson.M{"$set": bson.M{"billingStatus": "pending"}},
+				)
+				if err != nil {
+					return fmt.Errorf("failed to default booking billing status: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Version:     8,
+			Description: "unpaid invoice and payment reminder indexes",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				err := createIndexes(ctx, db.Collection("invoices"), mongo.IndexModel{
+					Keys:    bson.D{{Key: "status", Value: 1}, {Key: "issuedAt", Value: 1}},
+					Options: options.Index().SetName("status_issued"),
+				})
+				if err != nil {
+					return err
+				}
+				return createIndexes(ctx, db.Collection("payment_reminders"), mongo.IndexModel{
+					Keys:    bson.D{{Key: "invoiceId", Value: 1}, {Key: "createdAt", Value: 1}},
+					Options: options.Index().SetName("invoice_created"),
+				})
+			},
+		},
+		{
+			Version:     9,
+			Description: "payout request and batch indexes",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				err := createIndexes(ctx, db.Collection("payout_requests"),
+					mongo.IndexModel{
+						Keys: bson.D{{Key: "mowerId", Value: 1}},
+						Options: options.Index().
+							SetName("mower_open_unique").
+							SetUnique(true).
+							SetPartialFilterExpression(bson.M{"open": true}),
+					},
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "mowerId", Value: 1}, {Key: "createdAt", Value: -1}},
+						Options: options.Index().SetName("mower_created"),
+					},
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: 1}},
+						Options: options.Index().SetName("status_created"),
+					},
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "batchId", Value: 1}},
+						Options: options.Index().SetName("batch").SetSparse(true),
+					},
+				)
+				if err != nil {
+					return err
+				}
+				return createIndexes(ctx, db.Collection("payout_batches"), mongo.IndexModel{
+					Keys:    bson.D{{Key: "createdAt", Value: -1}},
+					Options: options.Index().SetName("created"),
+				})
+			},
+		},
+		{
+			Version:     10,
+			Description: "index on properties.customerId",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return createIndexes(ctx, db.Collection("properties"), mongo.IndexModel{
+					Keys:    bson.D{{Key: "customerId", Value: 1}, {Key: "createdAt", Value: 1}},
+					Options: options.Index().SetName("customer_created"),
+				})
+			},
+		},
+		{
+			Version:     11,
+			Description: "structured property addresses and labels",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				// Properties saved before addresses were structured keep the old
+				// string as the first line, which also becomes their label.
+				_, err := db.Collection("properties").UpdateMany(ctx,
+					bson.M{"address": bson.M{"$type": "string"}},
+					mongo.Pipeline{{{Key: "$set", Value: bson.M{
+						"label":   bson.M{"$ifNull": bson.A{"$label", "$address"}},
+						"address": bson.M{"line1": "$address", "city": "", "postalCode": ""},
+					}}}},
+				)
+				if err != nil {
+					return fmt.Errorf("failed to structure property addresses: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Version:     12,
+			Description: "2dsphere indexes on booking and property locations",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				err := createIndexes(ctx, db.Collection("bookings"), mongo.IndexModel{
+					Keys:    bson.D{{Key: "location", Value: "2dsphere"}},
+					Options: options.Index().SetName("location_2dsphere"),
+				})
+				if err != nil {
+					return err
+				}
+				return createIndexes(ctx, db.Collection("properties"), mongo.IndexModel{
+					Keys:    bson.D{{Key: "location", Value: "2dsphere"}},
+					Options: options.Index().SetName("location_2dsphere"),
+				})
+			},
+		},
+		{
+			// Pending bookings from before service areas belong to no area, so no
+			// mower could see them. They join the waitlist and return to the
+			// pending pool once an area covering them is created.
+			Version:     13,
+			Description: "service area indexes and waitlist for bookings outside any area",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				err := createIndexes(ctx, db.Collection("service_areas"), mongo.IndexModel{
+					Keys:    bson.D{{Key: "boundary", Value: "2dsphere"}},
+					Options: options.Index().SetName("boundary_2dsphere"),
+				})
+				if err != nil {
+					return err
+				}
+				err = createIndexes(ctx, db.Collection("bookings"), mongo.IndexModel{
+					Keys:    bson.D{{Key: "status", Value: 1}, {Key: "serviceAreaId", Value: 1}},
+					Options: options.Index().SetName("status_service_area"),
+				})
+				if err != nil {
+					return err
+				}
+
+				_, err = db.Collection("bookings").UpdateMany(ctx,
+					bson.M{"status": "pending", "serviceAreaId": bson.M{"$exists": false}},
+					bson.M{"$set": bson.M{"status": "waitlisted"}},
+				)
+				if err != nil {
+					return fmt.Errorf("failed to waitlist bookings outside service areas: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Version:     14,
+			Description: "review indexes",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return createIndexes(ctx, db.Collection("reviews"),
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "bookingId", Value: 1}},
+						Options: options.Index().SetName("booking_unique").SetUnique(true),
+					},
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "mowerId", Value: 1}, {Key: "createdAt", Value: -1}},
+						Options: options.Index().SetName("mower_created"),
+					},
+				)
+			},
+		},
+		{
+			// Tokens kept on the user document could not expire through a TTL
+			// index, which would delete the account, so expired tokens piled up.
+			// They move to their own collection, where the TTL index removes them.
+			Version:     15,
+			Description: "password reset tokens collection with TTL",
+			Up:          movePasswordResetTokens,
+		},
+		{
+			Version:     16,
+			Description: "invoice lookup index by mower and status",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return createIndexes(ctx, db.Collection("invoices"), mongo.IndexModel{
+					Keys:    bson.D{{Key: "mowerId", Value: 1}, {Key: "status", Value: 1}},
+					Options: options.Index().SetName("mower_status"),
+				})
+			},
+		},
+	}
+}
+
+// movePasswordResetTokens creates password_reset_tokens, moves the unexpired
+// tokens on user documents into it and removes the token fields from users.
+// Tokens are upserted, so a run that failed part-way can be repeated.
+func movePasswordResetTokens(ctx context.Context, db *mongo.Database) error {
+	tokens := db.Collection("password_reset_tokens")
+	err := createIndexes(ctx, tokens,
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("expires_ttl").SetExpireAfterSeconds(0),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "userId", Value: 1}},
+			Options: options.Index().SetName("user"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	users := db.Collection("users")
+	cursor, err := users.Find(ctx,
+		bson.M{"resetToken": bson.M{"$type": "string", "$gt": ""}, "resetTokenExpiresAt": bson.M{"$gt": time.Now()}},
+		options.Find().SetProjection(bson.M{"resetToken": 1, "resetTokenExpiresAt": 1}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to read reset tokens: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	moved := 0
+	for cursor.Next(ctx) {
+		var user struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			Token     string             `bson:"resetToken"`
+			ExpiresAt time.Time          `bson:"resetTokenExpiresAt"`
+		}
+		if err := cursor.Decode(&user); err != nil {
+			return fmt.Errorf("failed to decode reset token: %w", err)
+		}
+		_, err := tokens.UpdateOne(ctx,
+			bson.M{"_id": user.Token},
+			bson.M{"$setOnInsert": domain.PasswordResetToken{Token: user.Token, UserID: user.ID, ExpiresAt: user.ExpiresAt, CreatedAt: time.Now()}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to move reset token of user %s: %w", user.ID.Hex(), err)
+		}
+		moved++
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to read reset tokens: %w", err)
+	}
+	slog.Info("moved password reset tokens", "count", moved)
+
+	_, err = users.UpdateMany(ctx,
+		bson.M{"$or": bson.A{bson.M{"resetToken": bson.M{"$exists": true}}, bson.M{"resetTokenExpiresAt": bson.M{"$exists": true}}}},
+		bson.M{"$unset": bson.M{"resetToken": "", "resetTokenExpiresAt": ""}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove reset tokens from users: %w", err)
+	}
+	return nil
+}
+
+// normalizeUserEmails rewrites every stored email into domain.NormalizeEmail form.
+// Accounts whose normalized email collides with an existing account cannot be
+// merged automatically; they are left untouched and recorded in
+// email_normalization_conflicts for support to resolve.
+func normalizeUserEmails(ctx context.Context, db *mongo.Database) error {
+	users := db.Collection("users")
+	conflicts := db.Collection("email_normalization_conflicts")
+
+	cursor, err := users.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"email": 1}))
+	if err != nil {
+		return fmt.Errorf("failed to read users: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var user struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Email string             `bson:"email"`
+		}
+		if err := cursor.Decode(&user); err != nil {
+			return fmt.Errorf("failed to decode user: %w", err)
+		}
+
+		normalized := domain.NormalizeEmail(user.Email)
+		if normalized == user.Email {
+			continue
+		}
+
+		_, err := users.UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{"$set": bson.M{"email": normalized}})
+		if err == nil {
+			continue
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("failed to normalize email for user %s: %w", user.ID.Hex(), err)
+		}
+
+		slog.Warn("email normalization conflict recorded for manual merge", "user_id", user.ID.Hex())
+		_, err = conflicts.UpdateOne(ctx,
+			bson.M{"_id": user.ID},
+			bson.M{"$set": bson.M{"email": user.Email, "normalizedEmail": normalized, "detectedAt": time.Now()}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record email conflict for user %s: %w", user.ID.Hex(), err)
+		}
+	}
+	return cursor.Err()
+}
+
+// setAsideDuplicateEmails makes room for the unique email index when accounts
+// already share an exact email. The oldest account keeps the email; every other
+// one is recorded in email_normalization_conflicts for a manual merge, like the
+// conflicts normalizeUserEmails finds, and its email is prefixed with its ID so
+// the index can be built. Repeated runs find nothing left to set aside.
+func setAsideDuplicateEmails(ctx context.Context, db *mongo.Database) error {
+	users := db.Collection("users")
+	conflicts := db.Collection("email_normalization_conflicts")
+
+	cursor, err := users.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$email", "ids": bson.M{"$push": "$_id"}}}},
+		{{Key: "$match", Value: bson.M{"ids.1": bson.M{"$exists": true}}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to find duplicate emails: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var group struct {
+			Email string               `bson:"_id"`
+			IDs   []primitive.ObjectID `bson:"ids"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			return fmt.Errorf("failed to decode duplicate emails: %w", err)
+		}
+
+		kept := group.IDs[0]
+		for _, id := range group.IDs[1:] {
+			slog.Warn("duplicate email recorded for manual merge", "user_id", id.Hex(), "kept_user_id", kept.Hex())
+			_, err := conflicts.UpdateOne(ctx,
+				bson.M{"_id": id},
+				bson.M{"$set": bson.M{
+					"email":           group.Email,
+					"normalizedEmail": domain.NormalizeEmail(group.Email),
+					"duplicateOf":     kept,
+					"detectedAt":      time.Now(),
+				}},
+				options.Update().SetUpsert(true),
+			)
+			if err != nil {
+				return fmt.Errorf("failed to record duplicate email for user %s: %w", id.Hex(), err)
+			}
+			_, err = users.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"email": id.Hex() + ".duplicate." + group.Email}})
+			if err != nil {
+				return fmt.Errorf("failed to set aside duplicate email for user %s: %w", id.Hex(), err)
+			}
+		}
+	}
+	return cursor.Err()
+}
+
+// convertWalletBalances turns the legacy float walletBalance into integer minor
+// units and records each non-zero balance as an opening adjustment in the ledger,
+// so derived balances match the cached ones from the start. The opening entries
+// use the user's ID as their transaction ID, which makes the step safe to re-run.
+// Legacy balances were decimals in the configured currency, which the opening
+// entries record.
+func convertWalletBalances(ctx context.Context, db *mongo.Database, currency string) error {
+	users := db.Collection("users")
+	ledger := db.Collection("ledger_entries")
+
+	cursor, err := users.Find(ctx,
+		bson.M{"walletBalance": bson.M{"$type": "double"}},
+		options.Find().SetProjection(bson.M{"walletBalance": 1}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to read wallet balances: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var user struct {
+			ID            primitive.ObjectID `bson:"_id"`
+			WalletBalance float64            `bson:"walletBalance"`
+		}
+		if err := cursor.Decode(&user); err != nil {
+			return fmt.Errorf("failed to decode user: %w", err)
+		}
+		amount := domain.ToMinorUnits(user.WalletBalance, currency)
+
+		if amount != 0 {
+			existing, err := ledger.CountDocuments(ctx, bson.M{"transactionId": user.ID})
+			if err != nil {
+				return fmt.Errorf("failed to check opening balance for user %s: %w", user.ID.Hex(), err)
+			}
+			if existing == 0 {
+				now := time.Now()
+				_, err = ledger.InsertMany(ctx, []interface{}{
+					domain.LedgerEntry{ID: primitive.NewObjectID(), TransactionID: user.ID, Account: domain.AccountOpeningBalances,
+						Type: domain.LedgerAdjustment, Amount: -amount, Currency: currency, Memo: "Opening balance", CreatedAt: now},
+					domain.LedgerEntry{ID: primitive.NewObjectID(), TransactionID: user.ID, Account: domain.MowerAccount(user.ID),
+						Type: domain.LedgerAdjustment, Amount: amount, Currency: currency, Memo: "Opening balance", CreatedAt: now},
+				})
+				if err != nil {
+					return fmt.Errorf("failed to record opening balance for user %s: %w", user.ID.Hex(), err)
+				}
+			}
+		}
+
+		_, err := users.UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{"$set": bson.M{"walletBalance": amount}})
+		if err != nil {
+			return fmt.Errorf("failed to convert wallet balance for user %s: %w", user.ID.Hex(), err)
+		}
+	}
+	return cursor.Err()
+}
+
+// createIndexes creates the given indexes on a collection. Creating an index that
+// already exists with the same definition is a no-op in MongoDB.
+func createIndexes(ctx context.Context, collection *mongo.Collection, models ...mongo.IndexModel) error {
+	if _, err := collection.Indexes().CreateMany(ctx, models); err != nil {
+		return fmt.Errorf("failed to create indexes on %s: %w", collection.Name(), err)
+	}
+	return nil
+}
@@ -2,15 +2,17 @@ package database
 
 import (
 	"context"
-	"log"
+	"log/slog"
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 )
 
-// NewMongoClient initializes a new MongoDB client.
+// NewMongoClient initializes a new MongoDB client. Every command is traced as a
+// child span of the span in its context, using the global tracer provider.
 func NewMongoClient(ctx context.Context, uri string) (*mongo.Client, error) {
-	clientOptions := options.Client().ApplyURI(uri)
+	clientOptions := options.Client().ApplyURI(uri).SetMonitor(otelmongo.NewMonitor())
 
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
@@ -22,6 +24,6 @@ func NewMongoClient(ctx context.Context, uri string) (*mongo.Client, error) {
 		return nil, err
 	}
 
-	log.Println("Successfully connected to MongoDB!")
+	slog.Info("connected to MongoDB")
 	return client, nil
 }
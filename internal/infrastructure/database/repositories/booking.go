@@ -8,6 +8,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // BookingRepository defines the repository interface for bookings.
@@ -15,8 +16,32 @@ type BookingRepository interface {
 	CreateBooking(ctx context.Context, booking *domain.Booking) error
 	FindBookingByID(ctx context.Context, bookingID primitive.ObjectID) (*domain.Booking, error)
 	FindBookingsByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.Booking, error)
-	FindPendingBookings(ctx context.Context) ([]*domain.Booking, error)
+	// FindPendingBookingsNear returns the pending bookings in the given service
+	// areas located within radiusKm of origin, nearest first, with DistanceKm set.
+	FindPendingBookingsNear(ctx context.Context, origin domain.GeoPoint, radiusKm float64, areaIDs []primitive.ObjectID) ([]*domain.Booking, error)
+	// FindWaitlistedBookingsWithin returns the waitlisted bookings located
+	// inside boundary, oldest first.
+	FindWaitlistedBookingsWithin(ctx context.Context, boundary domain.GeoPolygon) ([]*domain.Booking, error)
+	// FindScheduledBookingsForMower returns the mower's accepted and ongoing
+	// bookings on date (YYYY-MM-DD), earliest time first.
+	FindScheduledBookingsForMower(ctx context.Context, mowerID primitive.ObjectID, date string) ([]*domain.Booking, error)
 	UpdateBooking(ctx context.Context, bookingID primitive.ObjectID, update bson.M) error
+	// UpdateBookingIfStatus applies update only while the booking has status
+	// and, unless quoteStatus is empty, a quote with quoteStatus. It returns
+	// apperror.NotFound otherwise, so concurrent transitions cannot both apply.
+	UpdateBookingIfStatus(ctx context.Context, bookingID primitive.ObjectID, status, quoteStatus string, update bson.M) error
+	// AddBookingComment appends a comment to a booking holding fewer than
+	// limit comments. A missing or full booking is returned as
+	// apperror.NotFound.
+	AddBookingComment(ctx context.Context, bookingID primitive.ObjectID, comment *domain.BookingComment, limit int) error
+	// UpdateBookingComment sets fields, keyed by their BSON names, on one
+	// comment of a booking. A missing booking or comment is returned as
+	// apperror.NotFound.
+	UpdateBookingComment(ctx context.Context, bookingID, commentID primitive.ObjectID, set bson.M) error
+	// DeleteBookingComment removes one comment from a booking. A missing
+	// booking or comment is returned as apperror.NotFound.
+	DeleteBookingComment(ctx context.Context, bookingID, commentID primitive.ObjectID) error
+	CountBookingsByStatus(ctx context.Context) (map[string]int64, error)
 }
 
 type bookingRepository struct {
@@ -73,12 +98,23 @@ func (r *bookingRepository) FindBookingsByUserID(ctx context.Context, userID pri
 	return bookings, nil
 }
 
-// FindPendingBookings retrieves all bookings with a "pending" status.
-func (r *bookingRepository) FindPendingBookings(ctx context.Context) ([]*domain.Booking, error) {
+// FindPendingBookingsNear retrieves the pending bookings near origin using the
+// 2dsphere index on location.
+func (r *bookingRepository) FindPendingBookingsNear(ctx context.Context, origin domain.GeoPoint, radiusKm float64, areaIDs []primitive.ObjectID) ([]*domain.Booking, error) {
 	var bookings []*domain.Booking
-	filter := bson.M{"status": "pending"}
+	pipeline := mongo.Pipeline{
+		{{Key: "$geoNear", Value: bson.M{
+			"near":               origin,
+			"key":                "location",
+			"distanceField":      "distanceKm",
+			"distanceMultiplier": 0.001,
+			"maxDistance":        radiusKm * 1000,
+			"spherical":          true,
+			"query":              bson.M{"status": "pending", "serviceAreaId": bson.M{"$in": areaIDs}},
+		}}},
+	}
 
-	cursor, err := r.collection.Find(ctx, filter)
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find pending bookings: %w", err)
 	}
@@ -90,6 +126,49 @@ func (r *bookingRepository) FindPendingBookings(ctx context.Context) ([]*domain.
 	return bookings, nil
 }
 
+// FindWaitlistedBookingsWithin retrieves the waitlisted bookings inside boundary
+// using the 2dsphere index on location.
+func (r *bookingRepository) FindWaitlistedBookingsWithin(ctx context.Context, boundary domain.GeoPolygon) ([]*domain.Booking, error) {
+	var bookings []*domain.Booking
+	filter := bson.M{
+		"status":   "waitlisted",
+		"location": bson.M{"$geoWithin": bson.M{"$geometry": boundary}},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find waitlisted bookings: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &bookings); err != nil {
+		return nil, fmt.Errorf("failed to decode waitlisted bookings: %w", err)
+	}
+	return bookings, nil
+}
+
+// FindScheduledBookingsForMower retrieves the mower's accepted and ongoing
+// bookings on date.
+func (r *bookingRepository) FindScheduledBookingsForMower(ctx context.Context, mowerID primitive.ObjectID, date string) ([]*domain.Booking, error) {
+	var bookings []*domain.Booking
+	filter := bson.M{
+		"mowerId": mowerID,
+		"date":    date,
+		"status":  bson.M{"$in": []string{"accepted", "ongoing"}},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "time", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find scheduled bookings: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &bookings); err != nil {
+		return nil, fmt.Errorf("failed to decode scheduled bookings: %w", err)
+	}
+	return bookings, nil
+}
+
 
 // UpdateBooking updates a booking document by its ID.
 func (r *bookingRepository) UpdateBooking(ctx context.Context, bookingID primitive.ObjectID, update bson.M) error {
@@ -100,3 +179,96 @@ func (r *bookingRepository) UpdateBooking(ctx context.Context, bookingID primiti
 	}
 	return nil
 }
+
+// UpdateBookingIfStatus updates a booking whose status, and quote status if
+// given, are still the expected ones.
+func (r *bookingRepository) UpdateBookingIfStatus(ctx context.Context, bookingID primitive.ObjectID, status, quoteStatus string, update bson.M) error {
+	filter := bson.M{"_id": bookingID, "status": status}
+	if quoteStatus != "" {
+		filter["quote.status"] = quoteStatus
+	}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update booking: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return apperror.NotFound{Resource: "Booking"}
+	}
+	return nil
+}
+
+// AddBookingComment pushes the comment only while the comments array has no
+// element at index limit-1.
+func (r *bookingRepository) AddBookingComment(ctx context.Context, bookingID primitive.ObjectID, comment *domain.BookingComment, limit int) error {
+	filter := bson.M{"_id": bookingID, fmt.Sprintf("comments.%d", limit-1): bson.M{"$exists": false}}
+	update := bson.M{
+		"$push": bson.M{"comments": comment},
+		"$set":  bson.M{"updatedAt": comment.Timestamp},
+	}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to add booking comment: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return apperror.NotFound{Resource: "Booking"}
+	}
+	return nil
+}
+
+// UpdateBookingComment sets fields on the comment matched by the positional
+// operator.
+func (r *bookingRepository) UpdateBookingComment(ctx context.Context, bookingID, commentID primitive.ObjectID, set bson.M) error {
+	fields := bson.M{}
+	for field, value := range set {
+		fields["comments.$."+field] = value
+	}
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": bookingID, "comments.id": commentID}, bson.M{"$set": fields})
+	if err != nil {
+		return fmt.Errorf("failed to update booking comment: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return apperror.NotFound{Resource: "Comment"}
+	}
+	return nil
+}
+
+// DeleteBookingComment pulls the comment from the booking's comments.
+func (r *bookingRepository) DeleteBookingComment(ctx context.Context, bookingID, commentID primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": bookingID, "comments.id": commentID},
+		bson.M{"$pull": bson.M{"comments": bson.M{"id": commentID}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete booking comment: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return apperror.NotFound{Resource: "Comment"}
+	}
+	return nil
+}
+
+// CountBookingsByStatus returns the number of bookings in each status.
+func (r *bookingRepository) CountBookingsByStatus(ctx context.Context) (map[string]int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count bookings by status: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Status string `bson:"_id"`
+		Count  int64  `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, fmt.Errorf("failed to decode booking counts: %w", err)
+	}
+
+	counts := make(map[string]int64, len(groups))
+	for _, g := range groups {
+		counts[g.Status] = g.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CommissionRuleRepository defines the repository interface for commission rules.
+type CommissionRuleRepository interface {
+	CreateRule(ctx context.Context, rule *domain.CommissionRule) error
+	FindRuleByID(ctx context.Context, id primitive.ObjectID) (*domain.CommissionRule, error)
+	// ListRules returns every rule, newest first. When activeOnly is set,
+	// deactivated rules are left out.
+	ListRules(ctx context.Context, activeOnly bool) ([]*domain.CommissionRule, error)
+	UpdateRule(ctx context.Context, id primitive.ObjectID, update bson.M) error
+}
+
+type commissionRuleRepository struct {
+	collection *mongo.Collection
+}
+
+// NewCommissionRuleRepository creates a new CommissionRuleRepository.
+func NewCommissionRuleRepository(db *mongo.Database) CommissionRuleRepository {
+	return &commissionRuleRepository{collection: db.Collection("commission_rules")}
+}
+
+// CreateRule inserts a new commission rule.
+func (r *commissionRuleRepository) CreateRule(ctx context.Context, rule *domain.CommissionRule) error {
+	if _, err := r.collection.InsertOne(ctx, rule); err != nil {
+		return fmt.Errorf("failed to insert commission rule: %w", err)
+	}
+	return nil
+}
+
+// FindRuleByID retrieves a commission rule by its ID.
+func (r *commissionRuleRepository) FindRuleByID(ctx context.Context, id primitive.ObjectID) (*domain.CommissionRule, error) {
+	var rule domain.CommissionRule
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&rule)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, apperror.NotFound{Resource: "Commission rule"}
+		}
+		return nil, fmt.Errorf("failed to find commission rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// ListRules retrieves commission rules, newest first.
+func (r *commissionRuleRepository) ListRules(ctx context.Context, activeOnly bool) ([]*domain.CommissionRule, error) {
+	filter := bson.M{}
+	if activeOnly {
+		filter["active"] = true
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find commission rules: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rules []*domain.CommissionRule
+	if err := cursor.All(ctx, &rules); err != nil {
+		return nil, fmt.Errorf("failed to decode commission rules: %w", err)
+	}
+	return rules, nil
+}
+
+// UpdateRule updates a commission rule by its ID.
+func (r *commissionRuleRepository) UpdateRule(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		return fmt.Errorf("failed to update commission rule: %w", err)
+	}
+	return nil
+}
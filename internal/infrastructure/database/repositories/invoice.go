@@ -0,0 +1,199 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// InvoiceRepository defines the repository interface for invoices.
+type InvoiceRepository interface {
+	// NextInvoiceSequence atomically allocates the next invoice sequence number
+	// for year, starting at 1.
+	NextInvoiceSequence(ctx context.Context, year int) (int64, error)
+	// CreateInvoice stores an invoice. A second invoice for the same booking or
+	// with the same number is rejected with apperror.DuplicateError.
+	CreateInvoice(ctx context.Context, invoice *domain.Invoice) error
+	FindInvoiceByID(ctx context.Context, id primitive.ObjectID) (*domain.Invoice, error)
+	FindInvoiceByBookingID(ctx context.Context, bookingID primitive.ObjectID) (*domain.Invoice, error)
+	// FindInvoicesByCustomerID returns a customer's invoices, newest first.
+	FindInvoicesByCustomerID(ctx context.Context, customerID primitive.ObjectID) ([]*domain.Invoice, error)
+	UpdateInvoice(ctx context.Context, id primitive.ObjectID, update bson.M) error
+	// UpdateInvoiceReminders applies update only while the invoice is unpaid and
+	// its reminder state (LastReminderAt and ReminderFailures) still matches
+	// invoice, and returns apperror.NotFound otherwise. It keeps a reminder from
+	// being recorded twice or after the invoice was paid.
+	UpdateInvoiceReminders(ctx context.Context, invoice *domain.Invoice, update bson.M) error
+	// FindUnpaidInvoicesIssuedBefore returns unpaid invoices issued before the
+	// given time, oldest first.
+	FindUnpaidInvoicesIssuedBefore(ctx context.Context, before time.Time) ([]*domain.Invoice, error)
+	// FindUnpaidInvoicesByMowerID returns the unpaid invoices for a mower's jobs.
+	FindUnpaidInvoicesByMowerID(ctx context.Context, mowerID primitive.ObjectID) ([]*domain.Invoice, error)
+	// CountOverdueInvoices counts a customer's unpaid invoices that reached the
+	// final reminder.
+	CountOverdueInvoices(ctx context.Context, customerID primitive.ObjectID) (int64, error)
+}
+
+type invoiceRepository struct {
+	collection *mongo.Collection
+	counters   *mongo.Collection
+}
+
+// NewInvoiceRepository creates a new InvoiceRepository.
+func NewInvoiceRepository(db *mongo.Database) InvoiceRepository {
+	return &invoiceRepository{
+		collection: db.Collection("invoices"),
+		counters:   db.Collection("invoice_counters"),
+	}
+}
+
+// NextInvoiceSequence increments the year's counter document, creating it on
+// first use. Inside a transaction the increment is rolled back with it, so
+// aborted completions do not leave gaps in the numbering.
+func (r *invoiceRepository) NextInvoiceSequence(ctx context.Context, year int) (int64, error) {
+	var counter struct {
+		Sequence int64 `bson:"sequence"`
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := r.counters.FindOneAndUpdate(ctx,
+		bson.M{"_id": year},
+		bson.M{"$inc": bson.M{"sequence": int64(1)}},
+		opts,
+	).Decode(&counter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to allocate invoice number for %d: %w", year, err)
+	}
+	return counter.Sequence, nil
+}
+
+// CreateInvoice inserts a new invoice document.
+func (r *invoiceRepository) CreateInvoice(ctx context.Context, invoice *domain.Invoice) error {
+	if _, err := r.collection.InsertOne(ctx, invoice); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return apperror.DuplicateError{Resource: "Invoice"}
+		}
+		return fmt.Errorf("failed to insert invoice: %w", err)
+	}
+	return nil
+}
+
+// FindInvoiceByID retrieves an invoice by its ID.
+func (r *invoiceRepository) FindInvoiceByID(ctx context.Context, id primitive.ObjectID) (*domain.Invoice, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+// FindInvoiceByBookingID retrieves the invoice issued for a booking.
+func (r *invoiceRepository) FindInvoiceByBookingID(ctx context.Context, bookingID primitive.ObjectID) (*domain.Invoice, error) {
+	return r.findOne(ctx, bson.M{"bookingId": bookingID})
+}
+
+func (r *invoiceRepository) findOne(ctx context.Context, filter bson.M) (*domain.Invoice, error) {
+	var invoice domain.Invoice
+	err := r.collection.FindOne(ctx, filter).Decode(&invoice)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, apperror.NotFound{Resource: "Invoice"}
+		}
+		return nil, fmt.Errorf("failed to find invoice: %w", err)
+	}
+	return &invoice, nil
+}
+
+// FindInvoicesByCustomerID retrieves a customer's invoices, newest first.
+func (r *invoiceRepository) FindInvoicesByCustomerID(ctx context.Context, customerID primitive.ObjectID) ([]*domain.Invoice, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "issuedAt", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"customerId": customerID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invoices: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var invoices []*domain.Invoice
+	if err := cursor.All(ctx, &invoices); err != nil {
+		return nil, fmt.Errorf("failed to decode invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+// UpdateInvoice updates an invoice by its ID.
+func (r *invoiceRepository) UpdateInvoice(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		return fmt.Errorf("failed to update invoice: %w", err)
+	}
+	return nil
+}
+
+// UpdateInvoiceReminders updates an unpaid invoice whose reminder state is
+// unchanged. Invoices from before reminderFailures was stored match a count of 0.
+func (r *invoiceRepository) UpdateInvoiceReminders(ctx context.Context, invoice *domain.Invoice, update bson.M) error {
+	filter := bson.M{"_id": invoice.ID, "status": domain.InvoiceIssued, "reminderFailures": invoice.ReminderFailures}
+	if invoice.ReminderFailures == 0 {
+		filter["reminderFailures"] = bson.M{"$in": bson.A{0, nil}}
+	}
+	filter["lastReminderAt"] = bson.M{"$exists": false}
+	if invoice.LastReminderAt != nil {
+		filter["lastReminderAt"] = *invoice.LastReminderAt
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update invoice reminders: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return apperror.NotFound{Resource: "Unpaid invoice"}
+	}
+	return nil
+}
+
+// FindUnpaidInvoicesIssuedBefore retrieves unpaid invoices issued before the given time.
+func (r *invoiceRepository) FindUnpaidInvoicesIssuedBefore(ctx context.Context, before time.Time) ([]*domain.Invoice, error) {
+	filter := bson.M{"status": domain.InvoiceIssued, "issuedAt": bson.M{"$lt": before}}
+	opts := options.Find().SetSort(bson.D{{Key: "issuedAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find unpaid invoices: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var invoices []*domain.Invoice
+	if err := cursor.All(ctx, &invoices); err != nil {
+		return nil, fmt.Errorf("failed to decode unpaid invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+// FindUnpaidInvoicesByMowerID retrieves the unpaid invoices for a mower's jobs.
+func (r *invoiceRepository) FindUnpaidInvoicesByMowerID(ctx context.Context, mowerID primitive.ObjectID) ([]*domain.Invoice, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"mowerId": mowerID, "status": domain.InvoiceIssued})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find unpaid invoices: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var invoices []*domain.Invoice
+	if err := cursor.All(ctx, &invoices); err != nil {
+		return nil, fmt.Errorf("failed to decode unpaid invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+// CountOverdueInvoices counts a customer's unpaid, overdue invoices.
+func (r *invoiceRepository) CountOverdueInvoices(ctx context.Context, customerID primitive.ObjectID) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{
+		"customerId": customerID,
+		"status":     domain.InvoiceIssued,
+		"overdue":    true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count overdue invoices: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// JobLockRepository provides named, expiring locks so a background job runs on
+// only one API instance at a time.
+type JobLockRepository interface {
+	// AcquireLock takes the named lock for owner until ttl from now. It reports
+	// false, without error, if another owner holds an unexpired lock. An owner may
+	// re-acquire its own lock to extend it.
+	AcquireLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+	// ReleaseLock gives up the lock if owner still holds it.
+	ReleaseLock(ctx context.Context, name, owner string) error
+}
+
+type jobLockRepository struct {
+	collection *mongo.Collection
+}
+
+// NewJobLockRepository creates a new JobLockRepository.
+func NewJobLockRepository(db *mongo.Database) JobLockRepository {
+	return &jobLockRepository{collection: db.Collection("job_locks")}
+}
+
+// AcquireLock upserts the lock document only if it has expired or is already
+// ours. When another owner holds it the filter does not match, the upsert tries
+// to insert a second document with the same _id, and the duplicate key error
+// means the lock is taken. Expiry relies on the instances' clocks agreeing to
+// well within ttl.
+func (r *jobLockRepository) AcquireLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id": name,
+		"$or": bson.A{
+			bson.M{"lockedUntil": bson.M{"$lte": now}},
+			bson.M{"owner": owner},
+		},
+	}
+	update := bson.M{"$set": bson.M{"owner": owner, "lockedUntil": now.Add(ttl), "acquiredAt": now}}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to acquire lock %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// ReleaseLock expires the lock if owner holds it.
+func (r *jobLockRepository) ReleaseLock(ctx context.Context, name, owner string) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": name, "owner": owner},
+		bson.M{"$set": bson.M{"lockedUntil": time.Now()}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LedgerRepository stores immutable ledger entries. There is deliberately no way
+// to update or delete an entry; corrections are new adjustment entries.
+type LedgerRepository interface {
+	// InsertEntries stores a balanced group of entries. An entry repeating the
+	// booking, type and account of an existing one is rejected with
+	// apperror.DuplicateError, so a booking can never be posted twice.
+	InsertEntries(ctx context.Context, entries []*domain.LedgerEntry) error
+	// FindEntriesByAccount returns an account's entries, oldest first.
+	FindEntriesByAccount(ctx context.Context, account string) ([]*domain.LedgerEntry, error)
+	// SumByAccount returns the balance of an account.
+	SumByAccount(ctx context.Context, account string) (int64, error)
+	// SumByPeriod totals an account's entries created in [from, to) per day, week
+	// (starting Monday) or month, in UTC, oldest period first.
+	SumByPeriod(ctx context.Context, account, period string, from, to time.Time) ([]domain.RevenueBucket, error)
+}
+
+type ledgerRepository struct {
+	collection *mongo.Collection
+}
+
+// NewLedgerRepository creates a new LedgerRepository.
+func NewLedgerRepository(db *mongo.Database) LedgerRepository {
+	return &ledgerRepository{collection: db.Collection("ledger_entries")}
+}
+
+// InsertEntries inserts all entries in one ordered write.
+func (r *ledgerRepository) InsertEntries(ctx context.Context, entries []*domain.LedgerEntry) error {
+	docs := make([]interface{}, len(entries))
+	for i, entry := range entries {
+		docs[i] = entry
+	}
+	_, err := r.collection.InsertMany(ctx, docs)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return apperror.DuplicateError{Resource: "Ledger entry"}
+		}
+		return fmt.Errorf("failed to insert ledger entries: %w", err)
+	}
+	return nil
+}
+
+// FindEntriesByAccount returns an account's entries in posting order.
+func (r *ledgerRepository) FindEntriesByAccount(ctx context.Context, account string) ([]*domain.LedgerEntry, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"account": account}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find ledger entries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*domain.LedgerEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode ledger entries: %w", err)
+	}
+	return entries, nil
+}
+
+// SumByAccount adds up an account's entries on the server.
+func (r *ledgerRepository) SumByAccount(ctx context.Context, account string) (int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"account": account}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "balance": bson.M{"$sum": "$amount"}}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum ledger entries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var result []struct {
+		Balance int64 `bson:"balance"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		return 0, fmt.Errorf("failed to decode ledger balance: %w", err)
+	}
+	if len(result) == 0 {
+		return 0, nil
+	}
+	return result[0].Balance, nil
+}
+
+// SumByPeriod groups an account's entries with $dateTrunc (MongoDB 5.0+).
+func (r *ledgerRepository) SumByPeriod(ctx context.Context, account, period string, from, to time.Time) ([]domain.RevenueBucket, error) {
+	truncate := bson.M{"date": "$createdAt", "unit": period, "timezone": "UTC"}
+	if period == domain.PeriodWeek {
+		truncate["startOfWeek"] = "monday"
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"account": account, "createdAt": bson.M{"$gte": from, "$lt": to}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     bson.M{"$dateTrunc": truncate},
+			"amount":  bson.M{"$sum": "$amount"},
+			"entries": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate ledger entries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var buckets []domain.RevenueBucket
+	if err := cursor.All(ctx, &buckets); err != nil {
+		return nil, fmt.Errorf("failed to decode ledger totals: %w", err)
+	}
+	for i := range buckets {
+		buckets[i].PeriodStart = buckets[i].PeriodStart.UTC()
+	}
+	return buckets, nil
+}
@@ -0,0 +1,248 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type bookingRepository struct {
+	bookings *collection
+}
+
+// NewBookingRepository creates an in-memory BookingRepository.
+func NewBookingRepository() repositories.BookingRepository {
+	return &bookingRepository{bookings: newCollection()}
+}
+
+// CreateBooking stores a new booking.
+func (r *bookingRepository) CreateBooking(ctx context.Context, booking *domain.Booking) error {
+	inserted, err := r.bookings.insert(booking.ID, booking)
+	if err != nil {
+		return fmt.Errorf("failed to insert booking: %w", err)
+	}
+	if !inserted {
+		return fmt.Errorf("failed to insert booking: duplicate id %s", booking.ID.Hex())
+	}
+	return nil
+}
+
+// FindBookingByID retrieves a single booking by its unique ID.
+func (r *bookingRepository) FindBookingByID(ctx context.Context, bookingID primitive.ObjectID) (*domain.Booking, error) {
+	var booking domain.Booking
+	found, err := r.bookings.get(bookingID, &booking)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find booking: %w", err)
+	}
+	if !found {
+		return nil, apperror.NotFound{Resource: "Booking"}
+	}
+	return &booking, nil
+}
+
+// FindBookingsByUserID retrieves all bookings where the user is the customer or the mower.
+func (r *bookingRepository) FindBookingsByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.Booking, error) {
+	return r.filter(func(b *domain.Booking) bool {
+		return b.CustomerID == userID || b.MowerID == userID
+	})
+}
+
+// FindPendingBookingsNear retrieves the pending bookings in areaIDs within
+// radiusKm of origin, nearest first.
+func (r *bookingRepository) FindPendingBookingsNear(ctx context.Context, origin domain.GeoPoint, radiusKm float64, areaIDs []primitive.ObjectID) ([]*domain.Booking, error) {
+	inAreas := make(map[primitive.ObjectID]bool, len(areaIDs))
+	for _, id := range areaIDs {
+		inAreas[id] = true
+	}
+	bookings, err := r.filter(func(b *domain.Booking) bool {
+		if b.Status != "pending" || b.Location == nil || !inAreas[b.ServiceAreaID] {
+			return false
+		}
+		b.DistanceKm = domain.DistanceKm(origin, *b.Location)
+		return b.DistanceKm <= radiusKm
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(bookings, func(i, j int) bool { return bookings[i].DistanceKm < bookings[j].DistanceKm })
+	return bookings, nil
+}
+
+// FindWaitlistedBookingsWithin retrieves the waitlisted bookings inside
+// boundary, oldest first.
+func (r *bookingRepository) FindWaitlistedBookingsWithin(ctx context.Context, boundary domain.GeoPolygon) ([]*domain.Booking, error) {
+	bookings, err := r.filter(func(b *domain.Booking) bool {
+		return b.Status == "waitlisted" && b.Location != nil && boundary.Contains(*b.Location)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(bookings, func(i, j int) bool { return bookings[i].CreatedAt.Before(bookings[j].CreatedAt) })
+	return bookings, nil
+}
+
+// FindScheduledBookingsForMower retrieves the mower's accepted and ongoing
+// bookings on date, earliest time first.
+func (r *bookingRepository) FindScheduledBookingsForMower(ctx context.Context, mowerID primitive.ObjectID, date string) ([]*domain.Booking, error) {
+	bookings, err := r.filter(func(b *domain.Booking) bool {
+		return b.MowerID == mowerID && b.Date == date && (b.Status == "accepted" || b.Status == "ongoing")
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(bookings, func(i, j int) bool { return bookings[i].Time < bookings[j].Time })
+	return bookings, nil
+}
+
+// UpdateBooking applies a BSON update document to a booking.
+func (r *bookingRepository) UpdateBooking(ctx context.Context, bookingID primitive.ObjectID, update bson.M) error {
+	if _, err := r.bookings.update(bookingID, update); err != nil {
+		return fmt.Errorf("failed to update booking: %w", err)
+	}
+	return nil
+}
+
+// UpdateBookingIfStatus updates a booking whose status, and quote status if
+// given, are still the expected ones.
+func (r *bookingRepository) UpdateBookingIfStatus(ctx context.Context, bookingID primitive.ObjectID, status, quoteStatus string, update bson.M) error {
+	matched, err := r.bookings.updateWhere(bookingID, func(raw bson.Raw) (bool, error) {
+		var current domain.Booking
+		if err := bson.Unmarshal(raw, &current); err != nil {
+			return false, err
+		}
+		if quoteStatus != "" && (current.Quote == nil || current.Quote.Status != quoteStatus) {
+			return false, nil
+		}
+		return current.Status == status, nil
+	}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update booking: %w", err)
+	}
+	if !matched {
+		return apperror.NotFound{Resource: "Booking"}
+	}
+	return nil
+}
+
+// AddBookingComment appends a comment to a booking that is not full.
+func (r *bookingRepository) AddBookingComment(ctx context.Context, bookingID primitive.ObjectID, comment *domain.BookingComment, limit int) error {
+	matched, err := r.bookings.updateWhere(bookingID, func(raw bson.Raw) (bool, error) {
+		var current domain.Booking
+		if err := bson.Unmarshal(raw, &current); err != nil {
+			return false, err
+		}
+		return len(current.Comments) < limit, nil
+	}, bson.M{
+		"$push": bson.M{"comments": comment},
+		"$set":  bson.M{"updatedAt": comment.Timestamp},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add booking comment: %w", err)
+	}
+	if !matched {
+		return apperror.NotFound{Resource: "Booking"}
+	}
+	return nil
+}
+
+// UpdateBookingComment sets fields on one comment of a booking.
+func (r *bookingRepository) UpdateBookingComment(ctx context.Context, bookingID, commentID primitive.ObjectID, set bson.M) error {
+	return r.editComments(bookingID, commentID, func(comments bson.A, i int) (bson.A, error) {
+		comment, ok := toM(comments[i])
+		if !ok {
+			return nil, fmt.Errorf("booking comment %d is not a document", i)
+		}
+		if err := applyUpdate(comment, bson.M{"$set": set}); err != nil {
+			return nil, err
+		}
+		comments[i] = comment
+		return comments, nil
+	})
+}
+
+// DeleteBookingComment removes one comment from a booking.
+func (r *bookingRepository) DeleteBookingComment(ctx context.Context, bookingID, commentID primitive.ObjectID) error {
+	return r.editComments(bookingID, commentID, func(comments bson.A, i int) (bson.A, error) {
+		return append(comments[:i:i], comments[i+1:]...), nil
+	})
+}
+
+// editComments replaces the comments of a booking with the result of edit,
+// which is given the index of the comment with commentID. The booking is read
+// and written under the collection lock, as the MongoDB updates are atomic.
+func (r *bookingRepository) editComments(bookingID, commentID primitive.ObjectID, edit func(comments bson.A, i int) (bson.A, error)) error {
+	r.bookings.mu.Lock()
+	defer r.bookings.mu.Unlock()
+
+	raw, ok := r.bookings.docs[bookingID]
+	if !ok {
+		return apperror.NotFound{Resource: "Comment"}
+	}
+	doc, err := decodeM(raw)
+	if err != nil {
+		return err
+	}
+	comments, _ := doc["comments"].(bson.A)
+	index := -1
+	for i, value := range comments {
+		if comment, ok := toM(value); ok && comment["id"] == commentID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return apperror.NotFound{Resource: "Comment"}
+	}
+	if doc["comments"], err = edit(comments, index); err != nil {
+		return fmt.Errorf("failed to update booking comment: %w", err)
+	}
+
+	encoded, err := bson.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode booking: %w", err)
+	}
+	r.bookings.docs[bookingID] = encoded
+	return nil
+}
+
+// CountBookingsByStatus returns the number of bookings in each status.
+func (r *bookingRepository) CountBookingsByStatus(ctx context.Context) (map[string]int64, error) {
+	bookings, err := r.filter(func(b *domain.Booking) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64)
+	for _, b := range bookings {
+		counts[b.Status]++
+	}
+	return counts, nil
+}
+
+// filter returns every booking matching keep, in insertion order.
+func (r *bookingRepository) filter(keep func(b *domain.Booking) bool) ([]*domain.Booking, error) {
+	var (
+		bookings  []*domain.Booking
+		decodeErr error
+	)
+	r.bookings.each(func(raw bson.Raw) bool {
+		var booking domain.Booking
+		if decodeErr = bson.Unmarshal(raw, &booking); decodeErr != nil {
+			return false
+		}
+		if keep(&booking) {
+			bookings = append(bookings, &booking)
+		}
+		return true
+	})
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode bookings: %w", decodeErr)
+	}
+	return bookings, nil
+}
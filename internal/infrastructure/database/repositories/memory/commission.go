@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type commissionRuleRepository struct {
+	rules *collection
+}
+
+// NewCommissionRuleRepository creates an in-memory CommissionRuleRepository.
+func NewCommissionRuleRepository() repositories.CommissionRuleRepository {
+	return &commissionRuleRepository{rules: newCollection()}
+}
+
+// CreateRule stores a new commission rule.
+func (r *commissionRuleRepository) CreateRule(ctx context.Context, rule *domain.CommissionRule) error {
+	inserted, err := r.rules.insert(rule.ID, rule)
+	if err != nil {
+		return fmt.Errorf("failed to insert commission rule: %w", err)
+	}
+	if !inserted {
+		return fmt.Errorf("failed to insert commission rule: duplicate id %s", rule.ID.Hex())
+	}
+	return nil
+}
+
+// FindRuleByID retrieves a commission rule by its ID.
+func (r *commissionRuleRepository) FindRuleByID(ctx context.Context, id primitive.ObjectID) (*domain.CommissionRule, error) {
+	var rule domain.CommissionRule
+	found, err := r.rules.get(id, &rule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find commission rule: %w", err)
+	}
+	if !found {
+		return nil, apperror.NotFound{Resource: "Commission rule"}
+	}
+	return &rule, nil
+}
+
+// ListRules returns commission rules, newest first.
+func (r *commissionRuleRepository) ListRules(ctx context.Context, activeOnly bool) ([]*domain.CommissionRule, error) {
+	var (
+		rules     []*domain.CommissionRule
+		decodeErr error
+	)
+	r.rules.each(func(raw bson.Raw) bool {
+		var rule domain.CommissionRule
+		if decodeErr = bson.Unmarshal(raw, &rule); decodeErr != nil {
+			return false
+		}
+		if !activeOnly || rule.Active {
+			// Insertion order is creation order, so prepending yields newest first.
+			rules = append([]*domain.CommissionRule{&rule}, rules...)
+		}
+		return true
+	})
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode commission rules: %w", decodeErr)
+	}
+	return rules, nil
+}
+
+// UpdateRule applies a BSON update document to a commission rule.
+func (r *commissionRuleRepository) UpdateRule(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	if _, err := r.rules.update(id, update); err != nil {
+		return fmt.Errorf("failed to update commission rule: %w", err)
+	}
+	return nil
+}
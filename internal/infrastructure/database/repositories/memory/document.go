@@ -0,0 +1,333 @@
+// Package memory provides thread-safe in-memory implementations of the repository
+// interfaces for unit tests and local development without MongoDB.
+package memory
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// collection is a thread-safe, insertion-ordered set of BSON documents keyed by _id.
+// Documents are stored as encoded BSON so callers never share memory with the store,
+// and fields that are not part of the domain structs (such as resetToken) survive
+// updates exactly as they would in MongoDB.
+type collection struct {
+	mu    sync.RWMutex
+	docs  map[primitive.ObjectID]bson.Raw
+	order []primitive.ObjectID
+}
+
+func newCollection() *collection {
+	return &collection{docs: make(map[primitive.ObjectID]bson.Raw)}
+}
+
+// insert stores a document. It reports false if a document with the same ID exists.
+func (c *collection) insert(id primitive.ObjectID, doc interface{}) (bool, error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode document: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.docs[id]; exists {
+		return false, nil
+	}
+	c.docs[id] = raw
+	c.order = append(c.order, id)
+	return true, nil
+}
+
+// get decodes the document with the given ID into out. It reports false if not found.
+func (c *collection) get(id primitive.ObjectID, out interface{}) (bool, error) {
+	c.mu.RLock()
+	raw, ok := c.docs[id]
+	c.mu.RUnlock()
+	if !ok {
+		return false, nil
+	}
+	return true, bson.Unmarshal(raw, out)
+}
+
+// each calls fn with every document in insertion order until fn returns false.
+func (c *collection) each(fn func(raw bson.Raw) bool) {
+	c.mu.RLock()
+	snapshot := make([]bson.Raw, 0, len(c.order))
+	for _, id := range c.order {
+		snapshot = append(snapshot, c.docs[id])
+	}
+	c.mu.RUnlock()
+
+	for _, raw := range snapshot {
+		if !fn(raw) {
+			return
+		}
+	}
+}
+
+// update applies a MongoDB-style update document to the document with the given ID.
+// Like UpdateOne, updating a missing document is not an error; matched reports
+// whether the document existed.
+func (c *collection) update(id primitive.ObjectID, update bson.M) (matched bool, err error) {
+	return c.updateChecked(id, update, nil)
+}
+
+// updateChecked is update with a check of the updated document before it is
+// stored, such as a unique constraint. check runs with the collection locked
+// and its error is returned as is.
+func (c *collection) updateChecked(id primitive.ObjectID, update bson.M, check func(doc bson.M) error) (matched bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	raw, ok := c.docs[id]
+	if !ok {
+		return false, nil
+	}
+	return true, c.apply(id, raw, update, check)
+}
+
+// updateWhere is update for an UpdateOne whose filter has conditions besides the
+// ID: the document is only updated if match accepts it. match runs with the
+// collection locked, so the test and the update are atomic; matched is false
+// when the document is missing or rejected.
+func (c *collection) updateWhere(id primitive.ObjectID, match func(raw bson.Raw) (bool, error), update bson.M) (matched bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	raw, ok := c.docs[id]
+	if !ok {
+		return false, nil
+	}
+	if ok, err := match(raw); err != nil || !ok {
+		return false, err
+	}
+	return true, c.apply(id, raw, update, nil)
+}
+
+// apply updates and stores a document. The caller holds the lock.
+func (c *collection) apply(id primitive.ObjectID, raw bson.Raw, update bson.M, check func(doc bson.M) error) error {
+	doc, err := decodeM(raw)
+	if err != nil {
+		return err
+	}
+	if err := applyUpdate(doc, update); err != nil {
+		return err
+	}
+	if check != nil {
+		if err := check(doc); err != nil {
+			return err
+		}
+	}
+
+	encoded, err := bson.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode updated document: %w", err)
+	}
+	c.docs[id] = encoded
+	return nil
+}
+
+// decodeM decodes raw BSON into a bson.M whose nested documents are also bson.M,
+// so dotted update paths can be walked.
+func decodeM(raw bson.Raw) (bson.M, error) {
+	dec, err := bson.NewDecoder(bsonrw.NewBSONDocumentReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	dec.DefaultDocumentM()
+
+	var doc bson.M
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	return doc, nil
+}
+
+// applyUpdate applies the subset of MongoDB update operators the services use.
+func applyUpdate(doc bson.M, update bson.M) error {
+	for op, arg := range update {
+		fields, ok := toM(arg)
+		if !ok {
+			return fmt.Errorf("update operator %s expects a document", op)
+		}
+
+		for path, value := range fields {
+			value, err := normalizeValue(value)
+			if err != nil {
+				return fmt.Errorf("invalid value for %q: %w", path, err)
+			}
+			switch op {
+			case "$set":
+				err = setPath(doc, path, value)
+			case "$unset":
+				unsetPath(doc, path)
+			case "$inc":
+				err = incPath(doc, path, value)
+			case "$push":
+				err = pushPath(doc, path, value)
+			default:
+				return fmt.Errorf("unsupported update operator %s", op)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeValue converts structs, slices and pointers in an update into the plain
+// bson.M / bson.A form they would have after a round trip through MongoDB, so later
+// dotted-path updates can traverse them.
+func normalizeValue(v interface{}) (interface{}, error) {
+	raw, err := bson.Marshal(bson.M{"v": v})
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeM(raw)
+	if err != nil {
+		return nil, err
+	}
+	return doc["v"], nil
+}
+
+// toM converts the document-like values the services build into a bson.M.
+func toM(v interface{}) (bson.M, bool) {
+	switch m := v.(type) {
+	case bson.M:
+		return m, true
+	case map[string]interface{}:
+		return bson.M(m), true
+	case bson.D:
+		return m.Map(), true
+	default:
+		return nil, false
+	}
+}
+
+// parent walks a dotted path, creating intermediate documents when create is true,
+// and returns the document holding the final path segment.
+func parent(doc bson.M, path string, create bool) (bson.M, string, error) {
+	segments := strings.Split(path, ".")
+	current := doc
+	for _, segment := range segments[:len(segments)-1] {
+		next, exists := current[segment]
+		if !exists || next == nil {
+			if !create {
+				return nil, "", nil
+			}
+			child := bson.M{}
+			current[segment] = child
+			current = child
+			continue
+		}
+		child, ok := toM(next)
+		if !ok {
+			return nil, "", fmt.Errorf("cannot traverse %q: field %q is not a document", path, segment)
+		}
+		current[segment] = child
+		current = child
+	}
+	return current, segments[len(segments)-1], nil
+}
+
+func setPath(doc bson.M, path string, value interface{}) error {
+	holder, key, err := parent(doc, path, true)
+	if err != nil {
+		return err
+	}
+	holder[key] = value
+	return nil
+}
+
+func unsetPath(doc bson.M, path string) {
+	holder, key, err := parent(doc, path, false)
+	if err != nil || holder == nil {
+		return
+	}
+	delete(holder, key)
+}
+
+func incPath(doc bson.M, path string, value interface{}) error {
+	holder, key, err := parent(doc, path, true)
+	if err != nil {
+		return err
+	}
+
+	current, exists := holder[key]
+	if !exists || current == nil {
+		holder[key] = value
+		return nil
+	}
+	sum, err := addNumbers(current, value)
+	if err != nil {
+		return fmt.Errorf("cannot $inc %q: %w", path, err)
+	}
+	holder[key] = sum
+	return nil
+}
+
+func pushPath(doc bson.M, path string, value interface{}) error {
+	holder, key, err := parent(doc, path, true)
+	if err != nil {
+		return err
+	}
+
+	switch existing := holder[key].(type) {
+	case nil:
+		holder[key] = bson.A{value}
+	case bson.A:
+		holder[key] = append(existing, value)
+	default:
+		return fmt.Errorf("cannot $push to %q: field is not an array", path)
+	}
+	return nil
+}
+
+// addNumbers adds two BSON numeric values, keeping integers integral where MongoDB would.
+func addNumbers(a, b interface{}) (interface{}, error) {
+	ai, aInt := asInt64(a)
+	bi, bInt := asInt64(b)
+	if aInt && bInt {
+		return ai + bi, nil
+	}
+
+	af, aOK := asFloat64(a)
+	bf, bOK := asFloat64(b)
+	if !aOK || !bOK {
+		return nil, fmt.Errorf("non-numeric operand")
+	}
+	return af + bf, nil
+}
+
+func asInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+func asFloat64(v interface{}) (float64, bool) {
+	if n, ok := asInt64(v); ok {
+		return float64(n), true
+	}
+	switch n := v.(type) {
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
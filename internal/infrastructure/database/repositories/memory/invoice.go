@@ -0,0 +1,182 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type invoiceRepository struct {
+	invoices *collection
+
+	// mu guards counters and makes the uniqueness checks in CreateInvoice atomic
+	// with the insert, like the unique indexes on bookingId and number.
+	mu       sync.Mutex
+	counters map[int]int64
+}
+
+// NewInvoiceRepository creates an in-memory InvoiceRepository.
+func NewInvoiceRepository() repositories.InvoiceRepository {
+	return &invoiceRepository{invoices: newCollection(), counters: make(map[int]int64)}
+}
+
+// NextInvoiceSequence allocates the next sequence number for year.
+func (r *invoiceRepository) NextInvoiceSequence(ctx context.Context, year int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counters[year]++
+	return r.counters[year], nil
+}
+
+// CreateInvoice stores a new invoice, rejecting a second invoice for the same
+// booking or number.
+func (r *invoiceRepository) CreateInvoice(ctx context.Context, invoice *domain.Invoice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.filter(func(i *domain.Invoice) bool {
+		return i.BookingID == invoice.BookingID || i.Number == invoice.Number
+	})
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return apperror.DuplicateError{Resource: "Invoice"}
+	}
+
+	inserted, err := r.invoices.insert(invoice.ID, invoice)
+	if err != nil {
+		return fmt.Errorf("failed to insert invoice: %w", err)
+	}
+	if !inserted {
+		return apperror.DuplicateError{Resource: "Invoice"}
+	}
+	return nil
+}
+
+// FindInvoiceByID retrieves an invoice by its ID.
+func (r *invoiceRepository) FindInvoiceByID(ctx context.Context, id primitive.ObjectID) (*domain.Invoice, error) {
+	var invoice domain.Invoice
+	found, err := r.invoices.get(id, &invoice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invoice: %w", err)
+	}
+	if !found {
+		return nil, apperror.NotFound{Resource: "Invoice"}
+	}
+	return &invoice, nil
+}
+
+// FindInvoiceByBookingID retrieves the invoice issued for a booking.
+func (r *invoiceRepository) FindInvoiceByBookingID(ctx context.Context, bookingID primitive.ObjectID) (*domain.Invoice, error) {
+	invoices, err := r.filter(func(i *domain.Invoice) bool { return i.BookingID == bookingID })
+	if err != nil {
+		return nil, err
+	}
+	if len(invoices) == 0 {
+		return nil, apperror.NotFound{Resource: "Invoice"}
+	}
+	return invoices[0], nil
+}
+
+// FindInvoicesByCustomerID retrieves a customer's invoices, newest first.
+func (r *invoiceRepository) FindInvoicesByCustomerID(ctx context.Context, customerID primitive.ObjectID) ([]*domain.Invoice, error) {
+	invoices, err := r.filter(func(i *domain.Invoice) bool { return i.CustomerID == customerID })
+	if err != nil {
+		return nil, err
+	}
+	// Insertion order is issue order.
+	for i, j := 0, len(invoices)-1; i < j; i, j = i+1, j-1 {
+		invoices[i], invoices[j] = invoices[j], invoices[i]
+	}
+	return invoices, nil
+}
+
+// UpdateInvoice applies a BSON update document to an invoice.
+func (r *invoiceRepository) UpdateInvoice(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	if _, err := r.invoices.update(id, update); err != nil {
+		return fmt.Errorf("failed to update invoice: %w", err)
+	}
+	return nil
+}
+
+// UpdateInvoiceReminders updates an unpaid invoice whose reminder state is unchanged.
+func (r *invoiceRepository) UpdateInvoiceReminders(ctx context.Context, invoice *domain.Invoice, update bson.M) error {
+	matched, err := r.invoices.updateWhere(invoice.ID, func(raw bson.Raw) (bool, error) {
+		var current domain.Invoice
+		if err := bson.Unmarshal(raw, &current); err != nil {
+			return false, err
+		}
+		sameReminder := current.LastReminderAt == nil && invoice.LastReminderAt == nil ||
+			current.LastReminderAt != nil && invoice.LastReminderAt != nil && current.LastReminderAt.Equal(*invoice.LastReminderAt)
+		return current.Status == domain.InvoiceIssued && current.ReminderFailures == invoice.ReminderFailures && sameReminder, nil
+	}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update invoice reminders: %w", err)
+	}
+	if !matched {
+		return apperror.NotFound{Resource: "Unpaid invoice"}
+	}
+	return nil
+}
+
+// FindUnpaidInvoicesIssuedBefore retrieves unpaid invoices issued before the given time, oldest first.
+func (r *invoiceRepository) FindUnpaidInvoicesIssuedBefore(ctx context.Context, before time.Time) ([]*domain.Invoice, error) {
+	invoices, err := r.filter(func(i *domain.Invoice) bool {
+		return i.Status == domain.InvoiceIssued && i.IssuedAt.Before(before)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(invoices, func(a, b int) bool { return invoices[a].IssuedAt.Before(invoices[b].IssuedAt) })
+	return invoices, nil
+}
+
+// FindUnpaidInvoicesByMowerID retrieves the unpaid invoices for a mower's jobs.
+func (r *invoiceRepository) FindUnpaidInvoicesByMowerID(ctx context.Context, mowerID primitive.ObjectID) ([]*domain.Invoice, error) {
+	return r.filter(func(i *domain.Invoice) bool {
+		return i.MowerID == mowerID && i.Status == domain.InvoiceIssued
+	})
+}
+
+// CountOverdueInvoices counts a customer's unpaid, overdue invoices.
+func (r *invoiceRepository) CountOverdueInvoices(ctx context.Context, customerID primitive.ObjectID) (int64, error) {
+	invoices, err := r.filter(func(i *domain.Invoice) bool {
+		return i.CustomerID == customerID && i.Status == domain.InvoiceIssued && i.Overdue
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(invoices)), nil
+}
+
+// filter returns every invoice matching keep, in insertion order.
+func (r *invoiceRepository) filter(keep func(i *domain.Invoice) bool) ([]*domain.Invoice, error) {
+	var (
+		invoices  []*domain.Invoice
+		decodeErr error
+	)
+	r.invoices.each(func(raw bson.Raw) bool {
+		var invoice domain.Invoice
+		if decodeErr = bson.Unmarshal(raw, &invoice); decodeErr != nil {
+			return false
+		}
+		if keep(&invoice) {
+			invoices = append(invoices, &invoice)
+		}
+		return true
+	})
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode invoices: %w", decodeErr)
+	}
+	return invoices, nil
+}
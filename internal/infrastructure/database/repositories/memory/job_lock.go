@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+)
+
+type jobLock struct {
+	owner       string
+	lockedUntil time.Time
+}
+
+type jobLockRepository struct {
+	mu    sync.Mutex
+	locks map[string]jobLock
+}
+
+// NewJobLockRepository creates an in-memory JobLockRepository, which only
+// coordinates jobs within one process.
+func NewJobLockRepository() repositories.JobLockRepository {
+	return &jobLockRepository{locks: make(map[string]jobLock)}
+}
+
+// AcquireLock takes the lock if it is free, expired or already owned by owner.
+func (r *jobLockRepository) AcquireLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if lock, held := r.locks[name]; held && lock.owner != owner && lock.lockedUntil.After(now) {
+		return false, nil
+	}
+	r.locks[name] = jobLock{owner: owner, lockedUntil: now.Add(ttl)}
+	return true, nil
+}
+
+// ReleaseLock expires the lock if owner holds it.
+func (r *jobLockRepository) ReleaseLock(ctx context.Context, name, owner string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if lock, held := r.locks[name]; held && lock.owner == owner {
+		lock.lockedUntil = time.Now()
+		r.locks[name] = lock
+	}
+	return nil
+}
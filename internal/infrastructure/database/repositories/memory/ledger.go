@@ -0,0 +1,142 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ledgerRepository struct {
+	mu      sync.Mutex
+	entries *collection
+	// posted holds the booking/type/account keys enforced by a unique index in MongoDB.
+	posted map[string]bool
+}
+
+// NewLedgerRepository creates an in-memory LedgerRepository.
+func NewLedgerRepository() repositories.LedgerRepository {
+	return &ledgerRepository{entries: newCollection(), posted: make(map[string]bool)}
+}
+
+// InsertEntries stores all entries, or none if any would repeat a booking posting.
+func (r *ledgerRepository) InsertEntries(ctx context.Context, entries []*domain.LedgerEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.BookingID.IsZero() {
+			continue
+		}
+		key := entry.BookingID.Hex() + "/" + string(entry.Type) + "/" + entry.Account
+		if r.posted[key] {
+			return apperror.DuplicateError{Resource: "Ledger entry"}
+		}
+		keys = append(keys, key)
+	}
+
+	for _, entry := range entries {
+		if entry.ID.IsZero() {
+			entry.ID = primitive.NewObjectID()
+		}
+		if _, err := r.entries.insert(entry.ID, entry); err != nil {
+			return fmt.Errorf("failed to insert ledger entries: %w", err)
+		}
+	}
+	for _, key := range keys {
+		r.posted[key] = true
+	}
+	return nil
+}
+
+// FindEntriesByAccount returns an account's entries in posting order.
+func (r *ledgerRepository) FindEntriesByAccount(ctx context.Context, account string) ([]*domain.LedgerEntry, error) {
+	var (
+		entries   []*domain.LedgerEntry
+		decodeErr error
+	)
+	r.entries.each(func(raw bson.Raw) bool {
+		var entry domain.LedgerEntry
+		if decodeErr = bson.Unmarshal(raw, &entry); decodeErr != nil {
+			return false
+		}
+		if entry.Account == account {
+			entries = append(entries, &entry)
+		}
+		return true
+	})
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode ledger entries: %w", decodeErr)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+	return entries, nil
+}
+
+// SumByAccount returns the balance of an account.
+func (r *ledgerRepository) SumByAccount(ctx context.Context, account string) (int64, error) {
+	entries, err := r.FindEntriesByAccount(ctx, account)
+	if err != nil {
+		return 0, err
+	}
+	var balance int64
+	for _, entry := range entries {
+		balance += entry.Amount
+	}
+	return balance, nil
+}
+
+// SumByPeriod totals an account's entries in [from, to) per UTC period.
+func (r *ledgerRepository) SumByPeriod(ctx context.Context, account, period string, from, to time.Time) ([]domain.RevenueBucket, error) {
+	entries, err := r.FindEntriesByAccount(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[time.Time]*domain.RevenueBucket)
+	for _, entry := range entries {
+		if entry.CreatedAt.Before(from) || !entry.CreatedAt.Before(to) {
+			continue
+		}
+		start := domain.TruncateToPeriod(entry.CreatedAt, period)
+		bucket, ok := totals[start]
+		if !ok {
+			bucket = &domain.RevenueBucket{PeriodStart: start}
+			totals[start] = bucket
+		}
+		bucket.Amount += entry.Amount
+		bucket.Entries++
+	}
+
+	buckets := make([]domain.RevenueBucket, 0, len(totals))
+	for _, bucket := range totals {
+		buckets = append(buckets, *bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].PeriodStart.Before(buckets[j].PeriodStart)
+	})
+	return buckets, nil
+}
+
+type transactor struct{}
+
+// NewTransactor returns a Transactor that simply runs the function. The in-memory
+// repositories have no rollback, so it only lets services run unchanged in tests.
+func NewTransactor() database.Transactor {
+	return transactor{}
+}
+
+func (transactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,93 @@
+package memory_test
+
+import (
+	"testing"
+
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+	"lawnconnect-api/internal/infrastructure/database/repositories/memory"
+	"lawnconnect-api/internal/infrastructure/database/repositories/repositorytest"
+)
+
+func TestUserRepository(t *testing.T) {
+	repositorytest.TestUserRepository(t, func(t *testing.T) repositories.UserRepository {
+		return memory.NewUserRepository()
+	})
+}
+
+func TestPasswordResetTokenRepository(t *testing.T) {
+	repositorytest.TestPasswordResetTokenRepository(t, func(t *testing.T) repositories.PasswordResetTokenRepository {
+		return memory.NewPasswordResetTokenRepository()
+	})
+}
+
+func TestBookingRepository(t *testing.T) {
+	repositorytest.TestBookingRepository(t, func(t *testing.T) repositories.BookingRepository {
+		return memory.NewBookingRepository()
+	})
+}
+
+func TestLedgerRepository(t *testing.T) {
+	repositorytest.TestLedgerRepository(t, func(t *testing.T) repositories.LedgerRepository {
+		return memory.NewLedgerRepository()
+	})
+}
+
+func TestCommissionRuleRepository(t *testing.T) {
+	repositorytest.TestCommissionRuleRepository(t, func(t *testing.T) repositories.CommissionRuleRepository {
+		return memory.NewCommissionRuleRepository()
+	})
+}
+
+func TestSystemMetricsRepository(t *testing.T) {
+	repositorytest.TestSystemMetricsRepository(t, func(t *testing.T) repositories.SystemMetricsRepository {
+		return memory.NewSystemMetricsRepository()
+	})
+}
+
+func TestInvoiceRepository(t *testing.T) {
+	repositorytest.TestInvoiceRepository(t, func(t *testing.T) repositories.InvoiceRepository {
+		return memory.NewInvoiceRepository()
+	})
+}
+
+func TestPaymentReminderRepository(t *testing.T) {
+	repositorytest.TestPaymentReminderRepository(t, func(t *testing.T) repositories.PaymentReminderRepository {
+		return memory.NewPaymentReminderRepository()
+	})
+}
+
+func TestJobLockRepository(t *testing.T) {
+	repositorytest.TestJobLockRepository(t, func(t *testing.T) repositories.JobLockRepository {
+		return memory.NewJobLockRepository()
+	})
+}
+
+func TestPaymentEventRepository(t *testing.T) {
+	repositorytest.TestPaymentEventRepository(t, func(t *testing.T) repositories.PaymentEventRepository {
+		return memory.NewPaymentEventRepository()
+	})
+}
+
+func TestPayoutRepository(t *testing.T) {
+	repositorytest.TestPayoutRepository(t, func(t *testing.T) repositories.PayoutRepository {
+		return memory.NewPayoutRepository()
+	})
+}
+
+func TestPropertyRepository(t *testing.T) {
+	repositorytest.TestPropertyRepository(t, func(t *testing.T) repositories.PropertyRepository {
+		return memory.NewPropertyRepository()
+	})
+}
+
+func TestServiceAreaRepository(t *testing.T) {
+	repositorytest.TestServiceAreaRepository(t, func(t *testing.T) repositories.ServiceAreaRepository {
+		return memory.NewServiceAreaRepository()
+	})
+}
+
+func TestReviewRepository(t *testing.T) {
+	repositorytest.TestReviewRepository(t, func(t *testing.T) repositories.ReviewRepository {
+		return memory.NewReviewRepository()
+	})
+}
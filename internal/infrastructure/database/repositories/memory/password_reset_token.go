@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type passwordResetTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[string]domain.PasswordResetToken
+}
+
+// NewPasswordResetTokenRepository creates an in-memory PasswordResetTokenRepository.
+// Like the TTL index, nothing is removed the moment it expires.
+func NewPasswordResetTokenRepository() repositories.PasswordResetTokenRepository {
+	return &passwordResetTokenRepository{tokens: make(map[string]domain.PasswordResetToken)}
+}
+
+// CreateResetToken stores the token.
+func (r *passwordResetTokenRepository) CreateResetToken(ctx context.Context, token *domain.PasswordResetToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tokens[token.Token] = *token
+	return nil
+}
+
+// FindResetToken retrieves a token by its value.
+func (r *passwordResetTokenRepository) FindResetToken(ctx context.Context, token string) (*domain.PasswordResetToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	found, ok := r.tokens[token]
+	if !ok {
+		return nil, apperror.NotFound{Resource: "Password reset token"}
+	}
+	return &found, nil
+}
+
+// DeleteUserResetTokens deletes the user's tokens.
+func (r *passwordResetTokenRepository) DeleteUserResetTokens(ctx context.Context, userID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for value, token := range r.tokens {
+		if token.UserID == userID {
+			delete(r.tokens, value)
+		}
+	}
+	return nil
+}
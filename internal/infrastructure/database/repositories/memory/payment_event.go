@@ -0,0 +1,32 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+)
+
+type paymentEventRepository struct {
+	mu     sync.Mutex
+	events map[string]domain.PaymentEvent
+}
+
+// NewPaymentEventRepository creates an in-memory PaymentEventRepository.
+func NewPaymentEventRepository() repositories.PaymentEventRepository {
+	return &paymentEventRepository{events: make(map[string]domain.PaymentEvent)}
+}
+
+// RecordEvent stores the event unless its ID has been seen.
+func (r *paymentEventRepository) RecordEvent(ctx context.Context, event *domain.PaymentEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, seen := r.events[event.ID]; seen {
+		return apperror.DuplicateError{Resource: "Payment event"}
+	}
+	r.events[event.ID] = *event
+	return nil
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type paymentReminderRepository struct {
+	reminders *collection
+}
+
+// NewPaymentReminderRepository creates an in-memory PaymentReminderRepository.
+func NewPaymentReminderRepository() repositories.PaymentReminderRepository {
+	return &paymentReminderRepository{reminders: newCollection()}
+}
+
+// CreateReminder stores a reminder attempt.
+func (r *paymentReminderRepository) CreateReminder(ctx context.Context, reminder *domain.PaymentReminder) error {
+	inserted, err := r.reminders.insert(reminder.ID, reminder)
+	if err != nil {
+		return fmt.Errorf("failed to insert payment reminder: %w", err)
+	}
+	if !inserted {
+		return fmt.Errorf("failed to insert payment reminder: duplicate id %s", reminder.ID.Hex())
+	}
+	return nil
+}
+
+// FindRemindersByInvoiceID retrieves an invoice's reminder attempts, oldest first.
+func (r *paymentReminderRepository) FindRemindersByInvoiceID(ctx context.Context, invoiceID primitive.ObjectID) ([]*domain.PaymentReminder, error) {
+	var (
+		reminders []*domain.PaymentReminder
+		decodeErr error
+	)
+	r.reminders.each(func(raw bson.Raw) bool {
+		var reminder domain.PaymentReminder
+		if decodeErr = bson.Unmarshal(raw, &reminder); decodeErr != nil {
+			return false
+		}
+		if reminder.InvoiceID == invoiceID {
+			reminders = append(reminders, &reminder)
+		}
+		return true
+	})
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode payment reminders: %w", decodeErr)
+	}
+	sort.SliceStable(reminders, func(a, b int) bool { return reminders[a].CreatedAt.Before(reminders[b].CreatedAt) })
+	return reminders, nil
+}
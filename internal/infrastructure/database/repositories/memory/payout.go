@@ -0,0 +1,190 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type payoutRepository struct {
+	requests *collection
+	batches  *collection
+
+	// mu makes the open request check in CreatePayoutRequest atomic with the
+	// insert, like the partial unique index on mowerId.
+	mu sync.Mutex
+}
+
+// NewPayoutRepository creates an in-memory PayoutRepository.
+func NewPayoutRepository() repositories.PayoutRepository {
+	return &payoutRepository{requests: newCollection(), batches: newCollection()}
+}
+
+// CreatePayoutRequest stores a new payout request, rejecting a second open
+// request for the same mower.
+func (r *payoutRepository) CreatePayoutRequest(ctx context.Context, request *domain.PayoutRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if request.Open {
+		open, err := r.filter(func(p *domain.PayoutRequest) bool { return p.MowerID == request.MowerID && p.Open })
+		if err != nil {
+			return err
+		}
+		if len(open) > 0 {
+			return apperror.DuplicateError{Resource: "Payout request"}
+		}
+	}
+
+	inserted, err := r.requests.insert(request.ID, request)
+	if err != nil {
+		return fmt.Errorf("failed to insert payout request: %w", err)
+	}
+	if !inserted {
+		return apperror.DuplicateError{Resource: "Payout request"}
+	}
+	return nil
+}
+
+// FindPayoutRequestByID retrieves a payout request by its ID.
+func (r *payoutRepository) FindPayoutRequestByID(ctx context.Context, id primitive.ObjectID) (*domain.PayoutRequest, error) {
+	var request domain.PayoutRequest
+	found, err := r.requests.get(id, &request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find payout request: %w", err)
+	}
+	if !found {
+		return nil, apperror.NotFound{Resource: "Payout request"}
+	}
+	return &request, nil
+}
+
+// FindOpenPayoutRequest retrieves the mower's open payout request.
+func (r *payoutRepository) FindOpenPayoutRequest(ctx context.Context, mowerID primitive.ObjectID) (*domain.PayoutRequest, error) {
+	open, err := r.filter(func(p *domain.PayoutRequest) bool { return p.MowerID == mowerID && p.Open })
+	if err != nil {
+		return nil, err
+	}
+	if len(open) == 0 {
+		return nil, apperror.NotFound{Resource: "Payout request"}
+	}
+	return open[0], nil
+}
+
+// FindPayoutRequestsByMowerID retrieves a mower's payout requests, newest first.
+func (r *payoutRepository) FindPayoutRequestsByMowerID(ctx context.Context, mowerID primitive.ObjectID) ([]*domain.PayoutRequest, error) {
+	requests, err := r.filter(func(p *domain.PayoutRequest) bool { return p.MowerID == mowerID })
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(requests, func(a, b int) bool { return requests[a].CreatedAt.After(requests[b].CreatedAt) })
+	return requests, nil
+}
+
+// FindPayoutRequestsByStatus retrieves the payout requests in status, oldest first.
+func (r *payoutRepository) FindPayoutRequestsByStatus(ctx context.Context, status string) ([]*domain.PayoutRequest, error) {
+	requests, err := r.filter(func(p *domain.PayoutRequest) bool { return p.Status == status })
+	if err != nil {
+		return nil, err
+	}
+	sortPayoutRequests(requests)
+	return requests, nil
+}
+
+// FindPayoutRequestsByBatchID retrieves the requests paid in a batch, oldest first.
+func (r *payoutRepository) FindPayoutRequestsByBatchID(ctx context.Context, batchID primitive.ObjectID) ([]*domain.PayoutRequest, error) {
+	requests, err := r.filter(func(p *domain.PayoutRequest) bool { return p.BatchID == batchID })
+	if err != nil {
+		return nil, err
+	}
+	sortPayoutRequests(requests)
+	return requests, nil
+}
+
+func sortPayoutRequests(requests []*domain.PayoutRequest) {
+	sort.SliceStable(requests, func(a, b int) bool { return requests[a].CreatedAt.Before(requests[b].CreatedAt) })
+}
+
+// UpdatePayoutRequest applies a BSON update document to a payout request.
+func (r *payoutRepository) UpdatePayoutRequest(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	if _, err := r.requests.update(id, update); err != nil {
+		return fmt.Errorf("failed to update payout request: %w", err)
+	}
+	return nil
+}
+
+// CreatePayoutBatch stores a new payout batch.
+func (r *payoutRepository) CreatePayoutBatch(ctx context.Context, batch *domain.PayoutBatch) error {
+	inserted, err := r.batches.insert(batch.ID, batch)
+	if err != nil {
+		return fmt.Errorf("failed to insert payout batch: %w", err)
+	}
+	if !inserted {
+		return fmt.Errorf("failed to insert payout batch: duplicate id %s", batch.ID.Hex())
+	}
+	return nil
+}
+
+// FindPayoutBatchByID retrieves a payout batch by its ID.
+func (r *payoutRepository) FindPayoutBatchByID(ctx context.Context, id primitive.ObjectID) (*domain.PayoutBatch, error) {
+	var batch domain.PayoutBatch
+	found, err := r.batches.get(id, &batch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find payout batch: %w", err)
+	}
+	if !found {
+		return nil, apperror.NotFound{Resource: "Payout batch"}
+	}
+	return &batch, nil
+}
+
+// FindPayoutBatches retrieves every payout batch, newest first.
+func (r *payoutRepository) FindPayoutBatches(ctx context.Context) ([]*domain.PayoutBatch, error) {
+	var (
+		batches   []*domain.PayoutBatch
+		decodeErr error
+	)
+	r.batches.each(func(raw bson.Raw) bool {
+		var batch domain.PayoutBatch
+		if decodeErr = bson.Unmarshal(raw, &batch); decodeErr != nil {
+			return false
+		}
+		batches = append(batches, &batch)
+		return true
+	})
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode payout batches: %w", decodeErr)
+	}
+	sort.SliceStable(batches, func(a, b int) bool { return batches[a].CreatedAt.After(batches[b].CreatedAt) })
+	return batches, nil
+}
+
+// filter returns every payout request matching keep, in insertion order.
+func (r *payoutRepository) filter(keep func(p *domain.PayoutRequest) bool) ([]*domain.PayoutRequest, error) {
+	var (
+		requests  []*domain.PayoutRequest
+		decodeErr error
+	)
+	r.requests.each(func(raw bson.Raw) bool {
+		var request domain.PayoutRequest
+		if decodeErr = bson.Unmarshal(raw, &request); decodeErr != nil {
+			return false
+		}
+		if keep(&request) {
+			requests = append(requests, &request)
+		}
+		return true
+	})
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode payout requests: %w", decodeErr)
+	}
+	return requests, nil
+}
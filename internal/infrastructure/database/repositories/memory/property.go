@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type propertyRepository struct {
+	properties *collection
+}
+
+// NewPropertyRepository creates an in-memory PropertyRepository.
+func NewPropertyRepository() repositories.PropertyRepository {
+	return &propertyRepository{properties: newCollection()}
+}
+
+// CreateProperty stores a new property.
+func (r *propertyRepository) CreateProperty(ctx context.Context, property *domain.Property) error {
+	inserted, err := r.properties.insert(property.ID, property)
+	if err != nil {
+		return fmt.Errorf("failed to insert property: %w", err)
+	}
+	if !inserted {
+		return fmt.Errorf("failed to insert property: duplicate id %s", property.ID.Hex())
+	}
+	return nil
+}
+
+// FindPropertyByID retrieves a property by its ID.
+func (r *propertyRepository) FindPropertyByID(ctx context.Context, id primitive.ObjectID) (*domain.Property, error) {
+	var property domain.Property
+	found, err := r.properties.get(id, &property)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find property: %w", err)
+	}
+	if !found {
+		return nil, apperror.NotFound{Resource: "Property"}
+	}
+	return &property, nil
+}
+
+// FindPropertiesByCustomerID retrieves a customer's current properties, oldest first.
+func (r *propertyRepository) FindPropertiesByCustomerID(ctx context.Context, customerID primitive.ObjectID) ([]*domain.Property, error) {
+	var (
+		properties []*domain.Property
+		decodeErr  error
+	)
+	r.properties.each(func(raw bson.Raw) bool {
+		var property domain.Property
+		if decodeErr = bson.Unmarshal(raw, &property); decodeErr != nil {
+			return false
+		}
+		if property.CustomerID == customerID && !property.Archived {
+			properties = append(properties, &property)
+		}
+		return true
+	})
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode properties: %w", decodeErr)
+	}
+	sort.SliceStable(properties, func(a, b int) bool { return properties[a].CreatedAt.Before(properties[b].CreatedAt) })
+	return properties, nil
+}
+
+// UpdateProperty applies a BSON update document to a property.
+func (r *propertyRepository) UpdateProperty(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	if _, err := r.properties.update(id, update); err != nil {
+		return fmt.Errorf("failed to update property: %w", err)
+	}
+	return nil
+}
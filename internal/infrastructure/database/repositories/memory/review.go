@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type reviewRepository struct {
+	reviews *collection
+
+	// mu makes the booking check in CreateReview atomic with the insert, like
+	// the unique index on bookingId.
+	mu sync.Mutex
+}
+
+// NewReviewRepository creates an in-memory ReviewRepository.
+func NewReviewRepository() repositories.ReviewRepository {
+	return &reviewRepository{reviews: newCollection()}
+}
+
+// CreateReview stores a new review, rejecting a second review of the same
+// booking.
+func (r *reviewRepository) CreateReview(ctx context.Context, review *domain.Review) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.filter(func(v *domain.Review) bool { return v.BookingID == review.BookingID })
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return apperror.DuplicateError{Resource: "Review for this booking"}
+	}
+
+	inserted, err := r.reviews.insert(review.ID, review)
+	if err != nil {
+		return fmt.Errorf("failed to insert review: %w", err)
+	}
+	if !inserted {
+		return apperror.DuplicateError{Resource: "Review"}
+	}
+	return nil
+}
+
+// FindReviewByBookingID retrieves the review of a booking.
+func (r *reviewRepository) FindReviewByBookingID(ctx context.Context, bookingID primitive.ObjectID) (*domain.Review, error) {
+	reviews, err := r.filter(func(v *domain.Review) bool { return v.BookingID == bookingID })
+	if err != nil {
+		return nil, err
+	}
+	if len(reviews) == 0 {
+		return nil, apperror.NotFound{Resource: "Review"}
+	}
+	return reviews[0], nil
+}
+
+// FindReviewsByMowerID retrieves a mower's most recent reviews, newest first.
+func (r *reviewRepository) FindReviewsByMowerID(ctx context.Context, mowerID primitive.ObjectID, limit int) ([]*domain.Review, error) {
+	reviews, err := r.filter(func(v *domain.Review) bool { return v.MowerID == mowerID })
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(reviews, func(i, j int) bool { return reviews[i].CreatedAt.After(reviews[j].CreatedAt) })
+	if len(reviews) > limit {
+		reviews = reviews[:limit]
+	}
+	return reviews, nil
+}
+
+// UpdateReview applies a BSON update document to a review.
+func (r *reviewRepository) UpdateReview(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	if _, err := r.reviews.update(id, update); err != nil {
+		return fmt.Errorf("failed to update review: %w", err)
+	}
+	return nil
+}
+
+// SetReviewReply sets the reply only while the review has none.
+func (r *reviewRepository) SetReviewReply(ctx context.Context, id primitive.ObjectID, reply *domain.ReviewReply) error {
+	matched, err := r.reviews.updateWhere(id, func(raw bson.Raw) (bool, error) {
+		var current domain.Review
+		if err := bson.Unmarshal(raw, &current); err != nil {
+			return false, err
+		}
+		return current.Reply == nil, nil
+	}, bson.M{"$set": bson.M{"reply": reply}})
+	if err != nil {
+		return fmt.Errorf("failed to set review reply: %w", err)
+	}
+	if !matched {
+		return apperror.DuplicateError{Resource: "Reply to this review"}
+	}
+	return nil
+}
+
+// filter returns every review matching keep, in insertion order.
+func (r *reviewRepository) filter(keep func(v *domain.Review) bool) ([]*domain.Review, error) {
+	var (
+		reviews   = []*domain.Review{}
+		decodeErr error
+	)
+	r.reviews.each(func(raw bson.Raw) bool {
+		var review domain.Review
+		if decodeErr = bson.Unmarshal(raw, &review); decodeErr != nil {
+			return false
+		}
+		if keep(&review) {
+			reviews = append(reviews, &review)
+		}
+		return true
+	})
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode reviews: %w", decodeErr)
+	}
+	return reviews, nil
+}
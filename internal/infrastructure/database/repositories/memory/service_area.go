@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type serviceAreaRepository struct {
+	areas *collection
+}
+
+// NewServiceAreaRepository creates an in-memory ServiceAreaRepository.
+func NewServiceAreaRepository() repositories.ServiceAreaRepository {
+	return &serviceAreaRepository{areas: newCollection()}
+}
+
+// CreateArea stores a new service area.
+func (r *serviceAreaRepository) CreateArea(ctx context.Context, area *domain.ServiceArea) error {
+	inserted, err := r.areas.insert(area.ID, area)
+	if err != nil {
+		return fmt.Errorf("failed to insert service area: %w", err)
+	}
+	if !inserted {
+		return fmt.Errorf("failed to insert service area: duplicate id %s", area.ID.Hex())
+	}
+	return nil
+}
+
+// FindAreaByID retrieves a service area by its ID.
+func (r *serviceAreaRepository) FindAreaByID(ctx context.Context, id primitive.ObjectID) (*domain.ServiceArea, error) {
+	var area domain.ServiceArea
+	found, err := r.areas.get(id, &area)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find service area: %w", err)
+	}
+	if !found {
+		return nil, apperror.NotFound{Resource: "Service area"}
+	}
+	return &area, nil
+}
+
+// FindAreaContaining returns the oldest active area whose boundary contains point.
+func (r *serviceAreaRepository) FindAreaContaining(ctx context.Context, point domain.GeoPoint) (*domain.ServiceArea, error) {
+	areas, err := r.ListAreas(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, area := range areas {
+		if area.Boundary.Contains(point) {
+			return area, nil
+		}
+	}
+	return nil, apperror.NotFound{Resource: "Service area"}
+}
+
+// ListAreas returns service areas, oldest first.
+func (r *serviceAreaRepository) ListAreas(ctx context.Context, activeOnly bool) ([]*domain.ServiceArea, error) {
+	var (
+		areas     []*domain.ServiceArea
+		decodeErr error
+	)
+	r.areas.each(func(raw bson.Raw) bool {
+		var area domain.ServiceArea
+		if decodeErr = bson.Unmarshal(raw, &area); decodeErr != nil {
+			return false
+		}
+		if !activeOnly || area.Active {
+			areas = append(areas, &area)
+		}
+		return true
+	})
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode service areas: %w", decodeErr)
+	}
+	sort.SliceStable(areas, func(a, b int) bool { return areas[a].CreatedAt.Before(areas[b].CreatedAt) })
+	return areas, nil
+}
+
+// UpdateArea applies a BSON update document to a service area.
+func (r *serviceAreaRepository) UpdateArea(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	if _, err := r.areas.update(id, update); err != nil {
+		return fmt.Errorf("failed to update service area: %w", err)
+	}
+	return nil
+}
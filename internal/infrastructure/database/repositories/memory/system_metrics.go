@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+)
+
+type systemMetricsRepository struct {
+	mu     sync.Mutex
+	values map[string]int64
+}
+
+// NewSystemMetricsRepository creates an in-memory SystemMetricsRepository.
+func NewSystemMetricsRepository() repositories.SystemMetricsRepository {
+	return &systemMetricsRepository{values: make(map[string]int64)}
+}
+
+// IncrementMetric adds delta to the named metric.
+func (r *systemMetricsRepository) IncrementMetric(ctx context.Context, name string, delta int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values[name] += delta
+	return nil
+}
+
+// FindMetric returns the named metric, zero if never written.
+func (r *systemMetricsRepository) FindMetric(ctx context.Context, name string) (*domain.SystemMetrics, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &domain.SystemMetrics{Name: name, Value: r.values[name]}, nil
+}
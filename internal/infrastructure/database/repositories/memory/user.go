@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type userRepository struct {
+	users *collection
+}
+
+// NewUserRepository creates an in-memory UserRepository with the same semantics
+// as the MongoDB implementation, including the unique email constraint.
+func NewUserRepository() repositories.UserRepository {
+	return &userRepository{users: newCollection()}
+}
+
+// CreateUser saves a new user, rejecting duplicate IDs and normalized emails.
+func (r *userRepository) CreateUser(ctx context.Context, user *domain.User) error {
+	user.Email = domain.NormalizeEmail(user.Email)
+
+	// The email check and the insert must be atomic to mirror the unique index.
+	r.users.mu.Lock()
+	defer r.users.mu.Unlock()
+
+	for _, raw := range r.users.docs {
+		if email, ok := raw.Lookup("email").StringValueOK(); ok && email == user.Email {
+			return apperror.DuplicateError{Resource: "User with this email"}
+		}
+	}
+	if _, exists := r.users.docs[user.ID]; exists {
+		return apperror.DuplicateError{Resource: "User"}
+	}
+
+	raw, err := bson.Marshal(user)
+	if err != nil {
+		return fmt.Errorf("failed to encode user: %w", err)
+	}
+	r.users.docs[user.ID] = raw
+	r.users.order = append(r.users.order, user.ID)
+	return nil
+}
+
+// FindUserByEmail retrieves a user by their email address.
+func (r *userRepository) FindUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return r.findOne("email", domain.NormalizeEmail(email))
+}
+
+// FindUserByID retrieves a user by their ID.
+func (r *userRepository) FindUserByID(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+	var user domain.User
+	found, err := r.users.get(id, &user)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, apperror.NotFound{Resource: "User"}
+	}
+	return &user, nil
+}
+
+// UpdateUser applies a BSON update document to a user, rejecting an email
+// another user already has.
+func (r *userRepository) UpdateUser(ctx context.Context, id primitive.ObjectID, update primitive.M) error {
+	_, err := r.users.updateChecked(id, update, func(doc bson.M) error {
+		email, ok := doc["email"].(string)
+		if !ok {
+			return nil
+		}
+		for otherID, raw := range r.users.docs {
+			if other, ok := raw.Lookup("email").StringValueOK(); ok && other == email && otherID != id {
+				return apperror.DuplicateError{Resource: "User with this email"}
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// RecordRating counts rating into the user's rating summary. The read and
+// write happen under the collection lock, as the MongoDB update pipeline is a
+// single atomic update.
+func (r *userRepository) RecordRating(ctx context.Context, id primitive.ObjectID, rating int) error {
+	r.users.mu.Lock()
+	defer r.users.mu.Unlock()
+
+	raw, ok := r.users.docs[id]
+	if !ok {
+		return nil
+	}
+	var user domain.User
+	if err := bson.Unmarshal(raw, &user); err != nil {
+		return fmt.Errorf("failed to decode user: %w", err)
+	}
+	summary := user.RatingSummary
+	if summary == nil {
+		summary = &domain.RatingSummary{}
+	}
+	summary.Add(rating)
+
+	doc, err := decodeM(raw)
+	if err != nil {
+		return err
+	}
+	if err := applyUpdate(doc, bson.M{"$set": bson.M{"ratingSummary": summary}}); err != nil {
+		return err
+	}
+	encoded, err := bson.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode user: %w", err)
+	}
+	r.users.docs[id] = encoded
+	return nil
+}
+
+// findOne returns the first user whose string field equals value.
+func (r *userRepository) findOne(field, value string) (*domain.User, error) {
+	var (
+		user    domain.User
+		found   bool
+		findErr error
+	)
+	r.users.each(func(raw bson.Raw) bool {
+		if v, ok := raw.Lookup(field).StringValueOK(); !ok || v != value {
+			return true
+		}
+		found = true
+		findErr = bson.Unmarshal(raw, &user)
+		return false
+	})
+	if findErr != nil {
+		return nil, findErr
+	}
+	if !found {
+		return nil, apperror.NotFound{Resource: "User"}
+	}
+	return &user, nil
+}
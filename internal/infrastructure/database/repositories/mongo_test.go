@@ -0,0 +1,140 @@
+package repositories_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"lawnconnect-api/internal/infrastructure/database"
+	"lawnconnect-api/internal/infrastructure/database/migrations"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+	"lawnconnect-api/internal/infrastructure/database/repositories/repositorytest"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// The conformance suite runs against MongoDB when MONGO_TEST_URI points at a
+// server, for example mongodb://localhost:27017. Every sub-test gets its own
+// database with all migrations applied, dropped when the sub-test ends.
+var mongoClient *mongo.Client
+
+func TestMain(m *testing.M) {
+	if uri := os.Getenv("MONGO_TEST_URI"); uri != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		client, err := database.NewMongoClient(ctx, uri)
+		cancel()
+		if err != nil {
+			panic("connecting to MONGO_TEST_URI: " + err.Error())
+		}
+		mongoClient = client
+	}
+
+	code := m.Run()
+	if mongoClient != nil {
+		mongoClient.Disconnect(context.Background())
+	}
+	os.Exit(code)
+}
+
+// testDatabase returns an empty, migrated database, skipping the test when no
+// MongoDB server is configured.
+func testDatabase(t *testing.T) *mongo.Database {
+	t.Helper()
+	if mongoClient == nil {
+		t.Skip("MONGO_TEST_URI is not set")
+	}
+
+	ctx := context.Background()
+	db := mongoClient.Database("lawnconnect_test_" + primitive.NewObjectID().Hex())
+	t.Cleanup(func() { db.Drop(context.Background()) })
+	if _, err := migrations.NewRunner(db, migrations.All("USD")).Up(ctx); err != nil {
+		t.Fatalf("migrating test database: %v", err)
+	}
+	return db
+}
+
+func TestUserRepository(t *testing.T) {
+	repositorytest.TestUserRepository(t, func(t *testing.T) repositories.UserRepository {
+		return repositories.NewUserRepository(testDatabase(t))
+	})
+}
+
+func TestPasswordResetTokenRepository(t *testing.T) {
+	repositorytest.TestPasswordResetTokenRepository(t, func(t *testing.T) repositories.PasswordResetTokenRepository {
+		return repositories.NewPasswordResetTokenRepository(testDatabase(t))
+	})
+}
+
+func TestBookingRepository(t *testing.T) {
+	repositorytest.TestBookingRepository(t, func(t *testing.T) repositories.BookingRepository {
+		return repositories.NewBookingRepository(testDatabase(t))
+	})
+}
+
+func TestLedgerRepository(t *testing.T) {
+	repositorytest.TestLedgerRepository(t, func(t *testing.T) repositories.LedgerRepository {
+		return repositories.NewLedgerRepository(testDatabase(t))
+	})
+}
+
+func TestCommissionRuleRepository(t *testing.T) {
+	repositorytest.TestCommissionRuleRepository(t, func(t *testing.T) repositories.CommissionRuleRepository {
+		return repositories.NewCommissionRuleRepository(testDatabase(t))
+	})
+}
+
+func TestSystemMetricsRepository(t *testing.T) {
+	repositorytest.TestSystemMetricsRepository(t, func(t *testing.T) repositories.SystemMetricsRepository {
+		return repositories.NewSystemMetricsRepository(testDatabase(t))
+	})
+}
+
+func TestInvoiceRepository(t *testing.T) {
+	repositorytest.TestInvoiceRepository(t, func(t *testing.T) repositories.InvoiceRepository {
+		return repositories.NewInvoiceRepository(testDatabase(t))
+	})
+}
+
+func TestPaymentReminderRepository(t *testing.T) {
+	repositorytest.TestPaymentReminderRepository(t, func(t *testing.T) repositories.PaymentReminderRepository {
+		return repositories.NewPaymentReminderRepository(testDatabase(t))
+	})
+}
+
+func TestJobLockRepository(t *testing.T) {
+	repositorytest.TestJobLockRepository(t, func(t *testing.T) repositories.JobLockRepository {
+		return repositories.NewJobLockRepository(testDatabase(t))
+	})
+}
+
+func TestPaymentEventRepository(t *testing.T) {
+	repositorytest.TestPaymentEventRepository(t, func(t *testing.T) repositories.PaymentEventRepository {
+		return repositories.NewPaymentEventRepository(testDatabase(t))
+	})
+}
+
+func TestPayoutRepository(t *testing.T) {
+	repositorytest.TestPayoutRepository(t, func(t *testing.T) repositories.PayoutRepository {
+		return repositories.NewPayoutRepository(testDatabase(t))
+	})
+}
+
+func TestPropertyRepository(t *testing.T) {
+	repositorytest.TestPropertyRepository(t, func(t *testing.T) repositories.PropertyRepository {
+		return repositories.NewPropertyRepository(testDatabase(t))
+	})
+}
+
+func TestServiceAreaRepository(t *testing.T) {
+	repositorytest.TestServiceAreaRepository(t, func(t *testing.T) repositories.ServiceAreaRepository {
+		return repositories.NewServiceAreaRepository(testDatabase(t))
+	})
+}
+
+func TestReviewRepository(t *testing.T) {
+	repositorytest.TestReviewRepository(t, func(t *testing.T) repositories.ReviewRepository {
+		return repositories.NewReviewRepository(testDatabase(t))
+	})
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// PasswordResetTokenRepository stores the tokens sent by password reset emails.
+type PasswordResetTokenRepository interface {
+	CreateResetToken(ctx context.Context, token *domain.PasswordResetToken) error
+	// FindResetToken returns the token, or apperror.NotFound. Expired tokens
+	// may still be returned until the TTL index removes them.
+	FindResetToken(ctx context.Context, token string) (*domain.PasswordResetToken, error)
+	// DeleteUserResetTokens removes every reset token of the user.
+	DeleteUserResetTokens(ctx context.Context, userID primitive.ObjectID) error
+}
+
+type passwordResetTokenRepository struct {
+	collection *mongo.Collection
+}
+
+// NewPasswordResetTokenRepository creates a new PasswordResetTokenRepository.
+func NewPasswordResetTokenRepository(db *mongo.Database) PasswordResetTokenRepository {
+	return &passwordResetTokenRepository{collection: db.Collection("password_reset_tokens")}
+}
+
+// CreateResetToken inserts the token.
+func (r *passwordResetTokenRepository) CreateResetToken(ctx context.Context, token *domain.PasswordResetToken) error {
+	if _, err := r.collection.InsertOne(ctx, token); err != nil {
+		return fmt.Errorf("failed to insert password reset token: %w", err)
+	}
+	return nil
+}
+
+// FindResetToken retrieves a token by its value.
+func (r *passwordResetTokenRepository) FindResetToken(ctx context.Context, token string) (*domain.PasswordResetToken, error) {
+	var found domain.PasswordResetToken
+	err := r.collection.FindOne(ctx, bson.M{"_id": token}).Decode(&found)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, apperror.NotFound{Resource: "Password reset token"}
+		}
+		return nil, fmt.Errorf("failed to find password reset token: %w", err)
+	}
+	return &found, nil
+}
+
+// DeleteUserResetTokens deletes the user's tokens.
+func (r *passwordResetTokenRepository) DeleteUserResetTokens(ctx context.Context, userID primitive.ObjectID) error {
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"userId": userID}); err != nil {
+		return fmt.Errorf("failed to delete password reset tokens: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// PaymentEventRepository remembers which payment webhooks have been processed.
+type PaymentEventRepository interface {
+	// RecordEvent stores an event. An event that was already recorded is rejected
+	// with apperror.DuplicateError.
+	RecordEvent(ctx context.Context, event *domain.PaymentEvent) error
+}
+
+type paymentEventRepository struct {
+	collection *mongo.Collection
+}
+
+// NewPaymentEventRepository creates a new PaymentEventRepository.
+func NewPaymentEventRepository(db *mongo.Database) PaymentEventRepository {
+	return &paymentEventRepository{collection: db.Collection("payment_events")}
+}
+
+// RecordEvent inserts the event keyed by its ID.
+func (r *paymentEventRepository) RecordEvent(ctx context.Context, event *domain.PaymentEvent) error {
+	if _, err := r.collection.InsertOne(ctx, event); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return apperror.DuplicateError{Resource: "Payment event"}
+		}
+		return fmt.Errorf("failed to insert payment event: %w", err)
+	}
+	return nil
+}
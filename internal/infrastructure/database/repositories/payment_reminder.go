@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"lawnconnect-api/internal/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PaymentReminderRepository records payment reminder attempts.
+type PaymentReminderRepository interface {
+	CreateReminder(ctx context.Context, reminder *domain.PaymentReminder) error
+	// FindRemindersByInvoiceID returns an invoice's reminder attempts, oldest first.
+	FindRemindersByInvoiceID(ctx context.Context, invoiceID primitive.ObjectID) ([]*domain.PaymentReminder, error)
+}
+
+type paymentReminderRepository struct {
+	collection *mongo.Collection
+}
+
+// NewPaymentReminderRepository creates a new PaymentReminderRepository.
+func NewPaymentReminderRepository(db *mongo.Database) PaymentReminderRepository {
+	return &paymentReminderRepository{collection: db.Collection("payment_reminders")}
+}
+
+// CreateReminder inserts a reminder attempt.
+func (r *paymentReminderRepository) CreateReminder(ctx context.Context, reminder *domain.PaymentReminder) error {
+	if _, err := r.collection.InsertOne(ctx, reminder); err != nil {
+		return fmt.Errorf("failed to insert payment reminder: %w", err)
+	}
+	return nil
+}
+
+// FindRemindersByInvoiceID retrieves an invoice's reminder attempts, oldest first.
+func (r *paymentReminderRepository) FindRemindersByInvoiceID(ctx context.Context, invoiceID primitive.ObjectID) ([]*domain.PaymentReminder, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"invoiceId": invoiceID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find payment reminders: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var reminders []*domain.PaymentReminder
+	if err := cursor.All(ctx, &reminders); err != nil {
+		return nil, fmt.Errorf("failed to decode payment reminders: %w", err)
+	}
+	return reminders, nil
+}
@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PayoutRepository defines the repository interface for payout requests and
+// the batches they are paid in.
+type PayoutRepository interface {
+	// CreatePayoutRequest stores a payout request. A second open request for the
+	// same mower is rejected with apperror.DuplicateError.
+	CreatePayoutRequest(ctx context.Context, request *domain.PayoutRequest) error
+	FindPayoutRequestByID(ctx context.Context, id primitive.ObjectID) (*domain.PayoutRequest, error)
+	// FindOpenPayoutRequest returns the mower's requested or approved payout, or
+	// apperror.NotFound if there is none.
+	FindOpenPayoutRequest(ctx context.Context, mowerID primitive.ObjectID) (*domain.PayoutRequest, error)
+	// FindPayoutRequestsByMowerID returns a mower's payout requests, newest first.
+	FindPayoutRequestsByMowerID(ctx context.Context, mowerID primitive.ObjectID) ([]*domain.PayoutRequest, error)
+	// FindPayoutRequestsByStatus returns the payout requests in status, oldest first.
+	FindPayoutRequestsByStatus(ctx context.Context, status string) ([]*domain.PayoutRequest, error)
+	// FindPayoutRequestsByBatchID returns the requests paid in a batch, oldest first.
+	FindPayoutRequestsByBatchID(ctx context.Context, batchID primitive.ObjectID) ([]*domain.PayoutRequest, error)
+	UpdatePayoutRequest(ctx context.Context, id primitive.ObjectID, update bson.M) error
+	CreatePayoutBatch(ctx context.Context, batch *domain.PayoutBatch) error
+	FindPayoutBatchByID(ctx context.Context, id primitive.ObjectID) (*domain.PayoutBatch, error)
+	// FindPayoutBatches returns every payout batch, newest first.
+	FindPayoutBatches(ctx context.Context) ([]*domain.PayoutBatch, error)
+}
+
+type payoutRepository struct {
+	requests *mongo.Collection
+	batches  *mongo.Collection
+}
+
+// NewPayoutRepository creates a new PayoutRepository.
+func NewPayoutRepository(db *mongo.Database) PayoutRepository {
+	return &payoutRepository{
+		requests: db.Collection("payout_requests"),
+		batches:  db.Collection("payout_batches"),
+	}
+}
+
+// CreatePayoutRequest inserts a new payout request. The partial unique index on
+// mowerId for open requests enforces one open request per mower.
+func (r *payoutRepository) CreatePayoutRequest(ctx context.Context, request *domain.PayoutRequest) error {
+	if _, err := r.requests.InsertOne(ctx, request); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return apperror.DuplicateError{Resource: "Payout request"}
+		}
+		return fmt.Errorf("failed to insert payout request: %w", err)
+	}
+	return nil
+}
+
+// FindPayoutRequestByID retrieves a payout request by its ID.
+func (r *payoutRepository) FindPayoutRequestByID(ctx context.Context, id primitive.ObjectID) (*domain.PayoutRequest, error) {
+	return r.findRequest(ctx, bson.M{"_id": id})
+}
+
+// FindOpenPayoutRequest retrieves the mower's open payout request.
+func (r *payoutRepository) FindOpenPayoutRequest(ctx context.Context, mowerID primitive.ObjectID) (*domain.PayoutRequest, error) {
+	return r.findRequest(ctx, bson.M{"mowerId": mowerID, "open": true})
+}
+
+func (r *payoutRepository) findRequest(ctx context.Context, filter bson.M) (*domain.PayoutRequest, error) {
+	var request domain.PayoutRequest
+	err := r.requests.FindOne(ctx, filter).Decode(&request)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, apperror.NotFound{Resource: "Payout request"}
+		}
+		return nil, fmt.Errorf("failed to find payout request: %w", err)
+	}
+	return &request, nil
+}
+
+// FindPayoutRequestsByMowerID retrieves a mower's payout requests, newest first.
+func (r *payoutRepository) FindPayoutRequestsByMowerID(ctx context.Context, mowerID primitive.ObjectID) ([]*domain.PayoutRequest, error) {
+	return r.findRequests(ctx, bson.M{"mowerId": mowerID}, -1)
+}
+
+// FindPayoutRequestsByStatus retrieves the payout requests in status, oldest first.
+func (r *payoutRepository) FindPayoutRequestsByStatus(ctx context.Context, status string) ([]*domain.PayoutRequest, error) {
+	return r.findRequests(ctx, bson.M{"status": status}, 1)
+}
+
+// FindPayoutRequestsByBatchID retrieves the requests paid in a batch, oldest first.
+func (r *payoutRepository) FindPayoutRequestsByBatchID(ctx context.Context, batchID primitive.ObjectID) ([]*domain.PayoutRequest, error) {
+	return r.findRequests(ctx, bson.M{"batchId": batchID}, 1)
+}
+
+func (r *payoutRepository) findRequests(ctx context.Context, filter bson.M, order int) ([]*domain.PayoutRequest, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: order}, {Key: "_id", Value: order}})
+	cursor, err := r.requests.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find payout requests: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var requests []*domain.PayoutRequest
+	if err := cursor.All(ctx, &requests); err != nil {
+		return nil, fmt.Errorf("failed to decode payout requests: %w", err)
+	}
+	return requests, nil
+}
+
+// UpdatePayoutRequest updates a payout request by its ID.
+func (r *payoutRepository) UpdatePayoutRequest(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	if _, err := r.requests.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		return fmt.Errorf("failed to update payout request: %w", err)
+	}
+	return nil
+}
+
+// CreatePayoutBatch inserts a new payout batch.
+func (r *payoutRepository) CreatePayoutBatch(ctx context.Context, batch *domain.PayoutBatch) error {
+	if _, err := r.batches.InsertOne(ctx, batch); err != nil {
+		return fmt.Errorf("failed to insert payout batch: %w", err)
+	}
+	return nil
+}
+
+// FindPayoutBatchByID retrieves a payout batch by its ID.
+func (r *payoutRepository) FindPayoutBatchByID(ctx context.Context, id primitive.ObjectID) (*domain.PayoutBatch, error) {
+	var batch domain.PayoutBatch
+	err := r.batches.FindOne(ctx, bson.M{"_id": id}).Decode(&batch)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, apperror.NotFound{Resource: "Payout batch"}
+		}
+		return nil, fmt.Errorf("failed to find payout batch: %w", err)
+	}
+	return &batch, nil
+}
+
+// FindPayoutBatches retrieves every payout batch, newest first.
+func (r *payoutRepository) FindPayoutBatches(ctx context.Context) ([]*domain.PayoutBatch, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := r.batches.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find payout batches: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var batches []*domain.PayoutBatch
+	if err := cursor.All(ctx, &batches); err != nil {
+		return nil, fmt.Errorf("failed to decode payout batches: %w", err)
+	}
+	return batches, nil
+}
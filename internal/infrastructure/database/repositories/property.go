@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PropertyRepository defines the repository interface for customers' properties.
+type PropertyRepository interface {
+	CreateProperty(ctx context.Context, property *domain.Property) error
+	// FindPropertyByID returns a property, archived or not.
+	FindPropertyByID(ctx context.Context, id primitive.ObjectID) (*domain.Property, error)
+	// FindPropertiesByCustomerID returns a customer's properties that are not
+	// archived, oldest first.
+	FindPropertiesByCustomerID(ctx context.Context, customerID primitive.ObjectID) ([]*domain.Property, error)
+	UpdateProperty(ctx context.Context, id primitive.ObjectID, update bson.M) error
+}
+
+type propertyRepository struct {
+	collection *mongo.Collection
+}
+
+// NewPropertyRepository creates a new PropertyRepository.
+func NewPropertyRepository(db *mongo.Database) PropertyRepository {
+	return &propertyRepository{collection: db.Collection("properties")}
+}
+
+// CreateProperty inserts a new property.
+func (r *propertyRepository) CreateProperty(ctx context.Context, property *domain.Property) error {
+	if _, err := r.collection.InsertOne(ctx, property); err != nil {
+		return fmt.Errorf("failed to insert property: %w", err)
+	}
+	return nil
+}
+
+// FindPropertyByID retrieves a property by its ID.
+func (r *propertyRepository) FindPropertyByID(ctx context.Context, id primitive.ObjectID) (*domain.Property, error) {
+	var property domain.Property
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&property)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, apperror.NotFound{Resource: "Property"}
+		}
+		return nil, fmt.Errorf("failed to find property: %w", err)
+	}
+	return &property, nil
+}
+
+// FindPropertiesByCustomerID retrieves a customer's current properties, oldest first.
+func (r *propertyRepository) FindPropertiesByCustomerID(ctx context.Context, customerID primitive.ObjectID) ([]*domain.Property, error) {
+	filter := bson.M{"customerId": customerID, "archived": bson.M{"$ne": true}}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find properties: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var properties []*domain.Property
+	if err := cursor.All(ctx, &properties); err != nil {
+		return nil, fmt.Errorf("failed to decode properties: %w", err)
+	}
+	return properties, nil
+}
+
+// UpdateProperty updates a property by its ID.
+func (r *propertyRepository) UpdateProperty(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		return fmt.Errorf("failed to update property: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,1490 @@
+// Package repositorytest provides a conformance suite that every implementation of
+// the repository interfaces must pass, so the in-memory repositories used in unit
+// tests behave like the MongoDB ones used in production.
+//
+// Implementations run the suite from their own tests; memory/memory_test.go runs
+// it against the in-memory repositories and mongo_test.go against MongoDB when
+// MONGO_TEST_URI is set:
+//
+//	func TestUserRepository(t *testing.T) {
+//		repositorytest.TestUserRepository(t, func(t *testing.T) repositories.UserRepository {
+//			return memory.NewUserRepository()
+//		})
+//	}
+//
+// The factory is called once per sub-test and must return an empty repository.
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TestUserRepository runs the UserRepository conformance suite.
+func TestUserRepository(t *testing.T, newRepo func(t *testing.T) repositories.UserRepository) {
+	ctx := context.Background()
+
+	t.Run("CreateAndFindByID", func(t *testing.T) {
+		repo := newRepo(t)
+		user := NewUser("customer")
+		if err := repo.CreateUser(ctx, user); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+
+		got, err := repo.FindUserByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("FindUserByID: %v", err)
+		}
+		if got.Email != user.Email || got.Name != user.Name || got.Role != user.Role {
+			t.Errorf("FindUserByID returned %+v, want %+v", got, user)
+		}
+	})
+
+	t.Run("FindByEmailIsCaseInsensitive", func(t *testing.T) {
+		repo := newRepo(t)
+		user := NewUser("mower")
+		user.Email = "  Mixed.Case@Example.COM "
+		if err := repo.CreateUser(ctx, user); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+
+		got, err := repo.FindUserByEmail(ctx, "mixed.case@example.com")
+		if err != nil {
+			t.Fatalf("FindUserByEmail: %v", err)
+		}
+		if got.ID != user.ID {
+			t.Errorf("FindUserByEmail returned user %s, want %s", got.ID.Hex(), user.ID.Hex())
+		}
+		if got.Email != "mixed.case@example.com" {
+			t.Errorf("stored email = %q, want normalized form", got.Email)
+		}
+	})
+
+	t.Run("DuplicateEmailIsRejected", func(t *testing.T) {
+		repo := newRepo(t)
+		first := NewUser("customer")
+		first.Email = "bob@example.com"
+		if err := repo.CreateUser(ctx, first); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+
+		second := NewUser("customer")
+		second.Email = "Bob@Example.com"
+		err := repo.CreateUser(ctx, second)
+		if _, ok := err.(apperror.DuplicateError); !ok {
+			t.Fatalf("CreateUser with duplicate email returned %v, want apperror.DuplicateError", err)
+		}
+	})
+
+	t.Run("MissingUserIsNotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.FindUserByID(ctx, primitive.NewObjectID()); !isNotFound(err) {
+			t.Errorf("FindUserByID returned %v, want apperror.NotFound", err)
+		}
+		if _, err := repo.FindUserByEmail(ctx, "nobody@example.com"); !isNotFound(err) {
+			t.Errorf("FindUserByEmail returned %v, want apperror.NotFound", err)
+		}
+	})
+
+	t.Run("UpdateSetsFields", func(t *testing.T) {
+		repo := newRepo(t)
+		user := NewUser("customer")
+		if err := repo.CreateUser(ctx, user); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+
+		err := repo.UpdateUser(ctx, user.ID, bson.M{"$set": bson.M{"name": "Renamed", "hourlyRate": 30.5}})
+		if err != nil {
+			t.Fatalf("UpdateUser: %v", err)
+		}
+
+		got, err := repo.FindUserByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("FindUserByID: %v", err)
+		}
+		if got.Name != "Renamed" || got.HourlyRate != 30.5 || got.Email != user.Email {
+			t.Errorf("FindUserByID returned %+v", got)
+		}
+	})
+
+	t.Run("UpdateRejectsDuplicateEmail", func(t *testing.T) {
+		repo := newRepo(t)
+		first, second := NewUser("customer"), NewUser("customer")
+		for _, user := range []*domain.User{first, second} {
+			if err := repo.CreateUser(ctx, user); err != nil {
+				t.Fatalf("CreateUser: %v", err)
+			}
+		}
+
+		err := repo.UpdateUser(ctx, second.ID, bson.M{"$set": bson.M{"email": first.Email}})
+		if _, ok := err.(apperror.DuplicateError); !ok {
+			t.Fatalf("UpdateUser to a taken email returned %v, want apperror.DuplicateError", err)
+		}
+		got, err := repo.FindUserByID(ctx, second.ID)
+		if err != nil {
+			t.Fatalf("FindUserByID: %v", err)
+		}
+		if got.Email != second.Email {
+			t.Errorf("rejected update changed the email to %q", got.Email)
+		}
+
+		if err := repo.UpdateUser(ctx, second.ID, bson.M{"$set": bson.M{"email": second.Email}}); err != nil {
+			t.Errorf("UpdateUser keeping the user's own email: %v", err)
+		}
+	})
+
+	t.Run("UpdateMissingUserIsNotAnError", func(t *testing.T) {
+		repo := newRepo(t)
+		err := repo.UpdateUser(ctx, primitive.NewObjectID(), bson.M{"$set": bson.M{"name": "x"}})
+		if err != nil {
+			t.Errorf("UpdateUser on missing user returned %v, want nil", err)
+		}
+	})
+
+	t.Run("RecordRatingAggregates", func(t *testing.T) {
+		repo := newRepo(t)
+		user := NewUser("mower")
+		if err := repo.CreateUser(ctx, user); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+
+		for _, rating := range []int{5, 4, 5} {
+			if err := repo.RecordRating(ctx, user.ID, rating); err != nil {
+				t.Fatalf("RecordRating(%d): %v", rating, err)
+			}
+		}
+		got, err := repo.FindUserByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("FindUserByID: %v", err)
+		}
+		summary := got.RatingSummary
+		if summary == nil || summary.Count != 3 || summary.Total != 14 || summary.Average != 4.67 {
+			t.Fatalf("RatingSummary = %+v, want 3 ratings totalling 14 averaging 4.67", summary)
+		}
+		if summary.Distribution["5"] != 2 || summary.Distribution["4"] != 1 || summary.Distribution["1"] != 0 {
+			t.Errorf("Distribution = %v, want two 5s and one 4", summary.Distribution)
+		}
+		if got.Name != user.Name {
+			t.Errorf("RecordRating changed the name to %q", got.Name)
+		}
+	})
+}
+
+// TestPasswordResetTokenRepository runs the PasswordResetTokenRepository conformance suite.
+func TestPasswordResetTokenRepository(t *testing.T, newRepo func(t *testing.T) repositories.PasswordResetTokenRepository) {
+	ctx := context.Background()
+
+	t.Run("TokensAreFoundUntilDeleted", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := primitive.NewObjectID()
+		expires := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+		for _, value := range []string{"token-1", "token-2"} {
+			token := &domain.PasswordResetToken{Token: value, UserID: userID, ExpiresAt: expires, CreatedAt: time.Now()}
+			if err := repo.CreateResetToken(ctx, token); err != nil {
+				t.Fatalf("CreateResetToken: %v", err)
+			}
+		}
+		other := &domain.PasswordResetToken{Token: "token-3", UserID: primitive.NewObjectID(), ExpiresAt: expires, CreatedAt: time.Now()}
+		if err := repo.CreateResetToken(ctx, other); err != nil {
+			t.Fatalf("CreateResetToken: %v", err)
+		}
+
+		got, err := repo.FindResetToken(ctx, "token-1")
+		if err != nil {
+			t.Fatalf("FindResetToken: %v", err)
+		}
+		if got.UserID != userID || !got.ExpiresAt.Equal(expires) {
+			t.Errorf("FindResetToken returned %+v", got)
+		}
+
+		if err := repo.DeleteUserResetTokens(ctx, userID); err != nil {
+			t.Fatalf("DeleteUserResetTokens: %v", err)
+		}
+		for _, value := range []string{"token-1", "token-2"} {
+			if _, err := repo.FindResetToken(ctx, value); !isNotFound(err) {
+				t.Errorf("FindResetToken(%s) after delete returned %v, want apperror.NotFound", value, err)
+			}
+		}
+		if _, err := repo.FindResetToken(ctx, "token-3"); err != nil {
+			t.Errorf("another user's token was deleted: %v", err)
+		}
+	})
+}
+
+// TestBookingRepository runs the BookingRepository conformance suite.
+func TestBookingRepository(t *testing.T, newRepo func(t *testing.T) repositories.BookingRepository) {
+	ctx := context.Background()
+
+	t.Run("CreateAndFindByID", func(t *testing.T) {
+		repo := newRepo(t)
+		booking := NewBooking(primitive.NewObjectID())
+		if err := repo.CreateBooking(ctx, booking); err != nil {
+			t.Fatalf("CreateBooking: %v", err)
+		}
+
+		got, err := repo.FindBookingByID(ctx, booking.ID)
+		if err != nil {
+			t.Fatalf("FindBookingByID: %v", err)
+		}
+		if got.CustomerID != booking.CustomerID || got.Address != booking.Address || got.Status != "pending" {
+			t.Errorf("FindBookingByID returned %+v, want %+v", got, booking)
+		}
+	})
+
+	t.Run("MissingBookingIsNotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.FindBookingByID(ctx, primitive.NewObjectID()); !isNotFound(err) {
+			t.Errorf("FindBookingByID returned %v, want apperror.NotFound", err)
+		}
+	})
+
+	t.Run("FindByUserMatchesCustomerAndMower", func(t *testing.T) {
+		repo := newRepo(t)
+		customer, mower, other := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+
+		mine := NewBooking(customer)
+		assigned := NewBooking(other)
+		assigned.MowerID = mower
+		unrelated := NewBooking(other)
+		for _, b := range []*domain.Booking{mine, assigned, unrelated} {
+			if err := repo.CreateBooking(ctx, b); err != nil {
+				t.Fatalf("CreateBooking: %v", err)
+			}
+		}
+
+		assertBookingIDs(t, "customer bookings", mustBookings(t)(repo.FindBookingsByUserID(ctx, customer)), mine.ID)
+		assertBookingIDs(t, "mower bookings", mustBookings(t)(repo.FindBookingsByUserID(ctx, mower)), assigned.ID)
+		assertBookingIDs(t, "other bookings", mustBookings(t)(repo.FindBookingsByUserID(ctx, other)), assigned.ID, unrelated.ID)
+	})
+
+	t.Run("UpdateAndPendingFilter", func(t *testing.T) {
+		repo := newRepo(t)
+		area := primitive.NewObjectID()
+		first := NewBooking(primitive.NewObjectID())
+		second := NewBooking(primitive.NewObjectID())
+		for _, b := range []*domain.Booking{first, second} {
+			b.ServiceAreaID = area
+			if err := repo.CreateBooking(ctx, b); err != nil {
+				t.Fatalf("CreateBooking: %v", err)
+			}
+		}
+
+		mower := primitive.NewObjectID()
+		err := repo.UpdateBooking(ctx, first.ID, bson.M{"$set": bson.M{
+			"status":    "accepted",
+			"mowerId":   mower,
+			"updatedAt": time.Now(),
+		}})
+		if err != nil {
+			t.Fatalf("UpdateBooking: %v", err)
+		}
+
+		got, err := repo.FindBookingByID(ctx, first.ID)
+		if err != nil {
+			t.Fatalf("FindBookingByID: %v", err)
+		}
+		if got.Status != "accepted" || got.MowerID != mower {
+			t.Errorf("updated booking = status %q mower %s", got.Status, got.MowerID.Hex())
+		}
+
+		assertBookingIDs(t, "pending bookings", mustBookings(t)(repo.FindPendingBookingsNear(ctx, *second.Location, 1, []primitive.ObjectID{area})), second.ID)
+	})
+
+	t.Run("PendingNear", func(t *testing.T) {
+		repo := newRepo(t)
+		origin := *domain.NewGeoPoint(40.7128, -74.006)
+		far := NewBooking(primitive.NewObjectID())
+		far.Location = domain.NewGeoPoint(40.85, -74.006) // About 15 km north
+		near := NewBooking(primitive.NewObjectID())
+		near.Location = domain.NewGeoPoint(40.75, -74.006) // About 4 km north
+		outside := NewBooking(primitive.NewObjectID())
+		outside.Location = domain.NewGeoPoint(41.2, -74.006) // About 54 km north
+		accepted := NewBooking(primitive.NewObjectID())
+		accepted.Status = "accepted"
+		unlocated := NewBooking(primitive.NewObjectID())
+		unlocated.Location = nil
+		area, otherArea := primitive.NewObjectID(), primitive.NewObjectID()
+		for _, b := range []*domain.Booking{far, near, outside, accepted, unlocated} {
+			b.ServiceAreaID = area
+		}
+		elsewhere := NewBooking(primitive.NewObjectID())
+		elsewhere.ServiceAreaID = otherArea
+		unassigned := NewBooking(primitive.NewObjectID())
+		for _, b := range []*domain.Booking{far, near, outside, accepted, unlocated, elsewhere, unassigned} {
+			if err := repo.CreateBooking(ctx, b); err != nil {
+				t.Fatalf("CreateBooking: %v", err)
+			}
+		}
+
+		found := mustBookings(t)(repo.FindPendingBookingsNear(ctx, origin, 20, []primitive.ObjectID{area}))
+		assertBookingIDs(t, "pending bookings within 20 km", found, near.ID, far.ID)
+		if len(found) == 2 && found[0].ID != near.ID {
+			t.Errorf("pending bookings within 20 km should be nearest first")
+		}
+		if len(found) == 2 && (found[0].DistanceKm < 3.5 || found[0].DistanceKm > 4.5 || found[1].DistanceKm < 15 || found[1].DistanceKm > 16) {
+			t.Errorf("distances = %.2f, %.2f km; want about 4.2 and 15.3", found[0].DistanceKm, found[1].DistanceKm)
+		}
+	})
+
+	t.Run("WaitlistedWithin", func(t *testing.T) {
+		repo := newRepo(t)
+		inside := NewBooking(primitive.NewObjectID())
+		inside.Status = "waitlisted"
+		older := NewBooking(primitive.NewObjectID())
+		older.Status = "waitlisted"
+		older.CreatedAt = older.CreatedAt.Add(-time.Hour)
+		outside := NewBooking(primitive.NewObjectID())
+		outside.Status = "waitlisted"
+		outside.Location = domain.NewGeoPoint(41.2, -74.006)
+		pending := NewBooking(primitive.NewObjectID())
+		for _, b := range []*domain.Booking{inside, older, outside, pending} {
+			if err := repo.CreateBooking(ctx, b); err != nil {
+				t.Fatalf("CreateBooking: %v", err)
+			}
+		}
+
+		found := mustBookings(t)(repo.FindWaitlistedBookingsWithin(ctx, NewServiceArea().Boundary))
+		assertBookingIDs(t, "waitlisted bookings inside the area", found, older.ID, inside.ID)
+		if len(found) == 2 && found[0].ID != older.ID {
+			t.Errorf("waitlisted bookings should be oldest first")
+		}
+	})
+
+	t.Run("ScheduledForMower", func(t *testing.T) {
+		repo := newRepo(t)
+		mowerID := primitive.NewObjectID()
+		schedule := func(status, date, clock string, mower primitive.ObjectID) *domain.Booking {
+			b := NewBooking(primitive.NewObjectID())
+			b.MowerID, b.Status, b.Date, b.Time = mower, status, date, clock
+			if err := repo.CreateBooking(ctx, b); err != nil {
+				t.Fatalf("CreateBooking: %v", err)
+			}
+			return b
+		}
+		late := schedule("accepted", "2030-06-01", "14:00", mowerID)
+		early := schedule("ongoing", "2030-06-01", "08:30", mowerID)
+		schedule("completed", "2030-06-01", "10:00", mowerID)
+		schedule("accepted", "2030-06-02", "10:00", mowerID)
+		schedule("accepted", "2030-06-01", "10:00", primitive.NewObjectID())
+
+		found := mustBookings(t)(repo.FindScheduledBookingsForMower(ctx, mowerID, "2030-06-01"))
+		assertBookingIDs(t, "scheduled bookings", found, early.ID, late.ID)
+		if len(found) == 2 && found[0].ID != early.ID {
+			t.Errorf("scheduled bookings should be earliest time first")
+		}
+	})
+
+	t.Run("CommentUpdates", func(t *testing.T) {
+		repo := newRepo(t)
+		booking := NewBooking(primitive.NewObjectID())
+		if err := repo.CreateBooking(ctx, booking); err != nil {
+			t.Fatalf("CreateBooking: %v", err)
+		}
+		comment := func(text string) domain.BookingComment {
+			return domain.BookingComment{ID: primitive.NewObjectID(), UserID: booking.CustomerID, UserName: "Customer",
+				CommentText: text, Timestamp: time.Now().Truncate(time.Millisecond)}
+		}
+		first, second, third := comment("first"), comment("second"), comment("third")
+		for _, c := range []domain.BookingComment{first, second, third} {
+			if err := repo.UpdateBooking(ctx, booking.ID, bson.M{"$push": bson.M{"comments": c}}); err != nil {
+				t.Fatalf("UpdateBooking($push): %v", err)
+			}
+		}
+
+		edited := time.Now().Truncate(time.Millisecond)
+		if err := repo.UpdateBookingComment(ctx, booking.ID, second.ID, bson.M{"commentText": "second, edited", "editedAt": edited}); err != nil {
+			t.Fatalf("UpdateBookingComment: %v", err)
+		}
+		if err := repo.DeleteBookingComment(ctx, booking.ID, first.ID); err != nil {
+			t.Fatalf("DeleteBookingComment: %v", err)
+		}
+		got, err := repo.FindBookingByID(ctx, booking.ID)
+		if err != nil {
+			t.Fatalf("FindBookingByID: %v", err)
+		}
+		if len(got.Comments) != 2 || got.Comments[0].ID != second.ID || got.Comments[1].ID != third.ID {
+			t.Fatalf("comments after edit and delete = %+v, want second and third", got.Comments)
+		}
+		if c := got.Comments[0]; c.CommentText != "second, edited" || c.EditedAt == nil || !c.EditedAt.Equal(edited) || c.UserName != "Customer" {
+			t.Errorf("edited comment = %+v", c)
+		}
+		if got.Comments[1].CommentText != "third" || got.Comments[1].EditedAt != nil {
+			t.Errorf("untouched comment = %+v", got.Comments[1])
+		}
+
+		if err := repo.UpdateBookingComment(ctx, booking.ID, first.ID, bson.M{"commentText": "x"}); !isNotFound(err) {
+			t.Errorf("UpdateBookingComment(deleted) = %v, want apperror.NotFound", err)
+		}
+		if err := repo.DeleteBookingComment(ctx, booking.ID, first.ID); !isNotFound(err) {
+			t.Errorf("DeleteBookingComment(deleted) = %v, want apperror.NotFound", err)
+		}
+		if err := repo.DeleteBookingComment(ctx, primitive.NewObjectID(), third.ID); !isNotFound(err) {
+			t.Errorf("DeleteBookingComment(unknown booking) = %v, want apperror.NotFound", err)
+		}
+	})
+
+	t.Run("AddBookingCommentUpToLimit", func(t *testing.T) {
+		repo := newRepo(t)
+		booking := NewBooking(primitive.NewObjectID())
+		if err := repo.CreateBooking(ctx, booking); err != nil {
+			t.Fatalf("CreateBooking: %v", err)
+		}
+		comment := func(text string) *domain.BookingComment {
+			return &domain.BookingComment{ID: primitive.NewObjectID(), UserID: booking.CustomerID, UserName: "Customer",
+				CommentText: text, Timestamp: time.Now().Truncate(time.Millisecond)}
+		}
+		for _, text := range []string{"first", "second"} {
+			if err := repo.AddBookingComment(ctx, booking.ID, comment(text), 2); err != nil {
+				t.Fatalf("AddBookingComment(%s): %v", text, err)
+			}
+		}
+		if err := repo.AddBookingComment(ctx, booking.ID, comment("third"), 2); !isNotFound(err) {
+			t.Errorf("AddBookingComment(full) = %v, want apperror.NotFound", err)
+		}
+		if err := repo.AddBookingComment(ctx, primitive.NewObjectID(), comment("lost"), 2); !isNotFound(err) {
+			t.Errorf("AddBookingComment(unknown booking) = %v, want apperror.NotFound", err)
+		}
+		got, err := repo.FindBookingByID(ctx, booking.ID)
+		if err != nil {
+			t.Fatalf("FindBookingByID: %v", err)
+		}
+		if len(got.Comments) != 2 || got.Comments[0].CommentText != "first" || got.Comments[1].CommentText != "second" {
+			t.Errorf("comments = %+v, want first and second", got.Comments)
+		}
+	})
+
+	t.Run("UpdateMissingBookingIsNotAnError", func(t *testing.T) {
+		repo := newRepo(t)
+		err := repo.UpdateBooking(ctx, primitive.NewObjectID(), bson.M{"$set": bson.M{"status": "cancelled"}})
+		if err != nil {
+			t.Errorf("UpdateBooking on missing booking returned %v, want nil", err)
+		}
+	})
+
+	t.Run("UpdateIfStatusIsConditional", func(t *testing.T) {
+		repo := newRepo(t)
+		booking := NewBooking(primitive.NewObjectID())
+		if err := repo.CreateBooking(ctx, booking); err != nil {
+			t.Fatalf("CreateBooking: %v", err)
+		}
+
+		accept := bson.M{"$set": bson.M{"status": "accepted", "quote": &domain.BookingQuote{Status: domain.QuoteProposed, Amount: 4550}}}
+		if err := repo.UpdateBookingIfStatus(ctx, booking.ID, "pending", "", accept); err != nil {
+			t.Fatalf("UpdateBookingIfStatus(pending): %v", err)
+		}
+		if err := repo.UpdateBookingIfStatus(ctx, booking.ID, "pending", "", accept); !isNotFound(err) {
+			t.Errorf("second UpdateBookingIfStatus(pending) = %v, want apperror.NotFound", err)
+		}
+
+		approve := bson.M{"$set": bson.M{"quote.status": domain.QuoteApproved}}
+		if err := repo.UpdateBookingIfStatus(ctx, booking.ID, "accepted", domain.QuoteProposed, approve); err != nil {
+			t.Fatalf("UpdateBookingIfStatus(proposed): %v", err)
+		}
+		decline := bson.M{"$set": bson.M{"status": "pending", "quote.status": domain.QuoteDeclined}}
+		if err := repo.UpdateBookingIfStatus(ctx, booking.ID, "accepted", domain.QuoteProposed, decline); !isNotFound(err) {
+			t.Errorf("decline after approval = %v, want apperror.NotFound", err)
+		}
+		got, err := repo.FindBookingByID(ctx, booking.ID)
+		if err != nil {
+			t.Fatalf("FindBookingByID: %v", err)
+		}
+		if got.Status != "accepted" || got.Quote == nil || got.Quote.Status != domain.QuoteApproved {
+			t.Errorf("after the conditional updates got status %q quote %+v", got.Status, got.Quote)
+		}
+
+		if err := repo.UpdateBookingIfStatus(ctx, primitive.NewObjectID(), "pending", "", accept); !isNotFound(err) {
+			t.Errorf("UpdateBookingIfStatus on missing booking = %v, want apperror.NotFound", err)
+		}
+	})
+
+	t.Run("CountByStatus", func(t *testing.T) {
+		repo := newRepo(t)
+		statuses := []string{"pending", "pending", "accepted", "completed"}
+		for _, status := range statuses {
+			booking := NewBooking(primitive.NewObjectID())
+			booking.Status = status
+			if err := repo.CreateBooking(ctx, booking); err != nil {
+				t.Fatalf("CreateBooking: %v", err)
+			}
+		}
+
+		counts, err := repo.CountBookingsByStatus(ctx)
+		if err != nil {
+			t.Fatalf("CountBookingsByStatus: %v", err)
+		}
+		want := map[string]int64{"pending": 2, "accepted": 1, "completed": 1}
+		if len(counts) != len(want) {
+			t.Errorf("CountBookingsByStatus = %v, want %v", counts, want)
+		}
+		for status, n := range want {
+			if counts[status] != n {
+				t.Errorf("CountBookingsByStatus[%q] = %d, want %d", status, counts[status], n)
+			}
+		}
+	})
+}
+
+// TestLedgerRepository runs the LedgerRepository conformance suite.
+func TestLedgerRepository(t *testing.T, newRepo func(t *testing.T) repositories.LedgerRepository) {
+	ctx := context.Background()
+
+	t.Run("EntriesAndBalanceByAccount", func(t *testing.T) {
+		repo := newRepo(t)
+		mower := domain.MowerAccount(primitive.NewObjectID())
+		first := NewLedgerTransaction(primitive.NewObjectID(), mower, 4550)
+		second := NewLedgerTransaction(primitive.NewObjectID(), mower, 1000)
+		second[0].CreatedAt = first[0].CreatedAt.Add(time.Second)
+		second[1].CreatedAt = second[0].CreatedAt
+		for _, entries := range [][]*domain.LedgerEntry{first, second} {
+			if err := repo.InsertEntries(ctx, entries); err != nil {
+				t.Fatalf("InsertEntries: %v", err)
+			}
+		}
+
+		entries, err := repo.FindEntriesByAccount(ctx, mower)
+		if err != nil {
+			t.Fatalf("FindEntriesByAccount: %v", err)
+		}
+		if len(entries) != 2 || entries[0].Amount != 4550 || entries[1].Amount != 1000 {
+			t.Errorf("FindEntriesByAccount returned %d entries in the wrong order or amounts", len(entries))
+		}
+
+		balance, err := repo.SumByAccount(ctx, mower)
+		if err != nil {
+			t.Fatalf("SumByAccount: %v", err)
+		}
+		if balance != 5550 {
+			t.Errorf("SumByAccount(mower) = %d, want 5550", balance)
+		}
+		if balance, _ := repo.SumByAccount(ctx, domain.AccountCustomerPayments); balance != -5550 {
+			t.Errorf("SumByAccount(customer payments) = %d, want -5550", balance)
+		}
+		if balance, _ := repo.SumByAccount(ctx, "unknown"); balance != 0 {
+			t.Errorf("SumByAccount(unknown) = %d, want 0", balance)
+		}
+	})
+
+	t.Run("BookingCannotBePostedTwice", func(t *testing.T) {
+		repo := newRepo(t)
+		bookingID := primitive.NewObjectID()
+		mower := domain.MowerAccount(primitive.NewObjectID())
+		if err := repo.InsertEntries(ctx, NewLedgerTransaction(bookingID, mower, 100)); err != nil {
+			t.Fatalf("InsertEntries: %v", err)
+		}
+
+		err := repo.InsertEntries(ctx, NewLedgerTransaction(bookingID, mower, 100))
+		if _, ok := err.(apperror.DuplicateError); !ok {
+			t.Errorf("second InsertEntries returned %v, want apperror.DuplicateError", err)
+		}
+		if balance, _ := repo.SumByAccount(ctx, mower); balance != 100 {
+			t.Errorf("balance after duplicate posting = %d, want 100", balance)
+		}
+	})
+
+	t.Run("SumByPeriodBucketsByUTCWeek", func(t *testing.T) {
+		repo := newRepo(t)
+		account := domain.MowerAccount(primitive.NewObjectID())
+		// 2030-06-03 is a Monday.
+		post := func(amount int64, at time.Time) {
+			t.Helper()
+			entries := NewLedgerTransaction(primitive.NewObjectID(), account, amount)
+			for _, e := range entries {
+				e.CreatedAt = at
+			}
+			if err := repo.InsertEntries(ctx, entries); err != nil {
+				t.Fatalf("InsertEntries: %v", err)
+			}
+		}
+		post(100, time.Date(2030, 6, 3, 0, 0, 0, 0, time.UTC))
+		post(200, time.Date(2030, 6, 9, 23, 59, 0, 0, time.UTC))
+		post(400, time.Date(2030, 6, 10, 12, 0, 0, 0, time.UTC))
+		post(800, time.Date(2030, 6, 17, 0, 0, 0, 0, time.UTC)) // outside the range
+
+		buckets, err := repo.SumByPeriod(ctx, account, domain.PeriodWeek,
+			time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2030, 6, 17, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("SumByPeriod: %v", err)
+		}
+		want := []domain.RevenueBucket{
+			{PeriodStart: time.Date(2030, 6, 3, 0, 0, 0, 0, time.UTC), Amount: 300, Entries: 2},
+			{PeriodStart: time.Date(2030, 6, 10, 0, 0, 0, 0, time.UTC), Amount: 400, Entries: 1},
+		}
+		if len(buckets) != len(want) {
+			t.Fatalf("SumByPeriod returned %d buckets, want %d: %+v", len(buckets), len(want), buckets)
+		}
+		for i := range want {
+			if !buckets[i].PeriodStart.Equal(want[i].PeriodStart) || buckets[i].Amount != want[i].Amount || buckets[i].Entries != want[i].Entries {
+				t.Errorf("bucket %d = %+v, want %+v", i, buckets[i], want[i])
+			}
+		}
+	})
+}
+
+// TestCommissionRuleRepository runs the CommissionRuleRepository contract against
+// repositories returned by newRepo.
+func TestCommissionRuleRepository(t *testing.T, newRepo func(t *testing.T) repositories.CommissionRuleRepository) {
+	ctx := context.Background()
+
+	t.Run("CreateFindAndList", func(t *testing.T) {
+		repo := newRepo(t)
+		older := NewCommissionRule(1000)
+		older.CreatedAt = older.CreatedAt.Add(-time.Hour)
+		newer := NewCommissionRule(500)
+		newer.MowerID = primitive.NewObjectID()
+		for _, rule := range []*domain.CommissionRule{older, newer} {
+			if err := repo.CreateRule(ctx, rule); err != nil {
+				t.Fatalf("CreateRule: %v", err)
+			}
+		}
+
+		found, err := repo.FindRuleByID(ctx, newer.ID)
+		if err != nil {
+			t.Fatalf("FindRuleByID: %v", err)
+		}
+		if found.PercentageBps != 500 || found.MowerID != newer.MowerID {
+			t.Errorf("FindRuleByID = %+v, want %+v", found, newer)
+		}
+
+		rules, err := repo.ListRules(ctx, false)
+		if err != nil {
+			t.Fatalf("ListRules: %v", err)
+		}
+		if len(rules) != 2 || rules[0].ID != newer.ID || rules[1].ID != older.ID {
+			t.Errorf("ListRules did not return both rules newest first: %+v", rules)
+		}
+	})
+
+	t.Run("MissingRuleIsNotFound", func(t *testing.T) {
+		_, err := newRepo(t).FindRuleByID(ctx, primitive.NewObjectID())
+		if !isNotFound(err) {
+			t.Errorf("FindRuleByID returned %v, want apperror.NotFound", err)
+		}
+	})
+
+	t.Run("ListActiveOnlySkipsDeactivated", func(t *testing.T) {
+		repo := newRepo(t)
+		active := NewCommissionRule(1000)
+		inactive := NewCommissionRule(2000)
+		for _, rule := range []*domain.CommissionRule{active, inactive} {
+			if err := repo.CreateRule(ctx, rule); err != nil {
+				t.Fatalf("CreateRule: %v", err)
+			}
+		}
+		if err := repo.UpdateRule(ctx, inactive.ID, bson.M{"$set": bson.M{"active": false}}); err != nil {
+			t.Fatalf("UpdateRule: %v", err)
+		}
+
+		rules, err := repo.ListRules(ctx, true)
+		if err != nil {
+			t.Fatalf("ListRules: %v", err)
+		}
+		if len(rules) != 1 || rules[0].ID != active.ID {
+			t.Errorf("ListRules(activeOnly) = %+v, want only %s", rules, active.ID.Hex())
+		}
+	})
+}
+
+// TestSystemMetricsRepository runs the SystemMetricsRepository contract against
+// repositories returned by newRepo.
+func TestSystemMetricsRepository(t *testing.T, newRepo func(t *testing.T) repositories.SystemMetricsRepository) {
+	ctx := context.Background()
+
+	t.Run("MissingMetricIsZero", func(t *testing.T) {
+		metric, err := newRepo(t).FindMetric(ctx, domain.SystemRevenueMetric)
+		if err != nil {
+			t.Fatalf("FindMetric: %v", err)
+		}
+		if metric.Name != domain.SystemRevenueMetric || metric.Value != 0 {
+			t.Errorf("FindMetric = %+v, want zero %s", metric, domain.SystemRevenueMetric)
+		}
+	})
+
+	t.Run("IncrementAccumulates", func(t *testing.T) {
+		repo := newRepo(t)
+		for _, delta := range []int64{150, 300, -50} {
+			if err := repo.IncrementMetric(ctx, domain.SystemRevenueMetric, delta); err != nil {
+				t.Fatalf("IncrementMetric: %v", err)
+			}
+		}
+		metric, err := repo.FindMetric(ctx, domain.SystemRevenueMetric)
+		if err != nil {
+			t.Fatalf("FindMetric: %v", err)
+		}
+		if metric.Value != 400 {
+			t.Errorf("metric value = %d, want 400", metric.Value)
+		}
+	})
+}
+
+// TestInvoiceRepository runs the InvoiceRepository contract against repositories
+// returned by newRepo.
+func TestInvoiceRepository(t *testing.T, newRepo func(t *testing.T) repositories.InvoiceRepository) {
+	ctx := context.Background()
+
+	t.Run("SequencesArePerYear", func(t *testing.T) {
+		repo := newRepo(t)
+		for _, want := range []struct {
+			year     int
+			sequence int64
+		}{{2030, 1}, {2030, 2}, {2031, 1}, {2030, 3}} {
+			got, err := repo.NextInvoiceSequence(ctx, want.year)
+			if err != nil {
+				t.Fatalf("NextInvoiceSequence(%d): %v", want.year, err)
+			}
+			if got != want.sequence {
+				t.Errorf("NextInvoiceSequence(%d) = %d, want %d", want.year, got, want.sequence)
+			}
+		}
+	})
+
+	t.Run("CreateAndFind", func(t *testing.T) {
+		repo := newRepo(t)
+		invoice := NewInvoice(primitive.NewObjectID(), 1)
+		if err := repo.CreateInvoice(ctx, invoice); err != nil {
+			t.Fatalf("CreateInvoice: %v", err)
+		}
+
+		byID, err := repo.FindInvoiceByID(ctx, invoice.ID)
+		if err != nil {
+			t.Fatalf("FindInvoiceByID: %v", err)
+		}
+		byBooking, err := repo.FindInvoiceByBookingID(ctx, invoice.BookingID)
+		if err != nil {
+			t.Fatalf("FindInvoiceByBookingID: %v", err)
+		}
+		for _, got := range []*domain.Invoice{byID, byBooking} {
+			if got.ID != invoice.ID || got.Number != invoice.Number || got.Amount != invoice.Amount {
+				t.Errorf("found %+v, want %+v", got, invoice)
+			}
+		}
+
+		if _, err := repo.FindInvoiceByID(ctx, primitive.NewObjectID()); !isNotFound(err) {
+			t.Errorf("FindInvoiceByID(missing) returned %v, want apperror.NotFound", err)
+		}
+		if _, err := repo.FindInvoiceByBookingID(ctx, primitive.NewObjectID()); !isNotFound(err) {
+			t.Errorf("FindInvoiceByBookingID(missing) returned %v, want apperror.NotFound", err)
+		}
+	})
+
+	t.Run("OneInvoicePerBookingAndNumber", func(t *testing.T) {
+		repo := newRepo(t)
+		first := NewInvoice(primitive.NewObjectID(), 1)
+		if err := repo.CreateInvoice(ctx, first); err != nil {
+			t.Fatalf("CreateInvoice: %v", err)
+		}
+
+		sameBooking := NewInvoice(primitive.NewObjectID(), 2)
+		sameBooking.BookingID = first.BookingID
+		sameNumber := NewInvoice(primitive.NewObjectID(), 1)
+		for name, invoice := range map[string]*domain.Invoice{"same booking": sameBooking, "same number": sameNumber} {
+			if _, ok := repo.CreateInvoice(ctx, invoice).(apperror.DuplicateError); !ok {
+				t.Errorf("CreateInvoice with %s did not return apperror.DuplicateError", name)
+			}
+		}
+	})
+
+	t.Run("ListByCustomerNewestFirstAndUpdate", func(t *testing.T) {
+		repo := newRepo(t)
+		customerID := primitive.NewObjectID()
+		older := NewInvoice(customerID, 1)
+		older.IssuedAt = older.IssuedAt.Add(-time.Hour)
+		newer := NewInvoice(customerID, 2)
+		other := NewInvoice(primitive.NewObjectID(), 3)
+		for _, invoice := range []*domain.Invoice{older, newer, other} {
+			if err := repo.CreateInvoice(ctx, invoice); err != nil {
+				t.Fatalf("CreateInvoice: %v", err)
+			}
+		}
+
+		invoices, err := repo.FindInvoicesByCustomerID(ctx, customerID)
+		if err != nil {
+			t.Fatalf("FindInvoicesByCustomerID: %v", err)
+		}
+		if len(invoices) != 2 || invoices[0].ID != newer.ID || invoices[1].ID != older.ID {
+			t.Errorf("FindInvoicesByCustomerID did not return the customer's invoices newest first: %+v", invoices)
+		}
+
+		paidAt := time.Now().Truncate(time.Millisecond)
+		if err := repo.UpdateInvoice(ctx, older.ID, bson.M{"$set": bson.M{"status": domain.InvoicePaid, "paidAt": paidAt}}); err != nil {
+			t.Fatalf("UpdateInvoice: %v", err)
+		}
+		got, err := repo.FindInvoiceByID(ctx, older.ID)
+		if err != nil {
+			t.Fatalf("FindInvoiceByID: %v", err)
+		}
+		if got.Status != domain.InvoicePaid || got.PaidAt == nil || !got.PaidAt.Equal(paidAt) {
+			t.Errorf("after UpdateInvoice got status %q paidAt %v", got.Status, got.PaidAt)
+		}
+	})
+
+	t.Run("FindUnpaidByMower", func(t *testing.T) {
+		repo := newRepo(t)
+		mowerID := primitive.NewObjectID()
+		unpaid := NewInvoice(primitive.NewObjectID(), 1)
+		unpaid.MowerID = mowerID
+		paid := NewInvoice(primitive.NewObjectID(), 2)
+		paid.MowerID = mowerID
+		paid.Status = domain.InvoicePaid
+		other := NewInvoice(primitive.NewObjectID(), 3)
+		for _, invoice := range []*domain.Invoice{unpaid, paid, other} {
+			if err := repo.CreateInvoice(ctx, invoice); err != nil {
+				t.Fatalf("CreateInvoice: %v", err)
+			}
+		}
+
+		invoices, err := repo.FindUnpaidInvoicesByMowerID(ctx, mowerID)
+		if err != nil {
+			t.Fatalf("FindUnpaidInvoicesByMowerID: %v", err)
+		}
+		if len(invoices) != 1 || invoices[0].ID != unpaid.ID {
+			t.Errorf("FindUnpaidInvoicesByMowerID returned %+v, want only the issued invoice", invoices)
+		}
+	})
+
+	t.Run("UnpaidAndOverdueLookups", func(t *testing.T) {
+		repo := newRepo(t)
+		customerID := primitive.NewObjectID()
+		oldest := NewInvoice(customerID, 1)
+		oldest.IssuedAt = oldest.IssuedAt.Add(-48 * time.Hour)
+		old := NewInvoice(customerID, 2)
+		old.IssuedAt = old.IssuedAt.Add(-24 * time.Hour)
+		paid := NewInvoice(customerID, 3)
+		paid.IssuedAt = paid.IssuedAt.Add(-72 * time.Hour)
+		paid.Status = domain.InvoicePaid
+		paid.Overdue = true
+		recent := NewInvoice(customerID, 4)
+		for _, invoice := range []*domain.Invoice{recent, old, paid, oldest} {
+			if err := repo.CreateInvoice(ctx, invoice); err != nil {
+				t.Fatalf("CreateInvoice: %v", err)
+			}
+		}
+
+		unpaid, err := repo.FindUnpaidInvoicesIssuedBefore(ctx, time.Now().Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("FindUnpaidInvoicesIssuedBefore: %v", err)
+		}
+		if len(unpaid) != 2 || unpaid[0].ID != oldest.ID || unpaid[1].ID != old.ID {
+			t.Errorf("FindUnpaidInvoicesIssuedBefore did not return the old unpaid invoices oldest first: %+v", unpaid)
+		}
+
+		count, err := repo.CountOverdueInvoices(ctx, customerID)
+		if err != nil {
+			t.Fatalf("CountOverdueInvoices: %v", err)
+		}
+		if count != 0 {
+			t.Errorf("CountOverdueInvoices = %d before any unpaid invoice is overdue, want 0", count)
+		}
+		if err := repo.UpdateInvoice(ctx, old.ID, bson.M{"$set": bson.M{"overdue": true}}); err != nil {
+			t.Fatalf("UpdateInvoice: %v", err)
+		}
+		if count, err = repo.CountOverdueInvoices(ctx, customerID); err != nil || count != 1 {
+			t.Errorf("CountOverdueInvoices = %d, %v, want 1", count, err)
+		}
+		if count, err = repo.CountOverdueInvoices(ctx, primitive.NewObjectID()); err != nil || count != 0 {
+			t.Errorf("CountOverdueInvoices(other customer) = %d, %v, want 0", count, err)
+		}
+	})
+
+	t.Run("UpdateRemindersIsConditional", func(t *testing.T) {
+		repo := newRepo(t)
+		invoice := NewInvoice(primitive.NewObjectID(), 1)
+		if err := repo.CreateInvoice(ctx, invoice); err != nil {
+			t.Fatalf("CreateInvoice: %v", err)
+		}
+		read, err := repo.FindInvoiceByID(ctx, invoice.ID)
+		if err != nil {
+			t.Fatalf("FindInvoiceByID: %v", err)
+		}
+
+		remindedAt := time.Now().Truncate(time.Millisecond)
+		remind := bson.M{"$set": bson.M{"remindersSent": 1, "lastReminderAt": remindedAt}}
+		if err := repo.UpdateInvoiceReminders(ctx, read, remind); err != nil {
+			t.Fatalf("UpdateInvoiceReminders: %v", err)
+		}
+		// read is now stale: a second runner holding it must not remind again.
+		if err := repo.UpdateInvoiceReminders(ctx, read, remind); !isNotFound(err) {
+			t.Errorf("UpdateInvoiceReminders with a stale invoice returned %v, want apperror.NotFound", err)
+		}
+
+		reminded, err := repo.FindInvoiceByID(ctx, invoice.ID)
+		if err != nil {
+			t.Fatalf("FindInvoiceByID: %v", err)
+		}
+		failed := bson.M{"$set": bson.M{"reminderFailures": 1}}
+		if err := repo.UpdateInvoiceReminders(ctx, reminded, failed); err != nil {
+			t.Errorf("UpdateInvoiceReminders after a reminder: %v", err)
+		}
+		if err := repo.UpdateInvoiceReminders(ctx, reminded, failed); !isNotFound(err) {
+			t.Errorf("UpdateInvoiceReminders with a stale failure count returned %v, want apperror.NotFound", err)
+		}
+
+		paid, err := repo.FindInvoiceByID(ctx, invoice.ID)
+		if err != nil {
+			t.Fatalf("FindInvoiceByID: %v", err)
+		}
+		if err := repo.UpdateInvoice(ctx, invoice.ID, bson.M{"$set": bson.M{"status": domain.InvoicePaid}}); err != nil {
+			t.Fatalf("UpdateInvoice: %v", err)
+		}
+		if err := repo.UpdateInvoiceReminders(ctx, paid, failed); !isNotFound(err) {
+			t.Errorf("UpdateInvoiceReminders on a paid invoice returned %v, want apperror.NotFound", err)
+		}
+	})
+}
+
+// TestPaymentReminderRepository runs the PaymentReminderRepository contract
+// against repositories created by newRepo.
+func TestPaymentReminderRepository(t *testing.T, newRepo func(t *testing.T) repositories.PaymentReminderRepository) {
+	ctx := context.Background()
+
+	t.Run("ListByInvoiceOldestFirst", func(t *testing.T) {
+		repo := newRepo(t)
+		invoiceID := primitive.NewObjectID()
+		now := time.Now().Truncate(time.Millisecond)
+		first := &domain.PaymentReminder{ID: primitive.NewObjectID(), InvoiceID: invoiceID, Level: 1, Error: "smtp unavailable", CreatedAt: now.Add(-2 * time.Hour)}
+		second := &domain.PaymentReminder{ID: primitive.NewObjectID(), InvoiceID: invoiceID, Level: 1, CreatedAt: now.Add(-time.Hour)}
+		other := &domain.PaymentReminder{ID: primitive.NewObjectID(), InvoiceID: primitive.NewObjectID(), Level: 1, CreatedAt: now}
+		for _, reminder := range []*domain.PaymentReminder{second, other, first} {
+			if err := repo.CreateReminder(ctx, reminder); err != nil {
+				t.Fatalf("CreateReminder: %v", err)
+			}
+		}
+
+		reminders, err := repo.FindRemindersByInvoiceID(ctx, invoiceID)
+		if err != nil {
+			t.Fatalf("FindRemindersByInvoiceID: %v", err)
+		}
+		if len(reminders) != 2 || reminders[0].ID != first.ID || reminders[1].ID != second.ID {
+			t.Fatalf("FindRemindersByInvoiceID did not return the invoice's reminders oldest first: %+v", reminders)
+		}
+		if reminders[0].Error != first.Error {
+			t.Errorf("reminder error = %q, want %q", reminders[0].Error, first.Error)
+		}
+
+		none, err := repo.FindRemindersByInvoiceID(ctx, primitive.NewObjectID())
+		if err != nil || len(none) != 0 {
+			t.Errorf("FindRemindersByInvoiceID(unknown) = %v, %v, want no reminders", none, err)
+		}
+	})
+}
+
+// TestPaymentEventRepository runs the PaymentEventRepository contract against
+// repositories created by newRepo.
+func TestPaymentEventRepository(t *testing.T, newRepo func(t *testing.T) repositories.PaymentEventRepository) {
+	ctx := context.Background()
+
+	t.Run("EventsAreRecordedOnce", func(t *testing.T) {
+		repo := newRepo(t)
+		event := &domain.PaymentEvent{ID: "fake:evt_1", Type: "payment.captured", PaymentID: "pay_1", BookingID: primitive.NewObjectID(), ReceivedAt: time.Now()}
+		if err := repo.RecordEvent(ctx, event); err != nil {
+			t.Fatalf("RecordEvent: %v", err)
+		}
+		if _, ok := repo.RecordEvent(ctx, event).(apperror.DuplicateError); !ok {
+			t.Errorf("recording the same event twice did not return apperror.DuplicateError")
+		}
+
+		other := *event
+		other.ID = "fake:evt_2"
+		if err := repo.RecordEvent(ctx, &other); err != nil {
+			t.Errorf("RecordEvent(another event for the same payment): %v", err)
+		}
+	})
+}
+
+// TestJobLockRepository runs the JobLockRepository contract against
+// repositories created by newRepo.
+func TestJobLockRepository(t *testing.T, newRepo func(t *testing.T) repositories.JobLockRepository) {
+	ctx := context.Background()
+
+	acquire := func(t *testing.T, repo repositories.JobLockRepository, name, owner string, ttl time.Duration, want bool) {
+		t.Helper()
+		got, err := repo.AcquireLock(ctx, name, owner, ttl)
+		if err != nil {
+			t.Fatalf("AcquireLock(%s, %s): %v", name, owner, err)
+		}
+		if got != want {
+			t.Fatalf("AcquireLock(%s, %s) = %v, want %v", name, owner, got, want)
+		}
+	}
+
+	t.Run("ExclusiveUntilReleased", func(t *testing.T) {
+		repo := newRepo(t)
+		acquire(t, repo, "job", "a", time.Minute, true)
+		acquire(t, repo, "job", "b", time.Minute, false)
+		acquire(t, repo, "job", "a", time.Minute, true)
+		acquire(t, repo, "other-job", "b", time.Minute, true)
+
+		if err := repo.ReleaseLock(ctx, "job", "b"); err != nil {
+			t.Fatalf("ReleaseLock by non-owner: %v", err)
+		}
+		acquire(t, repo, "job", "b", time.Minute, false)
+
+		if err := repo.ReleaseLock(ctx, "job", "a"); err != nil {
+			t.Fatalf("ReleaseLock: %v", err)
+		}
+		acquire(t, repo, "job", "b", time.Minute, true)
+	})
+
+	t.Run("ExpiredLocksCanBeTaken", func(t *testing.T) {
+		repo := newRepo(t)
+		acquire(t, repo, "job", "a", 50*time.Millisecond, true)
+		time.Sleep(100 * time.Millisecond)
+		acquire(t, repo, "job", "b", time.Minute, true)
+		acquire(t, repo, "job", "a", time.Minute, false)
+	})
+}
+
+// TestPayoutRepository runs the PayoutRepository conformance suite.
+func TestPayoutRepository(t *testing.T, newRepo func(t *testing.T) repositories.PayoutRepository) {
+	ctx := context.Background()
+
+	t.Run("OneOpenRequestPerMower", func(t *testing.T) {
+		repo := newRepo(t)
+		mowerID := primitive.NewObjectID()
+		first := NewPayoutRequest(mowerID, 2000)
+		if err := repo.CreatePayoutRequest(ctx, first); err != nil {
+			t.Fatalf("CreatePayoutRequest: %v", err)
+		}
+		if _, ok := repo.CreatePayoutRequest(ctx, NewPayoutRequest(mowerID, 3000)).(apperror.DuplicateError); !ok {
+			t.Errorf("second open request did not return apperror.DuplicateError")
+		}
+		if err := repo.CreatePayoutRequest(ctx, NewPayoutRequest(primitive.NewObjectID(), 3000)); err != nil {
+			t.Errorf("CreatePayoutRequest(another mower): %v", err)
+		}
+
+		open, err := repo.FindOpenPayoutRequest(ctx, mowerID)
+		if err != nil || open.ID != first.ID {
+			t.Fatalf("FindOpenPayoutRequest = %v, %v; want %s", open, err, first.ID.Hex())
+		}
+
+		err = repo.UpdatePayoutRequest(ctx, first.ID, bson.M{
+			"$set":   bson.M{"status": domain.PayoutRejected},
+			"$unset": bson.M{"open": ""},
+		})
+		if err != nil {
+			t.Fatalf("UpdatePayoutRequest: %v", err)
+		}
+		if _, err := repo.FindOpenPayoutRequest(ctx, mowerID); !isNotFound(err) {
+			t.Errorf("FindOpenPayoutRequest after closing = %v, want apperror.NotFound", err)
+		}
+		if err := repo.CreatePayoutRequest(ctx, NewPayoutRequest(mowerID, 3000)); err != nil {
+			t.Errorf("CreatePayoutRequest after closing the open one: %v", err)
+		}
+	})
+
+	t.Run("Lookups", func(t *testing.T) {
+		repo := newRepo(t)
+		mowerID := primitive.NewObjectID()
+		older := NewPayoutRequest(mowerID, 2000)
+		older.Status, older.Open = domain.PayoutPaid, false
+		older.CreatedAt = older.CreatedAt.Add(-time.Hour)
+		newer := NewPayoutRequest(mowerID, 3000)
+		other := NewPayoutRequest(primitive.NewObjectID(), 4000)
+		for _, request := range []*domain.PayoutRequest{newer, older, other} {
+			if err := repo.CreatePayoutRequest(ctx, request); err != nil {
+				t.Fatalf("CreatePayoutRequest: %v", err)
+			}
+		}
+
+		got, err := repo.FindPayoutRequestByID(ctx, newer.ID)
+		if err != nil || got.Amount != 3000 || got.Destination.AccountNumber != newer.Destination.AccountNumber {
+			t.Errorf("FindPayoutRequestByID = %+v, %v", got, err)
+		}
+		if _, err := repo.FindPayoutRequestByID(ctx, primitive.NewObjectID()); !isNotFound(err) {
+			t.Errorf("FindPayoutRequestByID(unknown) = %v, want apperror.NotFound", err)
+		}
+
+		mine, err := repo.FindPayoutRequestsByMowerID(ctx, mowerID)
+		if err != nil || len(mine) != 2 || mine[0].ID != newer.ID || mine[1].ID != older.ID {
+			t.Errorf("FindPayoutRequestsByMowerID should list the mower's requests newest first, got %d: %v", len(mine), err)
+		}
+		requested, err := repo.FindPayoutRequestsByStatus(ctx, domain.PayoutRequested)
+		if err != nil || len(requested) != 2 {
+			t.Errorf("FindPayoutRequestsByStatus(requested) = %d requests, %v; want 2", len(requested), err)
+		}
+	})
+
+	t.Run("Batches", func(t *testing.T) {
+		repo := newRepo(t)
+		batchID := primitive.NewObjectID()
+		paid := NewPayoutRequest(primitive.NewObjectID(), 2000)
+		paid.Status, paid.Open, paid.BatchID = domain.PayoutPaid, false, batchID
+		if err := repo.CreatePayoutRequest(ctx, paid); err != nil {
+			t.Fatalf("CreatePayoutRequest: %v", err)
+		}
+		if err := repo.CreatePayoutRequest(ctx, NewPayoutRequest(primitive.NewObjectID(), 3000)); err != nil {
+			t.Fatalf("CreatePayoutRequest: %v", err)
+		}
+
+		now := time.Now().Truncate(time.Millisecond)
+		older := &domain.PayoutBatch{ID: primitive.NewObjectID(), RequestIDs: []primitive.ObjectID{}, Currency: "USD", CreatedAt: now.Add(-time.Hour)}
+		batch := &domain.PayoutBatch{ID: batchID, RequestIDs: []primitive.ObjectID{paid.ID}, Count: 1, Total: 2000, Currency: "USD", CreatedAt: now}
+		for _, b := range []*domain.PayoutBatch{older, batch} {
+			if err := repo.CreatePayoutBatch(ctx, b); err != nil {
+				t.Fatalf("CreatePayoutBatch: %v", err)
+			}
+		}
+
+		got, err := repo.FindPayoutBatchByID(ctx, batchID)
+		if err != nil || got.Total != 2000 || len(got.RequestIDs) != 1 {
+			t.Errorf("FindPayoutBatchByID = %+v, %v", got, err)
+		}
+		if _, err := repo.FindPayoutBatchByID(ctx, primitive.NewObjectID()); !isNotFound(err) {
+			t.Errorf("FindPayoutBatchByID(unknown) = %v, want apperror.NotFound", err)
+		}
+		batches, err := repo.FindPayoutBatches(ctx)
+		if err != nil || len(batches) != 2 || batches[0].ID != batchID {
+			t.Errorf("FindPayoutBatches should list batches newest first, got %d: %v", len(batches), err)
+		}
+		inBatch, err := repo.FindPayoutRequestsByBatchID(ctx, batchID)
+		if err != nil || len(inBatch) != 1 || inBatch[0].ID != paid.ID {
+			t.Errorf("FindPayoutRequestsByBatchID = %d requests, %v; want the paid request", len(inBatch), err)
+		}
+	})
+}
+
+// NewPayoutRequest returns an unsaved, open payout request of amount minor units.
+func NewPayoutRequest(mowerID primitive.ObjectID, amount int64) *domain.PayoutRequest {
+	now := time.Now().Truncate(time.Millisecond)
+	return &domain.PayoutRequest{
+		ID:        primitive.NewObjectID(),
+		MowerID:   mowerID,
+		MowerName: "Mower",
+		Amount:    amount,
+		Currency:  "USD",
+		Destination: domain.PayoutDetails{
+			AccountHolder: "Mower",
+			RoutingNumber: "011000015",
+			AccountNumber: "000123456789",
+			AccountLast4:  "6789",
+			AccountType:   domain.BankAccountChecking,
+			UpdatedAt:     now,
+		},
+		Status:    domain.PayoutRequested,
+		Open:      true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// TestPropertyRepository runs the PropertyRepository contract against newRepo.
+func TestPropertyRepository(t *testing.T, newRepo func(t *testing.T) repositories.PropertyRepository) {
+	ctx := context.Background()
+	repo := newRepo(t)
+	customerID := primitive.NewObjectID()
+	older := NewProperty(customerID)
+	older.CreatedAt = older.CreatedAt.Add(-time.Hour)
+	newer := NewProperty(customerID)
+	archived := NewProperty(customerID)
+	archived.Archived = true
+	other := NewProperty(primitive.NewObjectID())
+	for _, property := range []*domain.Property{newer, archived, older, other} {
+		if err := repo.CreateProperty(ctx, property); err != nil {
+			t.Fatalf("CreateProperty: %v", err)
+		}
+	}
+
+	got, err := repo.FindPropertyByID(ctx, archived.ID)
+	if err != nil || !got.Archived || got.Address != archived.Address {
+		t.Errorf("FindPropertyByID(archived) = %+v, %v", got, err)
+	}
+	if _, err := repo.FindPropertyByID(ctx, primitive.NewObjectID()); !isNotFound(err) {
+		t.Errorf("FindPropertyByID(unknown) = %v, want apperror.NotFound", err)
+	}
+
+	mine, err := repo.FindPropertiesByCustomerID(ctx, customerID)
+	if err != nil || len(mine) != 2 || mine[0].ID != older.ID || mine[1].ID != newer.ID {
+		t.Errorf("FindPropertiesByCustomerID should list unarchived properties oldest first, got %d: %v", len(mine), err)
+	}
+
+	if err := repo.UpdateProperty(ctx, newer.ID, bson.M{"$set": bson.M{"lawnArea": 900.0, "archived": true}}); err != nil {
+		t.Fatalf("UpdateProperty: %v", err)
+	}
+	got, err = repo.FindPropertyByID(ctx, newer.ID)
+	if err != nil || got.LawnArea != 900 || !got.Archived {
+		t.Errorf("FindPropertyByID after update = %+v, %v", got, err)
+	}
+}
+
+// TestServiceAreaRepository runs the ServiceAreaRepository contract against newRepo.
+func TestServiceAreaRepository(t *testing.T, newRepo func(t *testing.T) repositories.ServiceAreaRepository) {
+	ctx := context.Background()
+	repo := newRepo(t)
+	older := NewServiceArea()
+	older.CreatedAt = older.CreatedAt.Add(-time.Hour)
+	newer := NewServiceArea()
+	inactive := NewServiceArea()
+	inactive.Active = false
+	inactive.CreatedAt = inactive.CreatedAt.Add(-2 * time.Hour)
+	for _, area := range []*domain.ServiceArea{newer, inactive, older} {
+		if err := repo.CreateArea(ctx, area); err != nil {
+			t.Fatalf("CreateArea: %v", err)
+		}
+	}
+
+	got, err := repo.FindAreaByID(ctx, inactive.ID)
+	if err != nil || got.Active || got.Name != inactive.Name || len(got.Boundary.Coordinates) != 1 {
+		t.Errorf("FindAreaByID(inactive) = %+v, %v", got, err)
+	}
+	if _, err := repo.FindAreaByID(ctx, primitive.NewObjectID()); !isNotFound(err) {
+		t.Errorf("FindAreaByID(unknown) = %v, want apperror.NotFound", err)
+	}
+
+	all, err := repo.ListAreas(ctx, false)
+	if err != nil || len(all) != 3 || all[0].ID != inactive.ID || all[1].ID != older.ID || all[2].ID != newer.ID {
+		t.Errorf("ListAreas(all) should list every area oldest first, got %d: %v", len(all), err)
+	}
+	active, err := repo.ListAreas(ctx, true)
+	if err != nil || len(active) != 2 || active[0].ID != older.ID {
+		t.Errorf("ListAreas(active) should leave out inactive areas, got %d: %v", len(active), err)
+	}
+
+	got, err = repo.FindAreaContaining(ctx, *domain.NewGeoPoint(40.7128, -74.006))
+	if err != nil || got.ID != older.ID {
+		t.Errorf("FindAreaContaining(inside) = %+v, %v; want the oldest active area", got, err)
+	}
+	if _, err := repo.FindAreaContaining(ctx, *domain.NewGeoPoint(41.2, -74.006)); !isNotFound(err) {
+		t.Errorf("FindAreaContaining(outside) = %v, want apperror.NotFound", err)
+	}
+
+	if err := repo.UpdateArea(ctx, older.ID, bson.M{"$set": bson.M{"active": false, "priceMultiplier": 1.5}}); err != nil {
+		t.Fatalf("UpdateArea: %v", err)
+	}
+	got, err = repo.FindAreaContaining(ctx, *domain.NewGeoPoint(40.7128, -74.006))
+	if err != nil || got.ID != newer.ID {
+		t.Errorf("FindAreaContaining after deactivating the oldest = %+v, %v", got, err)
+	}
+	got, err = repo.FindAreaByID(ctx, older.ID)
+	if err != nil || got.PriceMultiplier != 1.5 {
+		t.Errorf("FindAreaByID after update = %+v, %v", got, err)
+	}
+}
+
+// TestReviewRepository runs the ReviewRepository contract against newRepo.
+func TestReviewRepository(t *testing.T, newRepo func(t *testing.T) repositories.ReviewRepository) {
+	ctx := context.Background()
+	repo := newRepo(t)
+	mowerID := primitive.NewObjectID()
+	older := NewReview(mowerID, 4)
+	older.CreatedAt = older.CreatedAt.Add(-2 * time.Hour)
+	newest := NewReview(mowerID, 5)
+	middle := NewReview(mowerID, 3)
+	middle.CreatedAt = middle.CreatedAt.Add(-time.Hour)
+	other := NewReview(primitive.NewObjectID(), 1)
+	for _, review := range []*domain.Review{older, newest, middle, other} {
+		if err := repo.CreateReview(ctx, review); err != nil {
+			t.Fatalf("CreateReview: %v", err)
+		}
+	}
+
+	again := NewReview(mowerID, 1)
+	again.BookingID = older.BookingID
+	if _, ok := repo.CreateReview(ctx, again).(apperror.DuplicateError); !ok {
+		t.Error("CreateReview for a reviewed booking should be rejected with apperror.DuplicateError")
+	}
+
+	got, err := repo.FindReviewByBookingID(ctx, older.BookingID)
+	if err != nil || got.ID != older.ID || got.Rating != 4 || got.Comment != older.Comment || got.Reply != nil {
+		t.Errorf("FindReviewByBookingID = %+v, %v", got, err)
+	}
+	if _, err := repo.FindReviewByBookingID(ctx, primitive.NewObjectID()); !isNotFound(err) {
+		t.Errorf("FindReviewByBookingID(unknown) = %v, want apperror.NotFound", err)
+	}
+
+	recent, err := repo.FindReviewsByMowerID(ctx, mowerID, 2)
+	if err != nil || len(recent) != 2 || recent[0].ID != newest.ID || recent[1].ID != middle.ID {
+		t.Errorf("FindReviewsByMowerID should list the newest reviews first up to the limit, got %d: %v", len(recent), err)
+	}
+	if none, err := repo.FindReviewsByMowerID(ctx, primitive.NewObjectID(), 10); err != nil || none == nil || len(none) != 0 {
+		t.Errorf("FindReviewsByMowerID(unknown) = %v, %v; want an empty list", none, err)
+	}
+
+	reply := domain.ReviewReply{Text: "Thanks!", CreatedAt: time.Now().Truncate(time.Millisecond)}
+	if err := repo.UpdateReview(ctx, older.ID, bson.M{"$set": bson.M{"reply": reply}}); err != nil {
+		t.Fatalf("UpdateReview: %v", err)
+	}
+	got, err = repo.FindReviewByBookingID(ctx, older.BookingID)
+	if err != nil || got.Reply == nil || got.Reply.Text != "Thanks!" || !got.Reply.CreatedAt.Equal(reply.CreatedAt) {
+		t.Errorf("FindReviewByBookingID after reply = %+v, %v", got, err)
+	}
+
+	first := &domain.ReviewReply{Text: "First", CreatedAt: time.Now().Truncate(time.Millisecond)}
+	if err := repo.SetReviewReply(ctx, newest.ID, first); err != nil {
+		t.Fatalf("SetReviewReply: %v", err)
+	}
+	if _, ok := repo.SetReviewReply(ctx, newest.ID, &domain.ReviewReply{Text: "Second", CreatedAt: time.Now()}).(apperror.DuplicateError); !ok {
+		t.Error("SetReviewReply on a review with a reply should be rejected with apperror.DuplicateError")
+	}
+	got, err = repo.FindReviewByBookingID(ctx, newest.BookingID)
+	if err != nil || got.Reply == nil || got.Reply.Text != "First" {
+		t.Errorf("the first reply should be kept, got %+v, %v", got, err)
+	}
+}
+
+// NewReview returns an unsaved review of a new booking for the mower.
+func NewReview(mowerID primitive.ObjectID, rating int) *domain.Review {
+	return &domain.Review{
+		ID:           primitive.NewObjectID(),
+		BookingID:    primitive.NewObjectID(),
+		CustomerID:   primitive.NewObjectID(),
+		CustomerName: "Customer",
+		MowerID:      mowerID,
+		Rating:       rating,
+		Comment:      "Tidy work",
+		CreatedAt:    time.Now().Truncate(time.Millisecond),
+	}
+}
+
+// NewServiceArea returns an unsaved, active area of about 30 by 30 km centred
+// on 40.7128, -74.006, the location of NewBooking.
+func NewServiceArea() *domain.ServiceArea {
+	id := primitive.NewObjectID()
+	now := time.Now().Truncate(time.Millisecond)
+	return &domain.ServiceArea{
+		ID:   id,
+		Name: "Area " + id.Hex(),
+		Boundary: domain.GeoPolygon{Type: "Polygon", Coordinates: [][][]float64{{
+			{-74.2, 40.58}, {-73.8, 40.58}, {-73.8, 40.86}, {-74.2, 40.86}, {-74.2, 40.58},
+		}}},
+		PriceMultiplier: 1,
+		Active:          true,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// NewProperty returns an unsaved 400 m² flat lawn for the customer.
+func NewProperty(customerID primitive.ObjectID) *domain.Property {
+	now := time.Now().Truncate(time.Millisecond)
+	return &domain.Property{
+		ID:          primitive.NewObjectID(),
+		CustomerID:  customerID,
+		Label:       "Home",
+		Address:     domain.PostalAddress{Line1: "1 Lawn Street", City: "Springfield", PostalCode: "12345"},
+		LawnArea:    400,
+		Terrain:     domain.TerrainFlat,
+		ServiceType: "mowing",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// NewInvoice returns an unsaved invoice for the customer with the given 2030 sequence.
+func NewInvoice(customerID primitive.ObjectID, sequence int64) *domain.Invoice {
+	now := time.Now().Truncate(time.Millisecond)
+	return &domain.Invoice{
+		ID:             primitive.NewObjectID(),
+		Number:         domain.InvoiceNumber("INV", 2030, sequence),
+		Year:           2030,
+		Sequence:       sequence,
+		BookingID:      primitive.NewObjectID(),
+		Status:         domain.InvoiceIssued,
+		CustomerID:     customerID,
+		CustomerName:   "Customer",
+		MowerID:        primitive.NewObjectID(),
+		MowerName:      "Mower",
+		ServiceDate:    "2030-06-01",
+		ServiceAddress: "1 Lawn Street",
+		Amount:         4550,
+		Currency:       "USD",
+		IssuedAt:       now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// NewUser returns a unique, unsaved user with the given role.
+func NewUser(role string) *domain.User {
+	id := primitive.NewObjectID()
+	return &domain.User{
+		ID:        id,
+		Name:      "User " + id.Hex(),
+		Email:     id.Hex() + "@example.com",
+		Password:  "hashed",
+		Role:      role,
+		CreatedAt: time.Now().Truncate(time.Millisecond),
+		UpdatedAt: time.Now().Truncate(time.Millisecond),
+	}
+}
+
+// NewBooking returns an unsaved pending booking for the given customer.
+func NewBooking(customerID primitive.ObjectID) *domain.Booking {
+	return &domain.Booking{
+		ID:            primitive.NewObjectID(),
+		CustomerID:    customerID,
+		Date:          "2030-06-01",
+		Time:          "09:00",
+		Address:       "1 Lawn Street",
+		Location:      domain.NewGeoPoint(40.7128, -74.006),
+		Status:        "pending",
+		BillingStatus: "pending",
+		CreatedAt:     time.Now().Truncate(time.Millisecond),
+		UpdatedAt:     time.Now().Truncate(time.Millisecond),
+	}
+}
+
+// NewLedgerTransaction returns a balanced, unsaved booking payment of amount
+// minor units from customer payments to account.
+func NewLedgerTransaction(bookingID primitive.ObjectID, account string, amount int64) []*domain.LedgerEntry {
+	transactionID := primitive.NewObjectID()
+	now := time.Now().Truncate(time.Millisecond)
+	return []*domain.LedgerEntry{
+		{ID: primitive.NewObjectID(), TransactionID: transactionID, Account: domain.AccountCustomerPayments,
+			Type: domain.LedgerBookingPayment, Amount: -amount, Currency: "USD", BookingID: bookingID, CreatedAt: now},
+		{ID: primitive.NewObjectID(), TransactionID: transactionID, Account: account,
+			Type: domain.LedgerBookingPayment, Amount: amount, Currency: "USD", BookingID: bookingID, CreatedAt: now},
+	}
+}
+
+// NewCommissionRule returns an unsaved, active, standing rule for every mower.
+func NewCommissionRule(percentageBps int64) *domain.CommissionRule {
+	id := primitive.NewObjectID()
+	now := time.Now().Truncate(time.Millisecond)
+	return &domain.CommissionRule{
+		ID:            id,
+		Name:          "Rule " + id.Hex(),
+		PercentageBps: percentageBps,
+		Active:        true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func isNotFound(err error) bool {
+	_, ok := err.(apperror.NotFound)
+	return ok
+}
+
+func mustBookings(t *testing.T) func([]*domain.Booking, error) []*domain.Booking {
+	return func(bookings []*domain.Booking, err error) []*domain.Booking {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("listing bookings: %v", err)
+		}
+		return bookings
+	}
+}
+
+func assertBookingIDs(t *testing.T, what string, got []*domain.Booking, want ...primitive.ObjectID) {
+	t.Helper()
+	seen := make(map[primitive.ObjectID]bool, len(got))
+	for _, b := range got {
+		seen[b.ID] = true
+	}
+	if len(got) != len(want) {
+		t.Errorf("%s: got %d bookings, want %d", what, len(got), len(want))
+		return
+	}
+	for _, id := range want {
+		if !seen[id] {
+			t.Errorf("%s: missing booking %s", what, id.Hex())
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReviewRepository defines the repository interface for booking reviews.
+type ReviewRepository interface {
+	// CreateReview stores a review. A second review of the same booking is
+	// rejected with apperror.DuplicateError.
+	CreateReview(ctx context.Context, review *domain.Review) error
+	FindReviewByBookingID(ctx context.Context, bookingID primitive.ObjectID) (*domain.Review, error)
+	// FindReviewsByMowerID returns at most limit of the mower's reviews, newest
+	// first.
+	FindReviewsByMowerID(ctx context.Context, mowerID primitive.ObjectID, limit int) ([]*domain.Review, error)
+	UpdateReview(ctx context.Context, id primitive.ObjectID, update bson.M) error
+	// SetReviewReply sets the reply on a review that has none yet. A review
+	// that already has a reply, or does not exist, is left unchanged and
+	// apperror.DuplicateError is returned.
+	SetReviewReply(ctx context.Context, id primitive.ObjectID, reply *domain.ReviewReply) error
+}
+
+type reviewRepository struct {
+	collection *mongo.Collection
+}
+
+// NewReviewRepository creates a new ReviewRepository.
+func NewReviewRepository(db *mongo.Database) ReviewRepository {
+	return &reviewRepository{collection: db.Collection("reviews")}
+}
+
+// CreateReview inserts a new review. The unique index on bookingId enforces one
+// review per booking.
+func (r *reviewRepository) CreateReview(ctx context.Context, review *domain.Review) error {
+	if _, err := r.collection.InsertOne(ctx, review); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return apperror.DuplicateError{Resource: "Review for this booking"}
+		}
+		return fmt.Errorf("failed to insert review: %w", err)
+	}
+	return nil
+}
+
+// FindReviewByBookingID retrieves the review of a booking.
+func (r *reviewRepository) FindReviewByBookingID(ctx context.Context, bookingID primitive.ObjectID) (*domain.Review, error) {
+	var review domain.Review
+	err := r.collection.FindOne(ctx, bson.M{"bookingId": bookingID}).Decode(&review)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, apperror.NotFound{Resource: "Review"}
+		}
+		return nil, fmt.Errorf("failed to find review: %w", err)
+	}
+	return &review, nil
+}
+
+// FindReviewsByMowerID retrieves a mower's most recent reviews.
+func (r *reviewRepository) FindReviewsByMowerID(ctx context.Context, mowerID primitive.ObjectID, limit int) ([]*domain.Review, error) {
+	reviews := []*domain.Review{}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, bson.M{"mowerId": mowerID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reviews: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, fmt.Errorf("failed to decode reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// UpdateReview applies a BSON update document to a review.
+func (r *reviewRepository) UpdateReview(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		return fmt.Errorf("failed to update review: %w", err)
+	}
+	return nil
+}
+
+// SetReviewReply sets the reply only while the review has none, so concurrent
+// replies cannot overwrite each other.
+func (r *reviewRepository) SetReviewReply(ctx context.Context, id primitive.ObjectID, reply *domain.ReviewReply) error {
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "reply": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"reply": reply}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set review reply: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return apperror.DuplicateError{Resource: "Reply to this review"}
+	}
+	return nil
+}
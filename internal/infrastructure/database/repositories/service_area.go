@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ServiceAreaRepository defines the repository interface for service areas.
+type ServiceAreaRepository interface {
+	CreateArea(ctx context.Context, area *domain.ServiceArea) error
+	FindAreaByID(ctx context.Context, id primitive.ObjectID) (*domain.ServiceArea, error)
+	// FindAreaContaining returns the oldest active area whose boundary contains
+	// point, or apperror.NotFound if none does.
+	FindAreaContaining(ctx context.Context, point domain.GeoPoint) (*domain.ServiceArea, error)
+	// ListAreas returns every area, oldest first. When activeOnly is set,
+	// deactivated areas are left out.
+	ListAreas(ctx context.Context, activeOnly bool) ([]*domain.ServiceArea, error)
+	UpdateArea(ctx context.Context, id primitive.ObjectID, update bson.M) error
+}
+
+type serviceAreaRepository struct {
+	collection *mongo.Collection
+}
+
+// NewServiceAreaRepository creates a new ServiceAreaRepository.
+func NewServiceAreaRepository(db *mongo.Database) ServiceAreaRepository {
+	return &serviceAreaRepository{collection: db.Collection("service_areas")}
+}
+
+// CreateArea inserts a new service area.
+func (r *serviceAreaRepository) CreateArea(ctx context.Context, area *domain.ServiceArea) error {
+	if _, err := r.collection.InsertOne(ctx, area); err != nil {
+		return fmt.Errorf("failed to insert service area: %w", err)
+	}
+	return nil
+}
+
+// FindAreaByID retrieves a service area by its ID.
+func (r *serviceAreaRepository) FindAreaByID(ctx context.Context, id primitive.ObjectID) (*domain.ServiceArea, error) {
+	var area domain.ServiceArea
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&area)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, apperror.NotFound{Resource: "Service area"}
+		}
+		return nil, fmt.Errorf("failed to find service area: %w", err)
+	}
+	return &area, nil
+}
+
+// FindAreaContaining finds the active area covering point using the 2dsphere
+// index on boundary.
+func (r *serviceAreaRepository) FindAreaContaining(ctx context.Context, point domain.GeoPoint) (*domain.ServiceArea, error) {
+	filter := bson.M{
+		"active":   true,
+		"boundary": bson.M{"$geoIntersects": bson.M{"$geometry": point}},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	var area domain.ServiceArea
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&area)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, apperror.NotFound{Resource: "Service area"}
+		}
+		return nil, fmt.Errorf("failed to find service area: %w", err)
+	}
+	return &area, nil
+}
+
+// ListAreas retrieves service areas, oldest first.
+func (r *serviceAreaRepository) ListAreas(ctx context.Context, activeOnly bool) ([]*domain.ServiceArea, error) {
+	filter := bson.M{}
+	if activeOnly {
+		filter["active"] = true
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find service areas: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var areas []*domain.ServiceArea
+	if err := cursor.All(ctx, &areas); err != nil {
+		return nil, fmt.Errorf("failed to decode service areas: %w", err)
+	}
+	return areas, nil
+}
+
+// UpdateArea updates a service area by its ID.
+func (r *serviceAreaRepository) UpdateArea(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		return fmt.Errorf("failed to update service area: %w", err)
+	}
+	return nil
+}
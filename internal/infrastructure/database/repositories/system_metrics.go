@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"lawnconnect-api/internal/core/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SystemMetricsRepository stores platform-wide running totals such as system_revenue.
+type SystemMetricsRepository interface {
+	// IncrementMetric atomically adds delta to the named metric, creating it at zero first.
+	IncrementMetric(ctx context.Context, name string, delta int64) error
+	// FindMetric returns the named metric, or a zero-valued one if it was never written.
+	FindMetric(ctx context.Context, name string) (*domain.SystemMetrics, error)
+}
+
+type systemMetricsRepository struct {
+	collection *mongo.Collection
+}
+
+// NewSystemMetricsRepository creates a new SystemMetricsRepository.
+func NewSystemMetricsRepository(db *mongo.Database) SystemMetricsRepository {
+	return &systemMetricsRepository{collection: db.Collection("system_metrics")}
+}
+
+// IncrementMetric upserts the metric with $inc.
+func (r *systemMetricsRepository) IncrementMetric(ctx context.Context, name string, delta int64) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"name": name},
+		bson.M{"$inc": bson.M{"value": delta}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to increment metric %s: %w", name, err)
+	}
+	return nil
+}
+
+// FindMetric retrieves the named metric.
+func (r *systemMetricsRepository) FindMetric(ctx context.Context, name string) (*domain.SystemMetrics, error) {
+	var metric domain.SystemMetrics
+	err := r.collection.FindOne(ctx, bson.M{"name": name}).Decode(&metric)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return &domain.SystemMetrics{Name: name}, nil
+		}
+		return nil, fmt.Errorf("failed to find metric %s: %w", name, err)
+	}
+	return &metric, nil
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"strconv"
 
 	"lawnconnect-api/internal/core/apperror"
 	"lawnconnect-api/internal/core/domain"
@@ -15,8 +16,10 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, user *domain.User) error
 	FindUserByEmail(ctx context.Context, email string) (*domain.User, error)
 	FindUserByID(ctx context.Context, id primitive.ObjectID) (*domain.User, error)
-	FindUserByResetToken(ctx context.Context, token string) (*domain.User, error)
 	UpdateUser(ctx context.Context, id primitive.ObjectID, update primitive.M) error
+	// RecordRating counts a rating of 1 to 5 into the user's RatingSummary,
+	// recomputing its average in the same atomic update.
+	RecordRating(ctx context.Context, id primitive.ObjectID, rating int) error
 }
 
 type userRepository struct {
@@ -28,16 +31,25 @@ func NewUserRepository(db *mongo.Database) UserRepository {
 	return &userRepository{collection: db.Collection("users")}
 }
 
-// CreateUser saves a new user to the database.
+// CreateUser saves a new user to the database. The unique index on email is the
+// source of truth for duplicate accounts, so a duplicate-key error is reported as
+// apperror.DuplicateError.
 func (r *userRepository) CreateUser(ctx context.Context, user *domain.User) error {
+	user.Email = domain.NormalizeEmail(user.Email)
 	_, err := r.collection.InsertOne(ctx, user)
-	return err
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return apperror.DuplicateError{Resource: "User with this email"}
+		}
+		return err
+	}
+	return nil
 }
 
 // FindUserByEmail retrieves a user by their email address.
 func (r *userRepository) FindUserByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
-	filter := primitive.M{"email": email}
+	filter := primitive.M{"email": domain.NormalizeEmail(email)}
 	err := r.collection.FindOne(ctx, filter).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
@@ -62,23 +74,37 @@ func (r *userRepository) FindUserByID(ctx context.Context, id primitive.ObjectID
 	return &user, nil
 }
 
-// FindUserByResetToken retrieves a user by their password reset token.
-func (r *userRepository) FindUserByResetToken(ctx context.Context, token string) (*domain.User, error) {
-	var user domain.User
-	filter := primitive.M{"resetToken": token}
-	err := r.collection.FindOne(ctx, filter).Decode(&user)
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, apperror.NotFound{Resource: "User"}
-		}
-		return nil, err
-	}
-	return &user, nil
-}
-
-// UpdateUser updates a user's document with the provided BSON update.
+// UpdateUser updates a user's document with the provided BSON update. An email
+// another user already has is rejected by the unique index and returned as
+// apperror.DuplicateError.
 func (r *userRepository) UpdateUser(ctx context.Context, id primitive.ObjectID, update primitive.M) error {
 	filter := primitive.M{"_id": id}
 	_, err := r.collection.UpdateOne(ctx, filter, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return apperror.DuplicateError{Resource: "User with this email"}
+	}
+	return err
+}
+
+// RecordRating updates the user's rating summary with an update pipeline, so
+// the count, distribution and average change together.
+func (r *userRepository) RecordRating(ctx context.Context, id primitive.ObjectID, rating int) error {
+	increment := func(field string, by int) primitive.M {
+		return primitive.M{"$add": primitive.A{primitive.M{"$ifNull": primitive.A{"$" + field, 0}}, by}}
+	}
+	bucket := "ratingSummary.distribution." + strconv.Itoa(rating)
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: primitive.M{
+			"ratingSummary.count": increment("ratingSummary.count", 1),
+			"ratingSummary.total": increment("ratingSummary.total", rating),
+			bucket:                increment(bucket, 1),
+		}}},
+		{{Key: "$set", Value: primitive.M{
+			"ratingSummary.average": primitive.M{"$round": primitive.A{
+				primitive.M{"$divide": primitive.A{"$ratingSummary.total", "$ratingSummary.count"}}, 2,
+			}},
+		}}},
+	}
+	_, err := r.collection.UpdateOne(ctx, primitive.M{"_id": id}, pipeline)
 	return err
 }
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrTransactionsUnsupported is returned by NewTransactor when the server cannot
+// run multi-document transactions and non-atomic writes were not allowed.
+var ErrTransactionsUnsupported = errors.New("MongoDB is a standalone server without multi-document transactions; use a replica set or set MONGO_ALLOW_NON_ATOMIC=true")
+
+// Transactor runs a function atomically. Repository calls made with the context
+// passed to fn take part in the transaction.
+type Transactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type mongoTransactor struct {
+	client    *mongo.Client
+	supported bool
+}
+
+// NewTransactor creates a Transactor backed by MongoDB multi-document
+// transactions. Transactions need a replica set or sharded cluster. Against a
+// standalone server it returns ErrTransactionsUnsupported, unless allowNonAtomic
+// is set (for local development), in which case fn runs without a transaction
+// and a warning is logged.
+func NewTransactor(ctx context.Context, client *mongo.Client, allowNonAtomic bool) (Transactor, error) {
+	supported, err := detectSupport(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	t := &mongoTransactor{client: client, supported: supported}
+	if !t.supported {
+		if !allowNonAtomic {
+			return nil, ErrTransactionsUnsupported
+		}
+		slog.Warn("MongoDB is a standalone server; multi-document transactions are disabled and writes are not atomic")
+	}
+	return t, nil
+}
+
+func (t *mongoTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !t.supported {
+		return fn(ctx)
+	}
+
+	session, err := t.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
+
+// detectSupport reports whether the server is a replica set member or mongos.
+func detectSupport(ctx context.Context, client *mongo.Client) (bool, error) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		return false, fmt.Errorf("failed to detect MongoDB topology: %w", err)
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid", nil
+}
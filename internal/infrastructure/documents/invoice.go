@@ -0,0 +1,139 @@
+// Package documents renders customer-facing documents such as invoices.
+package documents
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+
+	"lawnconnect-api/internal/core/domain"
+)
+
+// Issuer is the business named on invoices.
+type Issuer struct {
+	Name    string
+	Address string
+}
+
+const dateLayout = "Jan 2, 2006"
+
+// maxDescriptionLines keeps long booking descriptions on the single invoice page.
+const maxDescriptionLines = 12
+
+var invoiceTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"money": domain.FormatMinorUnits,
+	"date":  func(t interface{ Format(string) string }) string { return t.Format(dateLayout) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Invoice {{.Invoice.Number}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; color: #222; max-width: 720px; margin: 2em auto; }
+header { display: flex; justify-content: space-between; }
+table { width: 100%; border-collapse: collapse; margin-top: 2em; }
+th, td { text-align: left; padding: 0.5em; border-bottom: 1px solid #ddd; }
+td.amount, th.amount { text-align: right; }
+.paid { color: #2e7d32; font-weight: bold; }
+</style>
+</head>
+<body>
+<header>
+  <div>
+    <h1>{{.Issuer.Name}}</h1>
+    {{if .Issuer.Address}}<p>{{.Issuer.Address}}</p>{{end}}
+  </div>
+  <div>
+    <h2>Invoice</h2>
+    <p>Number: {{.Invoice.Number}}<br>Issued: {{date .Invoice.IssuedAt}}</p>
+    {{if .Invoice.PaidAt}}<p class="paid">Paid {{date .Invoice.PaidAt}}</p>{{end}}
+  </div>
+</header>
+<section>
+  <h3>Bill to</h3>
+  <p>{{.Invoice.CustomerName}}<br>{{.Invoice.ServiceAddress}}</p>
+</section>
+<table>
+  <thead><tr><th>Description</th><th>Service date</th><th class="amount">Amount</th></tr></thead>
+  <tbody>
+    <tr>
+      <td>Lawn mowing by {{.Invoice.MowerName}}{{if .Invoice.Description}}<br><small>{{.Invoice.Description}}</small>{{end}}</td>
+      <td>{{.Invoice.ServiceDate}}</td>
+      <td class="amount">{{money .Invoice.Amount .Invoice.Currency}}</td>
+    </tr>
+  </tbody>
+  <tfoot><tr><th colspan="2">Total</th><th class="amount">{{money .Invoice.Amount .Invoice.Currency}}</th></tr></tfoot>
+</table>
+<p>Booking reference: {{.Invoice.BookingID.Hex}}</p>
+</body>
+</html>
+`))
+
+// InvoiceHTML renders an invoice as a standalone HTML page.
+func InvoiceHTML(invoice *domain.Invoice, issuer Issuer) ([]byte, error) {
+	var buf bytes.Buffer
+	data := struct {
+		Invoice *domain.Invoice
+		Issuer  Issuer
+	}{invoice, issuer}
+	if err := invoiceTemplate.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render invoice %s: %w", invoice.Number, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// InvoicePDF renders an invoice as a single-page PDF.
+func InvoicePDF(invoice *domain.Invoice, issuer Issuer) ([]byte, error) {
+	p := &pdfPage{title: "Invoice " + invoice.Number}
+	const left, right, column = 56.0, 556.0, 380.0
+
+	p.text(left, 730, 20, true, issuer.Name)
+	y := 712.0
+	for _, line := range wrap(issuer.Address, 45) {
+		p.text(left, y, 10, false, line)
+		y -= 13
+	}
+
+	p.text(column, 730, 16, true, "INVOICE")
+	p.text(column, 712, 10, false, "Number: "+invoice.Number)
+	p.text(column, 699, 10, false, "Issued: "+invoice.IssuedAt.Format(dateLayout))
+	if invoice.PaidAt != nil {
+		p.text(column, 680, 12, true, "PAID "+invoice.PaidAt.Format(dateLayout))
+	}
+
+	p.text(left, 640, 11, true, "Bill to")
+	p.text(left, 625, 10, false, invoice.CustomerName)
+	y = 612
+	for _, line := range wrap(invoice.ServiceAddress, 60) {
+		p.text(left, y, 10, false, line)
+		y -= 13
+	}
+
+	y = 560
+	p.text(left, y, 10, true, "Description")
+	p.text(320, y, 10, true, "Service date")
+	p.text(470, y, 10, true, "Amount")
+	p.rule(left, right, y-6)
+
+	y -= 22
+	p.text(left, y, 10, false, "Lawn mowing by "+invoice.MowerName)
+	p.text(320, y, 10, false, invoice.ServiceDate)
+	p.text(470, y, 10, false, domain.FormatMinorUnits(invoice.Amount, invoice.Currency))
+	description := wrap(invoice.Description, 50)
+	if len(description) > maxDescriptionLines {
+		description = append(description[:maxDescriptionLines-1], "...")
+	}
+	for _, line := range description {
+		y -= 13
+		p.text(left+10, y, 9, false, line)
+	}
+
+	y -= 14
+	p.rule(left, right, y)
+	y -= 18
+	p.text(320, y, 11, true, "Total")
+	p.text(470, y, 11, true, domain.FormatMinorUnits(invoice.Amount, invoice.Currency))
+
+	p.text(left, 72, 9, false, "Booking reference: "+invoice.BookingID.Hex())
+	return p.bytes(), nil
+}
@@ -0,0 +1,191 @@
+package documents
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"lawnconnect-api/internal/core/domain"
+)
+
+// Originator is the platform's company and bank as named in NACHA files.
+type Originator struct {
+	CompanyName string
+	// CompanyID is the ten-character originator ID assigned by the bank, usually
+	// "1" followed by the company's EIN.
+	CompanyID string
+	// BankRouting is the nine-digit routing number of the platform's bank, which
+	// receives the file and originates the transfers.
+	BankRouting string
+	BankName    string
+}
+
+// Configured reports whether NACHA files can be produced.
+func (o Originator) Configured() bool {
+	return o.CompanyID != "" && o.BankRouting != ""
+}
+
+// PayoutCSV renders a batch's payouts as a CSV with one row per transfer, for
+// banks that import spreadsheets. Amounts are decimal major units.
+func PayoutCSV(batch *domain.PayoutBatch, requests []*domain.PayoutRequest) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"batch_id", "payout_id", "mower_id", "account_holder", "routing_number", "account_number", "account_type", "amount", "currency"})
+	for _, request := range requests {
+		d := request.Destination
+		w.Write([]string{
+			batch.ID.Hex(),
+			request.ID.Hex(),
+			request.MowerID.Hex(),
+			d.AccountHolder,
+			d.RoutingNumber,
+			d.AccountNumber,
+			d.AccountType,
+			fmt.Sprintf("%d.%02d", request.Amount/100, request.Amount%100),
+			request.Currency,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write payout batch %s as CSV: %w", batch.ID.Hex(), err)
+	}
+	return buf.Bytes(), nil
+}
+
+// NACHA record layout constants.
+const (
+	nachaRecordSize   = 94
+	nachaBlockingSize = 10
+	// Service class 220 is a batch of credits only.
+	nachaCreditsOnly     = "220"
+	nachaCheckingCredit  = "22"
+	nachaSavingsCredit   = "32"
+	nachaEntryDesc       = "PAYOUT"
+	nachaPrearrangedPPD  = "PPD"
+	nachaBatchNumber     = 1
+	nachaFileIDModifier  = "A"
+	nachaOriginatorCode  = "1"
+	nachaFormatCode      = "1"
+	nachaPriorityCode    = "01"
+	nachaBlockingFactor  = "10"
+	nachaRecordSizeField = "094"
+)
+
+// PayoutNACHA renders a batch's payouts as a NACHA (ACH) file with one PPD
+// batch of credits, dated now and settling on the next day. All payouts must be
+// in US dollars.
+func PayoutNACHA(batch *domain.PayoutBatch, requests []*domain.PayoutRequest, originator Originator, now time.Time) ([]byte, error) {
+	if !originator.Configured() {
+		return nil, fmt.Errorf("NACHA originator is not configured")
+	}
+	if !domain.ValidRoutingNumber(originator.BankRouting) {
+		return nil, fmt.Errorf("NACHA originator bank routing number %q is invalid", originator.BankRouting)
+	}
+	odfi := originator.BankRouting[:8]
+	batchNumber := fmt.Sprintf("%07d", nachaBatchNumber)
+
+	var records []string
+	records = append(records, "1"+nachaPriorityCode+
+		" "+originator.BankRouting+
+		nachaField(originator.CompanyID, 10)+
+		now.Format("060102")+now.Format("1504")+
+		nachaFileIDModifier+nachaRecordSizeField+nachaBlockingFactor+nachaFormatCode+
+		nachaField(originator.BankName, 23)+
+		nachaField(originator.CompanyName, 23)+
+		nachaField(batch.ID.Hex(), 8))
+
+	records = append(records, "5"+nachaCreditsOnly+
+		nachaField(originator.CompanyName, 16)+
+		nachaField("", 20)+
+		nachaField(originator.CompanyID, 10)+
+		nachaPrearrangedPPD+
+		nachaField(nachaEntryDesc, 10)+
+		now.Format("060102")+
+		now.AddDate(0, 0, 1).Format("060102")+
+		"   "+nachaOriginatorCode+odfi+batchNumber)
+
+	var hash, total int64
+	for i, request := range requests {
+		d := request.Destination
+		if request.Currency != "USD" {
+			return nil, fmt.Errorf("payout %s is in %s; NACHA files carry US dollars only", request.ID.Hex(), request.Currency)
+		}
+		if !domain.ValidRoutingNumber(d.RoutingNumber) {
+			return nil, fmt.Errorf("payout %s has an invalid routing number", request.ID.Hex())
+		}
+		code := nachaCheckingCredit
+		if d.AccountType == domain.BankAccountSavings {
+			code = nachaSavingsCredit
+		}
+		rdfi, _ := strconv.ParseInt(d.RoutingNumber[:8], 10, 64)
+		hash += rdfi
+		total += request.Amount
+
+		id := request.ID.Hex()
+		records = append(records, "6"+code+
+			d.RoutingNumber+
+			nachaField(d.AccountNumber, 17)+
+			fmt.Sprintf("%010d", request.Amount)+
+			nachaField(id[len(id)-15:], 15)+
+			nachaField(d.AccountHolder, 22)+
+			"  0"+
+			odfi+fmt.Sprintf("%07d", i+1))
+	}
+
+	hashField := fmt.Sprintf("%010d", hash%10_000_000_000)
+	entries := len(requests)
+	records = append(records, "8"+nachaCreditsOnly+
+		fmt.Sprintf("%06d", entries)+
+		hashField+
+		fmt.Sprintf("%012d", 0)+
+		fmt.Sprintf("%012d", total)+
+		nachaField(originator.CompanyID, 10)+
+		nachaField("", 25)+
+		odfi+batchNumber)
+
+	// The file control record counts blocks including itself and the padding.
+	blocks := (len(records) + 1 + nachaBlockingSize - 1) / nachaBlockingSize
+	records = append(records, "9"+
+		fmt.Sprintf("%06d", 1)+
+		fmt.Sprintf("%06d", blocks)+
+		fmt.Sprintf("%08d", entries)+
+		hashField+
+		fmt.Sprintf("%012d", 0)+
+		fmt.Sprintf("%012d", total)+
+		nachaField("", 39))
+	for len(records)%nachaBlockingSize != 0 {
+		records = append(records, strings.Repeat("9", nachaRecordSize))
+	}
+
+	var buf bytes.Buffer
+	for _, record := range records {
+		if len(record) != nachaRecordSize {
+			return nil, fmt.Errorf("NACHA record %q is %d characters, not %d", record[:1], len(record), nachaRecordSize)
+		}
+		buf.WriteString(record)
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// nachaField upper-cases s, drops characters banks reject, and pads or cuts it
+// to width.
+func nachaField(s string, width int) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == ' ', r == '-', r == '.', r == '&':
+			return r
+		default:
+			return -1
+		}
+	}, s)
+	if len(s) > width {
+		return s[:width]
+	}
+	return s + strings.Repeat(" ", width-len(s))
+}
@@ -0,0 +1,103 @@
+package documents
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// pdfPage builds a single US Letter page of text and rules using the standard
+// Helvetica fonts, which every PDF reader provides, so no fonts are embedded.
+// Coordinates are in points from the bottom-left corner.
+type pdfPage struct {
+	content bytes.Buffer
+	title   string
+}
+
+const (
+	pageWidth  = 612
+	pageHeight = 792
+)
+
+// text draws s with its baseline starting at (x, y).
+func (p *pdfPage) text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.content, "BT /%s %.1f Tf %.1f %.1f Td (%s) Tj ET\n", font, size, x, y, pdfString(s))
+}
+
+// rule draws a horizontal line from x1 to x2 at height y.
+func (p *pdfPage) rule(x1, x2, y float64) {
+	fmt.Fprintf(&p.content, "0.5 w %.1f %.1f m %.1f %.1f l S\n", x1, y, x2, y)
+}
+
+// bytes returns the complete PDF document.
+func (p *pdfPage) bytes() []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>", pageWidth, pageHeight),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()),
+		fmt.Sprintf("<< /Title (%s) /Producer (LawnConnect) >>", pdfString(p.title)),
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, len(objects), xref)
+	return out.Bytes()
+}
+
+// winAnsi encodes text for the WinAnsiEncoding fonts, replacing characters the
+// encoding lacks.
+var winAnsi = encoding.ReplaceUnsupported(charmap.Windows1252.NewEncoder())
+
+// pdfString encodes s as the body of a PDF literal string.
+func pdfString(s string) string {
+	encoded, err := winAnsi.String(s)
+	if err != nil {
+		encoded = s
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`, "\r", " ", "\n", " ")
+	return replacer.Replace(encoded)
+}
+
+// wrap splits s into lines of at most width characters, breaking at spaces.
+func wrap(s string, width int) []string {
+	var (
+		lines []string
+		line  string
+	)
+	for _, word := range strings.Fields(s) {
+		switch {
+		case line == "":
+			line = word
+		case len(line)+1+len(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,173 @@
+// Package health checks the API's dependencies for the liveness and readiness
+// endpoints.
+package health
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// Dependency statuses reported by Readiness.
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusDisabled = "disabled"
+)
+
+// defaultTimeout bounds a dependency check that does not set its own timeout.
+const defaultTimeout = 2 * time.Second
+
+// Dependency is an external system the API relies on.
+type Dependency struct {
+	Name string
+	// Critical dependencies make the API not ready when they are down. Others are
+	// reported but only degrade the service.
+	Critical bool
+	Timeout  time.Duration
+	// Check returns nil when the dependency is healthy. A nil Check reports the
+	// dependency as disabled.
+	Check func(ctx context.Context) error
+}
+
+// DependencyStatus is the result of checking one dependency.
+type DependencyStatus struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	LatencyMS int64  `json:"latencyMs"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report is the readiness of the API and the status of each dependency.
+type Report struct {
+	Ready        bool               `json:"ready"`
+	ShuttingDown bool               `json:"shuttingDown"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+	CheckedAt    time.Time          `json:"checkedAt"`
+}
+
+// Reporter checks a fixed set of dependencies.
+type Reporter struct {
+	dependencies []Dependency
+	shuttingDown atomic.Bool
+}
+
+// NewReporter creates a Reporter for the given dependencies.
+func NewReporter(dependencies ...Dependency) *Reporter {
+	return &Reporter{dependencies: dependencies}
+}
+
+// SetShuttingDown marks the API as draining so readiness fails and load balancers
+// stop routing new traffic to this instance.
+func (r *Reporter) SetShuttingDown() {
+	r.shuttingDown.Store(true)
+}
+
+// Readiness checks every dependency concurrently, each under its own timeout.
+func (r *Reporter) Readiness(ctx context.Context) Report {
+	report := Report{
+		Ready:        true,
+		ShuttingDown: r.shuttingDown.Load(),
+		Dependencies: make([]DependencyStatus, len(r.dependencies)),
+		CheckedAt:    time.Now(),
+	}
+
+	var wg sync.WaitGroup
+	for i, dep := range r.dependencies {
+		wg.Add(1)
+		go func(i int, dep Dependency) {
+			defer wg.Done()
+			report.Dependencies[i] = check(ctx, dep)
+		}(i, dep)
+	}
+	wg.Wait()
+
+	if report.ShuttingDown {
+		report.Ready = false
+	}
+	for _, status := range report.Dependencies {
+		if status.Critical && status.Status == StatusDown {
+			report.Ready = false
+		}
+	}
+	return report
+}
+
+func check(ctx context.Context, dep Dependency) DependencyStatus {
+	status := DependencyStatus{Name: dep.Name, Critical: dep.Critical}
+	if dep.Check == nil {
+		status.Status = StatusDisabled
+		return status
+	}
+
+	timeout := dep.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := dep.Check(checkCtx)
+	status.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		status.Status = StatusDown
+		status.Error = err.Error()
+		return status
+	}
+	status.Status = StatusUp
+	return status
+}
+
+// MongoCheck pings the primary of the MongoDB deployment.
+func MongoCheck(client *mongo.Client) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return client.Ping(ctx, readpref.Primary())
+	}
+}
+
+// SMTPCheck connects to the SMTP server and waits for its 220 greeting. Servers
+// using implicit TLS do not greet before the handshake, so an open connection
+// that stays silent until the deadline is also treated as reachable.
+func SMTPCheck(host string, port int) func(ctx context.Context) error {
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	return func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return fmt.Errorf("cannot connect to %s: %w", addr, err)
+		}
+		defer conn.Close()
+
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			deadline = time.Now().Add(defaultTimeout)
+		}
+		// Leave part of the budget so a silent TLS server is not reported as a timeout.
+		conn.SetReadDeadline(time.Now().Add(time.Until(deadline) / 2))
+
+		greeting, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return nil
+			}
+			return fmt.Errorf("no greeting from %s: %w", addr, err)
+		}
+		if !strings.HasPrefix(greeting, "220") {
+			return fmt.Errorf("unexpected greeting from %s: %q", addr, strings.TrimSpace(greeting))
+		}
+		return nil
+	}
+}
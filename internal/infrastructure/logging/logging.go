@@ -0,0 +1,77 @@
+// Package logging configures the API's structured logger and carries a
+// request-scoped logger through context.Context.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Config selects the output format and minimum level of the logger.
+type Config struct {
+	// Format is "json" or "text".
+	Format string
+	// Level is "debug", "info", "warn" or "error".
+	Level string
+}
+
+// New creates a logger that writes to w and redacts personal data and secrets.
+func New(w io.Writer, cfg Config) (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q", cfg.Level)
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	case "text", "":
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("invalid log format %q (expected json or text)", cfg.Format)
+	}
+
+	return slog.New(NewRedactingHandler(handler)), nil
+}
+
+type loggerKey struct{}
+
+// requestAttrs collects attributes discovered while a request is being handled
+// (such as the authenticated user) so the access log line can include them.
+type requestAttrs struct {
+	attrs []any
+}
+
+type requestAttrsKey struct{}
+
+func contextWithRequestAttrs(ctx context.Context, collected *requestAttrs) context.Context {
+	return context.WithValue(ctx, requestAttrsKey{}, collected)
+}
+
+// WithLogger returns a context carrying logger.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the request-scoped logger, or the default logger when the
+// context does not carry one.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// With adds attributes to the context's logger and to the request's access log
+// entry, returning the updated context.
+func With(ctx context.Context, args ...any) context.Context {
+	if collected, ok := ctx.Value(requestAttrsKey{}).(*requestAttrs); ok {
+		collected.attrs = append(collected.attrs, args...)
+	}
+	return WithLogger(ctx, FromContext(ctx).With(args...))
+}
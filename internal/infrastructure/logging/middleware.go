@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// RequestIDHeader carries the request ID in requests and responses.
+const RequestIDHeader = "X-Request-ID"
+
+// Middleware attaches a request-scoped logger carrying the request ID to every
+// request and writes one structured access log entry when the request completes.
+// It must run after chi's middleware.RequestID.
+func Middleware(logger *slog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			requestID := middleware.GetReqID(r.Context())
+			if requestID != "" {
+				w.Header().Set(RequestIDHeader, requestID)
+			}
+
+			collected := &requestAttrs{}
+			ctx := WithLogger(r.Context(), logger.With("request_id", requestID))
+			ctx = contextWithRequestAttrs(ctx, collected)
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			route := r.URL.Path
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+
+			attrs := append([]any{
+				"request_id", requestID,
+				"method", r.Method,
+				"route", route,
+				"status", status,
+				"bytes", ww.BytesWritten(),
+				"duration_ms", time.Since(start).Milliseconds(),
+				"remote_ip", r.RemoteAddr,
+			}, collected.attrs...)
+			logger.Log(r.Context(), level, "request completed", attrs...)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute key fragments whose values are always redacted.
+var sensitiveKeys = []string{
+	"email", "password", "token", "secret", "authorization", "address", "phone", "cookie",
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	tokenPattern = regexp.MustCompile(`(?i)((?:token|password|secret)=)[^&\s"]+`)
+)
+
+// RedactingHandler wraps a slog.Handler and removes personal data and secrets:
+// values of sensitive attribute keys are replaced entirely, and email addresses
+// or token query parameters embedded in messages and other strings are masked.
+type RedactingHandler struct {
+	inner slog.Handler
+}
+
+// NewRedactingHandler wraps inner with redaction.
+func NewRedactingHandler(inner slog.Handler) *RedactingHandler {
+	return &RedactingHandler{inner: inner}
+}
+
+// Enabled reports whether the wrapped handler handles records at level.
+func (h *RedactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle redacts the record and passes it to the wrapped handler.
+func (h *RedactingHandler) Handle(ctx context.Context, r slog.Record) error {
+	clean := slog.NewRecord(r.Time, r.Level, scrub(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		clean.AddAttrs(redactAttr(a))
+		return true
+	})
+	return h.inner.Handle(ctx, clean)
+}
+
+// WithAttrs redacts attrs before attaching them to the wrapped handler.
+func (h *RedactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clean := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		clean[i] = redactAttr(a)
+	}
+	return &RedactingHandler{inner: h.inner.WithAttrs(clean)}
+}
+
+// WithGroup opens a group on the wrapped handler.
+func (h *RedactingHandler) WithGroup(name string) slog.Handler {
+	return &RedactingHandler{inner: h.inner.WithGroup(name)}
+}
+
+func redactAttr(a slog.Attr) slog.Attr {
+	value := a.Value.Resolve()
+
+	if value.Kind() == slog.KindGroup {
+		group := value.Group()
+		clean := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			clean[i] = redactAttr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(clean...)}
+	}
+
+	if isSensitiveKey(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, scrub(value.String()))
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			return slog.String(a.Key, scrub(err.Error()))
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: value}
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	if key == "to" {
+		return true
+	}
+	for _, fragment := range sensitiveKeys {
+		if strings.Contains(key, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// scrub masks email addresses and secret-bearing query parameters in free text.
+func scrub(s string) string {
+	s = emailPattern.ReplaceAllString(s, "[REDACTED_EMAIL]")
+	return tokenPattern.ReplaceAllString(s, "${1}"+redacted)
+}
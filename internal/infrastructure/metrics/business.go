@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// BookingCounter reports how many bookings are in each status.
+type BookingCounter interface {
+	CountBookingsByStatus(ctx context.Context) (map[string]int64, error)
+}
+
+// bookingStatuses are always exported, so a status with no bookings reads 0
+// instead of disappearing from dashboards.
+var bookingStatuses = []string{"pending", "accepted", "ongoing", "completed", "cancelled", "rejected"}
+
+// bookingCollector reads booking counts from the database on every scrape, so the
+// gauges are never stale and cost nothing between scrapes.
+type bookingCollector struct {
+	counter BookingCounter
+	timeout time.Duration
+	desc    *prometheus.Desc
+	up      *prometheus.Desc
+}
+
+// RegisterBookingGauges exports lawnconnect_bookings{status}, the number of
+// bookings currently in each status (pending bookings are status="pending").
+func (m *Metrics) RegisterBookingGauges(counter BookingCounter) {
+	m.Register(&bookingCollector{
+		counter: counter,
+		timeout: 5 * time.Second,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "bookings"),
+			"Number of bookings by status.",
+			[]string{"status"}, nil,
+		),
+		up: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "bookings_scrape_success"),
+			"Whether the booking counts could be read during this scrape.",
+			nil, nil,
+		),
+	})
+}
+
+func (c *bookingCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+	ch <- c.up
+}
+
+func (c *bookingCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	counts, err := c.counter.CountBookingsByStatus(ctx)
+	if err != nil {
+		slog.Error("counting bookings for metrics failed", "error", err)
+		ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, 0)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, 1)
+
+	for _, status := range bookingStatuses {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(counts[status]), status)
+	}
+	for status, n := range counts {
+		if !isKnownStatus(status) {
+			ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(n), status)
+		}
+	}
+}
+
+func isKnownStatus(status string) bool {
+	for _, known := range bookingStatuses {
+		if known == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+// Package metrics exposes the API's Prometheus metrics: HTTP traffic, MongoDB
+// operation latencies, email, upload, payment, geocoding and distance matrix
+// outcomes, and business gauges that are read from the database when /metrics
+// is scraped.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "lawnconnect"
+
+// Metrics owns a Prometheus registry and the API's collectors. Each Metrics has
+// its own registry so several API instances (e.g. in tests) can coexist.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests   *prometheus.CounterVec
+	httpDuration   *prometheus.HistogramVec
+	dbDuration     *prometheus.HistogramVec
+	emailsSent     *prometheus.CounterVec
+	uploadSize     prometheus.Histogram
+	uploadDuration *prometheus.HistogramVec
+	payments       *prometheus.CounterVec
+	geocoding      *prometheus.CounterVec
+	distanceMatrix *prometheus.CounterVec
+}
+
+// New creates a Metrics with the Go runtime and process collectors registered.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route pattern and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		dbDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_operation_duration_seconds",
+			Help:      "MongoDB operation latency by repository, method and outcome.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"repository", "method", "outcome"}),
+		emailsSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "emails_sent_total",
+			Help:      "Email send attempts by template and outcome.",
+		}, []string{"template", "outcome"}),
+		uploadSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "upload_size_bytes",
+			Help:      "Size of uploaded files.",
+			Buckets:   prometheus.ExponentialBuckets(16*1024, 4, 8), // 16KiB .. 256MiB
+		}),
+		uploadDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "upload_duration_seconds",
+			Help:      "File upload latency by outcome.",
+			Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+		}, []string{"outcome"}),
+		payments: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "payment_operations_total",
+			Help:      "Payment provider calls by provider, operation and outcome (success, declined or error).",
+		}, []string{"provider", "operation", "outcome"}),
+		geocoding: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "geocoding_requests_total",
+			Help:      "Geocoder lookups by geocoder and outcome (success, not_found or error).",
+		}, []string{"geocoder", "outcome"}),
+		distanceMatrix: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "distance_matrix_requests_total",
+			Help:      "Distance matrix lookups by provider and outcome (success or error).",
+		}, []string{"provider", "outcome"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.dbDuration,
+		m.emailsSent,
+		m.uploadSize,
+		m.uploadDuration,
+		m.payments,
+		m.geocoding,
+		m.distanceMatrix,
+	)
+	return m
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Register adds extra collectors to the registry.
+func (m *Metrics) Register(cs ...prometheus.Collector) {
+	m.registry.MustRegister(cs...)
+}
+
+// outcome classifies an error for the "outcome" label.
+func outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
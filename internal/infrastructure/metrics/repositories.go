@@ -0,0 +1,646 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"lawnconnect-api/internal/core/apperror"
+	"lawnconnect-api/internal/core/domain"
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// observeDB records the latency of a repository method. A missing document is a
+// normal result, so it is reported separately from errors.
+func (m *Metrics) observeDB(repository, method string, start time.Time, err error) {
+	result := outcome(err)
+	if _, ok := err.(apperror.NotFound); ok {
+		result = "not_found"
+	}
+	m.dbDuration.WithLabelValues(repository, method, result).Observe(time.Since(start).Seconds())
+}
+
+type userRepository struct {
+	next    repositories.UserRepository
+	metrics *Metrics
+}
+
+// InstrumentUserRepository wraps repo so every call is timed.
+func InstrumentUserRepository(repo repositories.UserRepository, m *Metrics) repositories.UserRepository {
+	return &userRepository{next: repo, metrics: m}
+}
+
+func (r *userRepository) CreateUser(ctx context.Context, user *domain.User) error {
+	start := time.Now()
+	err := r.next.CreateUser(ctx, user)
+	r.metrics.observeDB("users", "CreateUser", start, err)
+	return err
+}
+
+func (r *userRepository) FindUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	start := time.Now()
+	user, err := r.next.FindUserByEmail(ctx, email)
+	r.metrics.observeDB("users", "FindUserByEmail", start, err)
+	return user, err
+}
+
+func (r *userRepository) FindUserByID(ctx context.Context, id primitive.ObjectID) (*domain.User, error) {
+	start := time.Now()
+	user, err := r.next.FindUserByID(ctx, id)
+	r.metrics.observeDB("users", "FindUserByID", start, err)
+	return user, err
+}
+
+func (r *userRepository) UpdateUser(ctx context.Context, id primitive.ObjectID, update primitive.M) error {
+	start := time.Now()
+	err := r.next.UpdateUser(ctx, id, update)
+	r.metrics.observeDB("users", "UpdateUser", start, err)
+	return err
+}
+
+func (r *userRepository) RecordRating(ctx context.Context, id primitive.ObjectID, rating int) error {
+	start := time.Now()
+	err := r.next.RecordRating(ctx, id, rating)
+	r.metrics.observeDB("users", "RecordRating", start, err)
+	return err
+}
+
+type bookingRepository struct {
+	next    repositories.BookingRepository
+	metrics *Metrics
+}
+
+// InstrumentBookingRepository wraps repo so every call is timed.
+func InstrumentBookingRepository(repo repositories.BookingRepository, m *Metrics) repositories.BookingRepository {
+	return &bookingRepository{next: repo, metrics: m}
+}
+
+func (r *bookingRepository) CreateBooking(ctx context.Context, booking *domain.Booking) error {
+	start := time.Now()
+	err := r.next.CreateBooking(ctx, booking)
+	r.metrics.observeDB("bookings", "CreateBooking", start, err)
+	return err
+}
+
+func (r *bookingRepository) FindBookingByID(ctx context.Context, bookingID primitive.ObjectID) (*domain.Booking, error) {
+	start := time.Now()
+	booking, err := r.next.FindBookingByID(ctx, bookingID)
+	r.metrics.observeDB("bookings", "FindBookingByID", start, err)
+	return booking, err
+}
+
+func (r *bookingRepository) FindBookingsByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.Booking, error) {
+	start := time.Now()
+	bookings, err := r.next.FindBookingsByUserID(ctx, userID)
+	r.metrics.observeDB("bookings", "FindBookingsByUserID", start, err)
+	return bookings, err
+}
+
+func (r *bookingRepository) FindPendingBookingsNear(ctx context.Context, origin domain.GeoPoint, radiusKm float64, areaIDs []primitive.ObjectID) ([]*domain.Booking, error) {
+	start := time.Now()
+	bookings, err := r.next.FindPendingBookingsNear(ctx, origin, radiusKm, areaIDs)
+	r.metrics.observeDB("bookings", "FindPendingBookingsNear", start, err)
+	return bookings, err
+}
+
+func (r *bookingRepository) FindWaitlistedBookingsWithin(ctx context.Context, boundary domain.GeoPolygon) ([]*domain.Booking, error) {
+	start := time.Now()
+	bookings, err := r.next.FindWaitlistedBookingsWithin(ctx, boundary)
+	r.metrics.observeDB("bookings", "FindWaitlistedBookingsWithin", start, err)
+	return bookings, err
+}
+
+func (r *bookingRepository) FindScheduledBookingsForMower(ctx context.Context, mowerID primitive.ObjectID, date string) ([]*domain.Booking, error) {
+	start := time.Now()
+	bookings, err := r.next.FindScheduledBookingsForMower(ctx, mowerID, date)
+	r.metrics.observeDB("bookings", "FindScheduledBookingsForMower", start, err)
+	return bookings, err
+}
+
+func (r *bookingRepository) UpdateBooking(ctx context.Context, bookingID primitive.ObjectID, update bson.M) error {
+	start := time.Now()
+	err := r.next.UpdateBooking(ctx, bookingID, update)
+	r.metrics.observeDB("bookings", "UpdateBooking", start, err)
+	return err
+}
+
+func (r *bookingRepository) UpdateBookingIfStatus(ctx context.Context, bookingID primitive.ObjectID, status, quoteStatus string, update bson.M) error {
+	start := time.Now()
+	err := r.next.UpdateBookingIfStatus(ctx, bookingID, status, quoteStatus, update)
+	r.metrics.observeDB("bookings", "UpdateBookingIfStatus", start, err)
+	return err
+}
+
+func (r *bookingRepository) AddBookingComment(ctx context.Context, bookingID primitive.ObjectID, comment *domain.BookingComment, limit int) error {
+	start := time.Now()
+	err := r.next.AddBookingComment(ctx, bookingID, comment, limit)
+	r.metrics.observeDB("bookings", "AddBookingComment", start, err)
+	return err
+}
+
+func (r *bookingRepository) UpdateBookingComment(ctx context.Context, bookingID, commentID primitive.ObjectID, set bson.M) error {
+	start := time.Now()
+	err := r.next.UpdateBookingComment(ctx, bookingID, commentID, set)
+	r.metrics.observeDB("bookings", "UpdateBookingComment", start, err)
+	return err
+}
+
+func (r *bookingRepository) DeleteBookingComment(ctx context.Context, bookingID, commentID primitive.ObjectID) error {
+	start := time.Now()
+	err := r.next.DeleteBookingComment(ctx, bookingID, commentID)
+	r.metrics.observeDB("bookings", "DeleteBookingComment", start, err)
+	return err
+}
+
+func (r *bookingRepository) CountBookingsByStatus(ctx context.Context) (map[string]int64, error) {
+	start := time.Now()
+	counts, err := r.next.CountBookingsByStatus(ctx)
+	r.metrics.observeDB("bookings", "CountBookingsByStatus", start, err)
+	return counts, err
+}
+
+type ledgerRepository struct {
+	next    repositories.LedgerRepository
+	metrics *Metrics
+}
+
+// InstrumentLedgerRepository wraps repo so every call is timed.
+func InstrumentLedgerRepository(repo repositories.LedgerRepository, m *Metrics) repositories.LedgerRepository {
+	return &ledgerRepository{next: repo, metrics: m}
+}
+
+func (r *ledgerRepository) InsertEntries(ctx context.Context, entries []*domain.LedgerEntry) error {
+	start := time.Now()
+	err := r.next.InsertEntries(ctx, entries)
+	r.metrics.observeDB("ledger", "InsertEntries", start, err)
+	return err
+}
+
+func (r *ledgerRepository) FindEntriesByAccount(ctx context.Context, account string) ([]*domain.LedgerEntry, error) {
+	start := time.Now()
+	entries, err := r.next.FindEntriesByAccount(ctx, account)
+	r.metrics.observeDB("ledger", "FindEntriesByAccount", start, err)
+	return entries, err
+}
+
+func (r *ledgerRepository) SumByAccount(ctx context.Context, account string) (int64, error) {
+	start := time.Now()
+	balance, err := r.next.SumByAccount(ctx, account)
+	r.metrics.observeDB("ledger", "SumByAccount", start, err)
+	return balance, err
+}
+
+func (r *ledgerRepository) SumByPeriod(ctx context.Context, account, period string, from, to time.Time) ([]domain.RevenueBucket, error) {
+	start := time.Now()
+	buckets, err := r.next.SumByPeriod(ctx, account, period, from, to)
+	r.metrics.observeDB("ledger", "SumByPeriod", start, err)
+	return buckets, err
+}
+
+type commissionRuleRepository struct {
+	next    repositories.CommissionRuleRepository
+	metrics *Metrics
+}
+
+// InstrumentCommissionRuleRepository wraps repo so every call is timed.
+func InstrumentCommissionRuleRepository(repo repositories.CommissionRuleRepository, m *Metrics) repositories.CommissionRuleRepository {
+	return &commissionRuleRepository{next: repo, metrics: m}
+}
+
+func (r *commissionRuleRepository) CreateRule(ctx context.Context, rule *domain.CommissionRule) error {
+	start := time.Now()
+	err := r.next.CreateRule(ctx, rule)
+	r.metrics.observeDB("commission_rules", "CreateRule", start, err)
+	return err
+}
+
+func (r *commissionRuleRepository) FindRuleByID(ctx context.Context, id primitive.ObjectID) (*domain.CommissionRule, error) {
+	start := time.Now()
+	rule, err := r.next.FindRuleByID(ctx, id)
+	r.metrics.observeDB("commission_rules", "FindRuleByID", start, err)
+	return rule, err
+}
+
+func (r *commissionRuleRepository) ListRules(ctx context.Context, activeOnly bool) ([]*domain.CommissionRule, error) {
+	start := time.Now()
+	rules, err := r.next.ListRules(ctx, activeOnly)
+	r.metrics.observeDB("commission_rules", "ListRules", start, err)
+	return rules, err
+}
+
+func (r *commissionRuleRepository) UpdateRule(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	start := time.Now()
+	err := r.next.UpdateRule(ctx, id, update)
+	r.metrics.observeDB("commission_rules", "UpdateRule", start, err)
+	return err
+}
+
+type serviceAreaRepository struct {
+	next    repositories.ServiceAreaRepository
+	metrics *Metrics
+}
+
+// InstrumentServiceAreaRepository wraps repo so every call is timed.
+func InstrumentServiceAreaRepository(repo repositories.ServiceAreaRepository, m *Metrics) repositories.ServiceAreaRepository {
+	return &serviceAreaRepository{next: repo, metrics: m}
+}
+
+func (r *serviceAreaRepository) CreateArea(ctx context.Context, area *domain.ServiceArea) error {
+	start := time.Now()
+	err := r.next.CreateArea(ctx, area)
+	r.metrics.observeDB("service_areas", "CreateArea", start, err)
+	return err
+}
+
+func (r *serviceAreaRepository) FindAreaByID(ctx context.Context, id primitive.ObjectID) (*domain.ServiceArea, error) {
+	start := time.Now()
+	area, err := r.next.FindAreaByID(ctx, id)
+	r.metrics.observeDB("service_areas", "FindAreaByID", start, err)
+	return area, err
+}
+
+func (r *serviceAreaRepository) FindAreaContaining(ctx context.Context, point domain.GeoPoint) (*domain.ServiceArea, error) {
+	start := time.Now()
+	area, err := r.next.FindAreaContaining(ctx, point)
+	r.metrics.observeDB("service_areas", "FindAreaContaining", start, err)
+	return area, err
+}
+
+func (r *serviceAreaRepository) ListAreas(ctx context.Context, activeOnly bool) ([]*domain.ServiceArea, error) {
+	start := time.Now()
+	areas, err := r.next.ListAreas(ctx, activeOnly)
+	r.metrics.observeDB("service_areas", "ListAreas", start, err)
+	return areas, err
+}
+
+func (r *serviceAreaRepository) UpdateArea(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	start := time.Now()
+	err := r.next.UpdateArea(ctx, id, update)
+	r.metrics.observeDB("service_areas", "UpdateArea", start, err)
+	return err
+}
+
+type systemMetricsRepository struct {
+	next    repositories.SystemMetricsRepository
+	metrics *Metrics
+}
+
+// InstrumentSystemMetricsRepository wraps repo so every call is timed.
+func InstrumentSystemMetricsRepository(repo repositories.SystemMetricsRepository, m *Metrics) repositories.SystemMetricsRepository {
+	return &systemMetricsRepository{next: repo, metrics: m}
+}
+
+func (r *systemMetricsRepository) IncrementMetric(ctx context.Context, name string, delta int64) error {
+	start := time.Now()
+	err := r.next.IncrementMetric(ctx, name, delta)
+	r.metrics.observeDB("system_metrics", "IncrementMetric", start, err)
+	return err
+}
+
+func (r *systemMetricsRepository) FindMetric(ctx context.Context, name string) (*domain.SystemMetrics, error) {
+	start := time.Now()
+	metric, err := r.next.FindMetric(ctx, name)
+	r.metrics.observeDB("system_metrics", "FindMetric", start, err)
+	return metric, err
+}
+
+type invoiceRepository struct {
+	next    repositories.InvoiceRepository
+	metrics *Metrics
+}
+
+// InstrumentInvoiceRepository wraps repo so every call is timed.
+func InstrumentInvoiceRepository(repo repositories.InvoiceRepository, m *Metrics) repositories.InvoiceRepository {
+	return &invoiceRepository{next: repo, metrics: m}
+}
+
+func (r *invoiceRepository) NextInvoiceSequence(ctx context.Context, year int) (int64, error) {
+	start := time.Now()
+	sequence, err := r.next.NextInvoiceSequence(ctx, year)
+	r.metrics.observeDB("invoices", "NextInvoiceSequence", start, err)
+	return sequence, err
+}
+
+func (r *invoiceRepository) CreateInvoice(ctx context.Context, invoice *domain.Invoice) error {
+	start := time.Now()
+	err := r.next.CreateInvoice(ctx, invoice)
+	r.metrics.observeDB("invoices", "CreateInvoice", start, err)
+	return err
+}
+
+func (r *invoiceRepository) FindInvoiceByID(ctx context.Context, id primitive.ObjectID) (*domain.Invoice, error) {
+	start := time.Now()
+	invoice, err := r.next.FindInvoiceByID(ctx, id)
+	r.metrics.observeDB("invoices", "FindInvoiceByID", start, err)
+	return invoice, err
+}
+
+func (r *invoiceRepository) FindInvoiceByBookingID(ctx context.Context, bookingID primitive.ObjectID) (*domain.Invoice, error) {
+	start := time.Now()
+	invoice, err := r.next.FindInvoiceByBookingID(ctx, bookingID)
+	r.metrics.observeDB("invoices", "FindInvoiceByBookingID", start, err)
+	return invoice, err
+}
+
+func (r *invoiceRepository) FindInvoicesByCustomerID(ctx context.Context, customerID primitive.ObjectID) ([]*domain.Invoice, error) {
+	start := time.Now()
+	invoices, err := r.next.FindInvoicesByCustomerID(ctx, customerID)
+	r.metrics.observeDB("invoices", "FindInvoicesByCustomerID", start, err)
+	return invoices, err
+}
+
+func (r *invoiceRepository) UpdateInvoice(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	start := time.Now()
+	err := r.next.UpdateInvoice(ctx, id, update)
+	r.metrics.observeDB("invoices", "UpdateInvoice", start, err)
+	return err
+}
+
+func (r *invoiceRepository) UpdateInvoiceReminders(ctx context.Context, invoice *domain.Invoice, update bson.M) error {
+	start := time.Now()
+	err := r.next.UpdateInvoiceReminders(ctx, invoice, update)
+	r.metrics.observeDB("invoices", "UpdateInvoiceReminders", start, err)
+	return err
+}
+
+func (r *invoiceRepository) FindUnpaidInvoicesIssuedBefore(ctx context.Context, before time.Time) ([]*domain.Invoice, error) {
+	start := time.Now()
+	invoices, err := r.next.FindUnpaidInvoicesIssuedBefore(ctx, before)
+	r.metrics.observeDB("invoices", "FindUnpaidInvoicesIssuedBefore", start, err)
+	return invoices, err
+}
+
+func (r *invoiceRepository) FindUnpaidInvoicesByMowerID(ctx context.Context, mowerID primitive.ObjectID) ([]*domain.Invoice, error) {
+	start := time.Now()
+	invoices, err := r.next.FindUnpaidInvoicesByMowerID(ctx, mowerID)
+	r.metrics.observeDB("invoices", "FindUnpaidInvoicesByMowerID", start, err)
+	return invoices, err
+}
+
+func (r *invoiceRepository) CountOverdueInvoices(ctx context.Context, customerID primitive.ObjectID) (int64, error) {
+	start := time.Now()
+	count, err := r.next.CountOverdueInvoices(ctx, customerID)
+	r.metrics.observeDB("invoices", "CountOverdueInvoices", start, err)
+	return count, err
+}
+
+type paymentReminderRepository struct {
+	next    repositories.PaymentReminderRepository
+	metrics *Metrics
+}
+
+// InstrumentPaymentReminderRepository wraps repo so every call is timed.
+func InstrumentPaymentReminderRepository(repo repositories.PaymentReminderRepository, m *Metrics) repositories.PaymentReminderRepository {
+	return &paymentReminderRepository{next: repo, metrics: m}
+}
+
+func (r *paymentReminderRepository) CreateReminder(ctx context.Context, reminder *domain.PaymentReminder) error {
+	start := time.Now()
+	err := r.next.CreateReminder(ctx, reminder)
+	r.metrics.observeDB("payment_reminders", "CreateReminder", start, err)
+	return err
+}
+
+func (r *paymentReminderRepository) FindRemindersByInvoiceID(ctx context.Context, invoiceID primitive.ObjectID) ([]*domain.PaymentReminder, error) {
+	start := time.Now()
+	reminders, err := r.next.FindRemindersByInvoiceID(ctx, invoiceID)
+	r.metrics.observeDB("payment_reminders", "FindRemindersByInvoiceID", start, err)
+	return reminders, err
+}
+
+type jobLockRepository struct {
+	next    repositories.JobLockRepository
+	metrics *Metrics
+}
+
+// InstrumentJobLockRepository wraps repo so every call is timed.
+func InstrumentJobLockRepository(repo repositories.JobLockRepository, m *Metrics) repositories.JobLockRepository {
+	return &jobLockRepository{next: repo, metrics: m}
+}
+
+func (r *jobLockRepository) AcquireLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	start := time.Now()
+	acquired, err := r.next.AcquireLock(ctx, name, owner, ttl)
+	r.metrics.observeDB("job_locks", "AcquireLock", start, err)
+	return acquired, err
+}
+
+func (r *jobLockRepository) ReleaseLock(ctx context.Context, name, owner string) error {
+	start := time.Now()
+	err := r.next.ReleaseLock(ctx, name, owner)
+	r.metrics.observeDB("job_locks", "ReleaseLock", start, err)
+	return err
+}
+
+type passwordResetTokenRepository struct {
+	next    repositories.PasswordResetTokenRepository
+	metrics *Metrics
+}
+
+// InstrumentPasswordResetTokenRepository wraps repo so every call is timed.
+func InstrumentPasswordResetTokenRepository(repo repositories.PasswordResetTokenRepository, m *Metrics) repositories.PasswordResetTokenRepository {
+	return &passwordResetTokenRepository{next: repo, metrics: m}
+}
+
+func (r *passwordResetTokenRepository) CreateResetToken(ctx context.Context, token *domain.PasswordResetToken) error {
+	start := time.Now()
+	err := r.next.CreateResetToken(ctx, token)
+	r.metrics.observeDB("password_reset_tokens", "CreateResetToken", start, err)
+	return err
+}
+
+func (r *passwordResetTokenRepository) FindResetToken(ctx context.Context, token string) (*domain.PasswordResetToken, error) {
+	start := time.Now()
+	found, err := r.next.FindResetToken(ctx, token)
+	r.metrics.observeDB("password_reset_tokens", "FindResetToken", start, err)
+	return found, err
+}
+
+func (r *passwordResetTokenRepository) DeleteUserResetTokens(ctx context.Context, userID primitive.ObjectID) error {
+	start := time.Now()
+	err := r.next.DeleteUserResetTokens(ctx, userID)
+	r.metrics.observeDB("password_reset_tokens", "DeleteUserResetTokens", start, err)
+	return err
+}
+
+type paymentEventRepository struct {
+	next    repositories.PaymentEventRepository
+	metrics *Metrics
+}
+
+// InstrumentPaymentEventRepository wraps repo so every call is timed.
+func InstrumentPaymentEventRepository(repo repositories.PaymentEventRepository, m *Metrics) repositories.PaymentEventRepository {
+	return &paymentEventRepository{next: repo, metrics: m}
+}
+
+func (r *paymentEventRepository) RecordEvent(ctx context.Context, event *domain.PaymentEvent) error {
+	start := time.Now()
+	err := r.next.RecordEvent(ctx, event)
+	r.metrics.observeDB("payment_events", "RecordEvent", start, err)
+	return err
+}
+
+type payoutRepository struct {
+	next    repositories.PayoutRepository
+	metrics *Metrics
+}
+
+// InstrumentPayoutRepository wraps repo so every call is timed.
+func InstrumentPayoutRepository(repo repositories.PayoutRepository, m *Metrics) repositories.PayoutRepository {
+	return &payoutRepository{next: repo, metrics: m}
+}
+
+func (r *payoutRepository) CreatePayoutRequest(ctx context.Context, request *domain.PayoutRequest) error {
+	start := time.Now()
+	err := r.next.CreatePayoutRequest(ctx, request)
+	r.metrics.observeDB("payout_requests", "CreatePayoutRequest", start, err)
+	return err
+}
+
+func (r *payoutRepository) FindPayoutRequestByID(ctx context.Context, id primitive.ObjectID) (*domain.PayoutRequest, error) {
+	start := time.Now()
+	request, err := r.next.FindPayoutRequestByID(ctx, id)
+	r.metrics.observeDB("payout_requests", "FindPayoutRequestByID", start, err)
+	return request, err
+}
+
+func (r *payoutRepository) FindOpenPayoutRequest(ctx context.Context, mowerID primitive.ObjectID) (*domain.PayoutRequest, error) {
+	start := time.Now()
+	request, err := r.next.FindOpenPayoutRequest(ctx, mowerID)
+	r.metrics.observeDB("payout_requests", "FindOpenPayoutRequest", start, err)
+	return request, err
+}
+
+func (r *payoutRepository) FindPayoutRequestsByMowerID(ctx context.Context, mowerID primitive.ObjectID) ([]*domain.PayoutRequest, error) {
+	start := time.Now()
+	requests, err := r.next.FindPayoutRequestsByMowerID(ctx, mowerID)
+	r.metrics.observeDB("payout_requests", "FindPayoutRequestsByMowerID", start, err)
+	return requests, err
+}
+
+func (r *payoutRepository) FindPayoutRequestsByStatus(ctx context.Context, status string) ([]*domain.PayoutRequest, error) {
+	start := time.Now()
+	requests, err := r.next.FindPayoutRequestsByStatus(ctx, status)
+	r.metrics.observeDB("payout_requests", "FindPayoutRequestsByStatus", start, err)
+	return requests, err
+}
+
+func (r *payoutRepository) FindPayoutRequestsByBatchID(ctx context.Context, batchID primitive.ObjectID) ([]*domain.PayoutRequest, error) {
+	start := time.Now()
+	requests, err := r.next.FindPayoutRequestsByBatchID(ctx, batchID)
+	r.metrics.observeDB("payout_requests", "FindPayoutRequestsByBatchID", start, err)
+	return requests, err
+}
+
+func (r *payoutRepository) UpdatePayoutRequest(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	start := time.Now()
+	err := r.next.UpdatePayoutRequest(ctx, id, update)
+	r.metrics.observeDB("payout_requests", "UpdatePayoutRequest", start, err)
+	return err
+}
+
+func (r *payoutRepository) CreatePayoutBatch(ctx context.Context, batch *domain.PayoutBatch) error {
+	start := time.Now()
+	err := r.next.CreatePayoutBatch(ctx, batch)
+	r.metrics.observeDB("payout_batches", "CreatePayoutBatch", start, err)
+	return err
+}
+
+func (r *payoutRepository) FindPayoutBatchByID(ctx context.Context, id primitive.ObjectID) (*domain.PayoutBatch, error) {
+	start := time.Now()
+	batch, err := r.next.FindPayoutBatchByID(ctx, id)
+	r.metrics.observeDB("payout_batches", "FindPayoutBatchByID", start, err)
+	return batch, err
+}
+
+func (r *payoutRepository) FindPayoutBatches(ctx context.Context) ([]*domain.PayoutBatch, error) {
+	start := time.Now()
+	batches, err := r.next.FindPayoutBatches(ctx)
+	r.metrics.observeDB("payout_batches", "FindPayoutBatches", start, err)
+	return batches, err
+}
+
+type propertyRepository struct {
+	next    repositories.PropertyRepository
+	metrics *Metrics
+}
+
+// InstrumentPropertyRepository wraps repo so every call is timed.
+func InstrumentPropertyRepository(repo repositories.PropertyRepository, m *Metrics) repositories.PropertyRepository {
+	return &propertyRepository{next: repo, metrics: m}
+}
+
+func (r *propertyRepository) CreateProperty(ctx context.Context, property *domain.Property) error {
+	start := time.Now()
+	err := r.next.CreateProperty(ctx, property)
+	r.metrics.observeDB("properties", "CreateProperty", start, err)
+	return err
+}
+
+func (r *propertyRepository) FindPropertyByID(ctx context.Context, id primitive.ObjectID) (*domain.Property, error) {
+	start := time.Now()
+	property, err := r.next.FindPropertyByID(ctx, id)
+	r.metrics.observeDB("properties", "FindPropertyByID", start, err)
+	return property, err
+}
+
+func (r *propertyRepository) FindPropertiesByCustomerID(ctx context.Context, customerID primitive.ObjectID) ([]*domain.Property, error) {
+	start := time.Now()
+	properties, err := r.next.FindPropertiesByCustomerID(ctx, customerID)
+	r.metrics.observeDB("properties", "FindPropertiesByCustomerID", start, err)
+	return properties, err
+}
+
+func (r *propertyRepository) UpdateProperty(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	start := time.Now()
+	err := r.next.UpdateProperty(ctx, id, update)
+	r.metrics.observeDB("properties", "UpdateProperty", start, err)
+	return err
+}
+
+type reviewRepository struct {
+	next    repositories.ReviewRepository
+	metrics *Metrics
+}
+
+// InstrumentReviewRepository wraps repo so every call is timed.
+func InstrumentReviewRepository(repo repositories.ReviewRepository, m *Metrics) repositories.ReviewRepository {
+	return &reviewRepository{next: repo, metrics: m}
+}
+
+func (r *reviewRepository) CreateReview(ctx context.Context, review *domain.Review) error {
+	start := time.Now()
+	err := r.next.CreateReview(ctx, review)
+	r.metrics.observeDB("reviews", "CreateReview", start, err)
+	return err
+}
+
+func (r *reviewRepository) FindReviewByBookingID(ctx context.Context, bookingID primitive.ObjectID) (*domain.Review, error) {
+	start := time.Now()
+	review, err := r.next.FindReviewByBookingID(ctx, bookingID)
+	r.metrics.observeDB("reviews", "FindReviewByBookingID", start, err)
+	return review, err
+}
+
+func (r *reviewRepository) FindReviewsByMowerID(ctx context.Context, mowerID primitive.ObjectID, limit int) ([]*domain.Review, error) {
+	start := time.Now()
+	reviews, err := r.next.FindReviewsByMowerID(ctx, mowerID, limit)
+	r.metrics.observeDB("reviews", "FindReviewsByMowerID", start, err)
+	return reviews, err
+}
+
+func (r *reviewRepository) UpdateReview(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	start := time.Now()
+	err := r.next.UpdateReview(ctx, id, update)
+	r.metrics.observeDB("reviews", "UpdateReview", start, err)
+	return err
+}
+
+func (r *reviewRepository) SetReviewReply(ctx context.Context, id primitive.ObjectID, reply *domain.ReviewReply) error {
+	start := time.Now()
+	err := r.next.SetReviewReply(ctx, id, reply)
+	r.metrics.observeDB("reviews", "SetReviewReply", start, err)
+	return err
+}
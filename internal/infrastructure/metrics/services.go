@@ -0,0 +1,170 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"lawnconnect-api/internal/infrastructure/services"
+)
+
+type emailService struct {
+	next    services.EmailService
+	metrics *Metrics
+}
+
+// InstrumentEmailService wraps svc so every send is counted by template and outcome.
+func InstrumentEmailService(svc services.EmailService, m *Metrics) services.EmailService {
+	return &emailService{next: svc, metrics: m}
+}
+
+func (s *emailService) SendEmail(ctx context.Context, to, subject, templateName string, replacements map[string]interface{}) error {
+	err := s.next.SendEmail(ctx, to, subject, templateName, replacements)
+	s.metrics.emailsSent.WithLabelValues(templateName, outcome(err)).Inc()
+	return err
+}
+
+func (s *emailService) SendEmailWithAttachment(ctx context.Context, to, subject, templateName string, replacements map[string]interface{}, attachmentFilename string, attachmentContent []byte) error {
+	err := s.next.SendEmailWithAttachment(ctx, to, subject, templateName, replacements, attachmentFilename, attachmentContent)
+	s.metrics.emailsSent.WithLabelValues(templateName, outcome(err)).Inc()
+	return err
+}
+
+// SendBulkEmail counts one send per recipient. Individual failures are only
+// logged by the underlying service, so they are attributed to the whole batch.
+func (s *emailService) SendBulkEmail(ctx context.Context, toEmails []string, subject, templateName string, replacements map[string]interface{}) error {
+	err := s.next.SendBulkEmail(ctx, toEmails, subject, templateName, replacements)
+	s.metrics.emailsSent.WithLabelValues(templateName, outcome(err)).Add(float64(len(toEmails)))
+	return err
+}
+
+type uploadService struct {
+	next    services.UploadService
+	metrics *Metrics
+}
+
+// InstrumentUploadService wraps svc so every upload's size and duration are recorded.
+func InstrumentUploadService(svc services.UploadService, m *Metrics) services.UploadService {
+	return &uploadService{next: svc, metrics: m}
+}
+
+func (s *uploadService) UploadFile(ctx context.Context, file io.Reader, filename string) (string, error) {
+	start := time.Now()
+	counter := &countingReader{r: file}
+	url, err := s.next.UploadFile(ctx, counter, filename)
+	s.metrics.uploadDuration.WithLabelValues(outcome(err)).Observe(time.Since(start).Seconds())
+	if err == nil {
+		s.metrics.uploadSize.Observe(float64(counter.n))
+	}
+	return url, err
+}
+
+func (s *uploadService) Ping(ctx context.Context) error {
+	return s.next.Ping(ctx)
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type paymentProvider struct {
+	next    services.PaymentProvider
+	metrics *Metrics
+}
+
+// InstrumentPaymentProvider wraps provider so every call is counted by
+// operation and outcome. Declines are counted apart from errors.
+func InstrumentPaymentProvider(provider services.PaymentProvider, m *Metrics) services.PaymentProvider {
+	return &paymentProvider{next: provider, metrics: m}
+}
+
+func (p *paymentProvider) observe(operation string, err error) {
+	result := outcome(err)
+	if _, ok := err.(services.PaymentDeclinedError); ok {
+		result = "declined"
+	}
+	p.metrics.payments.WithLabelValues(p.next.Name(), operation, result).Inc()
+}
+
+func (p *paymentProvider) Name() string {
+	return p.next.Name()
+}
+
+func (p *paymentProvider) Authorize(ctx context.Context, req services.AuthorizeRequest) (*services.PaymentResult, error) {
+	result, err := p.next.Authorize(ctx, req)
+	p.observe("authorize", err)
+	return result, err
+}
+
+func (p *paymentProvider) Capture(ctx context.Context, paymentID string, amount int64, idempotencyKey string) (*services.PaymentResult, error) {
+	result, err := p.next.Capture(ctx, paymentID, amount, idempotencyKey)
+	p.observe("capture", err)
+	return result, err
+}
+
+func (p *paymentProvider) Refund(ctx context.Context, paymentID string, amount int64, idempotencyKey string) (*services.PaymentResult, error) {
+	result, err := p.next.Refund(ctx, paymentID, amount, idempotencyKey)
+	p.observe("refund", err)
+	return result, err
+}
+
+func (p *paymentProvider) VerifyWebhook(payload []byte, header http.Header) (*services.PaymentEvent, error) {
+	event, err := p.next.VerifyWebhook(payload, header)
+	p.observe("verify_webhook", err)
+	return event, err
+}
+
+type geocoder struct {
+	next    services.Geocoder
+	metrics *Metrics
+}
+
+// InstrumentGeocoder wraps g so every lookup is counted by outcome. Addresses
+// that cannot be located are counted apart from errors.
+func InstrumentGeocoder(g services.Geocoder, m *Metrics) services.Geocoder {
+	return &geocoder{next: g, metrics: m}
+}
+
+func (g *geocoder) Name() string {
+	return g.next.Name()
+}
+
+func (g *geocoder) Geocode(ctx context.Context, address string) (services.Location, error) {
+	location, err := g.next.Geocode(ctx, address)
+	result := outcome(err)
+	if errors.Is(err, services.ErrAddressNotFound) {
+		result = "not_found"
+	}
+	g.metrics.geocoding.WithLabelValues(g.next.Name(), result).Inc()
+	return location, err
+}
+
+type distanceMatrix struct {
+	next    services.DistanceMatrix
+	metrics *Metrics
+}
+
+// InstrumentDistanceMatrix wraps m so every lookup is counted by outcome.
+func InstrumentDistanceMatrix(m services.DistanceMatrix, metrics *Metrics) services.DistanceMatrix {
+	return &distanceMatrix{next: m, metrics: metrics}
+}
+
+func (m *distanceMatrix) Name() string {
+	return m.next.Name()
+}
+
+func (m *distanceMatrix) Matrix(ctx context.Context, points []services.Location) ([][]services.Leg, error) {
+	legs, err := m.next.Matrix(ctx, points)
+	m.metrics.distanceMatrix.WithLabelValues(m.next.Name(), outcome(err)).Inc()
+	return legs, err
+}
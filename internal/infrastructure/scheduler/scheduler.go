@@ -0,0 +1,107 @@
+// Package scheduler runs periodic background jobs. Every run takes a named lock
+// in the job_locks collection first, so when several API instances run the same
+// job, only one of them runs it at a time. Jobs must still be idempotent, since
+// instances whose tickers are out of step may each run it within one interval.
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"lawnconnect-api/internal/infrastructure/database/repositories"
+	"lawnconnect-api/internal/infrastructure/logging"
+)
+
+// Job is a unit of periodic work.
+type Job struct {
+	// Name identifies the job in logs and is the name of its lock.
+	Name string
+	// Interval is the time between runs. The first run starts immediately.
+	Interval time.Duration
+	// Timeout bounds a single run and is how long the lock is held; a crashed
+	// instance's lock is taken over once it expires. Zero uses Interval.
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+// Scheduler runs jobs under locks held in the name of one instance.
+type Scheduler struct {
+	locks repositories.JobLockRepository
+	owner string
+}
+
+// New creates a Scheduler whose locks are owned by a name unique to this process.
+func New(locks repositories.JobLockRepository) *Scheduler {
+	return &Scheduler{locks: locks, owner: instanceName()}
+}
+
+// Run runs job every Interval until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunOnce(ctx, job); err != nil {
+			slog.Error("scheduled job failed", "job", job.Name, "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce runs job if this instance can take its lock. It reports whether the
+// job ran.
+func (s *Scheduler) RunOnce(ctx context.Context, job Job) (bool, error) {
+	timeout := job.Timeout
+	if timeout == 0 {
+		timeout = job.Interval
+	}
+
+	acquired, err := s.locks.AcquireLock(ctx, job.Name, s.owner, timeout)
+	if err != nil {
+		return false, err
+	}
+	if !acquired {
+		slog.Debug("scheduled job is running elsewhere", "job", job.Name)
+		return false, nil
+	}
+	defer func() {
+		// Release with a fresh context so the lock is freed even during shutdown.
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.locks.ReleaseLock(releaseCtx, job.Name, s.owner); err != nil {
+			slog.Warn("releasing job lock failed", "job", job.Name, "error", err)
+		}
+	}()
+
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	logger := slog.Default().With("job", job.Name)
+	runCtx = logging.WithLogger(runCtx, logger)
+
+	start := time.Now()
+	if err := job.Run(runCtx); err != nil {
+		return true, fmt.Errorf("job %s: %w", job.Name, err)
+	}
+	logger.Debug("scheduled job finished", "duration_ms", time.Since(start).Milliseconds())
+	return true, nil
+}
+
+// instanceName identifies this process in lock documents.
+func instanceName() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return host + "-" + hex.EncodeToString(suffix)
+}
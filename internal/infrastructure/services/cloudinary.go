@@ -4,15 +4,18 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
 
 	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+
+	"lawnconnect-api/internal/infrastructure/logging"
 )
 
 // UploadService defines the interface for uploading files to a cloud storage.
 type UploadService interface {
 	UploadFile(ctx context.Context, file io.Reader, filename string) (string, error)
+	// Ping reports whether the storage backend is configured and reachable.
+	Ping(ctx context.Context) error
 }
 
 // uploadService implements UploadService using Cloudinary.
@@ -39,6 +42,22 @@ func (s *uploadService) UploadFile(ctx context.Context, file io.Reader, filename
 		return "", fmt.Errorf("failed to upload file to Cloudinary: %w", err)
 	}
 
-	log.Printf("File '%s' uploaded to Cloudinary. URL: %s", filename, uploadResult.SecureURL)
+	logging.FromContext(ctx).Info("file uploaded to Cloudinary", "filename", filename)
 	return uploadResult.SecureURL, nil
-}
\ No newline at end of file
+}
+
+// Ping checks that the Cloudinary API is reachable with the configured credentials.
+func (s *uploadService) Ping(ctx context.Context) error {
+	if s.cld == nil {
+		return fmt.Errorf("cloudinary service not initialized")
+	}
+
+	result, err := s.cld.Admin.Ping(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to reach Cloudinary: %w", err)
+	}
+	if result.Error.Message != "" {
+		return fmt.Errorf("cloudinary ping failed: %s", result.Error.Message)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Leg is the estimated trip from one point to another.
+type Leg struct {
+	DistanceKm float64
+	Duration   time.Duration
+}
+
+// DistanceMatrix estimates travel between points.
+type DistanceMatrix interface {
+	// Name identifies the provider in metrics and traces, e.g. "haversine".
+	Name() string
+	// Matrix returns the legs between every pair of points: legs[i][j] is the
+	// trip from points[i] to points[j]. Legs need not be symmetric.
+	Matrix(ctx context.Context, points []Location) ([][]Leg, error)
+}
+
+// earthRadiusKm matches the radius domain.DistanceKm uses, so route distances
+// agree with the distances shown in job lists.
+const earthRadiusKm = 6378.1
+
+// HaversineMatrix is a DistanceMatrix that never contacts a provider. It takes
+// the great-circle distance between points, lengthened by a detour factor for
+// the road network, and drives it at a constant average speed.
+type HaversineMatrix struct {
+	speedKmh     float64
+	detourFactor float64
+}
+
+// NewHaversineMatrix creates a HaversineMatrix driving at speedKmh along roads
+// detourFactor times longer than the straight line.
+func NewHaversineMatrix(speedKmh, detourFactor float64) *HaversineMatrix {
+	return &HaversineMatrix{speedKmh: speedKmh, detourFactor: detourFactor}
+}
+
+// Name returns "haversine".
+func (m *HaversineMatrix) Name() string {
+	return "haversine"
+}
+
+// Matrix estimates every leg from the great-circle distance.
+func (m *HaversineMatrix) Matrix(ctx context.Context, points []Location) ([][]Leg, error) {
+	legs := make([][]Leg, len(points))
+	for i, from := range points {
+		legs[i] = make([]Leg, len(points))
+		for j, to := range points {
+			if i == j {
+				continue
+			}
+			km := haversineKm(from, to) * m.detourFactor
+			legs[i][j] = Leg{
+				DistanceKm: km,
+				Duration:   time.Duration(km / m.speedKmh * float64(time.Hour)).Round(time.Second),
+			}
+		}
+	}
+	return legs, nil
+}
+
+// haversineKm returns the great-circle distance between two locations.
+func haversineKm(a, b Location) float64 {
+	const toRadians = math.Pi / 180
+	lat1, lat2 := a.Lat*toRadians, b.Lat*toRadians
+	dLat, dLng := lat2-lat1, (b.Lng-a.Lng)*toRadians
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
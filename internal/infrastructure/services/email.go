@@ -6,13 +6,15 @@ import (
 	"crypto/tls"
 	"encoding/base64"
 	"fmt"
-	"log"
+	"log/slog"
 	"mime/multipart"
 	"net/smtp"
 	"net/textproto"
 	"path/filepath"
 	"text/template"
 	"time"
+
+	"lawnconnect-api/internal/infrastructure/logging"
 )
 
 // EmailService defines the interface for sending emails.
@@ -60,7 +62,7 @@ func (s *emailService) executeTemplate(templateName string, data map[string]inte
 	data["CurrentYear"] = time.Now().Year()
 	var bodyBuffer bytes.Buffer
 	if err := tmpl.Execute(&bodyBuffer, data); err != nil {
-		log.Printf("Error executing template %s: %v", templateName, err)
+		slog.Error("executing email template failed", "template", templateName, "error", err)
 		return "", fmt.Errorf("failed to execute email template %s: %w", templateName, err)
 	}
 
@@ -207,9 +209,33 @@ func (s *emailService) SendBulkEmail(ctx context.Context, toEmails []string, sub
 		default:
 			err := s.SendEmail(ctx, recipient, subject, templateName, replacements)
 			if err != nil {
-				log.Printf("Warning: Failed to send bulk email to %s: %v\n", recipient, err)
+				logging.FromContext(ctx).Warn("sending bulk email failed", "template", templateName, "error", err)
 			}
 		}
 	}
 	return nil
 }
+
+// disabledEmailService is used when no SMTP server is configured. It logs and
+// drops every message so features that send email keep working.
+type disabledEmailService struct{}
+
+// NewDisabledEmailService creates an EmailService that does not send anything.
+func NewDisabledEmailService() EmailService {
+	return disabledEmailService{}
+}
+
+func (disabledEmailService) SendEmail(ctx context.Context, to, subject, templateName string, replacements map[string]interface{}) error {
+	logging.FromContext(ctx).Info("email disabled, not sending", "template", templateName)
+	return nil
+}
+
+func (disabledEmailService) SendEmailWithAttachment(ctx context.Context, to, subject, templateName string, replacements map[string]interface{}, attachmentFilename string, attachmentContent []byte) error {
+	logging.FromContext(ctx).Info("email disabled, not sending", "template", templateName, "attachment", attachmentFilename)
+	return nil
+}
+
+func (disabledEmailService) SendBulkEmail(ctx context.Context, toEmails []string, subject, templateName string, replacements map[string]interface{}) error {
+	logging.FromContext(ctx).Info("email disabled, not sending", "template", templateName, "recipients", len(toEmails))
+	return nil
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrAddressNotFound is returned by a Geocoder that cannot locate an address.
+var ErrAddressNotFound = errors.New("address not found")
+
+// Location is a geocoded position in degrees.
+type Location struct {
+	Lat float64
+	Lng float64
+}
+
+// Geocoder turns postal addresses into coordinates. An address it cannot
+// locate returns ErrAddressNotFound.
+type Geocoder interface {
+	// Name identifies the geocoder in metrics and traces, e.g. "fake".
+	Name() string
+	Geocode(ctx context.Context, address string) (Location, error)
+}
+
+// normalizeAddress lowercases address and collapses its whitespace, so lookup
+// tables match addresses however they were typed.
+func normalizeAddress(address string) string {
+	return strings.ToLower(strings.Join(strings.Fields(address), " "))
+}
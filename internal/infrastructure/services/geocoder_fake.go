@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"sync"
+)
+
+// fakeGeocoderSpreadKm is how far from its centre the fake geocoder places
+// addresses it has no entry for.
+const fakeGeocoderSpreadKm = 30
+
+// FakeGeocoder is a Geocoder that never contacts a provider, for development
+// and tests. Addresses given to Set resolve to their coordinates; any other
+// address resolves to a stable point within 30 km of the centre, unless Strict
+// is set, in which case it is not found.
+type FakeGeocoder struct {
+	// Strict makes addresses without an entry return ErrAddressNotFound.
+	Strict bool
+
+	center  Location
+	mu      sync.Mutex
+	entries map[string]Location
+}
+
+// NewFakeGeocoder creates a FakeGeocoder that places unknown addresses around
+// center.
+func NewFakeGeocoder(center Location) *FakeGeocoder {
+	return &FakeGeocoder{center: center, entries: make(map[string]Location)}
+}
+
+// Name returns "fake".
+func (g *FakeGeocoder) Name() string {
+	return "fake"
+}
+
+// Set makes address resolve to location.
+func (g *FakeGeocoder) Set(address string, location Location) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.entries[normalizeAddress(address)] = location
+}
+
+// Geocode looks address up in the entries, or derives a point from its hash.
+func (g *FakeGeocoder) Geocode(ctx context.Context, address string) (Location, error) {
+	key := normalizeAddress(address)
+	if key == "" {
+		return Location{}, ErrAddressNotFound
+	}
+
+	g.mu.Lock()
+	location, ok := g.entries[key]
+	g.mu.Unlock()
+	if ok {
+		return location, nil
+	}
+	if g.Strict {
+		return Location{}, ErrAddressNotFound
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	sum := h.Sum64()
+	bearing := float64(sum&0xffff) / 0xffff * 2 * math.Pi
+	distance := float64(sum>>16&0xffff) / 0xffff * fakeGeocoderSpreadKm
+	const kmPerDegree = 111.32
+	return Location{
+		Lat: g.center.Lat + distance*math.Cos(bearing)/kmPerDegree,
+		Lng: g.center.Lng + distance*math.Sin(bearing)/(kmPerDegree*math.Cos(g.center.Lat*math.Pi/180)),
+	}, nil
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// FileGeocoder is a Geocoder backed by a lookup table loaded from a CSV file of
+// "address,lat,lng" rows, for tests and offline environments. Addresses match
+// regardless of case and spacing; quote addresses that contain commas. Blank
+// lines and lines starting with "#" are ignored.
+type FileGeocoder struct {
+	entries map[string]Location
+}
+
+// NewFileGeocoder loads the lookup table at path.
+func NewFileGeocoder(path string) (*FileGeocoder, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open geocoding table: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	g := &FileGeocoder{entries: make(map[string]Location)}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read geocoding table %s: %w", path, err)
+		}
+		lat, latErr := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		lng, lngErr := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		if latErr != nil || lngErr != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("geocoding table %s line %d: invalid coordinates %q, %q", path, line, record[1], record[2])
+		}
+		g.entries[normalizeAddress(record[0])] = Location{Lat: lat, Lng: lng}
+	}
+	return g, nil
+}
+
+// Name returns "file".
+func (g *FileGeocoder) Name() string {
+	return "file"
+}
+
+// Geocode looks address up in the table.
+func (g *FileGeocoder) Geocode(ctx context.Context, address string) (Location, error) {
+	location, ok := g.entries[normalizeAddress(address)]
+	if !ok {
+		return Location{}, ErrAddressNotFound
+	}
+	return location, nil
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Payment statuses reported by a PaymentProvider.
+const (
+	PaymentStatusAuthorized = "authorized"
+	PaymentStatusCaptured   = "captured"
+	PaymentStatusRefunded   = "refunded"
+	// PaymentStatusVoided means an authorization was released before capture.
+	PaymentStatusVoided = "voided"
+	PaymentStatusFailed = "failed"
+)
+
+// Webhook event types a PaymentProvider delivers.
+const (
+	PaymentEventCaptured = "payment.captured"
+	PaymentEventFailed   = "payment.failed"
+	PaymentEventRefunded = "payment.refunded"
+)
+
+// PaymentProvider charges customers' cards through a payment processor. Calls
+// that move money take an idempotency key: repeating a call with the same key
+// returns the original result instead of charging again, so callers may retry
+// after timeouts. A processor that refuses a payment returns PaymentDeclinedError.
+type PaymentProvider interface {
+	// Name identifies the provider on stored payments, e.g. "fake".
+	Name() string
+	// Authorize places a hold of req.Amount on the customer's payment method.
+	Authorize(ctx context.Context, req AuthorizeRequest) (*PaymentResult, error)
+	// Capture charges amount, which may not exceed the authorized amount.
+	Capture(ctx context.Context, paymentID string, amount int64, idempotencyKey string) (*PaymentResult, error)
+	// Refund returns amount to the customer, or the whole payment when amount is
+	// zero. Refunding a payment that was only authorized releases the hold.
+	Refund(ctx context.Context, paymentID string, amount int64, idempotencyKey string) (*PaymentResult, error)
+	// VerifyWebhook checks a webhook's signature and decodes its event.
+	VerifyWebhook(payload []byte, header http.Header) (*PaymentEvent, error)
+}
+
+// AuthorizeRequest describes a hold to place on a payment method.
+type AuthorizeRequest struct {
+	// PaymentMethod is the provider's token for the customer's card, created by
+	// the provider's client-side SDK so card details never reach the API.
+	PaymentMethod string
+	Amount        int64
+	Currency      string
+	// Reference is echoed on webhook events, e.g. the booking ID.
+	Reference      string
+	IdempotencyKey string
+}
+
+// PaymentResult is a payment's state after a provider call. Amounts are in
+// minor units.
+type PaymentResult struct {
+	ID       string
+	Status   string
+	Amount   int64
+	Captured int64
+	Refunded int64
+	Currency string
+}
+
+// PaymentEvent is a verified webhook event.
+type PaymentEvent struct {
+	ID        string
+	Type      string
+	PaymentID string
+	Reference string
+	// Amount is the amount captured or refunded by the event, in minor units.
+	Amount int64
+	// Reason explains a payment.failed event.
+	Reason    string
+	CreatedAt time.Time
+}
+
+// PaymentDeclinedError is returned when the processor refuses a payment, as
+// opposed to failing to process it.
+type PaymentDeclinedError struct {
+	Code    string
+	Message string
+}
+
+func (e PaymentDeclinedError) Error() string {
+	return "payment declined: " + e.Message
+}
+
+// ErrPaymentsDisabled is returned by the disabled provider for calls that need
+// a processor.
+var ErrPaymentsDisabled = errors.New("card payments are disabled")
+
+// disabledPaymentProvider is used when no payment processor is configured. It
+// declines every card, so bookings are paid by invoice, and rejects webhooks.
+type disabledPaymentProvider struct{}
+
+// NewDisabledPaymentProvider creates a PaymentProvider that takes no payments.
+func NewDisabledPaymentProvider() PaymentProvider {
+	return disabledPaymentProvider{}
+}
+
+func (disabledPaymentProvider) Name() string {
+	return "none"
+}
+
+func (disabledPaymentProvider) Authorize(ctx context.Context, req AuthorizeRequest) (*PaymentResult, error) {
+	return nil, PaymentDeclinedError{Code: "payments_disabled", Message: "Card payments are not available; book without a payment method to pay by invoice"}
+}
+
+func (disabledPaymentProvider) Capture(ctx context.Context, paymentID string, amount int64, idempotencyKey string) (*PaymentResult, error) {
+	return nil, ErrPaymentsDisabled
+}
+
+func (disabledPaymentProvider) Refund(ctx context.Context, paymentID string, amount int64, idempotencyKey string) (*PaymentResult, error) {
+	return nil, ErrPaymentsDisabled
+}
+
+func (disabledPaymentProvider) VerifyWebhook(payload []byte, header http.Header) (*PaymentEvent, error) {
+	return nil, ErrPaymentsDisabled
+}
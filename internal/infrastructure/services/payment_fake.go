@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Test payment methods understood by the fake provider. Any other token with
+// the "pm_" prefix behaves like FakeCardVisa.
+const (
+	FakeCardVisa = "pm_card_visa"
+	// FakeCardDeclined is declined when authorized.
+	FakeCardDeclined = "pm_card_declined"
+	// FakeCardCaptureFails authorizes but is declined when captured.
+	FakeCardCaptureFails = "pm_card_capture_fails"
+)
+
+// FakeSignatureHeader carries a fake webhook's signature:
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>" keyed by the webhook secret>".
+const FakeSignatureHeader = "Fake-Signature"
+
+// fakeWebhookTolerance is how old a webhook signature may be.
+const fakeWebhookTolerance = 5 * time.Minute
+
+type fakePayment struct {
+	PaymentResult
+	method string
+}
+
+type fakeOutcome struct {
+	result *PaymentResult
+	err    error
+}
+
+// FakePaymentProvider is a PaymentProvider that keeps payments in memory and
+// never contacts a processor, for development and tests. Its webhooks are
+// signed with the configured secret, and Webhook builds one to deliver by hand.
+type FakePaymentProvider struct {
+	secret []byte
+
+	mu       sync.Mutex
+	payments map[string]*fakePayment
+	outcomes map[string]fakeOutcome
+}
+
+// NewFakePaymentProvider creates a FakePaymentProvider whose webhooks are signed
+// with webhookSecret.
+func NewFakePaymentProvider(webhookSecret string) *FakePaymentProvider {
+	return &FakePaymentProvider{
+		secret:   []byte(webhookSecret),
+		payments: make(map[string]*fakePayment),
+		outcomes: make(map[string]fakeOutcome),
+	}
+}
+
+// Name returns "fake".
+func (p *FakePaymentProvider) Name() string {
+	return "fake"
+}
+
+// Authorize holds the amount unless the payment method is FakeCardDeclined or
+// is not a "pm_" token.
+func (p *FakePaymentProvider) Authorize(ctx context.Context, req AuthorizeRequest) (*PaymentResult, error) {
+	return p.once("authorize:"+req.IdempotencyKey, func() (*PaymentResult, error) {
+		if req.Amount <= 0 {
+			return nil, fmt.Errorf("fake payments: amount must be positive")
+		}
+		switch {
+		case !strings.HasPrefix(req.PaymentMethod, "pm_"):
+			return nil, PaymentDeclinedError{Code: "invalid_payment_method", Message: "The payment method is not valid"}
+		case req.PaymentMethod == FakeCardDeclined:
+			return nil, PaymentDeclinedError{Code: "card_declined", Message: "Your card was declined"}
+		}
+
+		payment := &fakePayment{
+			PaymentResult: PaymentResult{
+				ID:       "fake_pay_" + randomHex(12),
+				Status:   PaymentStatusAuthorized,
+				Amount:   req.Amount,
+				Currency: req.Currency,
+			},
+			method: req.PaymentMethod,
+		}
+		p.payments[payment.ID] = payment
+		result := payment.PaymentResult
+		return &result, nil
+	})
+}
+
+// Capture charges an authorized payment.
+func (p *FakePaymentProvider) Capture(ctx context.Context, paymentID string, amount int64, idempotencyKey string) (*PaymentResult, error) {
+	return p.once("capture:"+idempotencyKey, func() (*PaymentResult, error) {
+		payment, ok := p.payments[paymentID]
+		if !ok {
+			return nil, fmt.Errorf("fake payments: unknown payment %s", paymentID)
+		}
+		switch {
+		case payment.Status != PaymentStatusAuthorized:
+			return nil, PaymentDeclinedError{Code: "payment_not_authorized", Message: "The payment is " + payment.Status + " and cannot be captured"}
+		case amount <= 0 || amount > payment.Amount:
+			return nil, PaymentDeclinedError{Code: "amount_exceeds_authorization", Message: "The amount exceeds the authorized amount"}
+		case payment.method == FakeCardCaptureFails:
+			// Like a processor, a declined capture leaves the hold in place.
+			return nil, PaymentDeclinedError{Code: "insufficient_funds", Message: "Your card has insufficient funds"}
+		}
+
+		payment.Status = PaymentStatusCaptured
+		payment.Captured = amount
+		result := payment.PaymentResult
+		return &result, nil
+	})
+}
+
+// Refund refunds a captured payment or voids an authorized one.
+func (p *FakePaymentProvider) Refund(ctx context.Context, paymentID string, amount int64, idempotencyKey string) (*PaymentResult, error) {
+	return p.once("refund:"+idempotencyKey, func() (*PaymentResult, error) {
+		payment, ok := p.payments[paymentID]
+		if !ok {
+			return nil, fmt.Errorf("fake payments: unknown payment %s", paymentID)
+		}
+
+		switch payment.Status {
+		case PaymentStatusAuthorized:
+			payment.Status = PaymentStatusVoided
+		case PaymentStatusCaptured:
+			if amount == 0 {
+				amount = payment.Captured - payment.Refunded
+			}
+			if amount <= 0 || payment.Refunded+amount > payment.Captured {
+				return nil, PaymentDeclinedError{Code: "amount_exceeds_capture", Message: "The refund exceeds the captured amount"}
+			}
+			payment.Refunded += amount
+			if payment.Refunded == payment.Captured {
+				payment.Status = PaymentStatusRefunded
+			}
+		default:
+			return nil, PaymentDeclinedError{Code: "payment_not_refundable", Message: "The payment is " + payment.Status + " and cannot be refunded"}
+		}
+		result := payment.PaymentResult
+		return &result, nil
+	})
+}
+
+// once runs fn under the provider's lock unless key has been seen, in which
+// case the first call's outcome is returned again.
+func (p *FakePaymentProvider) once(key string, fn func() (*PaymentResult, error)) (*PaymentResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if outcome, seen := p.outcomes[key]; seen {
+		return copyResult(outcome.result), outcome.err
+	}
+	result, err := fn()
+	p.outcomes[key] = fakeOutcome{result: copyResult(result), err: err}
+	return result, err
+}
+
+// Payment returns a payment's current state at the fake processor, or nil if it
+// does not know the payment.
+func (p *FakePaymentProvider) Payment(paymentID string) *PaymentResult {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	payment, ok := p.payments[paymentID]
+	if !ok {
+		return nil
+	}
+	return copyResult(&payment.PaymentResult)
+}
+
+func copyResult(result *PaymentResult) *PaymentResult {
+	if result == nil {
+		return nil
+	}
+	c := *result
+	return &c
+}
+
+// fakeWebhook is the JSON body of a fake webhook.
+type fakeWebhook struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+	Data    struct {
+		PaymentID string `json:"paymentId"`
+		Reference string `json:"reference"`
+		Amount    int64  `json:"amount"`
+		Reason    string `json:"reason,omitempty"`
+	} `json:"data"`
+}
+
+// VerifyWebhook checks the FakeSignatureHeader and decodes the event.
+func (p *FakePaymentProvider) VerifyWebhook(payload []byte, header http.Header) (*PaymentEvent, error) {
+	var timestamp, signature string
+	for _, part := range strings.Split(header.Get(FakeSignatureHeader), ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || signature == "" {
+		return nil, errors.New("fake payments: missing or malformed webhook signature")
+	}
+	if age := time.Since(time.Unix(seconds, 0)); age > fakeWebhookTolerance || age < -fakeWebhookTolerance {
+		return nil, errors.New("fake payments: webhook signature has expired")
+	}
+	expected := p.sign(timestamp, payload)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return nil, errors.New("fake payments: webhook signature does not match")
+	}
+
+	var webhook fakeWebhook
+	if err := json.Unmarshal(payload, &webhook); err != nil {
+		return nil, fmt.Errorf("fake payments: invalid webhook body: %w", err)
+	}
+	if webhook.ID == "" || webhook.Type == "" || webhook.Data.PaymentID == "" {
+		return nil, errors.New("fake payments: webhook is missing its id, type or payment")
+	}
+	return &PaymentEvent{
+		ID:        webhook.ID,
+		Type:      webhook.Type,
+		PaymentID: webhook.Data.PaymentID,
+		Reference: webhook.Data.Reference,
+		Amount:    webhook.Data.Amount,
+		Reason:    webhook.Data.Reason,
+		CreatedAt: time.Unix(webhook.Created, 0),
+	}, nil
+}
+
+// Webhook builds a signed webhook of eventType for a payment, as the fake
+// processor would deliver it. Delivering the same body twice simulates a retry.
+func (p *FakePaymentProvider) Webhook(eventType, paymentID, reference string, amount int64) ([]byte, http.Header) {
+	webhook := fakeWebhook{ID: "fake_evt_" + randomHex(12), Type: eventType, Created: time.Now().Unix()}
+	webhook.Data.PaymentID = paymentID
+	webhook.Data.Reference = reference
+	webhook.Data.Amount = amount
+
+	body, _ := json.Marshal(webhook)
+	timestamp := strconv.FormatInt(webhook.Created, 10)
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set(FakeSignatureHeader, "t="+timestamp+",v1="+p.sign(timestamp, body))
+	return body, header
+}
+
+func (p *FakePaymentProvider) sign(timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
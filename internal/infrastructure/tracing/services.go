@@ -0,0 +1,179 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"lawnconnect-api/internal/infrastructure/services"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// endSpan records err on span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+type emailService struct {
+	next services.EmailService
+}
+
+// TraceEmailService wraps svc so every send is a client span. Recipients are not
+// recorded, as trace backends are not cleared to hold personal data.
+func TraceEmailService(svc services.EmailService) services.EmailService {
+	return &emailService{next: svc}
+}
+
+func (s *emailService) SendEmail(ctx context.Context, to, subject, templateName string, replacements map[string]interface{}) error {
+	ctx, span := tracer().Start(ctx, "smtp.send", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("email.template", templateName)))
+	err := s.next.SendEmail(ctx, to, subject, templateName, replacements)
+	endSpan(span, err)
+	return err
+}
+
+func (s *emailService) SendEmailWithAttachment(ctx context.Context, to, subject, templateName string, replacements map[string]interface{}, attachmentFilename string, attachmentContent []byte) error {
+	ctx, span := tracer().Start(ctx, "smtp.send", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("email.template", templateName),
+			attribute.Int("email.attachment.size", len(attachmentContent)),
+		))
+	err := s.next.SendEmailWithAttachment(ctx, to, subject, templateName, replacements, attachmentFilename, attachmentContent)
+	endSpan(span, err)
+	return err
+}
+
+func (s *emailService) SendBulkEmail(ctx context.Context, toEmails []string, subject, templateName string, replacements map[string]interface{}) error {
+	ctx, span := tracer().Start(ctx, "smtp.send_bulk", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("email.template", templateName),
+			attribute.Int("email.recipients", len(toEmails)),
+		))
+	err := s.next.SendBulkEmail(ctx, toEmails, subject, templateName, replacements)
+	endSpan(span, err)
+	return err
+}
+
+type uploadService struct {
+	next services.UploadService
+}
+
+// TraceUploadService wraps svc so every upload is a client span.
+func TraceUploadService(svc services.UploadService) services.UploadService {
+	return &uploadService{next: svc}
+}
+
+func (s *uploadService) UploadFile(ctx context.Context, file io.Reader, filename string) (string, error) {
+	ctx, span := tracer().Start(ctx, "upload", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("upload.filename", filename)))
+	url, err := s.next.UploadFile(ctx, file, filename)
+	endSpan(span, err)
+	return url, err
+}
+
+func (s *uploadService) Ping(ctx context.Context) error {
+	return s.next.Ping(ctx)
+}
+
+type paymentProvider struct {
+	next services.PaymentProvider
+}
+
+// TracePaymentProvider wraps provider so every call that reaches the processor
+// is a client span. Payment methods are not recorded.
+func TracePaymentProvider(provider services.PaymentProvider) services.PaymentProvider {
+	return &paymentProvider{next: provider}
+}
+
+func (p *paymentProvider) start(ctx context.Context, operation, paymentID string) (context.Context, trace.Span) {
+	return tracer().Start(ctx, "payments."+operation, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("payment.provider", p.next.Name()),
+			attribute.String("payment.id", paymentID),
+		))
+}
+
+func (p *paymentProvider) Name() string {
+	return p.next.Name()
+}
+
+func (p *paymentProvider) Authorize(ctx context.Context, req services.AuthorizeRequest) (*services.PaymentResult, error) {
+	ctx, span := p.start(ctx, "authorize", "")
+	result, err := p.next.Authorize(ctx, req)
+	endSpan(span, err)
+	return result, err
+}
+
+func (p *paymentProvider) Capture(ctx context.Context, paymentID string, amount int64, idempotencyKey string) (*services.PaymentResult, error) {
+	ctx, span := p.start(ctx, "capture", paymentID)
+	result, err := p.next.Capture(ctx, paymentID, amount, idempotencyKey)
+	endSpan(span, err)
+	return result, err
+}
+
+func (p *paymentProvider) Refund(ctx context.Context, paymentID string, amount int64, idempotencyKey string) (*services.PaymentResult, error) {
+	ctx, span := p.start(ctx, "refund", paymentID)
+	result, err := p.next.Refund(ctx, paymentID, amount, idempotencyKey)
+	endSpan(span, err)
+	return result, err
+}
+
+func (p *paymentProvider) VerifyWebhook(payload []byte, header http.Header) (*services.PaymentEvent, error) {
+	return p.next.VerifyWebhook(payload, header)
+}
+
+type geocoder struct {
+	next services.Geocoder
+}
+
+// TraceGeocoder wraps g so every lookup is a client span. Addresses are not
+// recorded.
+func TraceGeocoder(g services.Geocoder) services.Geocoder {
+	return &geocoder{next: g}
+}
+
+func (g *geocoder) Name() string {
+	return g.next.Name()
+}
+
+func (g *geocoder) Geocode(ctx context.Context, address string) (services.Location, error) {
+	ctx, span := tracer().Start(ctx, "geocode", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("geocoder", g.next.Name())))
+	location, err := g.next.Geocode(ctx, address)
+	if errors.Is(err, services.ErrAddressNotFound) {
+		span.End()
+		return location, err
+	}
+	endSpan(span, err)
+	return location, err
+}
+
+type distanceMatrix struct {
+	next services.DistanceMatrix
+}
+
+// TraceDistanceMatrix wraps m so every lookup is a client span recording how
+// many points were requested. Locations are not recorded.
+func TraceDistanceMatrix(m services.DistanceMatrix) services.DistanceMatrix {
+	return &distanceMatrix{next: m}
+}
+
+func (m *distanceMatrix) Name() string {
+	return m.next.Name()
+}
+
+func (m *distanceMatrix) Matrix(ctx context.Context, points []services.Location) ([][]services.Leg, error) {
+	ctx, span := tracer().Start(ctx, "distance_matrix", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("provider", m.next.Name()), attribute.Int("points", len(points))))
+	legs, err := m.next.Matrix(ctx, points)
+	endSpan(span, err)
+	return legs, err
+}
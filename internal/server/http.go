@@ -0,0 +1,193 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HTTPConfig holds the network-level settings of the HTTP server.
+type HTTPConfig struct {
+	Addr              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	// ShutdownTimeout bounds how long Run waits for in-flight requests, background
+	// workers and shutdown hooks once shutdown starts.
+	ShutdownTimeout time.Duration
+	// DrainDelay keeps serving for this long after shutdown starts, so load
+	// balancers notice the failing readiness probe before connections are refused.
+	DrainDelay time.Duration
+	// TLSCertFile and TLSKeyFile enable HTTPS when both are set.
+	TLSCertFile string
+	TLSKeyFile  string
+}
+
+// Server runs the HTTP server together with background workers and shuts them
+// down in order: stop accepting connections, drain in-flight requests, stop the
+// workers, then run the shutdown hooks (e.g. closing the database connection).
+type Server struct {
+	cfg        HTTPConfig
+	httpServer *http.Server
+
+	workerCtx     context.Context
+	stopWorkers   context.CancelFunc
+	workers       sync.WaitGroup
+	shutdownMu    sync.Mutex
+	drainHooks    []func()
+	shutdownHooks []func(ctx context.Context) error
+}
+
+// NewServer creates a Server that serves handler with the given settings.
+func NewServer(cfg HTTPConfig, handler http.Handler) *Server {
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	return &Server{
+		cfg: cfg,
+		httpServer: &http.Server{
+			Addr:              cfg.Addr,
+			Handler:           handler,
+			ReadTimeout:       cfg.ReadTimeout,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
+			IdleTimeout:       cfg.IdleTimeout,
+			MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		},
+		workerCtx:   workerCtx,
+		stopWorkers: stopWorkers,
+	}
+}
+
+// Go runs a background worker. Its context is cancelled when shutdown starts and
+// Run waits for it to return before running the shutdown hooks.
+func (s *Server) Go(name string, worker func(ctx context.Context)) {
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				slog.Error("background worker panicked", "worker", name, "panic", r)
+			}
+		}()
+		worker(s.workerCtx)
+	}()
+}
+
+// OnDrain registers a hook that runs as soon as shutdown starts, while requests
+// are still being served, e.g. to fail the readiness probe.
+func (s *Server) OnDrain(hook func()) {
+	s.shutdownMu.Lock()
+	defer s.shutdownMu.Unlock()
+	s.drainHooks = append(s.drainHooks, hook)
+}
+
+// OnShutdown registers a hook to run after requests and workers have stopped.
+// Hooks run in registration order.
+func (s *Server) OnShutdown(hook func(ctx context.Context) error) {
+	s.shutdownMu.Lock()
+	defer s.shutdownMu.Unlock()
+	s.shutdownHooks = append(s.shutdownHooks, hook)
+}
+
+// Run serves until ctx is cancelled (typically by SIGINT/SIGTERM) or the listener
+// fails, then shuts everything down within ShutdownTimeout.
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		s.shutdown()
+		return fmt.Errorf("failed to listen on %s: %w", s.cfg.Addr, err)
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve is like Run but uses an existing listener.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		if s.cfg.TLSCertFile != "" && s.cfg.TLSKeyFile != "" {
+			slog.Info("server listening", "addr", listener.Addr().String(), "tls", true)
+			serveErr <- s.httpServer.ServeTLS(listener, s.cfg.TLSCertFile, s.cfg.TLSKeyFile)
+			return
+		}
+		slog.Info("server listening", "addr", listener.Addr().String(), "tls", false)
+		serveErr <- s.httpServer.Serve(listener)
+	}()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		slog.Info("shutdown signal received, draining in-flight requests")
+		s.drain()
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			runErr = fmt.Errorf("http server failed: %w", err)
+		}
+	}
+
+	if err := s.shutdown(); err != nil && runErr == nil {
+		runErr = err
+	}
+	return runErr
+}
+
+// drain runs the drain hooks and waits DrainDelay before connections are closed.
+func (s *Server) drain() {
+	s.shutdownMu.Lock()
+	hooks := append([]func(){}, s.drainHooks...)
+	s.shutdownMu.Unlock()
+	for _, hook := range hooks {
+		hook()
+	}
+	if s.cfg.DrainDelay > 0 {
+		time.Sleep(s.cfg.DrainDelay)
+	}
+}
+
+// shutdown stops the HTTP server, the workers and runs the hooks, all under a
+// single ShutdownTimeout deadline.
+func (s *Server) shutdown() error {
+	timeout := s.cfg.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("http server shutdown: %w", err))
+	}
+
+	s.stopWorkers()
+	workersDone := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+	case <-ctx.Done():
+		errs = append(errs, errors.New("background workers did not stop before the shutdown deadline"))
+	}
+
+	s.shutdownMu.Lock()
+	hooks := append([]func(ctx context.Context) error(nil), s.shutdownHooks...)
+	s.shutdownMu.Unlock()
+	for _, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	slog.Info("server stopped cleanly")
+	return nil
+}
@@ -0,0 +1,118 @@
+// Package server assembles the API's HTTP handler from its configuration and
+// dependencies, so the same router can be served by main, by tests and by other
+// binaries that embed the API.
+package server
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"lawnconnect-api/internal/api/handlers"
+	"lawnconnect-api/internal/core/services"
+	"lawnconnect-api/internal/infrastructure/health"
+	"lawnconnect-api/internal/infrastructure/logging"
+	"lawnconnect-api/internal/infrastructure/metrics"
+	infrastructureServices "lawnconnect-api/internal/infrastructure/services"
+	"lawnconnect-api/internal/infrastructure/tracing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Config holds the settings that shape the HTTP handler.
+type Config struct {
+	// StaticDir is served at the root path when not empty.
+	StaticDir string
+	// RequestTimeout cancels a request's context after this long. Zero uses 60s.
+	RequestTimeout time.Duration
+	// JWTSecret verifies the bearer tokens issued by AuthService.
+	JWTSecret []byte
+	// Logger writes access logs and is the base of every request-scoped logger.
+	// Nil uses slog.Default().
+	Logger *slog.Logger
+	// Metrics records HTTP metrics and is served at /metrics. Nil disables both.
+	Metrics *metrics.Metrics
+}
+
+// Dependencies are the services the API's handlers delegate to.
+type Dependencies struct {
+	AuthService        services.AuthService
+	BookingService     services.BookingService
+	LedgerService      services.LedgerService
+	CommissionService  services.CommissionService
+	InvoiceService     services.InvoiceService
+	DunningService     services.DunningService
+	PaymentService     services.PaymentService
+	PayoutService      services.PayoutService
+	PropertyService    services.PropertyService
+	LocationService    services.LocationService
+	ServiceAreaService services.ServiceAreaService
+	RouteService       services.RouteService
+	ReviewService      services.ReviewService
+	CommentService     services.CommentService
+	UploadService      infrastructureServices.UploadService
+	// Health backs the readiness probe. When nil, readiness always succeeds.
+	Health *health.Reporter
+}
+
+// New builds the API's HTTP handler.
+func New(cfg Config, deps Dependencies) http.Handler {
+	requestTimeout := cfg.RequestTimeout
+	if requestTimeout == 0 {
+		requestTimeout = 60 * time.Second
+	}
+
+	authHandler := handlers.NewAuthHandler(deps.AuthService)
+	authHandler.UploadService = deps.UploadService
+	bookingHandler := handlers.NewBookingHandler(deps.BookingService, deps.ReviewService, deps.CommentService)
+	walletHandler := handlers.NewWalletHandler(deps.LedgerService, deps.PayoutService)
+	adminHandler := handlers.NewAdminHandler(deps.CommissionService, deps.LedgerService, deps.PayoutService, deps.ServiceAreaService)
+	invoiceHandler := handlers.NewInvoiceHandler(deps.InvoiceService, deps.DunningService)
+	paymentHandler := handlers.NewPaymentHandler(deps.PaymentService)
+	propertyHandler := handlers.NewPropertyHandler(deps.PropertyService)
+	mowerHandler := handlers.NewMowerHandler(deps.LocationService, deps.ServiceAreaService, deps.RouteService)
+	profileHandler := handlers.NewProfileHandler(deps.ReviewService)
+	healthHandler := handlers.NewHealthHandler(deps.Health)
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(logging.Middleware(logger))
+	r.Use(tracing.Middleware)
+	if cfg.Metrics != nil {
+		r.Use(cfg.Metrics.Middleware)
+	}
+	r.Use(middleware.Recoverer)
+	r.Use(middleware.Timeout(requestTimeout))
+
+	r.Get("/healthz", healthHandler.Liveness)
+	r.Get("/readyz", healthHandler.Readiness)
+	if cfg.Metrics != nil {
+		r.Method(http.MethodGet, "/metrics", cfg.Metrics.Handler())
+	}
+
+	if cfg.StaticDir != "" {
+		r.Handle("/*", http.FileServer(http.Dir(cfg.StaticDir)))
+	}
+
+	authenticate := handlers.AuthMiddleware(cfg.JWTSecret)
+
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Mount("/auth", authHandler.Routes())
+		r.With(authenticate).Mount("/bookings", bookingHandler.Routes())
+		r.With(authenticate).Mount("/properties", propertyHandler.Routes())
+		r.With(authenticate).Mount("/mower", mowerHandler.Routes())
+		r.Mount("/mowers", profileHandler.Routes())
+		r.With(authenticate).Mount("/wallet", walletHandler.Routes())
+		r.With(authenticate).Mount("/invoices", invoiceHandler.Routes())
+		r.With(authenticate).Mount("/admin", adminHandler.Routes())
+		r.Mount("/payments", paymentHandler.Routes())
+	})
+
+	return r
+}
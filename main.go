@@ -2,128 +2,413 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
-	"net/http"
+	"log/slog"
 	"os"
-	"path/filepath"
-	"strconv"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/cloudinary/cloudinary-go/v2"
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
-	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 
-	"lawnconnect-api/internal/api/handlers"
+	"lawnconnect-api/internal/config"
 	coreServices "lawnconnect-api/internal/core/services"
 	"lawnconnect-api/internal/infrastructure/database"
+	"lawnconnect-api/internal/infrastructure/database/migrations"
 	"lawnconnect-api/internal/infrastructure/database/repositories"
+	"lawnconnect-api/internal/infrastructure/documents"
+	"lawnconnect-api/internal/infrastructure/health"
+	"lawnconnect-api/internal/infrastructure/logging"
+	"lawnconnect-api/internal/infrastructure/metrics"
+	"lawnconnect-api/internal/infrastructure/scheduler"
 	infrastructureServices "lawnconnect-api/internal/infrastructure/services"
+	"lawnconnect-api/internal/infrastructure/tracing"
+	"lawnconnect-api/internal/server"
 )
 
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using system environment variables")
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfigCommand(os.Args[2:]))
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	cfg, err := config.Load(config.Options{})
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	mongoURI := os.Getenv("MONGO_URI")
-	dbName := os.Getenv("MONGO_DB_NAME")
-	if mongoURI == "" || dbName == "" {
-		log.Fatal("MONGO_URI and MONGO_DB_NAME must be set in .env")
+	logger, err := logging.New(os.Stderr, logging.Config{Format: cfg.Log.Format, Level: cfg.Log.Level})
+	if err != nil {
+		log.Fatal(err)
 	}
-	mongoClient, err := database.NewMongoClient(ctx, mongoURI)
+	// Route the standard library logger (and packages still using it) through slog
+	// so their output is structured and redacted too.
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
 	if err != nil {
-		log.Fatalf("Failed to connect to MongoDB: %v", err)
+		fatal("failed to set up tracing", err)
 	}
-	defer mongoClient.Disconnect(context.Background())
-	db := mongoClient.Database(dbName)
 
-	cloudinaryURL := os.Getenv("CLOUDINARY_URL")
-	cld, err := cloudinary.NewFromURL(cloudinaryURL)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Mongo.ConnectTimeout)
+	defer cancel()
+
+	mongoClient, err := database.NewMongoClient(ctx, cfg.Mongo.URI)
 	if err != nil {
-		log.Printf("Could not initialize Cloudinary, uploads will not work: %v", err)
-	}
-	uploadService := infrastructureServices.NewUploadService(cld)
-	_ = uploadService
-
-	smtpHost := os.Getenv("SMTP_HOST")
-	smtpPortStr := os.Getenv("SMTP_PORT")
-	smtpUser := os.Getenv("SMTP_USER")
-	smtpPass := os.Getenv("SMTP_PASS")
-	fromEmail := os.Getenv("FROM_EMAIL")
-	templatesPath := os.Getenv("TEMPLATES_PATH")
-	loginURL := os.Getenv("LOGIN_URL")
-	smtpPort, err := strconv.Atoi(smtpPortStr)
+		fatal("failed to connect to MongoDB", err)
+	}
+	db := mongoClient.Database(cfg.Mongo.Database)
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := runMigrateCommand(db, cfg.App.Currency, os.Args[2:])
+		mongoClient.Disconnect(context.Background())
+		if err != nil {
+			fatal("migration command failed", err)
+		}
+		return
+	}
+
+	if cfg.Mongo.MigrateOnStartup {
+		// Instances starting together wait for whichever one takes the lock.
+		migrateCtx, migrateCancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		_, err := migrations.NewRunner(db, migrations.All(cfg.App.Currency)).UpWhenUnlocked(migrateCtx)
+		migrateCancel()
+		if err != nil {
+			mongoClient.Disconnect(context.Background())
+			fatal("failed to apply database migrations", err)
+		}
+	}
+
+	cld, err := cloudinary.NewFromURL(cfg.Cloudinary.URL)
 	if err != nil {
-		log.Fatalf("Invalid SMTP_PORT: %v", err)
+		slog.Warn("could not initialize Cloudinary, uploads will not work", "error", err)
 	}
-	emailService := infrastructureServices.NewEmailService(smtpHost, smtpPort, smtpUser, smtpPass, fromEmail, templatesPath, loginURL)
-	_ = emailService
+	uploadService := tracing.TraceUploadService(infrastructureServices.NewUploadService(cld))
+
+	emailService := infrastructureServices.NewDisabledEmailService()
+	if cfg.SMTP.Enabled() {
+		emailService = infrastructureServices.NewEmailService(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.User, cfg.SMTP.Password, cfg.SMTP.From, cfg.App.TemplatesPath, cfg.App.LoginURL)
+	} else {
+		slog.Warn("SMTP_HOST is not set, outgoing email is disabled")
+	}
+	emailService = tracing.TraceEmailService(emailService)
+
+	var paymentProvider infrastructureServices.PaymentProvider
+	switch cfg.Payment.Provider {
+	case "fake":
+		slog.Warn("PAYMENT_PROVIDER is fake: cards are not charged and holds are lost on restart and not shared between instances")
+		paymentProvider = infrastructureServices.NewFakePaymentProvider(cfg.Payment.WebhookSecret)
+	default:
+		slog.Info("PAYMENT_PROVIDER is none, bookings are paid by invoice")
+		paymentProvider = infrastructureServices.NewDisabledPaymentProvider()
+	}
+	paymentProvider = tracing.TracePaymentProvider(paymentProvider)
+
+	var geocoder infrastructureServices.Geocoder
+	switch cfg.Geocoding.Provider {
+	case "file":
+		fileGeocoder, err := infrastructureServices.NewFileGeocoder(cfg.Geocoding.File)
+		if err != nil {
+			fatal("failed to load geocoding table", err)
+		}
+		geocoder = fileGeocoder
+	default:
+		slog.Warn("GEOCODER_PROVIDER is fake, addresses are placed at made-up locations")
+		geocoder = infrastructureServices.NewFakeGeocoder(infrastructureServices.Location{Lat: cfg.Geocoding.FakeCenterLat, Lng: cfg.Geocoding.FakeCenterLng})
+	}
+	geocoder = tracing.TraceGeocoder(geocoder)
+
+	// Config validation only admits the offline haversine estimate until a
+	// routing provider is integrated.
+	var distanceMatrix infrastructureServices.DistanceMatrix = infrastructureServices.NewHaversineMatrix(cfg.Routing.AverageSpeedKmh, cfg.Routing.DetourFactor)
+	distanceMatrix = tracing.TraceDistanceMatrix(distanceMatrix)
 
 	userRepo := repositories.NewUserRepository(db)
+	resetTokenRepo := repositories.NewPasswordResetTokenRepository(db)
 	bookingRepo := repositories.NewBookingRepository(db)
+	ledgerRepo := repositories.NewLedgerRepository(db)
+	commissionRepo := repositories.NewCommissionRuleRepository(db)
+	systemMetricsRepo := repositories.NewSystemMetricsRepository(db)
+	invoiceRepo := repositories.NewInvoiceRepository(db)
+	reminderRepo := repositories.NewPaymentReminderRepository(db)
+	jobLockRepo := repositories.NewJobLockRepository(db)
+	paymentEventRepo := repositories.NewPaymentEventRepository(db)
+	payoutRepo := repositories.NewPayoutRepository(db)
+	propertyRepo := repositories.NewPropertyRepository(db)
+	serviceAreaRepo := repositories.NewServiceAreaRepository(db)
+	reviewRepo := repositories.NewReviewRepository(db)
 
-	authService := coreServices.NewAuthService(userRepo, emailService)
-	bookingService := coreServices.NewBookingService(bookingRepo)
-
-	authHandler := handlers.NewAuthHandler(authService)
-	bookingHandler := handlers.NewBookingHandler(bookingService)
-
-	r := chi.NewRouter()
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
-
-	workDir, _ := os.Getwd()
-	filesDir := http.Dir(filepath.Join(workDir, "web"))
-	r.Handle("/*", http.FileServer(filesDir))
-
-	// API routes
-	r.Route("/api/v1", func(r chi.Router) {
-		// Public routes for authentication
-		r.Route("/auth", func(r chi.Router) {
-			r.Post("/register", authHandler.Register)
-			r.Post("/login", authHandler.Login)
-			r.Post("/forgot-password", authHandler.ForgotPassword)
-			r.Post("/reset-password", authHandler.ResetPassword)
-		})
+	var appMetrics *metrics.Metrics
+	if cfg.Server.MetricsEnabled {
+		appMetrics = metrics.New()
+		appMetrics.RegisterBookingGauges(bookingRepo)
+		userRepo = metrics.InstrumentUserRepository(userRepo, appMetrics)
+		resetTokenRepo = metrics.InstrumentPasswordResetTokenRepository(resetTokenRepo, appMetrics)
+		bookingRepo = metrics.InstrumentBookingRepository(bookingRepo, appMetrics)
+		ledgerRepo = metrics.InstrumentLedgerRepository(ledgerRepo, appMetrics)
+		commissionRepo = metrics.InstrumentCommissionRuleRepository(commissionRepo, appMetrics)
+		systemMetricsRepo = metrics.InstrumentSystemMetricsRepository(systemMetricsRepo, appMetrics)
+		invoiceRepo = metrics.InstrumentInvoiceRepository(invoiceRepo, appMetrics)
+		reminderRepo = metrics.InstrumentPaymentReminderRepository(reminderRepo, appMetrics)
+		jobLockRepo = metrics.InstrumentJobLockRepository(jobLockRepo, appMetrics)
+		paymentEventRepo = metrics.InstrumentPaymentEventRepository(paymentEventRepo, appMetrics)
+		payoutRepo = metrics.InstrumentPayoutRepository(payoutRepo, appMetrics)
+		propertyRepo = metrics.InstrumentPropertyRepository(propertyRepo, appMetrics)
+		serviceAreaRepo = metrics.InstrumentServiceAreaRepository(serviceAreaRepo, appMetrics)
+		reviewRepo = metrics.InstrumentReviewRepository(reviewRepo, appMetrics)
+		emailService = metrics.InstrumentEmailService(emailService, appMetrics)
+		uploadService = metrics.InstrumentUploadService(uploadService, appMetrics)
+		paymentProvider = metrics.InstrumentPaymentProvider(paymentProvider, appMetrics)
+		geocoder = metrics.InstrumentGeocoder(geocoder, appMetrics)
+		distanceMatrix = metrics.InstrumentDistanceMatrix(distanceMatrix, appMetrics)
+	}
+
+	jwtSecret := []byte(cfg.Auth.JWTSecret)
+	authService := coreServices.NewAuthService(userRepo, resetTokenRepo, emailService, jwtSecret, cfg.Auth.TokenTTL, cfg.App.LoginURL)
+	ledgerService := coreServices.NewLedgerService(ledgerRepo, userRepo, systemMetricsRepo, cfg.App.Currency)
+	commissionService := coreServices.NewCommissionService(commissionRepo, cfg.App.CommissionDefaultBps)
+	txCtx, txCancel := context.WithTimeout(context.Background(), cfg.Mongo.ConnectTimeout)
+	transactor, err := database.NewTransactor(txCtx, mongoClient, cfg.Mongo.AllowNonAtomic)
+	txCancel()
+	if err != nil {
+		fatal("failed to set up transactions", err)
+	}
+	invoiceService := coreServices.NewInvoiceService(invoiceRepo, bookingRepo, userRepo, emailService, transactor, coreServices.InvoiceSettings{
+		Prefix:   cfg.Invoice.Prefix,
+		Currency: cfg.App.Currency,
+		Issuer:   documents.Issuer{Name: cfg.Invoice.IssuerName, Address: cfg.Invoice.IssuerAddress},
+	})
+	dunningService := coreServices.NewDunningService(invoiceRepo, reminderRepo, bookingRepo, userRepo, emailService, transactor, coreServices.DunningSettings{
+		Thresholds:     cfg.Dunning.Thresholds,
+		BlockOverdue:   cfg.Dunning.OverduePolicy == "block",
+		RetryBackoff:   cfg.Dunning.RetryBackoff,
+		MaxFailedSends: cfg.Dunning.MaxFailedSends,
+	})
+	paymentService := coreServices.NewPaymentService(paymentProvider, bookingRepo, paymentEventRepo, invoiceService, transactor, coreServices.PaymentSettings{
+		Currency:            cfg.App.Currency,
+		AuthorizationAmount: cfg.Payment.AuthorizationAmount,
+	})
+	payoutService := coreServices.NewPayoutService(payoutRepo, userRepo, invoiceRepo, ledgerService, transactor, coreServices.PayoutSettings{
+		Currency:      cfg.App.Currency,
+		MinimumAmount: cfg.Payout.MinimumAmount,
+		HoldPeriod:    cfg.Payout.HoldPeriod,
+		Originator: documents.Originator{
+			CompanyName: cfg.Payout.CompanyName,
+			CompanyID:   cfg.Payout.CompanyID,
+			BankRouting: cfg.Payout.BankRouting,
+			BankName:    cfg.Payout.BankName,
+		},
+	})
+	pricingSettings := coreServices.PricingSettings{
+		Currency:          cfg.App.Currency,
+		Regions:           cfg.Pricing.Regions,
+		Terrain:           cfg.Pricing.Terrain,
+		WeekendMultiplier: cfg.Pricing.WeekendMultiplier,
+		HolidayMultiplier: cfg.Pricing.HolidayMultiplier,
+		Holidays:          cfg.Pricing.Holidays,
+		RangeBps:          cfg.Pricing.RangeBps,
+	}
+	for _, service := range cfg.Pricing.Services {
+		rate := coreServices.ServiceRate{Name: service.Name, BaseFee: service.BaseFee}
+		for _, band := range service.Bands {
+			rate.Bands = append(rate.Bands, coreServices.AreaBand{UpTo: band.UpTo, Per100Sqm: band.Per100Sqm})
+		}
+		pricingSettings.Services = append(pricingSettings.Services, rate)
+	}
+	pricingService := coreServices.NewPricingService(pricingSettings)
+	locationService := coreServices.NewLocationService(geocoder, userRepo, coreServices.LocationSettings{MaxRadiusKm: cfg.Geocoding.MaxRadiusKm})
+	serviceAreaService := coreServices.NewServiceAreaService(serviceAreaRepo, bookingRepo, userRepo, transactor)
+	propertyService := coreServices.NewPropertyService(propertyRepo, pricingService, locationService, serviceAreaService, uploadService)
+	bookingService := coreServices.NewBookingService(bookingRepo, userRepo, propertyService, pricingService, locationService, serviceAreaService, ledgerService, commissionService, invoiceService, dunningService, paymentService, transactor, coreServices.BookingSettings{
+		QuoteToleranceBps:    cfg.App.QuoteToleranceBps,
+		WaitlistOutsideAreas: cfg.Geocoding.OutOfAreaPolicy == "waitlist",
+		Currency:             cfg.App.Currency,
+	})
+	routeService := coreServices.NewRouteService(bookingRepo, locationService, distanceMatrix, coreServices.RouteSettings{
+		DayStart:      cfg.Routing.DayStart,
+		JobDuration:   cfg.Routing.JobDuration,
+		ArrivalWindow: cfg.Routing.ArrivalWindow,
+	})
+	reviewService := coreServices.NewReviewService(reviewRepo, bookingRepo, userRepo, transactor, coreServices.ReviewSettings{Window: cfg.App.ReviewWindow})
+	commentService := coreServices.NewCommentService(bookingRepo, userRepo, uploadService, emailService, coreServices.CommentSettings{
+		EditWindow:   cfg.App.CommentEditWindow,
+		DeleteWindow: cfg.App.CommentDeleteWindow,
+	})
 
-		// Protected routes for customers
-		r.Group(func(r chi.Router) {
-			r.Use(handlers.AuthMiddleware)
-			r.Use(handlers.RoleMiddleware("customer"))
+	var smtpCheck func(ctx context.Context) error
+	if cfg.SMTP.Enabled() {
+		smtpCheck = health.SMTPCheck(cfg.SMTP.Host, cfg.SMTP.Port)
+	}
+	healthReporter := health.NewReporter(
+		health.Dependency{Name: "mongodb", Critical: true, Timeout: cfg.Mongo.HealthTimeout, Check: health.MongoCheck(mongoClient)},
+		health.Dependency{Name: "smtp", Timeout: cfg.SMTP.HealthTimeout, Check: smtpCheck},
+		health.Dependency{Name: "storage", Timeout: cfg.Cloudinary.HealthTimeout, Check: uploadService.Ping},
+	)
 
-			// Customer-specific booking routes
-			r.Post("/bookings", bookingHandler.CreateBooking)
-			r.Get("/bookings", bookingHandler.ListBookings)
-			r.Get("/bookings/{bookingID}", bookingHandler.GetBookingByID)
-			r.Put("/bookings/{bookingID}/cancel", bookingHandler.CancelBooking)
-		})
+	handler := server.New(server.Config{
+		StaticDir:      cfg.Server.StaticDir,
+		RequestTimeout: cfg.Server.RequestTimeout,
+		JWTSecret:      jwtSecret,
+		Logger:         logger,
+		Metrics:        appMetrics,
+	}, server.Dependencies{
+		AuthService:        authService,
+		BookingService:     bookingService,
+		LedgerService:      ledgerService,
+		CommissionService:  commissionService,
+		InvoiceService:     invoiceService,
+		DunningService:     dunningService,
+		PaymentService:     paymentService,
+		PayoutService:      payoutService,
+		PropertyService:    propertyService,
+		LocationService:    locationService,
+		ServiceAreaService: serviceAreaService,
+		RouteService:       routeService,
+		ReviewService:      reviewService,
+		CommentService:     commentService,
+		UploadService:      uploadService,
+		Health:             healthReporter,
+	})
 
-		// Protected routes for mowers
-		r.Group(func(r chi.Router) {
-			r.Use(handlers.AuthMiddleware)
-			r.Use(handlers.RoleMiddleware("mower"))
-
-			// Mower-specific booking routes
-			r.Get("/bookings", bookingHandler.ListBookings)
-			r.Get("/bookings/pending", bookingHandler.ListPendingBookings)
-			r.Get("/bookings/{bookingID}", bookingHandler.GetBookingByID)
-			r.Put("/bookings/{bookingID}/accept", bookingHandler.AcceptBooking)
-			r.Put("/bookings/{bookingID}/complete", bookingHandler.CompleteBooking)
-			r.Put("/bookings/{bookingID}/reject", bookingHandler.RejectBooking)
+	srv := server.NewServer(server.HTTPConfig{
+		Addr:              ":" + cfg.Server.Port,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		MaxHeaderBytes:    cfg.Server.MaxHeaderBytes,
+		ShutdownTimeout:   cfg.Server.ShutdownTimeout,
+		DrainDelay:        cfg.Server.DrainDelay,
+		TLSCertFile:       cfg.Server.TLSCertFile,
+		TLSKeyFile:        cfg.Server.TLSKeyFile,
+	}, handler)
+	srv.OnDrain(healthReporter.SetShuttingDown)
+	jobs := scheduler.New(jobLockRepo)
+	if cfg.Dunning.Enabled {
+		srv.Go("payment-reminders", func(ctx context.Context) {
+			jobs.Run(ctx, scheduler.Job{
+				Name:     "payment-reminders",
+				Interval: cfg.Dunning.Interval,
+				Run: func(ctx context.Context) error {
+					_, err := dunningService.SendDueReminders(ctx, time.Now())
+					return err
+				},
+			})
 		})
+	}
+	if cfg.Payout.BatchEnabled {
+		srv.Go("payout-batches", func(ctx context.Context) {
+			jobs.Run(ctx, scheduler.Job{
+				Name:     "payout-batches",
+				Interval: cfg.Payout.BatchInterval,
+				// A batch is one transaction; it should never take long.
+				Timeout: 10 * time.Minute,
+				Run: func(ctx context.Context) error {
+					_, err := payoutService.RunBatch(ctx, primitive.NilObjectID, time.Now())
+					return err
+				},
+			})
+		})
+	}
+	srv.OnShutdown(func(ctx context.Context) error {
+		if err := mongoClient.Disconnect(ctx); err != nil {
+			return fmt.Errorf("failed to disconnect from MongoDB: %w", err)
+		}
+		slog.Info("disconnected from MongoDB")
+		return nil
+	})
+	srv.OnShutdown(func(ctx context.Context) error {
+		if err := shutdownTracing(ctx); err != nil {
+			return fmt.Errorf("failed to flush traces: %w", err)
+		}
+		return nil
 	})
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := srv.Run(signalCtx); err != nil {
+		fatal("server exited with error", err)
 	}
-	log.Printf("Server listening on :%s", port)
-	if err := http.ListenAndServe(":"+port, r); err != nil {
-		log.Fatal(err)
+}
+
+// fatal logs err and exits with a non-zero status.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// runConfigCommand implements `lawnconnect-api config check [file]`, which prints
+// the resolved configuration with secrets redacted and reports every problem.
+func runConfigCommand(args []string) int {
+	if len(args) == 0 || args[0] != "check" {
+		fmt.Fprintln(os.Stderr, "usage: lawnconnect-api config check [config-file]")
+		return 2
+	}
+
+	var opts config.Options
+	if len(args) > 1 {
+		opts.File = args[1]
+	}
+
+	cfg, err := config.Load(opts)
+	if cfg != nil {
+		fmt.Print(cfg)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Println("configuration is valid")
+	return 0
+}
+
+// runMigrateCommand implements `lawnconnect-api migrate [up|status]`.
+func runMigrateCommand(db *mongo.Database, currency string, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	runner := migrations.NewRunner(db, migrations.All(currency))
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		applied, err := runner.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied %d: %s (%dms)\n", m.Version, m.Description, m.DurationMS)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("database is up to date")
+		}
+		return nil
+	case "status":
+		statuses, err := runner.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, st := range statuses {
+			state := "pending"
+			if st.Applied {
+				state = "applied " + st.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%4d  %-60s  %s\n", st.Version, st.Description, state)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q (expected \"up\" or \"status\")", command)
 	}
 }